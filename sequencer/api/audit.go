@@ -0,0 +1,220 @@
+package api
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// AuditPath is the route serving the audit trail of admin operations.
+const AuditPath = "GET /v1/audit"
+
+// ErrAuditChainBroken is returned when an audit trail entry was changed or removed.
+var ErrAuditChainBroken = errors.New("audit trail hash chain is broken")
+
+// Audit outcomes of admin operations
+const (
+	// AuditApplied means the operation was applied
+	AuditApplied = "applied"
+	// AuditDryRun means the operation was only previewed
+	AuditDryRun = "dry-run"
+	// AuditRejected means the operation was invalid and nothing was applied
+	AuditRejected = "rejected"
+)
+
+// maxAuditEntry is the longest audit entry line read
+const maxAuditEntry = 1 << 21
+
+// AuditEntry records an admin operation. Each entry carries the hash of the previous
+// one, so a changed or removed entry breaks the chain of every later entry.
+type AuditEntry struct {
+	Seq  uint64    `json:"seq"`
+	Time time.Time `json:"time"`
+	// Actor is the name of the API key the operation was authenticated with
+	Actor string `json:"actor,omitempty"`
+	// Remote is the address the operation was requested from
+	Remote    string `json:"remote"`
+	Operation string `json:"operation"`
+	// Request is the body of the operation request
+	Request json.RawMessage `json:"request,omitempty"`
+	Outcome string          `json:"outcome"`
+	Error   string          `json:"error,omitempty"`
+	// PrevHash is the hash of the previous entry, empty for the first one
+	PrevHash string `json:"prev_hash"`
+	// Hash is the hex encoded SHA-256 of the entry encoded without it
+	Hash string `json:"hash"`
+}
+
+// hash returns the hash of the entry.
+func (e AuditEntry) hash() (string, error) {
+	e.Hash = ""
+	bz, err := json.Marshal(e)
+	if err != nil {
+		return "", err
+	}
+	sum := sha256.Sum256(bz)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// AuditLog is an append-only audit trail of admin operations, stored as JSON lines.
+type AuditLog struct {
+	mu   sync.Mutex
+	file *os.File
+	seq  uint64
+	last string
+}
+
+// OpenAuditLog opens the audit trail at path, creating it if needed. The hash chain
+// of the existing entries is verified, and new entries continue it.
+func OpenAuditLog(path string) (*AuditLog, error) {
+	entries, err := readAuditLog(path)
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return nil, err
+	}
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open audit trail: %w", err)
+	}
+
+	l := &AuditLog{file: file}
+	if len(entries) > 0 {
+		l.seq = entries[len(entries)-1].Seq
+		l.last = entries[len(entries)-1].Hash
+	}
+	return l, nil
+}
+
+// readAuditLog reads and verifies the entries of the audit trail at path.
+func readAuditLog(path string) ([]AuditEntry, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	var entries []AuditEntry
+	prev := ""
+	scanner := bufio.NewScanner(file)
+	scanner.Buffer(nil, maxAuditEntry)
+	for scanner.Scan() {
+		var entry AuditEntry
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("failed to decode audit entry %d: %w", len(entries)+1, err)
+		}
+		hash, err := entry.hash()
+		if err != nil {
+			return nil, err
+		}
+		if entry.PrevHash != prev || entry.Hash != hash || entry.Seq != uint64(len(entries))+1 {
+			return nil, fmt.Errorf("%w at entry %d", ErrAuditChainBroken, len(entries)+1)
+		}
+		entries = append(entries, entry)
+		prev = entry.Hash
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read audit trail: %w", err)
+	}
+	return entries, nil
+}
+
+// Record appends entry to the trail and syncs it to disk. Seq, PrevHash and Hash are
+// set by the log.
+func (l *AuditLog) Record(entry AuditEntry) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	entry.Seq = l.seq + 1
+	entry.PrevHash = l.last
+	hash, err := entry.hash()
+	if err != nil {
+		return err
+	}
+	entry.Hash = hash
+
+	bz, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(append(bz, '\n')); err != nil {
+		return fmt.Errorf("failed to write audit entry: %w", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("failed to sync audit trail: %w", err)
+	}
+
+	l.seq = entry.Seq
+	l.last = entry.Hash
+	return nil
+}
+
+// Entries reads and verifies every entry of the trail.
+func (l *AuditLog) Entries() ([]AuditEntry, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return readAuditLog(l.file.Name())
+}
+
+// Close closes the trail.
+func (l *AuditLog) Close() error {
+	return l.file.Close()
+}
+
+// record records an admin operation requested by r, if audit is set. The actor is the
+// API key r was authenticated with by Policy.Protect.
+func record(audit *AuditLog, r *http.Request, operation string, request any, outcome string, opErr error) error {
+	if audit == nil {
+		return nil
+	}
+
+	entry := AuditEntry{
+		Time:      time.Now().UTC(),
+		Remote:    remoteIP(r),
+		Operation: operation,
+		Outcome:   outcome,
+	}
+	if client, ok := clientFrom(r.Context()); ok {
+		entry.Actor = client.Key
+	}
+	if request != nil {
+		bz, err := json.Marshal(request)
+		if err != nil {
+			return err
+		}
+		entry.Request = bz
+	}
+	if opErr != nil {
+		entry.Error = opErr.Error()
+	}
+	return audit.Record(entry)
+}
+
+// AuditResponse is the audit trail of admin operations.
+type AuditResponse struct {
+	// Entries are the operations, oldest first; the hash chain of them is verified
+	Entries []AuditEntry `json:"entries"`
+}
+
+// NewAuditHandler creates a handler serving the entries of audit, failing with
+// CodeInternal if the trail was tampered with.
+func NewAuditHandler(audit *AuditLog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		entries, err := audit.Entries()
+		if err != nil {
+			writeError(w, CodeInternal, err, nil)
+			return
+		}
+		if entries == nil {
+			entries = []AuditEntry{}
+		}
+		writeJSON(w, http.StatusOK, AuditResponse{Entries: entries})
+	})
+}
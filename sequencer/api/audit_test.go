@@ -0,0 +1,52 @@
+package api
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestAuditLog(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	audit, err := OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, op := range []string{"first", "second"} {
+		if err := audit.Record(AuditEntry{Operation: op, Outcome: AuditApplied}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	_ = audit.Close()
+
+	// Reopened trails continue the chain
+	audit, err = OpenAuditLog(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := audit.Record(AuditEntry{Operation: "third", Outcome: AuditApplied}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	entries, err := audit.Entries()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 3 || entries[2].Seq != 3 || entries[2].PrevHash != entries[1].Hash {
+		t.Fatalf("unexpected entries %+v", entries)
+	}
+	_ = audit.Close()
+
+	// Changed entries break the chain
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(path, []byte(strings.Replace(string(bz), "second", "altered", 1)), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := OpenAuditLog(path); !errors.Is(err, ErrAuditChainBroken) {
+		t.Fatalf("expected broken chain, got %v", err)
+	}
+}
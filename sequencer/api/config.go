@@ -0,0 +1,34 @@
+package api
+
+import (
+	"net/http"
+)
+
+// ConfigPath is the route serving the effective node configuration.
+const ConfigPath = "GET /v1/config"
+
+// ConfigValue is a resolved configuration value and where it came from.
+type ConfigValue struct {
+	Key   string `json:"key"`
+	Value any    `json:"value"`
+	// Source is one of default, file, env or flag
+	Source string `json:"source"`
+	// Env is the environment variable the value was read from
+	Env string `json:"env,omitempty"`
+}
+
+// ConfigResponse is the effective configuration of the node.
+type ConfigResponse struct {
+	// ConfigFile is the path of the config file values with the file source come from
+	ConfigFile string        `json:"config_file"`
+	Values     []ConfigValue `json:"values"`
+}
+
+// NewConfigHandler creates a handler serving the configuration the node was started
+// with. Secrets must already be redacted from values.
+func NewConfigHandler(configFile string, values []ConfigValue) http.Handler {
+	resp := ConfigResponse{ConfigFile: configFile, Values: values}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
@@ -0,0 +1,35 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestConfigHandler(t *testing.T) {
+	server := NewServer("", zerolog.Nop())
+	server.Handle(ConfigPath, NewConfigHandler("/home/config/evnode.yaml", []ConfigValue{
+		{Key: "da.address", Value: "http://localhost:7980", Source: "file"},
+		{Key: "api.addr", Value: ":7332", Source: "env", Env: "API.ADDR"},
+	}))
+
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/config", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var resp ConfigResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.ConfigFile != "/home/config/evnode.yaml" || len(resp.Values) != 2 {
+		t.Fatalf("unexpected response %+v", resp)
+	}
+	if resp.Values[1] != (ConfigValue{Key: "api.addr", Value: ":7332", Source: "env", Env: "API.ADDR"}) {
+		t.Errorf("unexpected value %+v", resp.Values[1])
+	}
+}
@@ -0,0 +1,21 @@
+package api
+
+import (
+	_ "embed"
+	"net/http"
+)
+
+// DashboardPath is the route serving the embedded telemetry dashboard.
+const DashboardPath = "GET /{$}"
+
+//go:embed dashboard.html
+var dashboardHTML []byte
+
+// NewDashboardHandler creates a handler serving the read-only telemetry dashboard.
+// The page polls StatusPath and LogsPath on the same server.
+func NewDashboardHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		_, _ = w.Write(dashboardHTML)
+	})
+}
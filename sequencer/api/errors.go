@@ -0,0 +1,111 @@
+package api
+
+import (
+	"net/http"
+)
+
+// ErrorsPath is the route serving the error code registry.
+const ErrorsPath = "GET /v1/errors"
+
+// ErrorCode is a machine-readable identifier of a class of API errors.
+type ErrorCode string
+
+// Error codes returned by the sequencer APIs
+const (
+	// CodeInvalidArgument means the request is malformed and must not be retried as is
+	CodeInvalidArgument ErrorCode = "INVALID_ARGUMENT"
+	// CodePayloadTooLarge means the request body exceeds the size the route accepts
+	CodePayloadTooLarge ErrorCode = "PAYLOAD_TOO_LARGE"
+	// CodeNotInitialized means the chain has not been initialized yet
+	CodeNotInitialized ErrorCode = "NOT_INITIALIZED"
+	// CodeBlockNotFound means the block is not in the local store
+	CodeBlockNotFound ErrorCode = "BLOCK_NOT_FOUND"
+	// CodeNotDAIncluded means the block has not been included in DA yet
+	CodeNotDAIncluded ErrorCode = "NOT_DA_INCLUDED"
+	// CodeBlobNotFound means the DA layer does not hold the blob recorded for the block
+	CodeBlobNotFound ErrorCode = "BLOB_NOT_FOUND"
+	// CodeDAUnavailable means the DA layer could not be queried
+	CodeDAUnavailable ErrorCode = "DA_UNAVAILABLE"
+	// CodeFeatureNotFound means the feature flag is not defined
+	CodeFeatureNotFound ErrorCode = "FEATURE_NOT_FOUND"
+	// CodeFeatureStartupOnly means the feature flag can only be changed by restarting the node
+	CodeFeatureStartupOnly ErrorCode = "FEATURE_STARTUP_ONLY"
+	// CodeUnauthenticated means the request has no valid API key
+	CodeUnauthenticated ErrorCode = "UNAUTHENTICATED"
+	// CodeRateLimited means the caller exceeded its request rate
+	CodeRateLimited ErrorCode = "RATE_LIMITED"
+	// CodeRouteNotFound means the route is not exposed by this node
+	CodeRouteNotFound ErrorCode = "ROUTE_NOT_FOUND"
+	// CodeExecutionUnavailable means the execution layer could not be reached
+	CodeExecutionUnavailable ErrorCode = "EXECUTION_UNAVAILABLE"
+	// CodeExecutionRestarting means the execution layer is already being restarted
+	CodeExecutionRestarting ErrorCode = "EXECUTION_RESTARTING"
+	// CodeInternal means the node failed to serve the request
+	CodeInternal ErrorCode = "INTERNAL"
+)
+
+// ErrorDefinition documents an error code in the registry.
+type ErrorDefinition struct {
+	Code ErrorCode `json:"code"`
+	// HTTPStatus is the status code responses with this error are sent with
+	HTTPStatus int `json:"http_status"`
+	// Retryable reports whether the same request may succeed later
+	Retryable   bool   `json:"retryable"`
+	Description string `json:"description"`
+}
+
+// errorRegistry is the single source of truth for error codes; responses and the
+// ErrorsPath listing are both derived from it.
+var errorRegistry = []ErrorDefinition{
+	{Code: CodeInvalidArgument, HTTPStatus: http.StatusBadRequest, Retryable: false, Description: "The request is malformed."},
+	{Code: CodePayloadTooLarge, HTTPStatus: http.StatusRequestEntityTooLarge, Retryable: false, Description: "The request body exceeds the size the route accepts."},
+	{Code: CodeNotInitialized, HTTPStatus: http.StatusServiceUnavailable, Retryable: true, Description: "The chain has not been initialized yet."},
+	{Code: CodeBlockNotFound, HTTPStatus: http.StatusNotFound, Retryable: true, Description: "The block is not in the local store yet."},
+	{Code: CodeNotDAIncluded, HTTPStatus: http.StatusNotFound, Retryable: true, Description: "The block has not been included in DA yet."},
+	{Code: CodeBlobNotFound, HTTPStatus: http.StatusNotFound, Retryable: false, Description: "The DA layer does not hold the blob recorded for the block."},
+	{Code: CodeDAUnavailable, HTTPStatus: http.StatusBadGateway, Retryable: true, Description: "The DA layer could not be queried."},
+	{Code: CodeFeatureNotFound, HTTPStatus: http.StatusNotFound, Retryable: false, Description: "The feature flag is not defined."},
+	{Code: CodeFeatureStartupOnly, HTTPStatus: http.StatusConflict, Retryable: false, Description: "The feature flag can only be changed by restarting the node."},
+	{Code: CodeUnauthenticated, HTTPStatus: http.StatusUnauthorized, Retryable: false, Description: "The request has no valid API key."},
+	{Code: CodeRateLimited, HTTPStatus: http.StatusTooManyRequests, Retryable: true, Description: "The caller exceeded its request rate."},
+	{Code: CodeRouteNotFound, HTTPStatus: http.StatusNotFound, Retryable: false, Description: "The route is not exposed by this node."},
+	{Code: CodeExecutionUnavailable, HTTPStatus: http.StatusBadGateway, Retryable: true, Description: "The execution layer could not be reached."},
+	{Code: CodeExecutionRestarting, HTTPStatus: http.StatusConflict, Retryable: true, Description: "The execution layer is already being restarted."},
+	{Code: CodeInternal, HTTPStatus: http.StatusInternalServerError, Retryable: true, Description: "The node failed to serve the request."},
+}
+
+// ErrorBody is the machine-readable description of a failed request.
+type ErrorBody struct {
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	// Details holds error specific context, e.g. the requested height
+	Details   map[string]any `json:"details,omitempty"`
+	Retryable bool           `json:"retryable"`
+}
+
+// ErrorResponse is the envelope every API error is returned in.
+type ErrorResponse struct {
+	Error ErrorBody `json:"error"`
+}
+
+// ErrorsResponse lists every error code the APIs may return.
+type ErrorsResponse struct {
+	Errors []ErrorDefinition `json:"errors"`
+}
+
+// lookupError returns the registry definition of code, falling back to CodeInternal.
+func lookupError(code ErrorCode) ErrorDefinition {
+	for _, def := range errorRegistry {
+		if def.Code == code {
+			return def
+		}
+	}
+	return ErrorDefinition{Code: CodeInternal, HTTPStatus: http.StatusInternalServerError, Retryable: true}
+}
+
+// newErrorsHandler creates a handler listing the error code registry.
+func newErrorsHandler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, ErrorsResponse{Errors: errorRegistry})
+	})
+}
@@ -0,0 +1,60 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/pkg/store"
+)
+
+func TestErrorEnvelope(t *testing.T) {
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+	server := NewServer("", zerolog.Nop())
+	server.Handle(InclusionPath, NewInclusionHandler(st, nil, nil, nil, zerolog.Nop()))
+
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/da/inclusion/7", nil))
+	if rec.Code != http.StatusNotFound {
+		t.Fatalf("expected status %d, got %d", http.StatusNotFound, rec.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Error.Code != CodeBlockNotFound || !resp.Error.Retryable || resp.Error.Details["height"] != float64(7) {
+		t.Errorf("expected retryable %s error for height 7, got %+v", CodeBlockNotFound, resp.Error)
+	}
+}
+
+func TestErrorsHandler(t *testing.T) {
+	server := NewServer("", zerolog.Nop())
+
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/errors", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var resp ErrorsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resp.Errors) != len(errorRegistry) {
+		t.Fatalf("expected %d error codes, got %d", len(errorRegistry), len(resp.Errors))
+	}
+
+	seen := make(map[ErrorCode]bool)
+	for _, def := range resp.Errors {
+		if seen[def.Code] {
+			t.Errorf("duplicate error code %s", def.Code)
+		}
+		seen[def.Code] = true
+	}
+}
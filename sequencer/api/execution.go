@@ -0,0 +1,37 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"github.com/pranklin/pranklin-sequencer/supervisor"
+)
+
+// ExecutionRestartPath is the admin route restarting the managed execution layer.
+const ExecutionRestartPath = "POST /v1/execution/restart"
+
+// ExecutionRestartResponse reports a completed restart of the execution layer.
+type ExecutionRestartResponse struct {
+	// PausedSeconds is how long block production was paused
+	PausedSeconds float64 `json:"paused_seconds"`
+}
+
+// NewExecutionRestartHandler creates a handler calling restart, which replaces the
+// execution subprocess while block production is paused, and replying once the new
+// execution layer is ready. The restart carries on if the client goes away.
+func NewExecutionRestartHandler(restart func() error) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		if err := restart(); err != nil {
+			code := CodeExecutionUnavailable
+			if errors.Is(err, supervisor.ErrReplacing) {
+				code = CodeExecutionRestarting
+			}
+			writeError(w, code, err, nil)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, ExecutionRestartResponse{PausedSeconds: time.Since(start).Seconds()})
+	})
+}
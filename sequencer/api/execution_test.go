@@ -0,0 +1,41 @@
+package api
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/pranklin/pranklin-sequencer/supervisor"
+)
+
+func TestExecutionRestartHandler(t *testing.T) {
+	var err error
+	restarts := 0
+	server := NewServer("", zerolog.Nop())
+	server.Handle(ExecutionRestartPath, NewExecutionRestartHandler(func() error {
+		restarts++
+		return err
+	}))
+
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/execution/restart", nil))
+	if rec.Code != http.StatusOK || restarts != 1 {
+		t.Fatalf("expected status %d after one restart, got %d after %d: %s", http.StatusOK, rec.Code, restarts, rec.Body.String())
+	}
+
+	err = fmt.Errorf("execution: %w", supervisor.ErrReplacing)
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/execution/restart", nil))
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusConflict || resp.Error.Code != CodeExecutionRestarting {
+		t.Errorf("expected %s with status %d, got %d: %+v", CodeExecutionRestarting, http.StatusConflict, rec.Code, resp.Error)
+	}
+}
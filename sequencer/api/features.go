@@ -0,0 +1,184 @@
+package api
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/pranklin/pranklin-sequencer/feature"
+)
+
+// FeaturesPath is the route serving the feature flags of the node.
+const FeaturesPath = "GET /v1/features"
+
+// FeaturePath is the admin route changing a feature flag at runtime.
+const FeaturePath = "PUT /v1/features/{name}"
+
+// FeaturesBatchPath is the admin route changing several feature flags at once.
+const FeaturesBatchPath = "POST /v1/features/batch"
+
+// Feature is the state of a feature flag.
+type Feature struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Enabled     bool   `json:"enabled"`
+	// Runtime reports whether the flag can be changed through FeaturePath
+	Runtime bool `json:"runtime"`
+}
+
+// FeaturesResponse lists the feature flags of the node.
+type FeaturesResponse struct {
+	Features []Feature `json:"features"`
+}
+
+// FeatureRequest is the body of a feature flag change.
+type FeatureRequest struct {
+	Enabled bool `json:"enabled"`
+}
+
+// FeatureChange is a change of one flag in a batch.
+type FeatureChange struct {
+	Name    string `json:"name"`
+	Enabled bool   `json:"enabled"`
+}
+
+// FeaturesBatchRequest is the body of a batch of feature flag changes.
+type FeaturesBatchRequest struct {
+	Changes []FeatureChange `json:"changes"`
+	// DryRun only checks the changes and previews the resulting flags
+	DryRun bool `json:"dry_run"`
+}
+
+// FeaturesBatchResponse is the result of a batch of feature flag changes.
+type FeaturesBatchResponse struct {
+	DryRun bool `json:"dry_run"`
+	// Features are every flag after the changes, or as they would be for a dry run
+	Features []Feature `json:"features"`
+}
+
+// NewFeaturesHandler creates a handler listing flags.
+func NewFeaturesHandler(flags *feature.Flags) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, FeaturesResponse{Features: features(flags.List())})
+	})
+}
+
+// NewFeatureHandler creates a handler changing one of flags and replying with the
+// resulting flag state. The change only applies to this node, and is recorded in
+// audit if it is set.
+func NewFeatureHandler(flags *feature.Flags, audit *AuditLog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		name := r.PathValue("name")
+
+		var req FeatureRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, CodeInvalidArgument, fmt.Errorf("invalid request body: %w", err), nil)
+			return
+		}
+
+		change := FeatureChange{Name: name, Enabled: req.Enabled}
+		states, err := setFeatures(w, r, flags, audit, "feature.set", change, []feature.Change{{Name: name, Enabled: req.Enabled}}, false)
+		if err != nil {
+			return
+		}
+
+		for _, f := range features(states) {
+			if f.Name == name {
+				writeJSON(w, http.StatusOK, f)
+				return
+			}
+		}
+	})
+}
+
+// NewFeaturesBatchHandler creates a handler changing several of flags at once, all or
+// none, so an incident response is never left half applied. A dry run previews the
+// resulting flags without changing them. Every batch is recorded in audit if it is set.
+func NewFeaturesBatchHandler(flags *feature.Flags, audit *AuditLog) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req FeaturesBatchRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, CodeInvalidArgument, fmt.Errorf("invalid request body: %w", err), nil)
+			return
+		}
+		if len(req.Changes) == 0 {
+			writeError(w, CodeInvalidArgument, errors.New("at least one change is required"), nil)
+			return
+		}
+
+		changes := make([]feature.Change, 0, len(req.Changes))
+		for _, change := range req.Changes {
+			changes = append(changes, feature.Change{Name: change.Name, Enabled: change.Enabled})
+		}
+
+		states, err := setFeatures(w, r, flags, audit, "feature.batch", req, changes, req.DryRun)
+		if err != nil {
+			return
+		}
+		writeJSON(w, http.StatusOK, FeaturesBatchResponse{DryRun: req.DryRun, Features: features(states)})
+	})
+}
+
+// setFeatures applies changes to flags as the operation requested by r, recording it in
+// audit. The changes are checked first and applied only once the operation is recorded,
+// so every applied change is in the trail. Errors are written to w.
+func setFeatures(w http.ResponseWriter, r *http.Request, flags *feature.Flags, audit *AuditLog, operation string, request any, changes []feature.Change, dryRun bool) ([]feature.State, error) {
+	states, err := flags.SetAll(changes, true)
+	if err != nil {
+		if auditErr := record(audit, r, operation, request, AuditRejected, err); auditErr != nil {
+			writeError(w, CodeInternal, auditErr, nil)
+			return nil, auditErr
+		}
+
+		code := CodeInternal
+		details := map[string]any{}
+		switch {
+		case errors.Is(err, feature.ErrUnknownFlag):
+			code = CodeFeatureNotFound
+		case errors.Is(err, feature.ErrStartupOnly):
+			code = CodeFeatureStartupOnly
+		}
+		// Name the first change that is invalid on its own
+		for _, change := range changes {
+			if _, err := flags.SetAll([]feature.Change{change}, true); err != nil {
+				details["name"] = change.Name
+				break
+			}
+		}
+		writeError(w, code, err, details)
+		return nil, err
+	}
+
+	outcome := AuditApplied
+	if dryRun {
+		outcome = AuditDryRun
+	}
+	if err := record(audit, r, operation, request, outcome, nil); err != nil {
+		writeError(w, CodeInternal, err, nil)
+		return nil, err
+	}
+	if dryRun {
+		return states, nil
+	}
+
+	if states, err = flags.SetAll(changes, false); err != nil {
+		writeError(w, CodeInternal, err, nil)
+		return nil, err
+	}
+	return states, nil
+}
+
+// features returns the API representation of states
+func features(states []feature.State) []Feature {
+	resp := make([]Feature, 0, len(states))
+	for _, state := range states {
+		resp = append(resp, Feature{
+			Name:        state.Name,
+			Description: state.Description,
+			Enabled:     state.Enabled,
+			Runtime:     state.Runtime,
+		})
+	}
+	return resp
+}
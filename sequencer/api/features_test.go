@@ -0,0 +1,167 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+
+	"github.com/pranklin/pranklin-sequencer/feature"
+)
+
+func TestFeatureHandlers(t *testing.T) {
+	metrics, _ := feature.NopMetrics()
+	flags, err := feature.NewFlags([]feature.Definition{
+		{Name: "startup"},
+		{Name: "runtime", Runtime: true},
+	}, nil, zerolog.Nop(), metrics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server := NewServer("", zerolog.Nop())
+	server.Handle(FeaturesPath, NewFeaturesHandler(flags))
+	server.Handle(FeaturePath, NewFeatureHandler(flags, nil))
+
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, "/v1/features/runtime", strings.NewReader(`{"enabled":true}`)))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+	var changed Feature
+	if err := json.NewDecoder(rec.Body).Decode(&changed); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if changed.Name != "runtime" || !changed.Enabled || !flags.Enabled("runtime") {
+		t.Errorf("expected runtime flag enabled, got %+v", changed)
+	}
+
+	tests := []struct {
+		path string
+		body string
+		code ErrorCode
+	}{
+		{"/v1/features/startup", `{"enabled":true}`, CodeFeatureStartupOnly},
+		{"/v1/features/undefined", `{"enabled":true}`, CodeFeatureNotFound},
+		{"/v1/features/runtime", `{`, CodeInvalidArgument},
+	}
+	for _, tt := range tests {
+		rec := httptest.NewRecorder()
+		server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPut, tt.path, strings.NewReader(tt.body)))
+
+		var resp ErrorResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if resp.Error.Code != tt.code || rec.Code != lookupError(tt.code).HTTPStatus {
+			t.Errorf("%s: expected %s, got %d %+v", tt.path, tt.code, rec.Code, resp.Error)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/features", nil))
+	var list FeaturesResponse
+	if err := json.NewDecoder(rec.Body).Decode(&list); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(list.Features) != 2 || list.Features[0].Name != "runtime" || !list.Features[0].Enabled || list.Features[1].Enabled {
+		t.Errorf("unexpected features: %+v", list.Features)
+	}
+}
+
+func TestFeaturesBatchHandler(t *testing.T) {
+	metrics, _ := feature.NopMetrics()
+	flags, err := feature.NewFlags([]feature.Definition{
+		{Name: "startup"},
+		{Name: "a", Runtime: true},
+		{Name: "b", Runtime: true},
+	}, nil, zerolog.Nop(), metrics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	audit, err := OpenAuditLog(filepath.Join(t.TempDir(), "audit.jsonl"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer audit.Close()
+
+	keys, err := NewAPIKeys([]APIKey{{Name: "ops", Token: "secret"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	apiMetrics, _ := NopMetrics()
+	admin := NewPolicy(keys, nil, apiMetrics)
+
+	server := NewServer("", zerolog.Nop())
+	server.Handle(FeaturesBatchPath, admin.Protect("admin", NewFeaturesBatchHandler(flags, audit)))
+	server.Handle(AuditPath, admin.Protect("admin", NewAuditHandler(audit)))
+
+	batch := func(body string) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/features/batch", strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		server.mux.ServeHTTP(rec, req)
+		return rec
+	}
+
+	// Batches need an admin key
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/features/batch", strings.NewReader(`{"changes": [{"name": "a", "enabled": true}]}`)))
+	if code := decodeErrorCode(t, rec); code != CodeUnauthenticated || flags.Enabled("a") {
+		t.Fatalf("expected an unauthenticated batch rejected, got %s", code)
+	}
+
+	// A dry run previews the flags
+	rec = batch(`{"changes": [{"name": "a", "enabled": true}, {"name": "b", "enabled": true}], "dry_run": true}`)
+	var resp FeaturesBatchResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if rec.Code != http.StatusOK || !resp.DryRun || !resp.Features[0].Enabled || !resp.Features[1].Enabled {
+		t.Fatalf("unexpected preview %d %+v", rec.Code, resp)
+	}
+	if flags.Enabled("a") || flags.Enabled("b") {
+		t.Fatal("expected no change from a dry run")
+	}
+
+	// A batch with an invalid change applies nothing
+	rec = batch(`{"changes": [{"name": "a", "enabled": true}, {"name": "startup", "enabled": true}]}`)
+	if code := decodeErrorCode(t, rec); code != CodeFeatureStartupOnly {
+		t.Fatalf("expected startup only error, got %s", code)
+	}
+	if flags.Enabled("a") {
+		t.Fatal("expected no change from a rejected batch")
+	}
+
+	rec = batch(`{"changes": [{"name": "a", "enabled": true}, {"name": "b", "enabled": true}]}`)
+	if rec.Code != http.StatusOK || !flags.Enabled("a") || !flags.Enabled("b") {
+		t.Fatalf("expected both changes applied, got %d", rec.Code)
+	}
+
+	// Every batch is in the audit trail
+	rec = httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/v1/audit", nil)
+	req.Header.Set("Authorization", "Bearer secret")
+	server.mux.ServeHTTP(rec, req)
+	var trail AuditResponse
+	if err := json.NewDecoder(rec.Body).Decode(&trail); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var outcomes []string
+	for _, entry := range trail.Entries {
+		outcomes = append(outcomes, entry.Outcome)
+		if entry.Actor != "ops" {
+			t.Errorf("expected the operation recorded for key ops, got %q", entry.Actor)
+		}
+	}
+	if strings.Join(outcomes, ",") != "dry-run,rejected,applied" {
+		t.Errorf("unexpected audit outcomes %v", outcomes)
+	}
+	if !strings.Contains(string(trail.Entries[1].Request), "startup") || trail.Entries[1].Error == "" {
+		t.Errorf("expected the rejected request and its error, got %+v", trail.Entries[1])
+	}
+}
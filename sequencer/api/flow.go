@@ -0,0 +1,310 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strconv"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// ClassifyProcedure is the gRPC method operator-supplied flow classifiers implement:
+//
+//	service FlowClassifier {
+//	  rpc Classify(google.protobuf.Struct) returns (google.protobuf.Struct);
+//	}
+//
+// in package pranklin.flow.v1. The request holds the FlowRequest fields and the
+// response a "tags" list of strings.
+const ClassifyProcedure = "/pranklin.flow.v1.FlowClassifier/Classify"
+
+// FlowRequest describes a submission to classify.
+type FlowRequest struct {
+	// Client is the identity limits are counted against, see Client.ID
+	Client string
+	IP     string
+	// Tx is the submitted transaction
+	Tx   []byte
+	Time time.Time
+}
+
+// Classifier tags order flow, e.g. "latency-arb" for latency arbitrage patterns.
+type Classifier interface {
+	Classify(ctx context.Context, req FlowRequest) ([]string, error)
+}
+
+// GRPCClassifier is a Classifier calling an operator-supplied gRPC plugin.
+type GRPCClassifier struct {
+	client *connect.Client[structpb.Struct, structpb.Struct]
+}
+
+// NewGRPCClassifier creates a classifier calling the ClassifyProcedure of the gRPC
+// server at baseURL with httpClient, which must speak HTTP/2 for cleartext servers.
+func NewGRPCClassifier(httpClient connect.HTTPClient, baseURL string) *GRPCClassifier {
+	return &GRPCClassifier{
+		client: connect.NewClient[structpb.Struct, structpb.Struct](httpClient, baseURL+ClassifyProcedure, connect.WithGRPC()),
+	}
+}
+
+// Classify returns the tags the plugin gives req.
+func (c *GRPCClassifier) Classify(ctx context.Context, req FlowRequest) ([]string, error) {
+	msg, err := structpb.NewStruct(map[string]any{
+		"client": req.Client,
+		"ip":     req.IP,
+		"tx":     hex.EncodeToString(req.Tx),
+		"time":   req.Time.UTC().Format(time.RFC3339Nano),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.client.CallUnary(ctx, connect.NewRequest(msg))
+	if err != nil {
+		return nil, err
+	}
+
+	var tags []string
+	for _, value := range resp.Msg.GetFields()["tags"].GetListValue().GetValues() {
+		if tag := value.GetStringValue(); tag != "" {
+			tags = append(tags, tag)
+		}
+	}
+	return tags, nil
+}
+
+// FlowPolicy is applied to the submissions of a tag.
+type FlowPolicy struct {
+	Tag string `json:"tag"`
+	// Delay is the speed bump submissions are held for before they are forwarded
+	Delay time.Duration `json:"-"`
+	// RateLimit is the per client request rate of the tag, 0 for none
+	RateLimit float64 `json:"rate_limit"`
+	// Burst is the per client request burst of the tag
+	Burst int `json:"burst"`
+}
+
+// LoadFlowPolicies reads the JSON list of flow policies at path, e.g.
+// [{"tag": "latency-arb", "delay": "250ms", "rate_limit": 1, "burst": 2}].
+func LoadFlowPolicies(path string) ([]FlowPolicy, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read flow policies: %w", err)
+	}
+
+	var entries []struct {
+		FlowPolicy
+		Delay string `json:"delay"`
+	}
+	if err := json.Unmarshal(bz, &entries); err != nil {
+		return nil, fmt.Errorf("failed to decode flow policies: %w", err)
+	}
+
+	policies := make([]FlowPolicy, 0, len(entries))
+	for _, entry := range entries {
+		policy := entry.FlowPolicy
+		if entry.Delay != "" {
+			if policy.Delay, err = time.ParseDuration(entry.Delay); err != nil {
+				return nil, fmt.Errorf("invalid delay of flow policy %s: %w", policy.Tag, err)
+			}
+		}
+		policies = append(policies, policy)
+	}
+	return policies, nil
+}
+
+// Flow decisions, the action labels of the flow metrics
+const (
+	// FlowPass means the submission was forwarded without a policy
+	FlowPass = "pass"
+	// FlowDelayed means the submission was held by a speed bump
+	FlowDelayed = "delay"
+	// FlowLimited means the submission was rejected by the rate limit of a tag
+	FlowLimited = "limit"
+	// FlowUnclassified means the classifier failed and the submission was forwarded
+	FlowUnclassified = "error"
+)
+
+// flowUntagged is the tag label of decisions on submissions without tags
+const flowUntagged = "none"
+
+// flowOther is the tag label of decisions on tags without a policy, which the
+// classifier may return any number of
+const flowOther = "other"
+
+// FlowGuard classifies proxied submissions and applies the policies of their tags.
+type FlowGuard struct {
+	classifier Classifier
+	timeout    time.Duration
+	policies   map[string]FlowPolicy
+	limiters   map[string]*RateLimiter
+	enabled    func() bool
+	metrics    *Metrics
+	logger     zerolog.Logger
+}
+
+// NewFlowGuard creates a new flow guard.
+//
+// Parameters:
+// - classifier: Classifier tagging submissions
+// - timeout: Longest a classification may take; submissions are forwarded unclassified after it
+// - policies: Policies of tags, at most one per tag
+// - enabled: Reports whether submissions are classified, read on every submission (nil always classifies)
+// - metrics: API metrics, recording every decision for fairness review
+// - logger: Logger for classifier failures
+//
+// Returns:
+// - *FlowGuard: The initialized guard; wrap the proxy with Guard
+// - error: If a policy is invalid
+func NewFlowGuard(classifier Classifier, timeout time.Duration, policies []FlowPolicy, enabled func() bool, metrics *Metrics, logger zerolog.Logger) (*FlowGuard, error) {
+	g := &FlowGuard{
+		classifier: classifier,
+		timeout:    timeout,
+		policies:   make(map[string]FlowPolicy, len(policies)),
+		limiters:   make(map[string]*RateLimiter),
+		enabled:    enabled,
+		metrics:    metrics,
+		logger:     logger,
+	}
+	for _, policy := range policies {
+		if policy.Tag == "" {
+			return nil, errors.New("flow policies need a tag")
+		}
+		if _, ok := g.policies[policy.Tag]; ok {
+			return nil, fmt.Errorf("duplicate flow policy %s", policy.Tag)
+		}
+		if policy.Delay < 0 || policy.RateLimit < 0 {
+			return nil, fmt.Errorf("flow policy %s needs a delay and rate limit >= 0", policy.Tag)
+		}
+		if policy.RateLimit > 0 {
+			if policy.Burst < 1 {
+				return nil, fmt.Errorf("flow policy %s needs a burst >= 1", policy.Tag)
+			}
+			g.limiters[policy.Tag] = NewRateLimiter(policy.RateLimit, policy.Burst)
+		}
+		g.policies[policy.Tag] = policy
+	}
+	return g, nil
+}
+
+// Guard returns next applying the policies of the tags of submissions through it.
+// It must be wrapped by Policy.Protect, which identifies the client.
+func (g *FlowGuard) Guard(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, ok := clientFrom(r.Context())
+		if !ok || r.Method != http.MethodPost || r.URL.Path != submitTxPath || (g.enabled != nil && !g.enabled()) {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSubmitBody))
+		if err != nil {
+			writeError(w, CodeInvalidArgument, err, nil)
+			return
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+		tx, ok := submittedTx(body)
+		if !ok {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		tags, err := g.classify(r.Context(), FlowRequest{Client: client.ID(), IP: client.IP, Tx: tx, Time: time.Now()})
+		if err != nil {
+			g.logger.Warn().Err(err).Str("client", client.ID()).Msg("Failed to classify submission, forwarding it unclassified")
+			g.metrics.FlowDecisions.With("tag", flowUntagged, "action", FlowUnclassified).Add(1)
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		// A submission rejected by the limit of one tag does not spend those of the others
+		cancels := make([]func(), 0, len(tags))
+		for _, tag := range tags {
+			limiter := g.limiters[tag]
+			if limiter == nil {
+				continue
+			}
+			usage, cancel := limiter.Reserve(client.ID())
+			if !usage.Allowed {
+				for _, cancel := range cancels {
+					cancel()
+				}
+				g.metrics.FlowDecisions.With("tag", tag, "action", FlowLimited).Add(1)
+				w.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(usage.RetryAfter)))
+				writeError(w, CodeRateLimited, fmt.Errorf("rate limit of %s flow exceeded", tag), map[string]any{"tag": tag, "retry_after_seconds": usage.RetryAfter.Seconds()})
+				return
+			}
+			cancels = append(cancels, cancel)
+		}
+
+		var delay time.Duration
+		var others bool
+		for _, tag := range tags {
+			policy, ok := g.policies[tag]
+			if !ok {
+				others = true
+				continue
+			}
+			if policy.Delay > 0 {
+				delay = max(delay, policy.Delay)
+				g.metrics.FlowDecisions.With("tag", tag, "action", FlowDelayed).Add(1)
+			} else {
+				g.metrics.FlowDecisions.With("tag", tag, "action", FlowPass).Add(1)
+			}
+		}
+		if others {
+			g.metrics.FlowDecisions.With("tag", flowOther, "action", FlowPass).Add(1)
+		}
+		if len(tags) == 0 {
+			g.metrics.FlowDecisions.With("tag", flowUntagged, "action", FlowPass).Add(1)
+		}
+
+		if delay > 0 {
+			g.metrics.FlowDelay.Observe(delay.Seconds())
+			timer := time.NewTimer(delay)
+			select {
+			case <-timer.C:
+			case <-r.Context().Done():
+				timer.Stop()
+				return
+			}
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// classify returns the sorted, unique tags of req within the classification timeout.
+func (g *FlowGuard) classify(ctx context.Context, req FlowRequest) ([]string, error) {
+	if g.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, g.timeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	tags, err := g.classifier.Classify(ctx, req)
+	g.metrics.FlowClassifyDuration.Observe(time.Since(start).Seconds())
+	if err != nil {
+		return nil, err
+	}
+
+	sort.Strings(tags)
+	unique := tags[:0]
+	for i, tag := range tags {
+		if i == 0 || tag != tags[i-1] {
+			unique = append(unique, tag)
+		}
+	}
+	return unique, nil
+}
@@ -0,0 +1,150 @@
+package api
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/hex"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestFlowGuard(t *testing.T) {
+	// The plugin tags transactions starting with "arb", "burst" or "strict", both of the
+	// last two for "mixed" ones, and stalls on "slow" ones
+	plugin := httptest.NewServer(h2c.NewHandler(connect.NewUnaryHandler(ClassifyProcedure,
+		func(ctx context.Context, req *connect.Request[structpb.Struct]) (*connect.Response[structpb.Struct], error) {
+			tx, _ := hex.DecodeString(req.Msg.GetFields()["tx"].GetStringValue())
+			tags := []any{}
+			switch {
+			case strings.HasPrefix(string(tx), "arb"):
+				tags = append(tags, "latency-arb", "latency-arb")
+			case strings.HasPrefix(string(tx), "burst"):
+				tags = append(tags, "burst", "unknown")
+			case strings.HasPrefix(string(tx), "strict"):
+				tags = append(tags, "strict")
+			case strings.HasPrefix(string(tx), "mixed"):
+				tags = append(tags, "strict", "burst")
+			case strings.HasPrefix(string(tx), "slow"):
+				<-ctx.Done()
+			}
+			msg, err := structpb.NewStruct(map[string]any{"tags": tags})
+			return connect.NewResponse(msg), err
+		},
+	), &http2.Server{}))
+	defer plugin.Close()
+
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	targetURL, _ := url.Parse(upstream.URL)
+	proxy, err := NewExecProxy(targetURL, []string{"/tx/"}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	httpClient := &http.Client{Transport: &http2.Transport{
+		AllowHTTP: true,
+		DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+			return net.Dial(network, addr)
+		},
+	}}
+	metrics, _ := NopMetrics()
+	policies := []FlowPolicy{
+		{Tag: "latency-arb", Delay: 100 * time.Millisecond, RateLimit: 0.001, Burst: 1},
+		{Tag: "burst", RateLimit: 0.001, Burst: 1},
+		{Tag: "strict", RateLimit: 0.001, Burst: 1},
+	}
+	var disabled atomic.Bool
+	enabled := func() bool { return !disabled.Load() }
+	guard, err := NewFlowGuard(NewGRPCClassifier(httpClient, plugin.URL), 200*time.Millisecond, policies, enabled, metrics, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server := NewServer("", zerolog.Nop())
+	server.Handle(ExecProxyPath, NewPolicy(nil, nil, metrics).Protect("exec", guard.Guard(proxy)))
+
+	submit := func(tx string) (int, time.Duration) {
+		req := httptest.NewRequest(http.MethodPost, "/exec/tx/submit", strings.NewReader(`{"tx": "0x`+hex.EncodeToString([]byte(tx))+`"}`))
+		start := time.Now()
+		return serveRequest(server, req).Code, time.Since(start)
+	}
+
+	// Untagged flow is forwarded right away
+	if code, took := submit("ok"); code != http.StatusOK || took >= 100*time.Millisecond {
+		t.Errorf("expected untagged flow forwarded right away, got %d after %v", code, took)
+	}
+
+	// Tagged flow is held by the speed bump, then limited by the rate of its tag
+	if code, took := submit("arb-1"); code != http.StatusOK || took < 100*time.Millisecond {
+		t.Errorf("expected tagged flow forwarded after the speed bump, got %d after %v", code, took)
+	}
+	if code, _ := submit("arb-2"); code != http.StatusTooManyRequests {
+		t.Errorf("expected tagged flow rate limited, got %d", code)
+	}
+
+	// A submission limited by one of its tags leaves the limits of the others untouched
+	if code, _ := submit("strict-1"); code != http.StatusOK {
+		t.Errorf("expected strict flow forwarded, got %d", code)
+	}
+	if code, _ := submit("mixed"); code != http.StatusTooManyRequests {
+		t.Errorf("expected mixed flow rate limited, got %d", code)
+	}
+	if code, _ := submit("burst-1"); code != http.StatusOK {
+		t.Errorf("expected burst flow forwarded, got %d", code)
+	}
+
+	// A stalled classifier does not block submissions
+	if code, _ := submit("slow"); code != http.StatusOK {
+		t.Errorf("expected unclassified flow forwarded, got %d", code)
+	}
+
+	// Disabled, the guard forwards tagged flow without its policies
+	disabled.Store(true)
+	if code, took := submit("arb-3"); code != http.StatusOK || took >= 100*time.Millisecond {
+		t.Errorf("expected flow forwarded right away by a disabled guard, got %d after %v", code, took)
+	}
+}
+
+func TestNewFlowGuard(t *testing.T) {
+	metrics, _ := NopMetrics()
+	for name, policies := range map[string][]FlowPolicy{
+		"no tag":        {{Delay: time.Second}},
+		"duplicate tag": {{Tag: "a"}, {Tag: "a"}},
+		"no burst":      {{Tag: "a", RateLimit: 1}},
+	} {
+		if _, err := NewFlowGuard(nil, 0, policies, nil, metrics, zerolog.Nop()); err == nil {
+			t.Errorf("%s: expected error", name)
+		}
+	}
+}
+
+func TestLoadFlowPolicies(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "policies.json")
+	if err := os.WriteFile(path, []byte(`[{"tag": "latency-arb", "delay": "250ms", "rate_limit": 2, "burst": 4}]`), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	policies, err := LoadFlowPolicies(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := FlowPolicy{Tag: "latency-arb", Delay: 250 * time.Millisecond, RateLimit: 2, Burst: 4}
+	if len(policies) != 1 || policies[0] != want {
+		t.Errorf("expected %+v, got %+v", want, policies)
+	}
+}
@@ -0,0 +1,148 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/store"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+// InclusionPath is the route serving DA inclusion information for a block height.
+const InclusionPath = "GET /v1/da/inclusion/{height}"
+
+// BlobInclusion describes where a block blob was published in the DA layer.
+type BlobInclusion struct {
+	DAHeight   uint64 `json:"da_height"`
+	Namespace  string `json:"namespace"`
+	ID         string `json:"id"`
+	Commitment string `json:"commitment"`
+	// Proof is the DA inclusion proof, omitted when the DA layer does not support proofs
+	Proof string `json:"proof,omitempty"`
+}
+
+// InclusionResponse is the DA inclusion information of a block.
+type InclusionResponse struct {
+	Height uint64         `json:"height"`
+	Header *BlobInclusion `json:"header"`
+	// Data is nil for blocks without transactions, whose data is never published to DA
+	Data *BlobInclusion `json:"data,omitempty"`
+}
+
+// InclusionHandler serves DA inclusion information so external verifiers can
+// independently confirm that a block was made available.
+type InclusionHandler struct {
+	store           store.Store
+	da              coreda.DA
+	headerNamespace []byte
+	dataNamespace   []byte
+	logger          zerolog.Logger
+}
+
+// NewInclusionHandler creates a new DA inclusion handler.
+//
+// Parameters:
+// - st: The local block store
+// - daLayer: The DA layer blobs are fetched from
+// - headerNamespace: The DA namespace headers are submitted to
+// - dataNamespace: The DA namespace block data is submitted to
+// - logger: Logger used to report DA errors
+//
+// Returns:
+// - *InclusionHandler: The initialized handler
+func NewInclusionHandler(st store.Store, daLayer coreda.DA, headerNamespace, dataNamespace []byte, logger zerolog.Logger) *InclusionHandler {
+	return &InclusionHandler{
+		store:           st,
+		da:              daLayer,
+		headerNamespace: headerNamespace,
+		dataNamespace:   dataNamespace,
+		logger:          logger,
+	}
+}
+
+// ServeHTTP handles GET /v1/da/inclusion/{height}.
+func (h *InclusionHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	height, err := strconv.ParseUint(r.PathValue("height"), 10, 64)
+	if err != nil || height == 0 {
+		writeError(w, CodeInvalidArgument, errors.New("height must be a positive integer"), map[string]any{"height": r.PathValue("height")})
+		return
+	}
+
+	ctx := r.Context()
+
+	header, data, err := h.store.GetBlockData(ctx, height)
+	if errors.Is(err, ds.ErrNotFound) {
+		writeError(w, CodeBlockNotFound, fmt.Errorf("block %d not found", height), map[string]any{"height": height})
+		return
+	} else if err != nil {
+		writeError(w, CodeInternal, err, nil)
+		return
+	}
+
+	headerDAHeight, dataDAHeight, err := seqda.BlockDAHeights(ctx, h.store, height)
+	if errors.Is(err, ds.ErrNotFound) {
+		writeError(w, CodeNotDAIncluded, fmt.Errorf("block %d is not yet included in DA", height), map[string]any{"height": height})
+		return
+	} else if err != nil {
+		writeError(w, CodeInternal, err, nil)
+		return
+	}
+
+	resp := InclusionResponse{Height: height}
+
+	headerRef, err := seqda.LocateHeaderBlob(ctx, h.da, h.headerNamespace, headerDAHeight, height, header.Hash())
+	if err != nil {
+		h.writeDAError(w, height, err)
+		return
+	}
+	resp.Header = h.describe(ctx, headerRef)
+
+	if len(data.Txs) > 0 {
+		dataRef, err := seqda.LocateDataBlob(ctx, h.da, h.dataNamespace, dataDAHeight, data.DACommitment())
+		if err != nil {
+			h.writeDAError(w, height, err)
+			return
+		}
+		resp.Data = h.describe(ctx, dataRef)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+// describe converts a blob reference to its API representation, fetching the inclusion proof when supported.
+func (h *InclusionHandler) describe(ctx context.Context, ref *seqda.BlobRef) *BlobInclusion {
+	inclusion := &BlobInclusion{
+		DAHeight:   ref.DAHeight,
+		Namespace:  hex.EncodeToString(ref.Namespace),
+		ID:         hex.EncodeToString(ref.ID),
+		Commitment: hex.EncodeToString(ref.Commitment),
+	}
+
+	proofs, err := h.da.GetProofs(ctx, []coreda.ID{ref.ID}, ref.Namespace)
+	if err != nil || len(proofs) == 0 {
+		h.logger.Debug().Err(err).Uint64("da_height", ref.DAHeight).Msg("DA inclusion proof unavailable")
+		return inclusion
+	}
+	inclusion.Proof = hex.EncodeToString(proofs[0])
+
+	return inclusion
+}
+
+// writeDAError reports a failure to locate a block's blobs in DA.
+func (h *InclusionHandler) writeDAError(w http.ResponseWriter, height uint64, err error) {
+	if errors.Is(err, seqda.ErrBlockBlobNotFound) {
+		h.logger.Error().Err(err).Uint64("height", height).Msg("Block blob missing from DA")
+		writeError(w, CodeBlobNotFound, fmt.Errorf("blob for block %d not found in DA: %w", height, err), map[string]any{"height": height})
+		return
+	}
+	writeError(w, CodeDAUnavailable, fmt.Errorf("failed to query DA: %w", err), map[string]any{"height": height})
+}
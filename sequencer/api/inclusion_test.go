@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+func TestInclusionHandler(t *testing.T) {
+	ctx := context.Background()
+	headerNamespace, dataNamespace := []byte("headers"), []byte("data")
+
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+	daLayer := seqda.NewMockDA(1024*1024, 0, 0, 10*time.Millisecond, seqda.MockConfig{})
+	daLayer.Start()
+	defer daLayer.Stop()
+
+	// Publish a block to DA and record it locally
+	header, data := types.GetRandomBlock(1, 2, "test-chain")
+	headerBlob, err := header.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dataBlob, err := (&types.SignedData{Data: *data}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headerIDs, err := daLayer.Submit(ctx, []coreda.Blob{headerBlob}, 0, headerNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dataIDs, err := daLayer.Submit(ctx, []coreda.Blob{dataBlob}, 0, dataNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	batch, _ := st.NewBatch(ctx)
+	if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for kind, id := range map[string]coreda.ID{"h": headerIDs[0], "d": dataIDs[0]} {
+		daHeight, _, _ := coreda.SplitID(id)
+		bz := make([]byte, 8)
+		binary.LittleEndian.PutUint64(bz, daHeight)
+		if err := st.SetMetadata(ctx, fmt.Sprintf("%s/1/%s", store.HeightToDAHeightKey, kind), bz); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	// Wait for the mock DA height to pass the submitted heights
+	time.Sleep(30 * time.Millisecond)
+
+	server := NewServer("", zerolog.Nop())
+	server.Handle(InclusionPath, NewInclusionHandler(st, daLayer, headerNamespace, dataNamespace, zerolog.Nop()))
+
+	tests := []struct {
+		name       string
+		path       string
+		wantStatus int
+	}{
+		{name: "success", path: "/v1/da/inclusion/1", wantStatus: http.StatusOK},
+		{name: "unknown block", path: "/v1/da/inclusion/2", wantStatus: http.StatusNotFound},
+		{name: "invalid height", path: "/v1/da/inclusion/abc", wantStatus: http.StatusBadRequest},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rec := httptest.NewRecorder()
+			server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, tt.path, nil))
+
+			if rec.Code != tt.wantStatus {
+				t.Fatalf("expected status %d, got %d: %s", tt.wantStatus, rec.Code, rec.Body.String())
+			}
+			if tt.wantStatus != http.StatusOK {
+				return
+			}
+
+			var resp InclusionResponse
+			if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if resp.Header == nil || resp.Header.ID != hex.EncodeToString(headerIDs[0]) {
+				t.Errorf("expected header ID %x, got %+v", headerIDs[0], resp.Header)
+			}
+			if resp.Data == nil || resp.Data.ID != hex.EncodeToString(dataIDs[0]) {
+				t.Errorf("expected data ID %x, got %+v", dataIDs[0], resp.Data)
+			}
+			if resp.Header.Proof == "" {
+				t.Errorf("expected header inclusion proof")
+			}
+		})
+	}
+}
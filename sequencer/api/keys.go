@@ -0,0 +1,112 @@
+package api
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// APIKey is an API key as stored in the keys file.
+type APIKey struct {
+	// Name identifies the key owner in limits and logs; it is never secret
+	Name  string `json:"name"`
+	Token string `json:"token"`
+}
+
+// APIKeys authenticates requests by bearer token. Only token hashes are kept.
+type APIKeys struct {
+	names map[[sha256.Size]byte]string
+}
+
+// NewAPIKeys creates the key set for keys. Names and tokens must be unique and non-empty.
+func NewAPIKeys(keys []APIKey) (*APIKeys, error) {
+	set := &APIKeys{names: make(map[[sha256.Size]byte]string, len(keys))}
+	names := make(map[string]bool, len(keys))
+	for _, key := range keys {
+		if key.Name == "" || key.Token == "" {
+			return nil, errors.New("API keys need a name and a token")
+		}
+		hash := sha256.Sum256([]byte(key.Token))
+		if names[key.Name] || set.names[hash] != "" {
+			return nil, fmt.Errorf("duplicate API key %s", key.Name)
+		}
+		names[key.Name] = true
+		set.names[hash] = key.Name
+	}
+	return set, nil
+}
+
+// LoadAPIKeys reads the JSON list of API keys at path.
+func LoadAPIKeys(path string) (*APIKeys, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read API keys: %w", err)
+	}
+
+	var keys []APIKey
+	if err := json.Unmarshal(bz, &keys); err != nil {
+		return nil, fmt.Errorf("failed to decode API keys: %w", err)
+	}
+	return NewAPIKeys(keys)
+}
+
+// Lookup returns the name of the key with token, or "" if there is none.
+func (k *APIKeys) Lookup(token string) string {
+	return k.names[sha256.Sum256([]byte(token))]
+}
+
+// Client identifies the caller of a request for limits.
+type Client struct {
+	// Key is the name of the API key the request was authenticated with, if any
+	Key string
+	// IP is the address the request came from
+	IP string
+}
+
+// ID returns the identity limits are counted against: the key if there is one,
+// the IP address otherwise.
+func (c Client) ID() string {
+	if c.Key != "" {
+		return "key:" + c.Key
+	}
+	return "ip:" + c.IP
+}
+
+// clientKey is the context key of the client of a protected request
+type clientKey struct{}
+
+// withClient returns ctx carrying the client of its request.
+func withClient(ctx context.Context, client Client) context.Context {
+	return context.WithValue(ctx, clientKey{}, client)
+}
+
+// clientFrom returns the client of a request protected by a Policy.
+func clientFrom(ctx context.Context) (Client, bool) {
+	client, ok := ctx.Value(clientKey{}).(Client)
+	return client, ok
+}
+
+// bearerToken returns the bearer token of r, or "" if it has none.
+func bearerToken(r *http.Request) string {
+	scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return ""
+	}
+	return strings.TrimSpace(token)
+}
+
+// remoteIP returns the IP address of the peer of r. Forwarding headers are not
+// trusted, so behind a proxy every request shares the proxy address.
+func remoteIP(r *http.Request) string {
+	host, _, err := net.SplitHostPort(r.RemoteAddr)
+	if err != nil {
+		return r.RemoteAddr
+	}
+	return host
+}
@@ -0,0 +1,243 @@
+package api
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// LatencySelfPath is the route reporting the inclusion latency of the caller's transactions.
+const LatencySelfPath = "GET /v1/latency/self"
+
+// submitTxPath is the proxied route transactions are submitted to
+const submitTxPath = "/exec/tx/submit"
+
+// maxSubmitBody is the largest submission body read to identify its transaction
+const maxSubmitBody = 1 << 20
+
+// latencyPendingTTL is how long a submitted transaction is waited for before it is
+// dropped as never included
+const latencyPendingTTL = 10 * time.Minute
+
+// latencyBuckets are the upper bounds, in seconds, of the inclusion latency histograms
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60}
+
+// LatencyTracker measures the time from the submission of each transaction through the
+// proxy to the execution of the block including it, in a histogram per client.
+type LatencyTracker struct {
+	mu        sync.Mutex
+	pending   map[[sha256.Size]byte]submission
+	clients   map[string]*latencyHistogram
+	lastSweep time.Time
+}
+
+// submission is a transaction waiting for inclusion
+type submission struct {
+	client string
+	at     time.Time
+}
+
+// latencyHistogram is the inclusion latency histogram of one client
+type latencyHistogram struct {
+	since time.Time
+	// counts holds the count of each bucket, the last one for latencies above every bound
+	counts []uint64
+	count  uint64
+	sum    float64
+}
+
+// NewLatencyTracker creates an inclusion latency tracker. Wrap the proxy with Track and
+// pass executed blocks to Included.
+func NewLatencyTracker() *LatencyTracker {
+	return &LatencyTracker{
+		pending: make(map[[sha256.Size]byte]submission),
+		clients: make(map[string]*latencyHistogram),
+	}
+}
+
+// Track returns next recording the transactions accepted by submissions through it.
+// It must be wrapped by Policy.Protect, which identifies the client.
+func (t *LatencyTracker) Track(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, ok := clientFrom(r.Context())
+		if !ok || r.Method != http.MethodPost || r.URL.Path != submitTxPath {
+			next.ServeHTTP(w, r)
+			return
+		}
+
+		body, err := io.ReadAll(io.LimitReader(r.Body, maxSubmitBody))
+		if err != nil {
+			writeError(w, CodeInvalidArgument, err, nil)
+			return
+		}
+		r.Body = io.NopCloser(io.MultiReader(bytes.NewReader(body), r.Body))
+
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+
+		// Rejected submissions are never included
+		if rec.status < 200 || rec.status > 299 {
+			return
+		}
+		if tx, ok := submittedTx(body); ok {
+			t.Submitted(client.ID(), tx, start)
+		}
+	})
+}
+
+// submittedTx returns the transaction of a submission body, {"tx": "<hex>"}.
+func submittedTx(body []byte) ([]byte, bool) {
+	var req struct {
+		Tx string `json:"tx"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		return nil, false
+	}
+	tx, err := hex.DecodeString(strings.TrimPrefix(req.Tx, "0x"))
+	if err != nil || len(tx) == 0 {
+		return nil, false
+	}
+	return tx, true
+}
+
+// Submitted records that the client with id submitted tx at.
+func (t *LatencyTracker) Submitted(id string, tx []byte, at time.Time) {
+	hash := sha256.Sum256(tx)
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	t.sweep(at)
+	if _, ok := t.pending[hash]; !ok {
+		t.pending[hash] = submission{client: id, at: at}
+	}
+}
+
+// Included records the latency of the submitted transactions among txs, executed in a
+// block at.
+func (t *LatencyTracker) Included(txs [][]byte, at time.Time) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if len(t.pending) == 0 {
+		return
+	}
+	for _, tx := range txs {
+		hash := sha256.Sum256(tx)
+		sub, ok := t.pending[hash]
+		if !ok {
+			continue
+		}
+		delete(t.pending, hash)
+
+		histogram := t.clients[sub.client]
+		if histogram == nil {
+			histogram = &latencyHistogram{since: at, counts: make([]uint64, len(latencyBuckets)+1)}
+			t.clients[sub.client] = histogram
+		}
+		histogram.observe(at.Sub(sub.at).Seconds())
+	}
+}
+
+// sweep drops the transactions submitted longer than latencyPendingTTL before now, at
+// most once per TTL.
+func (t *LatencyTracker) sweep(now time.Time) {
+	if now.Sub(t.lastSweep) < latencyPendingTTL {
+		return
+	}
+	t.lastSweep = now
+	for hash, sub := range t.pending {
+		if now.Sub(sub.at) > latencyPendingTTL {
+			delete(t.pending, hash)
+		}
+	}
+}
+
+// observe records a latency of seconds.
+func (h *latencyHistogram) observe(seconds float64) {
+	i := 0
+	for i < len(latencyBuckets) && seconds > latencyBuckets[i] {
+		i++
+	}
+	h.counts[i]++
+	h.count++
+	h.sum += seconds
+}
+
+// LatencyResponse is the inclusion latency histogram of the caller of LatencySelfPath.
+type LatencyResponse struct {
+	// Client is the identity latencies are counted for, e.g. "key:mm-1"
+	Client string `json:"client"`
+	// Since is when the first latency was recorded, omitted when none was
+	Since *time.Time `json:"since,omitempty"`
+	// Count is the number of included transactions measured
+	Count      uint64  `json:"count"`
+	SumSeconds float64 `json:"sum_seconds"`
+	// Buckets are cumulative: each counts the transactions included within its bound
+	Buckets []LatencyBucket `json:"buckets"`
+	// Pending is the number of submitted transactions not included yet
+	Pending int `json:"pending"`
+}
+
+// LatencyBucket is a bucket of a latency histogram.
+type LatencyBucket struct {
+	// LE is the upper bound in seconds, "+Inf" for the last bucket
+	LE    string `json:"le"`
+	Count uint64 `json:"count"`
+}
+
+// report returns the latency histogram of the client with id.
+func (t *LatencyTracker) report(id string) LatencyResponse {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	resp := LatencyResponse{Client: id, Buckets: make([]LatencyBucket, 0, len(latencyBuckets)+1)}
+	for _, sub := range t.pending {
+		if sub.client == id {
+			resp.Pending++
+		}
+	}
+
+	var counts []uint64
+	if histogram := t.clients[id]; histogram != nil {
+		since := histogram.since
+		resp.Since = &since
+		resp.Count = histogram.count
+		resp.SumSeconds = histogram.sum
+		counts = histogram.counts
+	}
+
+	var cumulative uint64
+	for i := range len(latencyBuckets) + 1 {
+		le := "+Inf"
+		if i < len(latencyBuckets) {
+			le = strconv.FormatFloat(latencyBuckets[i], 'g', -1, 64)
+		}
+		if counts != nil {
+			cumulative += counts[i]
+		}
+		resp.Buckets = append(resp.Buckets, LatencyBucket{LE: le, Count: cumulative})
+	}
+	return resp
+}
+
+// NewLatencyHandler creates a handler reporting the inclusion latency histogram of the
+// caller's transactions measured by tracker. The caller authenticates like for
+// protected routes, so each API key only sees its own transactions.
+func NewLatencyHandler(policy *Policy, tracker *LatencyTracker) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, ok := policy.authenticate(w, r, "latency")
+		if !ok {
+			return
+		}
+		writeJSON(w, http.StatusOK, tracker.report(client.ID()))
+	})
+}
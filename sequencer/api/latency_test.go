@@ -0,0 +1,110 @@
+package api
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLatencyTracker(t *testing.T) {
+	// The execution layer accepts transactions starting with "ok" only
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if !strings.Contains(string(body), hex.EncodeToString([]byte("ok"))) {
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer upstream.Close()
+
+	targetURL, _ := url.Parse(upstream.URL)
+	proxy, err := NewExecProxy(targetURL, []string{"/tx/"}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, err := NewAPIKeys([]APIKey{{Name: "mm-1", Token: "token-1"}, {Name: "mm-2", Token: "token-2"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metrics, _ := NopMetrics()
+	policy := NewPolicy(keys, nil, metrics)
+	tracker := NewLatencyTracker()
+	server := NewServer("", zerolog.Nop())
+	server.Handle(ExecProxyPath, policy.Protect("exec", tracker.Track(proxy)))
+	server.Handle(LatencySelfPath, NewLatencyHandler(policy, tracker))
+
+	submit := func(token string, tx []byte) int {
+		req := httptest.NewRequest(http.MethodPost, "/exec/tx/submit", strings.NewReader(`{"tx": "0x`+hex.EncodeToString(tx)+`"}`))
+		req.Header.Set("Authorization", "Bearer "+token)
+		return serveRequest(server, req).Code
+	}
+	report := func(token string) LatencyResponse {
+		req := httptest.NewRequest(http.MethodGet, "/v1/latency/self", nil)
+		req.Header.Set("Authorization", "Bearer "+token)
+		rec := serveRequest(server, req)
+		if rec.Code != http.StatusOK {
+			t.Fatalf("expected 200, got %d", rec.Code)
+		}
+		var resp LatencyResponse
+		if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		return resp
+	}
+
+	// The body still reaches the execution layer, and rejected submissions are not tracked
+	if code := submit("token-1", []byte("ok-1")); code != http.StatusOK {
+		t.Fatalf("expected submission accepted, got %d", code)
+	}
+	if code := submit("token-1", []byte("bad")); code != http.StatusBadRequest {
+		t.Fatalf("expected submission rejected, got %d", code)
+	}
+	if code := submit("token-2", []byte("ok-2")); code != http.StatusOK {
+		t.Fatalf("expected submission accepted, got %d", code)
+	}
+	if resp := report("token-1"); resp.Pending != 1 || resp.Count != 0 || resp.Since != nil {
+		t.Fatalf("expected 1 pending transaction, got %+v", resp)
+	}
+
+	// Inclusion records the latency for the submitting key only
+	tracker.Included([][]byte{[]byte("ok-1"), []byte("other")}, time.Now().Add(300*time.Millisecond))
+	resp := report("token-1")
+	if resp.Client != "key:mm-1" || resp.Pending != 0 || resp.Count != 1 {
+		t.Fatalf("expected 1 included transaction, got %+v", resp)
+	}
+	if resp.SumSeconds < 0.3 {
+		t.Errorf("expected latency of at least 0.3s, got %f", resp.SumSeconds)
+	}
+
+	// Buckets are cumulative
+	for _, bucket := range resp.Buckets {
+		want := uint64(1)
+		if bucket.LE == "0.05" || bucket.LE == "0.1" || bucket.LE == "0.25" {
+			want = 0
+		}
+		if bucket.Count != want {
+			t.Errorf("bucket %s: expected %d, got %d", bucket.LE, want, bucket.Count)
+		}
+	}
+	if last := resp.Buckets[len(resp.Buckets)-1]; last.LE != "+Inf" {
+		t.Errorf("expected last bucket +Inf, got %s", last.LE)
+	}
+
+	if resp := report("token-2"); resp.Pending != 1 || resp.Count != 0 {
+		t.Errorf("expected the other key unaffected, got %+v", resp)
+	}
+
+	// The report requires a key like the proxied routes
+	rec := serveRequest(server, httptest.NewRequest(http.MethodGet, "/v1/latency/self", nil))
+	if rec.Code != http.StatusUnauthorized {
+		t.Errorf("expected 401, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,72 @@
+package api
+
+import (
+	"net/http"
+	"time"
+)
+
+// LimitsSelfPath is the route reporting the rate limit usage of the caller.
+const LimitsSelfPath = "GET /v1/limits/self"
+
+// LimitsResponse is the rate limit state of the caller of LimitsSelfPath.
+type LimitsResponse struct {
+	// Client is the identity limits are counted against, e.g. "key:mm-1" or "ip:10.0.0.1"
+	Client string `json:"client"`
+	// Key is the name of the API key the request was authenticated with, if any
+	Key string `json:"key,omitempty"`
+	IP  string `json:"ip"`
+	// RateLimited reports whether requests of the client are rate limited at all
+	RateLimited bool `json:"rate_limited"`
+	// RequestsPerSecond is the sustained request rate allowed, 0 when not rate limited
+	RequestsPerSecond float64 `json:"requests_per_second"`
+	Burst             int     `json:"burst"`
+	// Remaining is the number of requests that may be sent now
+	Remaining int `json:"remaining"`
+	// ResetSeconds is how long until the full burst is available again
+	ResetSeconds float64 `json:"reset_seconds"`
+	// Usage counts the requests of the client to protected routes
+	Usage LimitsUsage `json:"usage"`
+}
+
+// LimitsUsage counts the requests of a client. Counting restarts after the client has
+// been idle for 10 minutes.
+type LimitsUsage struct {
+	Since       time.Time `json:"since"`
+	Requests    uint64    `json:"requests"`
+	RateLimited uint64    `json:"rate_limited"`
+}
+
+// NewLimitsHandler creates a handler reporting the usage of the caller under policy.
+// The caller authenticates like for protected routes; the request itself is not
+// counted or limited, so clients can poll it to pace themselves.
+func NewLimitsHandler(policy *Policy) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		client, ok := policy.authenticate(w, r, "limits")
+		if !ok {
+			return
+		}
+
+		resp := LimitsResponse{
+			Client: client.ID(),
+			Key:    client.Key,
+			IP:     client.IP,
+		}
+
+		if policy.limiter != nil {
+			usage := policy.limiter.Peek(client.ID())
+			setRateLimitHeaders(w.Header(), usage)
+			resp.RateLimited = usage.Limited
+			resp.RequestsPerSecond = usage.PerSecond
+			resp.Burst = usage.Burst
+			resp.Remaining = usage.Remaining
+			resp.ResetSeconds = usage.Reset.Seconds()
+			resp.Usage = LimitsUsage{
+				Since:       usage.Since,
+				Requests:    usage.Requests,
+				RateLimited: usage.RateLimited,
+			}
+		}
+
+		writeJSON(w, http.StatusOK, resp)
+	})
+}
@@ -0,0 +1,71 @@
+package api
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"sync"
+)
+
+// LogsPath is the route serving the most recent log lines.
+const LogsPath = "GET /v1/logs"
+
+// LogBuffer is an io.Writer keeping the most recent JSON log lines in memory.
+// It is meant to be added to the node logger's outputs so recent logs can be
+// served without access to the log files.
+type LogBuffer struct {
+	mu    sync.Mutex
+	lines []json.RawMessage
+	next  int
+	full  bool
+}
+
+// NewLogBuffer creates a log buffer holding up to size lines.
+func NewLogBuffer(size int) *LogBuffer {
+	return &LogBuffer{
+		lines: make([]json.RawMessage, size),
+	}
+}
+
+// Write stores a JSON log line, evicting the oldest one when the buffer is full.
+// Writes that are not valid JSON are ignored.
+func (b *LogBuffer) Write(p []byte) (int, error) {
+	line := bytes.TrimSpace(p)
+	if len(b.lines) == 0 || !json.Valid(line) {
+		return len(p), nil
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.lines[b.next] = append(json.RawMessage(nil), line...)
+	b.next = (b.next + 1) % len(b.lines)
+	if b.next == 0 {
+		b.full = true
+	}
+
+	return len(p), nil
+}
+
+// Lines returns the buffered log lines, oldest first.
+func (b *LogBuffer) Lines() []json.RawMessage {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if !b.full {
+		return append([]json.RawMessage{}, b.lines[:b.next]...)
+	}
+	return append(append([]json.RawMessage{}, b.lines[b.next:]...), b.lines[:b.next]...)
+}
+
+// LogsResponse holds the most recent log lines.
+type LogsResponse struct {
+	Logs []json.RawMessage `json:"logs"`
+}
+
+// NewLogsHandler creates a handler serving the lines held by logs.
+func NewLogsHandler(logs *LogBuffer) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, LogsResponse{Logs: logs.Lines()})
+	})
+}
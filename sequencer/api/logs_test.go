@@ -0,0 +1,25 @@
+package api
+
+import (
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLogBuffer(t *testing.T) {
+	logs := NewLogBuffer(2)
+	logger := zerolog.New(logs)
+
+	logger.Info().Msg("first")
+	logger.Info().Msg("second")
+	logger.Info().Msg("third")
+	_, _ = logs.Write([]byte("not json\n"))
+
+	lines := logs.Lines()
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d", len(lines))
+	}
+	if string(lines[0]) != `{"level":"info","message":"second"}` || string(lines[1]) != `{"level":"info","message":"third"}` {
+		t.Errorf("expected the two most recent lines, got %s and %s", lines[0], lines[1])
+	}
+}
@@ -0,0 +1,114 @@
+package api
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "api"
+)
+
+// MetricsProvider returns API Metrics.
+type MetricsProvider func(chainID string) (*Metrics, error)
+
+// DefaultMetricsProvider returns Metrics build using Prometheus client library
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+func DefaultMetricsProvider(enabled bool) MetricsProvider {
+	return func(chainID string) (*Metrics, error) {
+		if enabled {
+			return PrometheusMetrics("chain_id", chainID)
+		}
+		return NopMetrics()
+	}
+}
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of requests served by protected routes, labelled by route and status code
+	Requests metrics.Counter
+	// Time spent serving requests of protected routes, labelled by route
+	RequestDuration metrics.Histogram
+	// Number of requests rejected for a missing or unknown API key, labelled by route
+	Unauthenticated metrics.Counter
+	// Number of requests rejected by the rate limiter, labelled by route
+	RateLimited metrics.Counter
+	// Number of flow classifier decisions on submissions, labelled by tag and action
+	FlowDecisions metrics.Counter
+	// Speed bumps submissions were held for, in seconds
+	FlowDelay metrics.Histogram
+	// Time spent classifying submissions
+	FlowClassifyDuration metrics.Histogram
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(labelsAndValues ...string) (*Metrics, error) {
+	if len(labelsAndValues)%2 != 0 {
+		return nil, errors.New("uneven number of labels and values; labels and values should be provided in pairs")
+	}
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Requests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "requests",
+			Help:      "Number of requests served by protected API routes.",
+		}, append(labels, "route", "code")).With(labelsAndValues...),
+		RequestDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "request_duration_seconds",
+			Help:      "Time spent serving requests of protected API routes.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, append(labels, "route")).With(labelsAndValues...),
+		Unauthenticated: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "unauthenticated",
+			Help:      "Number of requests rejected for a missing or unknown API key.",
+		}, append(labels, "route")).With(labelsAndValues...),
+		RateLimited: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "rate_limited",
+			Help:      "Number of requests rejected by the rate limiter.",
+		}, append(labels, "route")).With(labelsAndValues...),
+		FlowDecisions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "flow_decisions",
+			Help:      "Number of flow classifier decisions on submissions.",
+		}, append(labels, "tag", "action")).With(labelsAndValues...),
+		FlowDelay: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "flow_delay_seconds",
+			Help:      "Speed bumps submissions were held for.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 10),
+		}, labels).With(labelsAndValues...),
+		FlowClassifyDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "flow_classify_duration_seconds",
+			Help:      "Time spent classifying submissions.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0005, 2, 12),
+		}, labels).With(labelsAndValues...),
+	}, nil
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() (*Metrics, error) {
+	return &Metrics{
+		Requests:             discard.NewCounter(),
+		RequestDuration:      discard.NewHistogram(),
+		Unauthenticated:      discard.NewCounter(),
+		RateLimited:          discard.NewCounter(),
+		FlowDecisions:        discard.NewCounter(),
+		FlowDelay:            discard.NewHistogram(),
+		FlowClassifyDuration: discard.NewHistogram(),
+	}, nil
+}
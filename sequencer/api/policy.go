@@ -0,0 +1,125 @@
+package api
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// Policy authenticates, rate limits and measures the requests of protected routes.
+type Policy struct {
+	keys    *APIKeys
+	limiter *RateLimiter
+	metrics *Metrics
+}
+
+// NewPolicy creates a new request policy.
+//
+// Parameters:
+// - keys: API keys requests must present as bearer tokens (nil allows anonymous requests)
+// - limiter: Per client rate limiter and usage counter (nil disables both)
+// - metrics: API metrics
+//
+// Returns:
+// - *Policy: The initialized policy; wrap handlers with Protect
+func NewPolicy(keys *APIKeys, limiter *RateLimiter, metrics *Metrics) *Policy {
+	return &Policy{
+		keys:    keys,
+		limiter: limiter,
+		metrics: metrics,
+	}
+}
+
+// Protect returns next applying the policy to its requests. route labels the metrics
+// of the requests.
+func (p *Policy) Protect(route string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		defer func() {
+			p.metrics.Requests.With("route", route, "code", strconv.Itoa(rec.status)).Add(1)
+			p.metrics.RequestDuration.With("route", route).Observe(time.Since(start).Seconds())
+		}()
+
+		client, ok := p.authenticate(rec, r, route)
+		if !ok {
+			return
+		}
+
+		if p.limiter != nil {
+			usage := p.limiter.Take(client.ID())
+			setRateLimitHeaders(rec.Header(), usage)
+			if !usage.Allowed {
+				p.metrics.RateLimited.With("route", route).Add(1)
+				rec.Header().Set("Retry-After", strconv.Itoa(ceilSeconds(usage.RetryAfter)))
+				writeError(rec, CodeRateLimited, errors.New("rate limit exceeded"), map[string]any{"retry_after_seconds": usage.RetryAfter.Seconds()})
+				return
+			}
+		}
+
+		next.ServeHTTP(rec, r.WithContext(withClient(r.Context(), client)))
+	})
+}
+
+// authenticate returns the client of r, replying with CodeUnauthenticated when API
+// keys are required and r has no valid one.
+func (p *Policy) authenticate(w http.ResponseWriter, r *http.Request, route string) (Client, bool) {
+	client := Client{IP: remoteIP(r)}
+	if p.keys == nil {
+		return client, true
+	}
+
+	client.Key = p.keys.Lookup(bearerToken(r))
+	if client.Key == "" {
+		p.metrics.Unauthenticated.With("route", route).Add(1)
+		w.Header().Set("WWW-Authenticate", "Bearer")
+		writeError(w, CodeUnauthenticated, errors.New("a valid API key is required"), nil)
+		return Client{}, false
+	}
+	return client, true
+}
+
+// setRateLimitHeaders sets the RateLimit-* headers of the IETF rate limit fields
+// draft from usage, so clients can pace themselves before they are limited.
+func setRateLimitHeaders(header http.Header, usage Usage) {
+	if !usage.Limited {
+		return
+	}
+	header.Set("RateLimit-Limit", strconv.Itoa(usage.Burst))
+	header.Set("RateLimit-Remaining", strconv.Itoa(usage.Remaining))
+	header.Set("RateLimit-Reset", strconv.Itoa(ceilSeconds(usage.Reset)))
+}
+
+// ceilSeconds returns d in whole seconds, rounded up.
+func ceilSeconds(d time.Duration) int {
+	return int((d + time.Second - 1) / time.Second)
+}
+
+// statusRecorder records the status code written to a response.
+type statusRecorder struct {
+	http.ResponseWriter
+	status      int
+	wroteHeader bool
+}
+
+// WriteHeader records status and writes it.
+func (r *statusRecorder) WriteHeader(status int) {
+	if !r.wroteHeader {
+		r.status = status
+		r.wroteHeader = true
+	}
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Flush flushes the underlying response when it supports flushing.
+func (r *statusRecorder) Flush() {
+	if flusher, ok := r.ResponseWriter.(http.Flusher); ok {
+		flusher.Flush()
+	}
+}
+
+// Unwrap returns the underlying response for http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
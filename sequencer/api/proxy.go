@@ -0,0 +1,57 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// ExecProxyPath is the route prefix proxied to the execution layer REST API.
+const ExecProxyPath = "/exec/"
+
+// NewExecProxy creates a handler forwarding requests below ExecProxyPath to the
+// execution layer REST API at target, with the prefix removed. Only paths starting
+// with one of routes, e.g. "/tx/", are forwarded; others are rejected with
+// CodeRouteNotFound.
+func NewExecProxy(target *url.URL, routes []string, logger zerolog.Logger) (http.Handler, error) {
+	if target.Scheme != "http" && target.Scheme != "https" {
+		return nil, fmt.Errorf("execution REST URL must be http(s), got %q", target.String())
+	}
+	if len(routes) == 0 {
+		return nil, errors.New("at least one execution route must be proxied")
+	}
+	for _, route := range routes {
+		if !strings.HasPrefix(route, "/") {
+			return nil, fmt.Errorf("execution route %q must start with /", route)
+		}
+	}
+
+	logger = logger.With().Str("component", "exec-proxy").Logger()
+	proxy := &httputil.ReverseProxy{
+		Rewrite: func(pr *httputil.ProxyRequest) {
+			pr.SetURL(target)
+			pr.SetXForwarded()
+			// API keys are for this server only
+			pr.Out.Header.Del("Authorization")
+		},
+		ErrorHandler: func(w http.ResponseWriter, r *http.Request, err error) {
+			logger.Warn().Err(err).Str("path", r.URL.Path).Msg("Execution layer request failed")
+			writeError(w, CodeExecutionUnavailable, errors.New("execution layer is unavailable"), nil)
+		},
+	}
+
+	return http.StripPrefix(strings.TrimSuffix(ExecProxyPath, "/"), http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for _, route := range routes {
+			if strings.HasPrefix(r.URL.Path, route) {
+				proxy.ServeHTTP(w, r)
+				return
+			}
+		}
+		writeError(w, CodeRouteNotFound, fmt.Errorf("route %s is not exposed", r.URL.Path), map[string]any{"path": r.URL.Path})
+	})), nil
+}
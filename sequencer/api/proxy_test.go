@@ -0,0 +1,188 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestProxyServer(t *testing.T, target string, keys *APIKeys, limiter *RateLimiter) *Server {
+	t.Helper()
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	proxy, err := NewExecProxy(targetURL, []string{"/tx/", "/account/"}, zerolog.Nop())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metrics, _ := NopMetrics()
+	server := NewServer("", zerolog.Nop())
+	server.Handle(ExecProxyPath, NewPolicy(keys, limiter, metrics).Protect("exec", proxy))
+	return server
+}
+
+func serveRequest(server *Server, req *http.Request) *httptest.ResponseRecorder {
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, req)
+	return rec
+}
+
+func decodeErrorCode(t *testing.T, rec *httptest.ResponseRecorder) ErrorCode {
+	t.Helper()
+	var resp ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return resp.Error.Code
+}
+
+func TestExecProxy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			t.Error("expected the API key not to be forwarded")
+		}
+		w.WriteHeader(http.StatusAccepted)
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer upstream.Close()
+
+	server := newTestProxyServer(t, upstream.URL, nil, nil)
+
+	rec := serveRequest(server, httptest.NewRequest(http.MethodPost, "/exec/tx/submit", nil))
+	if rec.Code != http.StatusAccepted || rec.Body.String() != "/tx/submit" {
+		t.Errorf("expected request proxied to /tx/submit, got %d %q", rec.Code, rec.Body.String())
+	}
+
+	rec = serveRequest(server, httptest.NewRequest(http.MethodGet, "/exec/metrics", nil))
+	if rec.Code != http.StatusNotFound || decodeErrorCode(t, rec) != CodeRouteNotFound {
+		t.Errorf("expected unexposed route to be rejected, got %d", rec.Code)
+	}
+
+	upstream.Close()
+	rec = serveRequest(server, httptest.NewRequest(http.MethodPost, "/exec/tx/submit", nil))
+	if rec.Code != http.StatusBadGateway || decodeErrorCode(t, rec) != CodeExecutionUnavailable {
+		t.Errorf("expected unavailable execution layer, got %d", rec.Code)
+	}
+}
+
+func TestPolicy(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer upstream.Close()
+
+	path := filepath.Join(t.TempDir(), "keys.json")
+	if err := os.WriteFile(path, []byte(`[{"name":"mm-1","token":"secret-1"},{"name":"mm-2","token":"secret-2"}]`), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	keys, err := LoadAPIKeys(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	server := newTestProxyServer(t, upstream.URL, keys, NewRateLimiter(1, 2))
+	request := func(token string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPost, "/exec/account/balance", nil)
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		return serveRequest(server, req)
+	}
+
+	for _, token := range []string{"", "unknown"} {
+		rec := request(token)
+		if rec.Code != http.StatusUnauthorized || decodeErrorCode(t, rec) != CodeUnauthenticated {
+			t.Errorf("token %q: expected unauthenticated, got %d", token, rec.Code)
+		}
+	}
+
+	// Each key has its own bucket of 2 requests
+	for i := range 2 {
+		rec := request("secret-1")
+		if rec.Code != http.StatusOK {
+			t.Fatalf("request %d: expected status %d, got %d", i, http.StatusOK, rec.Code)
+		}
+		if remaining := rec.Header().Get("RateLimit-Remaining"); remaining != strconv.Itoa(1-i) {
+			t.Errorf("request %d: expected %d remaining, got %q", i, 1-i, remaining)
+		}
+	}
+	rec := request("secret-1")
+	if rec.Code != http.StatusTooManyRequests || decodeErrorCode(t, rec) != CodeRateLimited {
+		t.Errorf("expected rate limited, got %d", rec.Code)
+	}
+	if rec.Header().Get("Retry-After") != "1" {
+		t.Errorf("expected Retry-After of 1s, got %q", rec.Header().Get("Retry-After"))
+	}
+	if rec := request("secret-2"); rec.Code != http.StatusOK {
+		t.Errorf("expected another key not to be limited, got %d", rec.Code)
+	}
+}
+
+func TestLimitsSelf(t *testing.T) {
+	keys, err := NewAPIKeys([]APIKey{{Name: "mm-1", Token: "secret-1"}})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	metrics, _ := NopMetrics()
+	policy := NewPolicy(keys, NewRateLimiter(1, 5), metrics)
+	server := NewServer("", zerolog.Nop())
+	server.Handle(ExecProxyPath, policy.Protect("exec", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {})))
+	server.Handle(LimitsSelfPath, NewLimitsHandler(policy))
+
+	request := func(method, path string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, nil)
+		req.Header.Set("Authorization", "Bearer secret-1")
+		return serveRequest(server, req)
+	}
+
+	for range 2 {
+		request(http.MethodPost, "/exec/tx/submit")
+	}
+
+	rec := request(http.MethodGet, "/v1/limits/self")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d", http.StatusOK, rec.Code)
+	}
+
+	var resp LimitsResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Client != "key:mm-1" || !resp.RateLimited || resp.Burst != 5 {
+		t.Errorf("unexpected limits: %+v", resp)
+	}
+	if resp.Remaining != 3 || resp.Usage.Requests != 2 {
+		t.Errorf("expected 2 requests counted and 3 remaining, got %+v", resp)
+	}
+
+	// Polling the limits does not consume the quota
+	if rec := request(http.MethodGet, "/v1/limits/self"); rec.Header().Get("RateLimit-Remaining") != "3" {
+		t.Errorf("expected limits requests not to be counted, got %q remaining", rec.Header().Get("RateLimit-Remaining"))
+	}
+
+	unauthenticated := serveRequest(server, httptest.NewRequest(http.MethodGet, "/v1/limits/self", nil))
+	if unauthenticated.Code != http.StatusUnauthorized {
+		t.Errorf("expected unauthenticated, got %d", unauthenticated.Code)
+	}
+}
+
+func TestNewAPIKeys(t *testing.T) {
+	tests := [][]APIKey{
+		{{Name: "a", Token: ""}},
+		{{Name: "a", Token: "x"}, {Name: "a", Token: "y"}},
+		{{Name: "a", Token: "x"}, {Name: "b", Token: "x"}},
+	}
+	for i, keys := range tests {
+		if _, err := NewAPIKeys(keys); err == nil {
+			t.Errorf("case %d: expected an error", i)
+		}
+	}
+}
@@ -0,0 +1,173 @@
+package api
+
+import (
+	"math"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// rateLimiterIdle is how long a client's bucket is kept after its last request
+const rateLimiterIdle = 10 * time.Minute
+
+// RateLimiter limits the request rate of each client with a token bucket and counts
+// the requests of each client.
+type RateLimiter struct {
+	limit rate.Limit
+	burst int
+
+	mu        sync.Mutex
+	clients   map[string]*clientBucket
+	lastSweep time.Time
+}
+
+// clientBucket is the token bucket and usage of one client
+type clientBucket struct {
+	limiter   *rate.Limiter
+	firstSeen time.Time
+	lastSeen  time.Time
+	requests  uint64
+	limited   uint64
+}
+
+// Usage is the rate limit state of a client.
+type Usage struct {
+	// Allowed reports whether the request the usage was taken for may proceed
+	Allowed bool
+	// Limited reports whether requests are rate limited at all
+	Limited bool
+	// PerSecond is the sustained request rate allowed, 0 when requests are not limited
+	PerSecond float64
+	// Burst is the number of requests allowed at once
+	Burst int
+	// Remaining is the number of requests that may be sent now
+	Remaining int
+	// Reset is how long until the bucket is full again
+	Reset time.Duration
+	// RetryAfter is how long until the next request is allowed, 0 when one is allowed now
+	RetryAfter time.Duration
+	// Since is when counting started for the client; counts are dropped after an idle period
+	Since time.Time
+	// Requests is the number of requests the client made since Since
+	Requests uint64
+	// RateLimited is the number of those requests that were rejected
+	RateLimited uint64
+}
+
+// NewRateLimiter creates a rate limiter allowing each client perSecond requests per
+// second on average, in bursts of up to burst requests. A perSecond of 0 only counts
+// the requests of each client.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	limit := rate.Limit(perSecond)
+	if perSecond == 0 {
+		limit = rate.Inf
+	}
+	return &RateLimiter{
+		limit:   limit,
+		burst:   burst,
+		clients: make(map[string]*clientBucket),
+	}
+}
+
+// Take counts a request of the client with id, consuming a token if it is allowed,
+// and returns the resulting usage.
+func (l *RateLimiter) Take(id string) Usage {
+	usage, _ := l.Reserve(id)
+	return usage
+}
+
+// Reserve is like Take, but also returns a function giving the token back, e.g. when
+// the request is rejected by another limit after all. It does nothing if the request
+// was not allowed.
+func (l *RateLimiter) Reserve(id string) (Usage, func()) {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	bucket := l.bucket(id, now)
+	reservation := bucket.limiter.ReserveN(now, 1)
+	allowed := reservation.OK() && reservation.DelayFrom(now) == 0
+	bucket.requests++
+	if !allowed {
+		// Rejected requests do not wait for their token
+		reservation.CancelAt(now)
+		bucket.limited++
+	}
+
+	usage := l.usage(bucket, now)
+	usage.Allowed = allowed
+	if !allowed {
+		return usage, func() {}
+	}
+	// The token is given back as if right away, the limiter restores none once the
+	// reservation is due
+	return usage, func() { reservation.CancelAt(now) }
+}
+
+// Peek returns the usage of the client with id without counting a request.
+func (l *RateLimiter) Peek(id string) Usage {
+	now := time.Now()
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	usage := l.usage(l.bucket(id, now), now)
+	usage.Allowed = usage.Remaining > 0
+	return usage
+}
+
+// bucket returns the bucket of the client with id, creating it if needed. l.mu must be held.
+func (l *RateLimiter) bucket(id string, now time.Time) *clientBucket {
+	// Forget idle clients so the limiter does not grow with every address seen
+	if now.Sub(l.lastSweep) > rateLimiterIdle {
+		for clientID, bucket := range l.clients {
+			if now.Sub(bucket.lastSeen) > rateLimiterIdle {
+				delete(l.clients, clientID)
+			}
+		}
+		l.lastSweep = now
+	}
+
+	bucket, ok := l.clients[id]
+	if !ok {
+		bucket = &clientBucket{limiter: rate.NewLimiter(l.limit, l.burst), firstSeen: now}
+		l.clients[id] = bucket
+	}
+	bucket.lastSeen = now
+	return bucket
+}
+
+// usage returns the usage of bucket at now. l.mu must be held.
+func (l *RateLimiter) usage(bucket *clientBucket, now time.Time) Usage {
+	usage := Usage{
+		Limited:     l.limit != rate.Inf,
+		Burst:       l.burst,
+		Since:       bucket.firstSeen,
+		Requests:    bucket.requests,
+		RateLimited: bucket.limited,
+	}
+
+	if !usage.Limited {
+		usage.Remaining = l.burst
+		return usage
+	}
+
+	usage.PerSecond = float64(l.limit)
+	tokens := bucket.limiter.TokensAt(now)
+	usage.Remaining = max(int(math.Floor(tokens)), 0)
+	usage.Reset = tokenDuration(float64(l.burst)-tokens, l.limit)
+	if tokens < 1 {
+		usage.RetryAfter = tokenDuration(1-tokens, l.limit)
+	}
+	return usage
+}
+
+// tokenDuration returns how long limit takes to refill tokens.
+func tokenDuration(tokens float64, limit rate.Limit) time.Duration {
+	if tokens <= 0 {
+		return 0
+	}
+	return time.Duration(tokens / float64(limit) * float64(time.Second))
+}
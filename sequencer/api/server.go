@@ -0,0 +1,96 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Server is the sequencer's HTTP API server.
+//
+// Handlers are registered with Handle before Start is called.
+type Server struct {
+	logger zerolog.Logger
+	mux    *http.ServeMux
+	srv    *http.Server
+}
+
+// NewServer creates a new HTTP API server listening on addr.
+// Every server lists its error codes at ErrorsPath.
+//
+// Parameters:
+// - addr: The address to listen on (host:port)
+// - logger: Logger used to report server lifecycle events
+//
+// Returns:
+// - *Server: The initialized server; register handlers then call Start
+func NewServer(addr string, logger zerolog.Logger) *Server {
+	mux := http.NewServeMux()
+	mux.Handle(ErrorsPath, newErrorsHandler())
+	return &Server{
+		logger: logger.With().Str("component", "api").Logger(),
+		mux:    mux,
+		srv: &http.Server{
+			Addr:              addr,
+			Handler:           mux,
+			ReadHeaderTimeout: 5 * time.Second,
+		},
+	}
+}
+
+// Handle registers the handler for the given pattern.
+func (s *Server) Handle(pattern string, handler http.Handler) {
+	s.mux.Handle(pattern, handler)
+}
+
+// Start starts serving requests in the background.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", s.srv.Addr)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info().Str("listening_on", listener.Addr().String()).Msg("API server started")
+	go func() {
+		if err := s.srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			s.logger.Error().Err(err).Msg("API server failed")
+		}
+	}()
+
+	return nil
+}
+
+// Stop gracefully shuts the server down.
+func (s *Server) Stop(ctx context.Context) error {
+	return s.srv.Shutdown(ctx)
+}
+
+// writeJSON writes v as a JSON response with the given status code.
+func writeJSON(w http.ResponseWriter, status int, v any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
+
+// WriteError writes err in the error envelope of the sequencer APIs, for handlers
+// served outside this package. details may be nil.
+func WriteError(w http.ResponseWriter, code ErrorCode, err error, details map[string]any) {
+	writeError(w, code, err, details)
+}
+
+// writeError writes err in the error envelope, with the status code registered for code.
+// details may be nil.
+func writeError(w http.ResponseWriter, code ErrorCode, err error, details map[string]any) {
+	def := lookupError(code)
+	writeJSON(w, def.HTTPStatus, ErrorResponse{Error: ErrorBody{
+		Code:      def.Code,
+		Message:   err.Error(),
+		Details:   details,
+		Retryable: def.Retryable,
+	}})
+}
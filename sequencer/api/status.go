@@ -0,0 +1,150 @@
+package api
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/pkg/store"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+// StatusPath is the route serving the live node status.
+const StatusPath = "GET /v1/status"
+
+// blockTimeWindow is the number of recent blocks block times are reported for
+const blockTimeWindow = 50
+
+// ComponentHealth is the health of a managed subprocess.
+type ComponentHealth struct {
+	Name    string `json:"name"`
+	PID     int    `json:"pid"`
+	Running bool   `json:"running"`
+}
+
+// HealthEvent is a health problem scraped from the output of a managed subprocess.
+type HealthEvent struct {
+	Time      time.Time `json:"time"`
+	Component string    `json:"component"`
+	Kind      string    `json:"kind"`
+	Severity  string    `json:"severity"`
+	Line      string    `json:"line"`
+}
+
+// MempoolStatus is the backlog of transactions waiting in the execution layer mempool.
+type MempoolStatus struct {
+	TxCount            int     `json:"tx_count"`
+	Bytes              uint64  `json:"bytes"`
+	OldestTxAgeSeconds float64 `json:"oldest_tx_age_seconds"`
+}
+
+// StatusResponse is the live status of the node.
+type StatusResponse struct {
+	Height uint64 `json:"height"`
+	// LastBlockTime is omitted until the first block is produced
+	LastBlockTime *time.Time `json:"last_block_time,omitempty"`
+	// BlockTimes are the seconds between consecutive recent blocks, oldest first
+	BlockTimes       []float64 `json:"block_times"`
+	DAIncludedHeight uint64    `json:"da_included_height"`
+	// DABacklog is the number of blocks not yet included in DA
+	DABacklog  uint64            `json:"da_backlog"`
+	Components []ComponentHealth `json:"components"`
+	// Events are the recent subprocess health events, oldest first
+	Events []HealthEvent `json:"events"`
+	// Mempool is omitted when the execution layer mempool is not observed
+	Mempool *MempoolStatus `json:"mempool,omitempty"`
+}
+
+// StatusHandler serves the live status of the node from its local store.
+type StatusHandler struct {
+	store      store.Store
+	components func() []ComponentHealth
+	events     func() []HealthEvent
+	mempool    func() *MempoolStatus
+	logger     zerolog.Logger
+}
+
+// NewStatusHandler creates a new status handler.
+//
+// Parameters:
+// - st: The local block store
+// - components: Function reporting the health of managed subprocesses (may be nil)
+// - events: Function reporting recent subprocess health events (may be nil)
+// - mempool: Function reporting the execution layer mempool backlog (may be nil)
+// - logger: Logger used to report store errors
+//
+// Returns:
+// - *StatusHandler: The initialized handler
+func NewStatusHandler(st store.Store, components func() []ComponentHealth, events func() []HealthEvent, mempool func() *MempoolStatus, logger zerolog.Logger) *StatusHandler {
+	return &StatusHandler{
+		store:      st,
+		components: components,
+		events:     events,
+		mempool:    mempool,
+		logger:     logger,
+	}
+}
+
+// ServeHTTP handles GET /v1/status.
+func (h *StatusHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	ctx := r.Context()
+
+	height, err := h.store.Height(ctx)
+	if err != nil {
+		h.logger.Warn().Err(err).Msg("Failed to load store height")
+		writeError(w, CodeInternal, fmt.Errorf("failed to load height: %w", err), nil)
+		return
+	}
+
+	resp := StatusResponse{
+		Height:     height,
+		BlockTimes: []float64{},
+		Components: []ComponentHealth{},
+		Events:     []HealthEvent{},
+	}
+
+	// Walk back over the most recent blocks to derive block times
+	var newer time.Time
+	for cur := height; cur > 0 && height-cur <= blockTimeWindow; cur-- {
+		header, err := h.store.GetHeader(ctx, cur)
+		if err != nil {
+			break
+		}
+
+		blockTime := header.Time()
+		if cur == height {
+			resp.LastBlockTime = &blockTime
+		} else {
+			resp.BlockTimes = append(resp.BlockTimes, newer.Sub(blockTime).Seconds())
+		}
+		newer = blockTime
+	}
+	slices.Reverse(resp.BlockTimes)
+
+	included, err := seqda.DAIncludedHeight(ctx, h.store)
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		h.logger.Warn().Err(err).Msg("Failed to load DA included height")
+	}
+	resp.DAIncludedHeight = included
+	if height > included {
+		resp.DABacklog = height - included
+	}
+
+	if h.components != nil {
+		resp.Components = append(resp.Components, h.components()...)
+	}
+	if h.events != nil {
+		resp.Events = append(resp.Events, h.events()...)
+	}
+	if h.mempool != nil {
+		resp.Mempool = h.mempool()
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+func TestStatusHandler(t *testing.T) {
+	ctx := context.Background()
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+
+	for height := uint64(1); height <= 3; height++ {
+		header, data := types.GetRandomBlock(height, 1, "test-chain")
+		batch, _ := st.NewBatch(ctx)
+		if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.SetHeight(height); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	bz := make([]byte, 8)
+	binary.LittleEndian.PutUint64(bz, 1)
+	if err := st.SetMetadata(ctx, store.DAIncludedHeightKey, bz); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	components := func() []ComponentHealth {
+		return []ComponentHealth{{Name: "execution", PID: 42, Running: true}}
+	}
+
+	events := func() []HealthEvent {
+		return []HealthEvent{{Component: "execution", Kind: "panic", Severity: "critical", Line: "thread 'main' panicked"}}
+	}
+
+	mempool := func() *MempoolStatus {
+		return &MempoolStatus{TxCount: 5, Bytes: 512, OldestTxAgeSeconds: 1.5}
+	}
+
+	server := NewServer("", zerolog.Nop())
+	server.Handle(DashboardPath, NewDashboardHandler())
+	server.Handle(StatusPath, NewStatusHandler(st, components, events, mempool, zerolog.Nop()))
+
+	rec := httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/status", nil))
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status %d, got %d: %s", http.StatusOK, rec.Code, rec.Body.String())
+	}
+
+	var resp StatusResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if resp.Height != 3 || resp.DAIncludedHeight != 1 || resp.DABacklog != 2 {
+		t.Errorf("expected height 3, DA included 1 and backlog 2, got %+v", resp)
+	}
+	if len(resp.BlockTimes) != 2 || resp.LastBlockTime == nil {
+		t.Errorf("expected 2 block times and a last block time, got %+v", resp)
+	}
+	if len(resp.Components) != 1 || resp.Components[0].Name != "execution" {
+		t.Errorf("expected execution component, got %+v", resp.Components)
+	}
+	if len(resp.Events) != 1 || resp.Events[0].Kind != "panic" {
+		t.Errorf("expected panic event, got %+v", resp.Events)
+	}
+	if resp.Mempool == nil || resp.Mempool.TxCount != 5 {
+		t.Errorf("expected mempool with 5 txs, got %+v", resp.Mempool)
+	}
+
+	rec = httptest.NewRecorder()
+	server.mux.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), "Pranklin Sequencer") {
+		t.Errorf("expected dashboard page, got %d", rec.Code)
+	}
+}
@@ -0,0 +1,305 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"path/filepath"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+	"golang.org/x/net/http2"
+
+	"github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/api"
+	"github.com/pranklin/pranklin-sequencer/feature"
+	"github.com/pranklin/pranklin-sequencer/nodeconfig"
+)
+
+const (
+	// FlagAPIAddr is the flag for the sequencer HTTP API listen address
+	FlagAPIAddr = "api.addr"
+	// FlagAPIExecProxy is the flag for the execution layer REST API proxied below /exec/
+	FlagAPIExecProxy = "api.exec-proxy"
+	// FlagAPIExecRoutes is the flag for the execution layer routes exposed by the proxy
+	FlagAPIExecRoutes = "api.exec-routes"
+	// FlagAPIKeysFile is the flag for the file of API keys required by proxied routes
+	FlagAPIKeysFile = "api.keys-file"
+	// FlagAPIAdminKeysFile is the flag for the file of API keys admin routes require
+	FlagAPIAdminKeysFile = "api.admin-keys-file"
+	// FlagAPIRateLimit is the flag for the per client request rate of proxied routes
+	FlagAPIRateLimit = "api.rate-limit"
+	// FlagAPIRateBurst is the flag for the per client request burst of proxied routes
+	FlagAPIRateBurst = "api.rate-burst"
+	// FlagAPIAuditFile is the flag for the audit trail of admin operations
+	FlagAPIAuditFile = "api.audit-file"
+	// FlagAPIFlowClassifier is the flag for the gRPC flow classifier plugin tagging proxied submissions
+	FlagAPIFlowClassifier = "api.flow-classifier"
+	// FlagAPIFlowClassifierTimeout is the flag for the longest a submission is classified for
+	FlagAPIFlowClassifierTimeout = "api.flow-classifier-timeout"
+	// FlagAPIFlowPoliciesFile is the flag for the file of policies applied to tagged submissions
+	FlagAPIFlowPoliciesFile = "api.flow-policies-file"
+)
+
+// addAPIFlags adds flags for the sequencer HTTP API
+func addAPIFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagAPIAddr, "", "Sequencer HTTP API listen address, e.g. 127.0.0.1:7332 (empty disables the API)")
+	cmd.Flags().String(FlagAPIExecProxy, "", "Execution layer REST API URL to reverse-proxy below /exec/, e.g. http://127.0.0.1:3000 (empty disables the proxy)")
+	cmd.Flags().StringSlice(FlagAPIExecRoutes, []string{"/tx/", "/account/", "/order/", "/market/", "/asset/"}, "Execution layer route prefixes exposed by the proxy (comma-separated)")
+	cmd.Flags().String(FlagAPIKeysFile, "", "JSON file of API keys ([{\"name\": ..., \"token\": ...}]) proxied requests must present as bearer tokens (empty allows anonymous requests)")
+	cmd.Flags().String(FlagAPIAdminKeysFile, "", "JSON file of API keys ([{\"name\": ..., \"token\": ...}]) admin routes, such as feature flag changes, the audit trail and execution restarts, require as bearer tokens (empty disables the admin routes)")
+	cmd.Flags().Float64(FlagAPIRateLimit, 0, "Requests per second each API key, or IP address without keys, may send to proxied routes (0 disables rate limiting)")
+	cmd.Flags().Int(FlagAPIRateBurst, 20, "Requests each client may send to proxied routes in a burst above the rate limit")
+	cmd.Flags().String(FlagAPIAuditFile, "audit.jsonl", "Append-only, hash-chained audit trail of admin operations such as feature flag changes, recording the admin key of each (relative to the root directory)")
+	cmd.Flags().String(FlagAPIFlowClassifier, "", "gRPC flow classifier plugin URL tagging proxied submissions, e.g. http://127.0.0.1:7400 (empty disables classification)")
+	cmd.Flags().Duration(FlagAPIFlowClassifierTimeout, 50*time.Millisecond, "Longest a submission is classified for before it is forwarded unclassified")
+	cmd.Flags().String(FlagAPIFlowPoliciesFile, "", "JSON file of policies applied to tagged submissions ([{\"tag\": ..., \"delay\": \"250ms\", \"rate_limit\": ..., \"burst\": ...}])")
+}
+
+// startAPIServer starts the sequencer HTTP API if it is enabled. Holders of admin keys
+// can change the feature flags of the node through it, and restart the execution layer
+// with restartExecution when it is managed by the node (nil otherwise).
+// Proxied submissions are timed by latency, which configureExecutor passes the
+// executed blocks. The server is shut down when ctx is cancelled.
+func startAPIServer(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, datastore ds.Batching, daLayer da.DA, latency *api.LatencyTracker, features *feature.Flags, restartExecution func() error, nodeConfig config.Config, chainID string) error {
+	addr, err := cmd.Flags().GetString(FlagAPIAddr)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagAPIAddr, err)
+	}
+
+	if addr == "" {
+		return nil
+	}
+
+	server := api.NewServer(addr, logger)
+	server.Handle(api.InclusionPath, api.NewInclusionHandler(
+		nodeStore(datastore),
+		daLayer,
+		da.NamespaceFromString(nodeConfig.DA.GetNamespace()).Bytes(),
+		da.NamespaceFromString(nodeConfig.DA.GetDataNamespace()).Bytes(),
+		logger,
+	))
+
+	// Report the configuration with the source of every value
+	settings, err := nodeconfig.Resolve(cmd, nodeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to resolve configuration sources: %w", err)
+	}
+	values := make([]api.ConfigValue, 0, len(settings))
+	for _, setting := range settings {
+		values = append(values, api.ConfigValue{
+			Key:    setting.Key,
+			Value:  setting.Value,
+			Source: string(setting.Source),
+			Env:    setting.Env,
+		})
+	}
+	server.Handle(api.ConfigPath, api.NewConfigHandler(nodeConfig.ConfigPath(), values))
+	server.Handle(api.FeaturesPath, api.NewFeaturesHandler(features))
+
+	metrics, err := api.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return err
+	}
+
+	audit, err := addAdminRoutes(cmd, server, logger, features, restartExecution, metrics, nodeConfig)
+	if err != nil {
+		return err
+	}
+	closeAudit := func() {
+		if audit != nil {
+			_ = audit.Close()
+		}
+	}
+
+	if err := addExecProxy(cmd, server, logger, latency, features, metrics); err != nil {
+		closeAudit()
+		return err
+	}
+
+	if err := server.Start(); err != nil {
+		closeAudit()
+		return fmt.Errorf("failed to start API server: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Stop(shutdownCtx)
+		closeAudit()
+	}()
+
+	return nil
+}
+
+// addAdminRoutes registers the admin routes on server if admin keys are configured,
+// only serving requests authenticated with one of them. It returns the audit trail the
+// operations are recorded in, nil without admin routes.
+func addAdminRoutes(cmd *cobra.Command, server *api.Server, logger zerolog.Logger, features *feature.Flags, restartExecution func() error, metrics *api.Metrics, nodeConfig config.Config) (*api.AuditLog, error) {
+	keysFile, err := cmd.Flags().GetString(FlagAPIAdminKeysFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagAPIAdminKeysFile, err)
+	}
+
+	if keysFile == "" {
+		logger.Info().Msg("Admin API routes disabled, set --" + FlagAPIAdminKeysFile + " to enable them")
+		return nil, nil
+	}
+
+	keys, err := api.LoadAPIKeys(keysFile)
+	if err != nil {
+		return nil, err
+	}
+
+	auditFile, err := cmd.Flags().GetString(FlagAPIAuditFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagAPIAuditFile, err)
+	}
+	if !filepath.IsAbs(auditFile) {
+		auditFile = filepath.Join(nodeConfig.RootDir, auditFile)
+	}
+	audit, err := api.OpenAuditLog(auditFile)
+	if err != nil {
+		return nil, err
+	}
+
+	admin := api.NewPolicy(keys, nil, metrics)
+	server.Handle(api.FeaturePath, admin.Protect("admin", api.NewFeatureHandler(features, audit)))
+	server.Handle(api.FeaturesBatchPath, admin.Protect("admin", api.NewFeaturesBatchHandler(features, audit)))
+	server.Handle(api.AuditPath, admin.Protect("admin", api.NewAuditHandler(audit)))
+	if restartExecution != nil {
+		server.Handle(api.ExecutionRestartPath, admin.Protect("admin", api.NewExecutionRestartHandler(restartExecution)))
+	}
+	return audit, nil
+}
+
+// addExecProxy registers the execution layer REST proxy on server if it is enabled.
+func addExecProxy(cmd *cobra.Command, server *api.Server, logger zerolog.Logger, latency *api.LatencyTracker, features *feature.Flags, metrics *api.Metrics) error {
+	target, err := cmd.Flags().GetString(FlagAPIExecProxy)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagAPIExecProxy, err)
+	}
+
+	if target == "" {
+		return nil
+	}
+
+	targetURL, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid '%s' flag: %w", FlagAPIExecProxy, err)
+	}
+
+	routes, err := cmd.Flags().GetStringSlice(FlagAPIExecRoutes)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagAPIExecRoutes, err)
+	}
+
+	proxy, err := api.NewExecProxy(targetURL, routes, logger)
+	if err != nil {
+		return err
+	}
+
+	keysFile, err := cmd.Flags().GetString(FlagAPIKeysFile)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagAPIKeysFile, err)
+	}
+
+	var keys *api.APIKeys
+	if keysFile != "" {
+		if keys, err = api.LoadAPIKeys(keysFile); err != nil {
+			return err
+		}
+	}
+
+	perSecond, err := cmd.Flags().GetFloat64(FlagAPIRateLimit)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagAPIRateLimit, err)
+	}
+
+	burst, err := cmd.Flags().GetInt(FlagAPIRateBurst)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagAPIRateBurst, err)
+	}
+
+	if perSecond < 0 || burst < 1 {
+		return fmt.Errorf("%s must be >= 0 and %s must be >= 1", FlagAPIRateLimit, FlagAPIRateBurst)
+	}
+
+	// The limiter also counts usage when requests are not limited
+	limiter := api.NewRateLimiter(perSecond, burst)
+
+	// Speed bumps count towards the inclusion latency
+	var handler http.Handler = proxy
+	guard, err := flowGuard(cmd, features, metrics, logger)
+	if err != nil {
+		return err
+	}
+	if guard != nil {
+		handler = guard.Guard(handler)
+	}
+
+	policy := api.NewPolicy(keys, limiter, metrics)
+	server.Handle(api.ExecProxyPath, policy.Protect("exec", latency.Track(handler)))
+	server.Handle(api.LimitsSelfPath, api.NewLimitsHandler(policy))
+	server.Handle(api.LatencySelfPath, api.NewLatencyHandler(policy, latency))
+	logger.Info().Str("target", targetURL.String()).Strs("routes", routes).Bool("auth", keys != nil).Msg("Proxying execution layer routes below " + api.ExecProxyPath)
+	return nil
+}
+
+// flowGuard returns the guard applying flow policies to proxied submissions, nil if
+// no classifier is configured. It classifies while the feature.FlowClassifier flag is
+// enabled.
+func flowGuard(cmd *cobra.Command, features *feature.Flags, metrics *api.Metrics, logger zerolog.Logger) (*api.FlowGuard, error) {
+	target, err := cmd.Flags().GetString(FlagAPIFlowClassifier)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagAPIFlowClassifier, err)
+	}
+
+	if target == "" {
+		return nil, nil
+	}
+
+	timeout, err := cmd.Flags().GetDuration(FlagAPIFlowClassifierTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagAPIFlowClassifierTimeout, err)
+	}
+
+	policiesFile, err := cmd.Flags().GetString(FlagAPIFlowPoliciesFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagAPIFlowPoliciesFile, err)
+	}
+
+	var policies []api.FlowPolicy
+	if policiesFile != "" {
+		if policies, err = api.LoadFlowPolicies(policiesFile); err != nil {
+			return nil, err
+		}
+	}
+
+	// Plugins are usually local sidecars speaking gRPC over h2c
+	httpClient := &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		},
+	}
+
+	enabled := func() bool { return features.Enabled(feature.FlowClassifier) }
+	guard, err := api.NewFlowGuard(api.NewGRPCClassifier(httpClient, target), timeout, policies, enabled, metrics, logger)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' flag: %w", FlagAPIFlowPoliciesFile, err)
+	}
+	logger.Info().Str("classifier", target).Int("policies", len(policies)).Msg("Classifying proxied submissions")
+	return guard, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/node"
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+	"github.com/evstack/ev-node/pkg/store"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+	"github.com/pranklin/pranklin-sequencer/era"
+)
+
+const (
+	// FlagArchiveEraSize is the flag for the number of blocks of an era file
+	FlagArchiveEraSize = "era-size"
+	// FlagArchiveTo is the flag for the last height that may be archived
+	FlagArchiveTo = "to"
+)
+
+// ArchiveCmd returns the archive command for moving finalized blocks out of the datastore
+func ArchiveCmd() *cobra.Command {
+	archiveCmd := &cobra.Command{
+		Use:   "archive",
+		Short: "Archive finalized blocks into era files",
+	}
+
+	archiveCmd.AddCommand(archiveCompactCmd())
+	return archiveCmd
+}
+
+// archiveCompactCmd returns the archive compact command
+func archiveCompactCmd() *cobra.Command {
+	compactCmd := &cobra.Command{
+		Use:   "compact",
+		Short: "Move finalized blocks from the datastore into era files",
+		Long: `Move the headers, data, signatures and states of DA included blocks out of the
+datastore into compressed, append-only era files in the era directory of the home
+directory, one file per --era-size blocks. Only full eras up to --to are archived;
+eras already archived are skipped.
+
+Each era file is verified against the datastore before its blocks are deleted. The
+sequencer and the export, import, simulate, report and conformance commands read
+archived blocks from the era files transparently. Lookups by hash keep working, as the
+hash index stays in the datastore.
+
+Era files are immutable: do not archive blocks that may still be rolled back. Badger
+reclaims the space of deleted blocks as its value log is garbage collected, so the
+datastore shrinks gradually rather than at once. The node must be stopped, as the
+datastore is opened directly.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConfig, err := rollcmd.ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			size, _ := cmd.Flags().GetUint64(FlagArchiveEraSize)
+			to, _ := cmd.Flags().GetUint64(FlagArchiveTo)
+			if size == 0 {
+				return fmt.Errorf("%s must be > 0", FlagArchiveEraSize)
+			}
+
+			genesis, err := rollgenesis.LoadGenesis(rollgenesis.GenesisPath(nodeConfig.RootDir))
+			if err != nil {
+				return err
+			}
+
+			if err := checkDatastoreLock(nodeConfig); err != nil {
+				return err
+			}
+
+			// The era files are written from the datastore itself, not through them
+			datastore, err := store.NewDefaultKVStore(nodeConfig.RootDir, nodeConfig.DBPath, datastoreName)
+			if err != nil {
+				return err
+			}
+			defer datastore.Close()
+
+			included, err := seqda.DAIncludedHeight(cmd.Context(), nodeStore(datastore))
+			if err != nil && !errors.Is(err, ds.ErrNotFound) {
+				return fmt.Errorf("failed to load DA included height: %w", err)
+			}
+			if to == 0 || to > included {
+				to = included
+			}
+
+			written, err := era.Compact(cmd.Context(), datastore, eraDir(nodeConfig), era.CompactConfig{
+				Prefix:        ds.NewKey(node.EvPrefix),
+				InitialHeight: genesis.InitialHeight,
+				Size:          size,
+				To:            to,
+			})
+			for _, path := range written {
+				cmd.PrintErrf("Archived %s\n", filepath.Base(path))
+			}
+			if err != nil {
+				return err
+			}
+
+			if len(written) == 0 {
+				cmd.PrintErrf("No full era of DA included blocks to archive up to height %d\n", to)
+			}
+			return nil
+		},
+	}
+
+	compactCmd.Flags().Uint64(FlagArchiveEraSize, 100_000, "Number of blocks of an era file")
+	compactCmd.Flags().Uint64(FlagArchiveTo, 0, "Last height that may be archived (0 uses the DA included height; later heights are never archived)")
+
+	return compactCmd
+}
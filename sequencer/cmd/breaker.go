@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/core/execution"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorCircuitFailures is the flag for the consecutive execution layer failures opening the circuit breaker
+	FlagExecutorCircuitFailures = "executor.circuit-failures"
+	// FlagExecutorCircuitProbeInterval is the flag for how long the circuit breaker stays open before probing
+	FlagExecutorCircuitProbeInterval = "executor.circuit-probe-interval"
+)
+
+// addExecutorBreakerFlags adds flags for the circuit breaker around the execution layer
+func addExecutorBreakerFlags(cmd *cobra.Command) {
+	cmd.Flags().Int(FlagExecutorCircuitFailures, 0, "Consecutive execution layer failures, such as timeouts or an unreachable service, after which calls fail right away until a probe succeeds (0 disables the circuit breaker)")
+	cmd.Flags().Duration(FlagExecutorCircuitProbeInterval, 5*time.Second, "How long the execution layer circuit breaker stays open before a call probes the execution layer")
+}
+
+// breakExecutor wraps executor with a circuit breaker if enabled by flags.
+func breakExecutor(cmd *cobra.Command, logger zerolog.Logger, executor execution.Executor, metrics *grpc.Metrics) (execution.Executor, error) {
+	failures, err := cmd.Flags().GetInt(FlagExecutorCircuitFailures)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorCircuitFailures, err)
+	}
+
+	probeInterval, err := cmd.Flags().GetDuration(FlagExecutorCircuitProbeInterval)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorCircuitProbeInterval, err)
+	}
+
+	if failures < 0 || probeInterval <= 0 {
+		return nil, fmt.Errorf("%s must be >= 0 and %s must be > 0", FlagExecutorCircuitFailures, FlagExecutorCircuitProbeInterval)
+	}
+
+	if failures == 0 {
+		return executor, nil
+	}
+
+	logger.Info().Int("failures", failures).Dur("probe_interval", probeInterval).Msg("Execution layer circuit breaker enabled")
+	return grpc.NewBreakerExecutor(executor, failures, probeInterval, logger, metrics), nil
+}
@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/export"
+)
+
+const (
+	// FlagCommandsJSON is the flag for printing the command tree as JSON
+	FlagCommandsJSON = "json"
+)
+
+// flagValues lists the accepted values of enumerated flags for shell completion
+var flagValues = map[string][]string{
+	config.FlagLogLevel:   {"debug", "info", "warn", "error"},
+	config.FlagLogFormat:  {"text", "json"},
+	config.FlagSignerType: {"file", "grpc"},
+	FlagDABackend:         {DABackendLocal, DABackendMock},
+	FlagExportFormat:      {export.FormatPB, export.FormatCAR},
+	FlagFeatureFlags:      featureNames(),
+}
+
+// dirFlags lists flags taking a directory, completed with directory names only
+var dirFlags = []string{
+	config.FlagRootDir,
+	config.FlagDBPath,
+	config.FlagSignerPath,
+	FlagExecutionDBPath,
+}
+
+// registerCompletions registers value completions for the flags of root and all of its
+// subcommands. It must be called once the command tree is complete.
+func registerCompletions(root *cobra.Command) {
+	valueCompletion := func(values []string) cobra.CompletionFunc {
+		return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			return values, cobra.ShellCompDirectiveNoFileComp
+		}
+	}
+	dirCompletion := func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		return nil, cobra.ShellCompDirectiveFilterDirs
+	}
+
+	var walk func(cmd *cobra.Command)
+	walk = func(cmd *cobra.Command) {
+		// Persistent flags are registered on the command defining them only
+		flags := pflag.NewFlagSet(cmd.Name(), pflag.ContinueOnError)
+		flags.AddFlagSet(cmd.LocalNonPersistentFlags())
+		flags.AddFlagSet(cmd.PersistentFlags())
+
+		for name, values := range flagValues {
+			if flags.Lookup(name) != nil {
+				_ = cmd.RegisterFlagCompletionFunc(name, valueCompletion(values))
+			}
+		}
+		for _, name := range dirFlags {
+			if flags.Lookup(name) != nil {
+				_ = cmd.RegisterFlagCompletionFunc(name, dirCompletion)
+			}
+		}
+
+		for _, child := range cmd.Commands() {
+			walk(child)
+		}
+	}
+	walk(root)
+}
+
+// commandInfo describes a command for wrappers built on the CLI
+type commandInfo struct {
+	Name     string        `json:"name"`
+	Path     string        `json:"path"`
+	Use      string        `json:"use"`
+	Short    string        `json:"short,omitempty"`
+	Aliases  []string      `json:"aliases,omitempty"`
+	Runnable bool          `json:"runnable"`
+	Flags    []flagInfo    `json:"flags,omitempty"`
+	Commands []commandInfo `json:"commands,omitempty"`
+}
+
+// flagInfo describes a command flag
+type flagInfo struct {
+	Name      string `json:"name"`
+	Shorthand string `json:"shorthand,omitempty"`
+	Type      string `json:"type"`
+	Default   string `json:"default"`
+	Usage     string `json:"usage"`
+	// Persistent reports whether the flag is inherited by subcommands
+	Persistent bool     `json:"persistent"`
+	Values     []string `json:"values,omitempty"`
+}
+
+// describeCommand returns the description of cmd and its available subcommands.
+// Inherited flags are listed on the command defining them only.
+func describeCommand(cmd *cobra.Command) commandInfo {
+	info := commandInfo{
+		Name:     cmd.Name(),
+		Path:     cmd.CommandPath(),
+		Use:      cmd.Use,
+		Short:    cmd.Short,
+		Aliases:  cmd.Aliases,
+		Runnable: cmd.Runnable(),
+	}
+
+	describeFlags := func(flags *pflag.FlagSet, persistent bool) {
+		flags.VisitAll(func(f *pflag.Flag) {
+			if f.Hidden {
+				return
+			}
+			info.Flags = append(info.Flags, flagInfo{
+				Name:       f.Name,
+				Shorthand:  f.Shorthand,
+				Type:       f.Value.Type(),
+				Default:    f.DefValue,
+				Usage:      f.Usage,
+				Persistent: persistent,
+				Values:     flagValues[f.Name],
+			})
+		})
+	}
+	describeFlags(cmd.LocalNonPersistentFlags(), false)
+	describeFlags(cmd.PersistentFlags(), true)
+	sort.Slice(info.Flags, func(i, j int) bool { return info.Flags[i].Name < info.Flags[j].Name })
+
+	for _, child := range cmd.Commands() {
+		if !child.IsAvailableCommand() {
+			continue
+		}
+		info.Commands = append(info.Commands, describeCommand(child))
+	}
+
+	return info
+}
+
+// CommandsCmd returns the commands command printing the command tree
+func CommandsCmd() *cobra.Command {
+	commandsCmd := &cobra.Command{
+		Use:   "commands",
+		Short: "Print the command tree, with flags, for building wrappers",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			tree := describeCommand(cmd.Root())
+
+			asJSON, _ := cmd.Flags().GetBool(FlagCommandsJSON)
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(tree)
+			}
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', 0)
+			var printTree func(info commandInfo, depth int)
+			printTree = func(info commandInfo, depth int) {
+				fmt.Fprintf(w, "%s%s\t%s\n", strings.Repeat("  ", depth), info.Name, info.Short)
+				for _, child := range info.Commands {
+					printTree(child, depth+1)
+				}
+			}
+			printTree(tree, 0)
+			return w.Flush()
+		},
+	}
+
+	commandsCmd.Flags().Bool(FlagCommandsJSON, false, "Print the command tree as JSON")
+	return commandsCmd
+}
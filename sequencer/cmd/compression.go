@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorCompress is the flag for gzip compressing large requests to the execution layer
+	FlagExecutorCompress = "executor.compress"
+	// FlagExecutorCompressMinBytes is the flag for the smallest request compressed
+	FlagExecutorCompressMinBytes = "executor.compress-min-bytes"
+)
+
+// addExecutorCompressionFlags adds flags for compressing requests to the execution layer
+func addExecutorCompressionFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagExecutorCompress, false, "Gzip compress large requests to the execution layer, such as ExecuteTxs of big blocks, once it advertises gzip support")
+	cmd.Flags().Int(FlagExecutorCompressMinBytes, 64*1024, "Smallest request to the execution layer compressed, in bytes")
+}
+
+// setCompression compresses the large requests of client to the execution layer if
+// enabled by flags.
+func setCompression(cmd *cobra.Command, client *grpc.Client) error {
+	enabled, err := cmd.Flags().GetBool(FlagExecutorCompress)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorCompress, err)
+	}
+
+	if !enabled {
+		return nil
+	}
+
+	minBytes, err := cmd.Flags().GetInt(FlagExecutorCompressMinBytes)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorCompressMinBytes, err)
+	}
+	if err := client.SetCompression(minBytes); err != nil {
+		return fmt.Errorf("invalid '%s' flag: %w", FlagExecutorCompressMinBytes, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+
+	"github.com/pranklin/pranklin-sequencer/conformance"
+)
+
+const (
+	// FlagConformanceTo is the flag for the last block of a generated vector
+	FlagConformanceTo = "to"
+	// FlagConformanceOutput is the flag for the vector output file
+	FlagConformanceOutput = "output"
+	// FlagConformanceInput is the flag for the vector input file
+	FlagConformanceInput = "input"
+)
+
+// ConformanceCmd returns the conformance command for testing execution clients
+func ConformanceCmd() *cobra.Command {
+	conformanceCmd := &cobra.Command{
+		Use:   "conformance",
+		Short: "Generate and verify executor conformance test vectors",
+	}
+
+	conformanceCmd.AddCommand(conformanceGenerateCmd(), conformanceVerifyCmd())
+	return conformanceCmd
+}
+
+// conformanceGenerateCmd returns the conformance generate command
+func conformanceGenerateCmd() *cobra.Command {
+	generateCmd := &cobra.Command{
+		Use:   "generate",
+		Short: "Write a test vector of the stored chain",
+		Long: `Write a test vector of the blocks in the local store, from the genesis up to --to.
+
+The vector holds the genesis, the transactions of each block and the state root the
+node recorded after executing it:
+
+  {"chain_id": "...", "initial_height": 1, "genesis_time": "...",
+   "genesis_state_root": "...",
+   "blocks": [{"height": 1, "time": "...", "txs": ["..."], "state_root": "..."}]}
+
+Bytes are base64 encoded. The node must be stopped, as the datastore is opened directly.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			nodeConfig, err := rollcmd.ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			to, _ := cmd.Flags().GetUint64(FlagConformanceTo)
+			output, _ := cmd.Flags().GetString(FlagConformanceOutput)
+
+			genesis, err := rollgenesis.LoadGenesis(rollgenesis.GenesisPath(nodeConfig.RootDir))
+			if err != nil {
+				return err
+			}
+
+			if err := checkDatastoreLock(nodeConfig); err != nil {
+				return err
+			}
+
+			datastore, err := openDatastore(nodeConfig)
+			if err != nil {
+				return err
+			}
+			defer datastore.Close()
+
+			st := nodeStore(datastore)
+			if to == 0 {
+				if to, err = st.Height(cmd.Context()); err != nil {
+					return fmt.Errorf("failed to load store height: %w", err)
+				}
+			}
+
+			vector, err := conformance.Generate(cmd.Context(), st, genesis, to)
+			if err != nil {
+				return err
+			}
+
+			var out io.Writer = cmd.OutOrStdout()
+			if output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer func() {
+					if cerr := file.Close(); err == nil {
+						err = cerr
+					}
+				}()
+				out = file
+			}
+
+			if err := conformance.Write(out, vector); err != nil {
+				return fmt.Errorf("failed to write vector: %w", err)
+			}
+
+			cmd.PrintErrf("Generated a vector of %d blocks (heights %d-%d)\n", len(vector.Blocks), genesis.InitialHeight, to)
+			return nil
+		},
+	}
+
+	generateCmd.Flags().Uint64(FlagConformanceTo, 0, "Last block of the vector (0 uses the latest stored height)")
+	generateCmd.Flags().StringP(FlagConformanceOutput, "o", "-", "Output file (- writes to stdout)")
+
+	return generateCmd
+}
+
+// conformanceVerifyCmd returns the conformance verify command
+func conformanceVerifyCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify",
+		Short: "Replay a test vector against an execution service",
+		Long: `Replay a vector written by 'conformance generate' against the ExecutorService at
+--grpc-executor-url and check every state root it computes.
+
+The execution service must be running with an empty state. Each block is executed
+and finalized in order; verification stops at the first error or state root mismatch.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			executor, err := createGRPCExecutionClient(cmd)
+			if err != nil {
+				return err
+			}
+			defer executor.Close()
+
+			input, _ := cmd.Flags().GetString(FlagConformanceInput)
+
+			var in io.Reader = cmd.InOrStdin()
+			if input != "-" {
+				file, err := os.Open(input)
+				if err != nil {
+					return fmt.Errorf("failed to open input file: %w", err)
+				}
+				defer file.Close()
+				in = file
+			}
+
+			vector, err := conformance.Read(in)
+			if err != nil {
+				return err
+			}
+
+			if err := conformance.Verify(cmd.Context(), executor, vector); err != nil {
+				return err
+			}
+
+			cmd.PrintErrf("Execution service conforms to the vector of %d blocks\n", len(vector.Blocks))
+			return nil
+		},
+	}
+
+	addGRPCFlags(verifyCmd)
+	verifyCmd.Flags().StringP(FlagConformanceInput, "i", "-", "Input file (- reads from stdin)")
+
+	return verifyCmd
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/api"
+	"github.com/pranklin/pranklin-sequencer/supervisor"
+)
+
+const (
+	// FlagDashboardAddr is the flag for the telemetry dashboard listen address
+	FlagDashboardAddr = "dashboard.addr"
+)
+
+// dashboardLogLines is the number of recent log lines kept for the dashboard
+const dashboardLogLines = 500
+
+// addDashboardFlags adds flags for the telemetry dashboard
+func addDashboardFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagDashboardAddr, "", "Read-only telemetry dashboard listen address, e.g. 127.0.0.1:7333 (empty disables the dashboard)")
+}
+
+// captureDashboardLogs returns a logger writing to output that also keeps its most
+// recent lines for the dashboard, together with the buffer holding them. It must be
+// called before the logger is handed to any component. When the dashboard is disabled
+// logger is returned as is.
+func captureDashboardLogs(cmd *cobra.Command, logger zerolog.Logger, output io.Writer) (zerolog.Logger, *api.LogBuffer, error) {
+	addr, err := cmd.Flags().GetString(FlagDashboardAddr)
+	if err != nil {
+		return logger, nil, fmt.Errorf("failed to get '%s' flag: %w", FlagDashboardAddr, err)
+	}
+
+	if addr == "" {
+		return logger, nil, nil
+	}
+
+	logs := api.NewLogBuffer(dashboardLogLines)
+	return logger.Output(zerolog.MultiLevelWriter(output, logs)), logs, nil
+}
+
+// startDashboard starts the telemetry dashboard if it is enabled. events reports the
+// subprocess health events and may be nil; mempool reports the execution layer mempool
+// backlog. The server is shut down when ctx is cancelled.
+func startDashboard(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, datastore ds.Batching, logs *api.LogBuffer, events func() []api.HealthEvent, mempool func() *api.MempoolStatus, nodeConfig config.Config) error {
+	addr, err := cmd.Flags().GetString(FlagDashboardAddr)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDashboardAddr, err)
+	}
+
+	if addr == "" {
+		return nil
+	}
+
+	// Subprocess health is read from the pidfiles written by the supervisor
+	components := func() []api.ComponentHealth {
+		pidFiles, err := supervisor.ListPIDFiles(pidDir(nodeConfig))
+		if err != nil {
+			return nil
+		}
+
+		health := make([]api.ComponentHealth, 0, len(pidFiles))
+		for _, pidFile := range pidFiles {
+			health = append(health, api.ComponentHealth{
+				Name:    pidFile.Name,
+				PID:     pidFile.PID,
+				Running: pidFile.Running(),
+			})
+		}
+		return health
+	}
+
+	server := api.NewServer(addr, logger)
+	server.Handle(api.DashboardPath, api.NewDashboardHandler())
+	server.Handle(api.StatusPath, api.NewStatusHandler(nodeStore(datastore), components, events, mempool, logger))
+	if logs != nil {
+		server.Handle(api.LogsPath, api.NewLogsHandler(logs))
+	}
+
+	if err := server.Start(); err != nil {
+		return fmt.Errorf("failed to start dashboard: %w", err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Stop(shutdownCtx)
+	}()
+
+	return nil
+}
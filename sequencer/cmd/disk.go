@@ -0,0 +1,86 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/disk"
+)
+
+const (
+	// FlagDiskCheckInterval is the flag for the interval between disk usage checks
+	FlagDiskCheckInterval = "disk.check-interval"
+	// FlagDiskWarnPercent is the flag for the disk usage at which an alert is logged
+	FlagDiskWarnPercent = "disk.warn-percent"
+	// FlagDiskCompactPercent is the flag for the disk usage at which the datastore is compacted
+	FlagDiskCompactPercent = "disk.compact-percent"
+	// FlagDiskHaltPercent is the flag for the disk usage at which the node halts
+	FlagDiskHaltPercent = "disk.halt-percent"
+)
+
+// addDiskFlags adds flags for disk usage monitoring
+func addDiskFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration(FlagDiskCheckInterval, time.Minute, "Interval between disk usage checks of the data directories (0 disables monitoring)")
+	cmd.Flags().Float64(FlagDiskWarnPercent, 85, "Disk usage percentage at which an alert is logged")
+	cmd.Flags().Float64(FlagDiskCompactPercent, 90, "Disk usage percentage at which the datastore is compacted")
+	cmd.Flags().Float64(FlagDiskHaltPercent, 97, "Disk usage percentage at which the node halts gracefully")
+}
+
+// startDiskMonitor starts monitoring the filesystems holding paths if it is enabled.
+// halt is called once when the disk is about to fill up. The monitor stops when ctx is cancelled.
+func startDiskMonitor(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, datastore ds.Batching, paths []string, nodeConfig config.Config, chainID string, halt func(err error)) error {
+	interval, err := cmd.Flags().GetDuration(FlagDiskCheckInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDiskCheckInterval, err)
+	}
+
+	if interval <= 0 {
+		return nil
+	}
+
+	cfg := disk.Config{
+		Paths:    paths,
+		Interval: interval,
+	}
+
+	for flag, threshold := range map[string]*float64{
+		FlagDiskWarnPercent:    &cfg.WarnPercent,
+		FlagDiskCompactPercent: &cfg.CompactPercent,
+		FlagDiskHaltPercent:    &cfg.HaltPercent,
+	} {
+		if *threshold, err = cmd.Flags().GetFloat64(flag); err != nil {
+			return fmt.Errorf("failed to get '%s' flag: %w", flag, err)
+		}
+	}
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid disk monitor configuration: %w", err)
+	}
+
+	metrics, err := disk.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return err
+	}
+
+	// Badger only returns space to the filesystem once its value log is garbage collected
+	compact := func(ctx context.Context) error {
+		if gc, ok := datastore.(ds.GCDatastore); ok {
+			return gc.CollectGarbage(ctx)
+		}
+		return nil
+	}
+
+	monitor := disk.NewMonitor(cfg, compact, halt, logger, metrics)
+
+	logger.Info().Strs("paths", paths).Dur("interval", interval).Msg("Starting disk usage monitor")
+	go monitor.Run(ctx)
+
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+
+	"github.com/pranklin/pranklin-sequencer/drift"
+	"github.com/pranklin/pranklin-sequencer/nodeconfig"
+)
+
+const (
+	// FlagDriftManifest is the flag for the URL or path of the signed reference manifest
+	FlagDriftManifest = "drift.manifest"
+	// FlagDriftManifestPubKey is the flag for the hex encoded key the manifest must be signed with
+	FlagDriftManifestPubKey = "drift.manifest-pubkey"
+	// FlagDriftInterval is the flag for the interval between drift checks
+	FlagDriftInterval = "drift.interval"
+)
+
+// addDriftFlags adds flags for configuration drift detection
+func addDriftFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagDriftManifest, "", "URL or path of the signed reference manifest to check the configuration against (empty disables drift detection)")
+	cmd.Flags().String(FlagDriftManifestPubKey, "", "Hex encoded ed25519 public key the reference manifest must be signed with")
+	cmd.Flags().Duration(FlagDriftInterval, time.Hour, "Interval between configuration drift checks")
+}
+
+// startDriftDetector starts comparing the running configuration and genesis against the
+// signed reference manifest if it is enabled. The detector stops when ctx is cancelled.
+func startDriftDetector(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, nodeConfig config.Config, chainID string) error {
+	source, err := cmd.Flags().GetString(FlagDriftManifest)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDriftManifest, err)
+	}
+
+	if source == "" {
+		return nil
+	}
+
+	pubKeyHex, err := cmd.Flags().GetString(FlagDriftManifestPubKey)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDriftManifestPubKey, err)
+	}
+	pubKey, err := hex.DecodeString(pubKeyHex)
+	if err != nil {
+		return fmt.Errorf("invalid '%s' flag: %w", FlagDriftManifestPubKey, err)
+	}
+
+	interval, err := cmd.Flags().GetDuration(FlagDriftInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDriftInterval, err)
+	}
+
+	cfg := drift.Config{
+		Source:    source,
+		PublicKey: ed25519.PublicKey(pubKey),
+		Interval:  interval,
+	}
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid drift detector configuration: %w", err)
+	}
+
+	genesisHash, err := drift.GenesisSHA256(rollgenesis.GenesisPath(nodeConfig.RootDir))
+	if err != nil {
+		return err
+	}
+
+	settings, err := nodeconfig.Resolve(cmd, nodeConfig)
+	if err != nil {
+		return fmt.Errorf("failed to resolve configuration: %w", err)
+	}
+	params := make(map[string]string, len(settings))
+	for _, setting := range settings {
+		params[setting.Key] = fmt.Sprint(setting.Value)
+	}
+
+	metrics, err := drift.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return err
+	}
+
+	state := drift.State{
+		ChainID:       chainID,
+		GenesisSHA256: genesisHash,
+		Parameters:    params,
+	}
+	detector := drift.NewDetector(cfg, state, logger, metrics)
+
+	logger.Info().Str("manifest", source).Dur("interval", interval).Msg("Starting configuration drift detector")
+	go detector.Run(ctx)
+
+	return nil
+}
@@ -0,0 +1,129 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+
+	"github.com/pranklin/pranklin-sequencer/export"
+)
+
+const (
+	// FlagExportFormat is the flag for the export archive format
+	FlagExportFormat = "format"
+	// FlagExportFrom is the flag for the first exported height
+	FlagExportFrom = "from"
+	// FlagExportTo is the flag for the last exported height
+	FlagExportTo = "to"
+	// FlagExportOutput is the flag for the export output file
+	FlagExportOutput = "output"
+)
+
+// ExportCmd returns the export command for streaming stored chain data out of a node
+func ExportCmd() *cobra.Command {
+	exportCmd := &cobra.Command{
+		Use:   "export",
+		Short: "Export stored chain data",
+	}
+
+	exportCmd.AddCommand(exportBlocksCmd())
+	return exportCmd
+}
+
+// exportBlocksCmd returns the export blocks command
+func exportBlocksCmd() *cobra.Command {
+	blocksCmd := &cobra.Command{
+		Use:   "blocks",
+		Short: "Stream stored blocks with their DA metadata to an archive",
+		Long: `Stream a range of blocks from the local store, with their signed headers, data and
+DA inclusion heights, to a portable archive.
+
+Each block is encoded as a BlockRecord protobuf message:
+
+  message BlockRecord {
+    uint64 height           = 1;
+    bytes  header           = 2; // evnode.v1.SignedHeader
+    bytes  data             = 3; // evnode.v1.Data
+    uint64 header_da_height = 4; // 0 if not yet included in DA
+    uint64 data_da_height   = 5; // 0 if not yet included in DA
+  }
+
+With --format pb records are written length-delimited (uvarint length prefix).
+With --format car records are written as raw blocks of a CARv1 archive rooted at the
+first record. The node must be stopped, as the datastore is opened directly.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			nodeConfig, err := rollcmd.ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			format, _ := cmd.Flags().GetString(FlagExportFormat)
+			from, _ := cmd.Flags().GetUint64(FlagExportFrom)
+			to, _ := cmd.Flags().GetUint64(FlagExportTo)
+			output, _ := cmd.Flags().GetString(FlagExportOutput)
+
+			if err := checkDatastoreLock(nodeConfig); err != nil {
+				return err
+			}
+
+			datastore, err := openDatastore(nodeConfig)
+			if err != nil {
+				return err
+			}
+			defer datastore.Close()
+
+			st := nodeStore(datastore)
+			if to == 0 {
+				if to, err = st.Height(cmd.Context()); err != nil {
+					return fmt.Errorf("failed to load store height: %w", err)
+				}
+			}
+
+			if to < from {
+				return fmt.Errorf("no stored blocks from height %d (store height %d)", from, to)
+			}
+
+			var out io.Writer = cmd.OutOrStdout()
+			if output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer func() {
+					if cerr := file.Close(); err == nil {
+						err = cerr
+					}
+				}()
+				out = file
+			}
+
+			w, err := export.NewWriter(format, out)
+			if err != nil {
+				return err
+			}
+
+			written, err := export.Export(cmd.Context(), st, from, to, w)
+			if err != nil {
+				return err
+			}
+			if err := w.Close(); err != nil {
+				return fmt.Errorf("failed to flush export: %w", err)
+			}
+
+			cmd.PrintErrf("Exported %d blocks (heights %d-%d)\n", written, from, to)
+			return nil
+		},
+	}
+
+	blocksCmd.Flags().String(FlagExportFormat, export.FormatPB, fmt.Sprintf("Archive format (%s or %s)", export.FormatPB, export.FormatCAR))
+	blocksCmd.Flags().Uint64(FlagExportFrom, 1, "First height to export")
+	blocksCmd.Flags().Uint64(FlagExportTo, 0, "Last height to export (0 uses the latest stored height)")
+	blocksCmd.Flags().StringP(FlagExportOutput, "o", "-", "Output file (- writes to stdout)")
+
+	return blocksCmd
+}
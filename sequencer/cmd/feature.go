@@ -0,0 +1,65 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/feature"
+)
+
+const (
+	// FlagFeatureFlags is the flag for the feature flags enabled on this replica
+	FlagFeatureFlags = "feature.flags"
+)
+
+// addFeatureFlags adds flags for feature flags
+func addFeatureFlags(cmd *cobra.Command) {
+	cmd.Flags().StringSlice(FlagFeatureFlags, nil, fmt.Sprintf("Feature flags of this replica as name or name=true|false, e.g. %s (see GET /v1/features)", feature.LazyBlocks))
+}
+
+// loadFeatureFlags loads the feature flags of this replica and applies the flags read
+// at startup to nodeConfig. It must be called before nodeConfig is used.
+func loadFeatureFlags(cmd *cobra.Command, logger zerolog.Logger, nodeConfig *config.Config, chainID string) (*feature.Flags, error) {
+	values, err := cmd.Flags().GetStringSlice(FlagFeatureFlags)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagFeatureFlags, err)
+	}
+
+	overrides, err := feature.ParseOverrides(values)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' flag: %w", FlagFeatureFlags, err)
+	}
+
+	metrics, err := feature.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return nil, err
+	}
+
+	flags, err := feature.NewFlags(feature.Definitions, overrides, logger, metrics)
+	if err != nil {
+		return nil, fmt.Errorf("invalid '%s' flag: %w", FlagFeatureFlags, err)
+	}
+
+	// Lazy blocks only add to the lazy mode set in the node configuration
+	if flags.Enabled(feature.LazyBlocks) && !nodeConfig.Node.LazyMode {
+		nodeConfig.Node.LazyMode = true
+		if err := nodeConfig.Validate(); err != nil {
+			return nil, fmt.Errorf("cannot enable %s: %w", feature.LazyBlocks, err)
+		}
+	}
+
+	return flags, nil
+}
+
+// featureNames returns the names of the defined feature flags
+func featureNames() []string {
+	names := make([]string, 0, len(feature.Definitions))
+	for _, def := range feature.Definitions {
+		names = append(names, def.Name)
+	}
+	return names
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/api"
+	"github.com/pranklin/pranklin-sequencer/health"
+)
+
+// healthEventsKept is the number of recent health events kept per subprocess
+const healthEventsKept = 100
+
+// newExecutionScanner creates the scanner turning known execution layer log patterns
+// into health events. It is added to the execution subprocess outputs.
+func newExecutionScanner(logger zerolog.Logger, nodeConfig config.Config, chainID string) (*health.Scanner, error) {
+	metrics, err := health.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create health metrics: %w", err)
+	}
+
+	return health.NewScanner("execution", health.ExecutionPatterns, healthEventsKept, logger, metrics), nil
+}
+
+// healthEvents returns a function reporting the recent events of scanner for the
+// status API.
+func healthEvents(scanner *health.Scanner) func() []api.HealthEvent {
+	return func() []api.HealthEvent {
+		events := scanner.Events()
+		resp := make([]api.HealthEvent, 0, len(events))
+		for _, event := range events {
+			resp = append(resp, api.HealthEvent{
+				Time:      event.Time,
+				Component: event.Component,
+				Kind:      string(event.Kind),
+				Severity:  string(event.Severity),
+				Line:      event.Line,
+			})
+		}
+		return resp
+	}
+}
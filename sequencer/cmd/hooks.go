@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/hook"
+)
+
+const (
+	// FlagHooksPreStart is the flag for the hooks run before the node starts its components
+	FlagHooksPreStart = "hooks.pre-start"
+	// FlagHooksPostReady is the flag for the hooks run once the node is running
+	FlagHooksPostReady = "hooks.post-ready"
+	// FlagHooksPreShutdown is the flag for the hooks run before the node shuts down
+	FlagHooksPreShutdown = "hooks.pre-shutdown"
+	// FlagHooksComponentCrash is the flag for the hooks run when a component exits unexpectedly
+	FlagHooksComponentCrash = "hooks.component-crash"
+	// FlagHooksTimeout is the flag for how long each hook may take
+	FlagHooksTimeout = "hooks.timeout"
+)
+
+// hookFlags are the flags of the hooks of each event
+var hookFlags = map[hook.Event]string{
+	hook.PreStart:       FlagHooksPreStart,
+	hook.PostReady:      FlagHooksPostReady,
+	hook.PreShutdown:    FlagHooksPreShutdown,
+	hook.ComponentCrash: FlagHooksComponentCrash,
+}
+
+// addHookFlags adds flags for the lifecycle hooks of the unified node
+func addHookFlags(cmd *cobra.Command) {
+	const usage = " (repeatable): an executable, given the event as JSON on stdin and PRANKLIN_HOOK_* variables, or an http(s) URL posted the JSON"
+	cmd.Flags().StringArray(FlagHooksPreStart, nil, "Hook run before the components start; a failing hook aborts the start"+usage)
+	cmd.Flags().StringArray(FlagHooksPostReady, nil, "Hook run once every component is started and the sequencer is running"+usage)
+	cmd.Flags().StringArray(FlagHooksPreShutdown, nil, "Hook run before the node drains and stops its components, after a signal or a component failure"+usage)
+	cmd.Flags().StringArray(FlagHooksComponentCrash, nil, "Hook run in the background when the execution layer, local-da or sequencer exits unexpectedly"+usage)
+	cmd.Flags().Duration(FlagHooksTimeout, 30*time.Second, "How long each hook may take before it is killed")
+}
+
+// hookRunner creates the runner of the lifecycle hooks configured by flags
+func hookRunner(cmd *cobra.Command, logger zerolog.Logger, chainID string) (*hook.Runner, error) {
+	hooks := make(map[hook.Event][]string, len(hookFlags))
+	for event, name := range hookFlags {
+		values, err := cmd.Flags().GetStringArray(name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get '%s' flag: %w", name, err)
+		}
+		hooks[event] = values
+	}
+
+	timeout, err := cmd.Flags().GetDuration(FlagHooksTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagHooksTimeout, err)
+	}
+
+	if timeout <= 0 {
+		return nil, fmt.Errorf("%s must be > 0", FlagHooksTimeout)
+	}
+
+	return hook.NewRunner(hooks, chainID, timeout, logger), nil
+}
+
+// errorString returns the message of err, empty for nil
+func errorString(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+
+	"github.com/pranklin/pranklin-sequencer/export"
+)
+
+const (
+	// FlagImportFormat is the flag for the import archive format
+	FlagImportFormat = "format"
+	// FlagImportInput is the flag for the import input file
+	FlagImportInput = "input"
+)
+
+// ImportCmd returns the import command for replaying exported chain data into a node
+func ImportCmd() *cobra.Command {
+	importCmd := &cobra.Command{
+		Use:   "import",
+		Short: "Import exported chain data",
+	}
+
+	importCmd.AddCommand(importBlocksCmd())
+	return importCmd
+}
+
+// importBlocksCmd returns the import blocks command
+func importBlocksCmd() *cobra.Command {
+	blocksCmd := &cobra.Command{
+		Use:   "blocks",
+		Short: "Validate and replay an exported block archive into a fresh node",
+		Long: `Validate and replay an archive written by 'export blocks' into a fresh node.
+
+Every block is checked against the genesis proposer and its signature, its data hash
+and the previous block, then executed by the gRPC execution service. The state root
+committed to by each header must match the root computed by the execution service, so
+a successful import proves the archive replays to the same state.
+
+DA inclusion heights are restored. Blocks up to the last contiguously DA included one
+are marked as submitted and final, so the node does not post them to DA again.
+
+The node must be initialized and stopped, its store must be empty and the execution
+service must be running with an empty state.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			executor, err := createGRPCExecutionClient(cmd)
+			if err != nil {
+				return err
+			}
+
+			nodeConfig, err := rollcmd.ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger := rollcmd.SetupLogger(nodeConfig.Log)
+
+			format, _ := cmd.Flags().GetString(FlagImportFormat)
+			input, _ := cmd.Flags().GetString(FlagImportInput)
+
+			var in io.Reader = cmd.InOrStdin()
+			if input != "-" {
+				file, err := os.Open(input)
+				if err != nil {
+					return fmt.Errorf("failed to open input file: %w", err)
+				}
+				defer file.Close()
+				in = file
+			}
+
+			r, err := export.NewReader(format, in)
+			if err != nil {
+				return err
+			}
+
+			genesis, err := rollgenesis.LoadGenesis(rollgenesis.GenesisPath(nodeConfig.RootDir))
+			if err != nil {
+				return err
+			}
+
+			if err := checkDatastoreLock(nodeConfig); err != nil {
+				return err
+			}
+
+			datastore, err := openDatastore(nodeConfig)
+			if err != nil {
+				return err
+			}
+			defer func() {
+				if cerr := datastore.Close(); err == nil {
+					err = cerr
+				}
+			}()
+
+			imported, err := export.Import(cmd.Context(), nodeStore(datastore), executor, genesis, r, logger)
+			if err != nil {
+				return fmt.Errorf("import failed after %d blocks: %w", imported, err)
+			}
+
+			cmd.PrintErrf("Imported %d blocks\n", imported)
+			return nil
+		},
+	}
+
+	addGRPCFlags(blocksCmd)
+	blocksCmd.Flags().String(FlagImportFormat, export.FormatPB, fmt.Sprintf("Archive format (%s or %s)", export.FormatPB, export.FormatCAR))
+	blocksCmd.Flags().StringP(FlagImportInput, "i", "-", "Input file (- reads from stdin)")
+
+	return blocksCmd
+}
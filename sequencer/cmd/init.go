@@ -9,6 +9,8 @@ import (
 	rollcmd "github.com/evstack/ev-node/pkg/cmd"
 	rollconf "github.com/evstack/ev-node/pkg/config"
 	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+
+	"github.com/pranklin/pranklin-sequencer/nodeconfig"
 )
 
 // InitCmd returns the init command for initializing the Pranklin Sequencer
@@ -52,6 +54,11 @@ This will create the necessary configuration structure in the specified root dir
 				return fmt.Errorf("error writing evnode.yml file: %w", err)
 			}
 
+			// Unified node settings live next to the node configuration
+			if err := nodeconfig.WriteSection(cmd, cfg.ConfigPath(), nodeSection, nodeSectionFlags); err != nil {
+				return err
+			}
+
 			if err := rollcmd.LoadOrGenNodeKey(homePath); err != nil {
 				return err
 			}
@@ -89,5 +96,8 @@ This will create the necessary configuration structure in the specified root dir
 	rollconf.AddFlags(initCmd)
 	initCmd.Flags().String(rollgenesis.ChainIDFlag, "pranklin-mainnet-1", "chain ID")
 
+	// Add unified node flags written to the pranklin section
+	addNodeSectionFlags(initCmd)
+
 	return initCmd
 }
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/supervisor"
+)
+
+const (
+	// FlagLogDir is the flag for the directory component log files are written to
+	FlagLogDir = "log.dir"
+	// FlagLogMaxSize is the flag for the size a log file may reach before it is rotated
+	FlagLogMaxSize = "log.max-size"
+	// FlagLogRotateInterval is the flag for how long a log file is written to before it is rotated
+	FlagLogRotateInterval = "log.rotate-interval"
+	// FlagLogMaxFiles is the flag for how many rotated files are kept per component
+	FlagLogMaxFiles = "log.max-files"
+	// FlagLogMaxAge is the flag for how long rotated log files are kept
+	FlagLogMaxAge = "log.max-age"
+)
+
+// Log file names of the unified node components, inside the log directory
+const (
+	sequencerLogFile = "sequencer.log"
+	daLogFile        = "da.log"
+	executionLogFile = "execution.log"
+)
+
+// addLogFileFlags adds flags for writing component logs to rotated files
+func addLogFileFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagLogDir, "", "Directory to write sequencer.log, da.log and execution.log to, in addition to the console (empty disables log files)")
+	cmd.Flags().Int64(FlagLogMaxSize, 100<<20, "Bytes a log file may reach before it is rotated (0 disables size rotation)")
+	cmd.Flags().Duration(FlagLogRotateInterval, 24*time.Hour, "How long a log file is written to before it is rotated (0 disables time rotation)")
+	cmd.Flags().Int(FlagLogMaxFiles, 7, "Number of rotated files kept per component (0 keeps all)")
+	cmd.Flags().Duration(FlagLogMaxAge, 0, "How long rotated log files are kept (0 keeps them regardless of age)")
+}
+
+// logFiles holds the rotated log files of the node components. A nil *logFiles writes
+// no files.
+type logFiles struct {
+	dir   string
+	cfg   supervisor.RotateConfig
+	files []*supervisor.RotatingFile
+}
+
+// openLogFiles reads the log file configuration from command flags. It returns nil
+// when --log.dir is not set. Relative directories resolve against the root directory.
+func openLogFiles(cmd *cobra.Command, nodeConfig config.Config) (*logFiles, error) {
+	dir, err := cmd.Flags().GetString(FlagLogDir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagLogDir, err)
+	}
+	if dir == "" {
+		return nil, nil
+	}
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(nodeConfig.RootDir, dir)
+	}
+
+	var cfg supervisor.RotateConfig
+	if cfg.MaxSize, err = cmd.Flags().GetInt64(FlagLogMaxSize); err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagLogMaxSize, err)
+	}
+	if cfg.Interval, err = cmd.Flags().GetDuration(FlagLogRotateInterval); err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagLogRotateInterval, err)
+	}
+	if cfg.MaxFiles, err = cmd.Flags().GetInt(FlagLogMaxFiles); err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagLogMaxFiles, err)
+	}
+	if cfg.MaxAge, err = cmd.Flags().GetDuration(FlagLogMaxAge); err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagLogMaxAge, err)
+	}
+
+	if cfg.MaxSize < 0 || cfg.Interval < 0 || cfg.MaxFiles < 0 || cfg.MaxAge < 0 {
+		return nil, fmt.Errorf("%s, %s, %s and %s must be >= 0", FlagLogMaxSize, FlagLogRotateInterval, FlagLogMaxFiles, FlagLogMaxAge)
+	}
+
+	return &logFiles{dir: dir, cfg: cfg}, nil
+}
+
+// open opens the log file name, or returns nil when log files are disabled.
+func (l *logFiles) open(name string) (io.Writer, error) {
+	if l == nil {
+		return nil, nil
+	}
+
+	f, err := supervisor.OpenRotatingFile(filepath.Join(l.dir, name), l.cfg)
+	if err != nil {
+		return nil, err
+	}
+	l.files = append(l.files, f)
+	return f, nil
+}
+
+// consoleLogOutput returns the console output chosen by rollcmd.SetupLogger
+func consoleLogOutput(nodeConfig config.Config) io.Writer {
+	if nodeConfig.Log.Format == "json" {
+		return os.Stderr
+	}
+	return zerolog.ConsoleWriter{Out: os.Stderr}
+}
+
+// nodeLogOutput returns the output of the node logger: the console, and sequencer.log
+// when log files are enabled. The file is written in the console format without colors.
+func (l *logFiles) nodeLogOutput(nodeConfig config.Config) (io.Writer, error) {
+	console := consoleLogOutput(nodeConfig)
+
+	file, err := l.open(sequencerLogFile)
+	if err != nil || file == nil {
+		return console, err
+	}
+	if nodeConfig.Log.Format != "json" {
+		file = zerolog.ConsoleWriter{Out: file, NoColor: true}
+	}
+	return zerolog.MultiLevelWriter(console, file), nil
+}
+
+// Close closes the opened log files.
+func (l *logFiles) Close() error {
+	if l == nil {
+		return nil
+	}
+
+	var errs []error
+	for _, f := range l.files {
+		errs = append(errs, f.Close())
+	}
+	return errors.Join(errs...)
+}
+
+// bestEffortWriter is an io.Writer ignoring the errors of the writer it wraps
+type bestEffortWriter struct {
+	w io.Writer
+}
+
+// Write writes p to the wrapped writer and always reports success
+func (b bestEffortWriter) Write(p []byte) (int, error) {
+	_, _ = b.w.Write(p)
+	return len(p), nil
+}
@@ -25,12 +25,24 @@ Connects to Pranklin execution layer via gRPC for trading operations.`,
 		InitCmd(),
 		NodeCmd, // Unified node command (DA + Execution + Sequencer)
 		RunCmd,  // Legacy: sequencer only (requires external DA + Execution)
+		SimulateCmd(),
+		ExportCmd(),
+		ImportCmd(),
+		ReportCmd(),
+		ArchiveCmd(),
+		ConformanceCmd(),
+		ReferenceExecutorCmd(),
+		VerifyBuildCmd(),
+		CommandsCmd(),
 		evcmd.VersionCmd,
 		evcmd.NetInfoCmd,
 		evcmd.StoreUnsafeCleanCmd,
 		evcmd.KeysCmd(),
 	)
 
+	// Complete enumerated flag values and directories in generated shell completions
+	registerCompletions(rootCmd)
+
 	if err := rootCmd.Execute(); err != nil {
 		// Print to stderr and exit with error
 		fmt.Fprintln(os.Stderr, err)
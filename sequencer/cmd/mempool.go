@@ -0,0 +1,126 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/api"
+	"github.com/pranklin/pranklin-sequencer/feature"
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorGetTxsBudget is the flag for the share of the block time a GetTxs call may take
+	FlagExecutorGetTxsBudget = "executor.get-txs-budget"
+)
+
+// mempoolReportInterval is the interval between mempool metric updates
+const mempoolReportInterval = time.Second
+
+// addExecutorFlags adds flags for calls to the execution layer
+func addExecutorFlags(cmd *cobra.Command) {
+	cmd.Flags().Float64(FlagExecutorGetTxsBudget, 0.2, "Share of the block time (0..1) a GetTxs call may take before collection proceeds without it (0 disables the budget)")
+}
+
+// executorMetrics creates the metrics of the execution layer client
+func executorMetrics(nodeConfig config.Config, chainID string) (*grpc.Metrics, error) {
+	metrics, err := grpc.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create executor metrics: %w", err)
+	}
+	return metrics, nil
+}
+
+// configureExecutor configures client as set by flags, recording its calls in metrics
+// and passing the blocks it executes to latency. It must be called right after the
+// client is created, before anything uses it.
+func configureExecutor(cmd *cobra.Command, logger zerolog.Logger, client *grpc.Client, latency *api.LatencyTracker, metrics *grpc.Metrics) error {
+	client.SetMetrics(metrics)
+	client.OnExecuted(latency.Included)
+	if err := setTxTelemetry(cmd, logger, client, metrics); err != nil {
+		return err
+	}
+	if err := setRetryPolicy(cmd, logger, client); err != nil {
+		return err
+	}
+	if err := setTimeouts(cmd, client); err != nil {
+		return err
+	}
+	if err := setTracing(cmd, client); err != nil {
+		return err
+	}
+	if err := setCompression(cmd, client); err != nil {
+		return err
+	}
+	return setTxStream(cmd, client)
+}
+
+// budgetGetTxs wraps executor so GetTxs calls are bounded by a share of the block time
+// while the feature.GetTxsBudget flag is enabled.
+func budgetGetTxs(cmd *cobra.Command, logger zerolog.Logger, executor execution.Executor, features *feature.Flags, metrics *grpc.Metrics, nodeConfig config.Config) (execution.Executor, error) {
+	share, err := cmd.Flags().GetFloat64(FlagExecutorGetTxsBudget)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorGetTxsBudget, err)
+	}
+
+	if share < 0 || share > 1 {
+		return nil, fmt.Errorf("%s must be between 0 and 1, got %v", FlagExecutorGetTxsBudget, share)
+	}
+
+	budget := time.Duration(share * float64(nodeConfig.Node.BlockTime.Duration))
+	if budget <= 0 {
+		return executor, nil
+	}
+
+	logger.Info().Dur("budget", budget).Msg("GetTxs budget enabled")
+	enabled := func() bool { return features.Enabled(feature.GetTxsBudget) }
+	return grpc.NewBudgetExecutor(executor, budget, enabled, logger, metrics), nil
+}
+
+// startMempoolReporter reports the execution layer mempool backlog seen by client in
+// metrics until ctx is cancelled.
+func startMempoolReporter(ctx context.Context, client *grpc.Client, metrics *grpc.Metrics) {
+	go func() {
+		ticker := time.NewTicker(mempoolReportInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			info, err := client.GetMempoolInfo(ctx)
+			if err != nil {
+				continue
+			}
+			metrics.MempoolTxs.Set(float64(info.TxCount))
+			metrics.MempoolBytes.Set(float64(info.Bytes))
+			metrics.MempoolOldestTxAge.Set(info.OldestTxAge.Seconds())
+		}
+	}()
+}
+
+// mempoolStatus returns a function reporting the mempool backlog seen by client for
+// the status API.
+func mempoolStatus(client *grpc.Client) func() *api.MempoolStatus {
+	return func() *api.MempoolStatus {
+		info, err := client.GetMempoolInfo(context.Background())
+		if err != nil || info.UpdatedAt.IsZero() {
+			return nil
+		}
+		return &api.MempoolStatus{
+			TxCount:            info.TxCount,
+			Bytes:              info.Bytes,
+			OldestTxAgeSeconds: info.OldestTxAge.Seconds(),
+		}
+	}
+}
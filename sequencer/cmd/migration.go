@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/da/jsonrpc"
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+	"github.com/evstack/ev-node/pkg/config"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+const (
+	// FlagDAMigrationAddress is the flag for the address of the DA network being migrated to
+	FlagDAMigrationAddress = "da.migration.address"
+	// FlagDAMigrationAuthToken is the flag for the auth token of the DA network being migrated to
+	FlagDAMigrationAuthToken = "da.migration.auth-token"
+	// FlagDAMigrationCutoverHeight is the flag for the first block submitted to the new DA network only
+	FlagDAMigrationCutoverHeight = "da.migration.cutover-height"
+)
+
+// addDAMigrationFlags adds flags for moving block submission to another DA network
+func addDAMigrationFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagDAMigrationAddress, "", "Address of a DA network to migrate to: blocks below the cutover height are written to both networks, later ones to this one only (empty disables the migration)")
+	cmd.Flags().String(FlagDAMigrationAuthToken, "", "Auth token of the DA network migrated to")
+	cmd.Flags().Uint64(FlagDAMigrationCutoverHeight, 0, "First block height submitted to the DA network migrated to only")
+}
+
+// migrateDA wraps daLayer to migrate block submission to the DA network configured by
+// flags. It returns daLayer unchanged when no migration is configured.
+func migrateDA(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, daLayer da.DA, datastore ds.Batching, nodeConfig config.Config) (da.DA, error) {
+	address, err := cmd.Flags().GetString(FlagDAMigrationAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagDAMigrationAddress, err)
+	}
+
+	if address == "" {
+		return daLayer, nil
+	}
+
+	authToken, err := cmd.Flags().GetString(FlagDAMigrationAuthToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagDAMigrationAuthToken, err)
+	}
+
+	cutover, err := cmd.Flags().GetUint64(FlagDAMigrationCutoverHeight)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagDAMigrationCutoverHeight, err)
+	}
+
+	if cutover == 0 {
+		return nil, fmt.Errorf("%s is required with %s", FlagDAMigrationCutoverHeight, FlagDAMigrationAddress)
+	}
+
+	// Blocks below the cutover already on the old network mean the cutover happened
+	included, err := seqda.DAIncludedHeight(ctx, nodeStore(datastore))
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return nil, fmt.Errorf("failed to load DA included height: %w", err)
+	}
+
+	newDA, err := jsonrpc.NewClient(ctx, logger, address, authToken, nodeConfig.DA.GasPrice, nodeConfig.DA.GasMultiplier, rollcmd.DefaultMaxBlobSize)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client of the DA network migrated to: %w", err)
+	}
+
+	migration := seqda.NewMigrationDA(seqda.MigrationConfig{
+		CutoverHeight: cutover,
+		CutOver:       included+1 >= cutover,
+	}, daLayer, &newDA.DA, logger)
+
+	logger.Info().
+		Str("address", address).
+		Uint64("cutover_height", cutover).
+		Bool("cut_over", migration.CutOver()).
+		Msg("🔀 Migrating DA network")
+	return migration, nil
+}
@@ -3,28 +3,32 @@ package main
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
 	"os/signal"
 	"path/filepath"
 	"sync"
-	"syscall"
 	"time"
 
+	"github.com/rs/zerolog"
 	"github.com/spf13/cobra"
 
 	"github.com/evstack/ev-node/core/da"
 	"github.com/evstack/ev-node/da/jsonrpc"
-	"github.com/evstack/ev-node/node"
 	rollcmd "github.com/evstack/ev-node/pkg/cmd"
 	"github.com/evstack/ev-node/pkg/config"
 	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
 	"github.com/evstack/ev-node/pkg/p2p"
 	"github.com/evstack/ev-node/pkg/p2p/key"
-	"github.com/evstack/ev-node/pkg/store"
 	"github.com/evstack/ev-node/sequencers/single"
 
+	"github.com/pranklin/pranklin-sequencer/api"
+	seqda "github.com/pranklin/pranklin-sequencer/da"
 	"github.com/pranklin/pranklin-sequencer/grpc"
+	"github.com/pranklin/pranklin-sequencer/hook"
+	"github.com/pranklin/pranklin-sequencer/nodeconfig"
+	"github.com/pranklin/pranklin-sequencer/supervisor"
 )
 
 const (
@@ -42,6 +46,100 @@ const (
 	FlagExecutionDBPath = "execution-db-path"
 	// FlagBridgeOperators is the flag for bridge operator addresses
 	FlagBridgeOperators = "bridge-operators"
+	// FlagExecutionExternal is the flag for attaching to an execution layer managed outside the node
+	FlagExecutionExternal = "execution.external"
+	// FlagExecutionEnv is the flag for environment variables of the execution subprocess
+	FlagExecutionEnv = "execution.env"
+	// FlagExecutionExtraArgs is the flag for extra arguments of the execution subprocess
+	FlagExecutionExtraArgs = "execution.extra-args"
+	// FlagExecutionWorkdir is the flag for the working directory of the execution subprocess
+	FlagExecutionWorkdir = "execution.workdir"
+	// FlagExecutionMaxOpenFiles is the flag for the open file limit of the execution subprocess
+	FlagExecutionMaxOpenFiles = "execution.max-open-files"
+	// FlagExecutionMaxMemory is the flag for the address space limit of the execution subprocess
+	FlagExecutionMaxMemory = "execution.max-memory"
+	// FlagExecutionNice is the flag for the niceness of the execution subprocess
+	FlagExecutionNice = "execution.nice"
+	// FlagExecutionRestart is the flag for the restart mode of the execution subprocess
+	FlagExecutionRestart = "execution.restart"
+	// FlagExecutionMaxRestarts is the flag for how many times in a row the execution subprocess is restarted
+	FlagExecutionMaxRestarts = "execution.max-restarts"
+	// FlagDAEnv is the flag for environment variables of the local-da subprocess
+	FlagDAEnv = "da.env"
+	// FlagDAExtraArgs is the flag for extra arguments of the local-da subprocess
+	FlagDAExtraArgs = "da.extra-args"
+	// FlagDAWorkdir is the flag for the working directory of the local-da subprocess
+	FlagDAWorkdir = "da.workdir"
+	// FlagDAMaxOpenFiles is the flag for the open file limit of the local-da subprocess
+	FlagDAMaxOpenFiles = "da.max-open-files"
+	// FlagDAMaxMemory is the flag for the address space limit of the local-da subprocess
+	FlagDAMaxMemory = "da.max-memory"
+	// FlagDANice is the flag for the niceness of the local-da subprocess
+	FlagDANice = "da.nice"
+	// FlagDARestart is the flag for the restart mode of the local-da subprocess
+	FlagDARestart = "da.restart"
+	// FlagDAMaxRestarts is the flag for how many times in a row the local-da subprocess is restarted
+	FlagDAMaxRestarts = "da.max-restarts"
+
+	// FlagDABackend is the flag for selecting the DA backend (local, mock)
+	FlagDABackend = "da.backend"
+	// FlagDAEmbedded is the flag for running the local DA in-process instead of spawning local-da
+	FlagDAEmbedded = "da.embedded"
+	// FlagDAMockSubmitLatency is the flag for the mock DA submission latency profile
+	FlagDAMockSubmitLatency = "da.mock.submit-latency"
+	// FlagDAMockRetrieveLatency is the flag for the mock DA retrieval latency profile
+	FlagDAMockRetrieveLatency = "da.mock.retrieve-latency"
+	// FlagDAMockFailureRate is the flag for the mock DA submission failure rate
+	FlagDAMockFailureRate = "da.mock.failure-rate"
+	// FlagDAMockReorgInterval is the flag for the mock DA reorg interval (in submissions)
+	FlagDAMockReorgInterval = "da.mock.reorg-interval"
+	// FlagDAMockReorgDepth is the flag for the mock DA reorg depth (in DA heights)
+	FlagDAMockReorgDepth = "da.mock.reorg-depth"
+	// FlagDAMockSeed is the flag for the mock DA random seed
+	FlagDAMockSeed = "da.mock.seed"
+)
+
+// nodeSection is the config file section holding the unified node settings
+const nodeSection = "pranklin"
+
+// nodeSectionFlags are the unified node flags that can also be set in the nodeSection
+// of the config file, e.g. pranklin.execution_binary for --execution-binary
+var nodeSectionFlags = []string{
+	FlagLocalDABinary,
+	FlagLocalDAPort,
+	FlagExecutionBinary,
+	FlagExecutionGrpcAddr,
+	FlagExecutionRpcAddr,
+	FlagExecutionDBPath,
+	FlagBridgeOperators,
+	FlagExecutionEnv,
+	FlagExecutionExtraArgs,
+	FlagExecutionWorkdir,
+	FlagExecutionMaxOpenFiles,
+	FlagExecutionMaxMemory,
+	FlagExecutionNice,
+	FlagExecutionRestart,
+	FlagExecutionMaxRestarts,
+	FlagDAEnv,
+	FlagDAExtraArgs,
+	FlagDAWorkdir,
+	FlagDAMaxOpenFiles,
+	FlagDAMaxMemory,
+	FlagDANice,
+	FlagDARestart,
+	FlagDAMaxRestarts,
+	FlagHooksPreStart,
+	FlagHooksPostReady,
+	FlagHooksPreShutdown,
+	FlagHooksComponentCrash,
+	FlagHooksTimeout,
+}
+
+const (
+	// DABackendLocal spawns the local-da binary as a subprocess
+	DABackendLocal = "local"
+	// DABackendMock runs an in-process mock DA with programmable failures
+	DABackendMock = "mock"
 )
 
 var NodeCmd = &cobra.Command{
@@ -54,11 +152,21 @@ var NodeCmd = &cobra.Command{
   - Sequencer for consensus and block production
 
 This is similar to how Cosmos nodes embed Tendermint.
-All components run as managed subprocesses with graceful shutdown.`,
+All components run as managed subprocesses with graceful shutdown.
+
+The execution layer can be restarted, e.g. onto an upgraded binary, without stopping
+the sequencer with POST /v1/execution/restart on the API (--api.addr). Block
+production pauses until the new execution layer is ready, which must pick up from its
+persisted state.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
 		ctx, cancel := context.WithCancel(cmd.Context())
 		defer cancel()
 
+		// Take flags not given on the command line from the config file
+		if err := applyNodeSection(cmd); err != nil {
+			return err
+		}
+
 		// Parse flags
 		localDABinary, _ := cmd.Flags().GetString(FlagLocalDABinary)
 		localDAPort, _ := cmd.Flags().GetString(FlagLocalDAPort)
@@ -68,6 +176,48 @@ All components run as managed subprocesses with graceful shutdown.`,
 		executionDBPath, _ := cmd.Flags().GetString(FlagExecutionDBPath)
 		bridgeOperators, _ := cmd.Flags().GetString(FlagBridgeOperators)
 		chainID, _ := cmd.Flags().GetString(rollgenesis.ChainIDFlag)
+		daBackend, _ := cmd.Flags().GetString(FlagDABackend)
+		daEmbedded, _ := cmd.Flags().GetBool(FlagDAEmbedded)
+		executionExternal, _ := cmd.Flags().GetBool(FlagExecutionExternal)
+
+		if daBackend != DABackendLocal && daBackend != DABackendMock {
+			return fmt.Errorf("unknown DA backend: %s (expected %s or %s)", daBackend, DABackendLocal, DABackendMock)
+		}
+
+		execOptions, err := commandOptionsFromFlags(cmd, FlagExecutionEnv, FlagExecutionExtraArgs, FlagExecutionWorkdir)
+		if err != nil {
+			return err
+		}
+		daOptions, err := commandOptionsFromFlags(cmd, FlagDAEnv, FlagDAExtraArgs, FlagDAWorkdir)
+		if err != nil {
+			return err
+		}
+		execLimits, err := limitsFromFlags(cmd, FlagExecutionMaxOpenFiles, FlagExecutionMaxMemory, FlagExecutionNice)
+		if err != nil {
+			return err
+		}
+		daLimits, err := limitsFromFlags(cmd, FlagDAMaxOpenFiles, FlagDAMaxMemory, FlagDANice)
+		if err != nil {
+			return err
+		}
+		execPolicy, err := restartPolicy(cmd, FlagExecutionRestart, FlagExecutionMaxRestarts)
+		if err != nil {
+			return err
+		}
+		daPolicy, err := restartPolicy(cmd, FlagDARestart, FlagDAMaxRestarts)
+		if err != nil {
+			return err
+		}
+
+		mockDAConfig, err := mockDAConfigFromFlags(cmd)
+		if err != nil {
+			return err
+		}
+
+		shutdownCfg, err := shutdownConfigFromFlags(cmd)
+		if err != nil {
+			return err
+		}
 
 		// Parse node configuration
 		nodeConfig, err := rollcmd.ParseConfig(cmd)
@@ -77,168 +227,345 @@ All components run as managed subprocesses with graceful shutdown.`,
 
 		logger := rollcmd.SetupLogger(nodeConfig.Log)
 
+		// Write component logs to rotated files; they are closed once the subprocesses stopped
+		files, err := openLogFiles(cmd, nodeConfig)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = files.Close() }()
+
+		logOutput, err := files.nodeLogOutput(nodeConfig)
+		if err != nil {
+			return err
+		}
+		logger = logger.Output(logOutput)
+
+		// Keep recent logs for the dashboard
+		logger, dashboardLogs, err := captureDashboardLogs(cmd, logger, logOutput)
+		if err != nil {
+			return err
+		}
+
+		if daEmbedded && daBackend != DABackendLocal {
+			return fmt.Errorf("--%s only applies to the %s DA backend", FlagDAEmbedded, DABackendLocal)
+		}
+
 		// Validate binary paths
-		if _, err := exec.LookPath(localDABinary); err != nil {
-			return fmt.Errorf("local-da binary not found: %s\nPlease install it or specify the correct path with --local-da-binary", localDABinary)
+		if daBackend == DABackendLocal && !daEmbedded {
+			if _, err := exec.LookPath(localDABinary); err != nil {
+				return fmt.Errorf("local-da binary not found: %s\nPlease install it or specify the correct path with --local-da-binary", localDABinary)
+			}
 		}
 
 		// Check if execution binary exists (could be absolute or relative path)
-		if _, err := os.Stat(executionBinary); err != nil {
-			// Try to find it in PATH
-			if _, pathErr := exec.LookPath(executionBinary); pathErr != nil {
-				return fmt.Errorf("execution binary not found: %s\nPlease build it first: cd .. && cargo build --release --bin pranklin-app\nOr specify the correct path with --execution-binary", executionBinary)
+		if !executionExternal {
+			if _, err := os.Stat(executionBinary); err != nil {
+				// Try to find it in PATH
+				if _, pathErr := exec.LookPath(executionBinary); pathErr != nil {
+					return fmt.Errorf("execution binary not found: %s\nPlease build it first: cd .. && cargo build --release --bin pranklin-app\nOr specify the correct path with --execution-binary", executionBinary)
+				}
 			}
 		}
 
+		// Refuse to start a second node against the same root dir
+		nodeLock, err := acquireNodeLock(nodeConfig)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = nodeLock.Remove() }()
+
+		// Clean up after a previous crash before starting any subprocess
+		if err := checkDatastoreLock(nodeConfig); err != nil {
+			return err
+		}
+
+		if err := cleanupOrphans(cmd, logger, nodeConfig); err != nil {
+			return err
+		}
+
+		// Run operator hooks around node events
+		hooks, err := hookRunner(cmd, logger, chainID)
+		if err != nil {
+			return err
+		}
+		if err := hooks.Run(ctx, hook.Context{Event: hook.PreStart}); err != nil {
+			return fmt.Errorf("pre-start hooks failed: %w", err)
+		}
+
 		logger.Info().Msg("🚀 Starting Pranklin Unified Node")
 		logger.Info().Msg("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
 		// Setup signal handling for graceful shutdown
 		sigChan := make(chan os.Signal, 1)
-		signal.Notify(sigChan, syscall.SIGINT, syscall.SIGTERM)
+		signal.Notify(sigChan, shutdownSignals...)
+
+		// Subprocesses outlive the sequencer context so they are only stopped after it,
+		// each within its own timeout
+		procCtx, cancelProcs := context.WithCancel(cmd.Context())
+		defer cancelProcs()
+
+		// managedProcess is a supervised subprocess and how long it is given to stop
+		type managedProcess struct {
+			proc        *supervisor.Process
+			stopTimeout time.Duration
+		}
 
 		// Track all subprocesses
 		var wg sync.WaitGroup
 		var mu sync.Mutex
-		processes := make([]*exec.Cmd, 0)
-		errChan := make(chan error, 3)
+		processes := make([]managedProcess, 0)
+		errChan := make(chan error, 4)
 
-		// Cleanup function
+		// Cleanup function; subprocesses stop in reverse start order, execution before DA
 		cleanup := func() {
 			logger.Info().Msg("🛑 Shutting down all components...")
 			mu.Lock()
 			defer mu.Unlock()
 
 			for i := len(processes) - 1; i >= 0; i-- {
-				proc := processes[i]
-				if proc != nil && proc.Process != nil {
-					logger.Info().Int("pid", proc.Process.Pid).Msg("Stopping process")
-					_ = proc.Process.Signal(syscall.SIGTERM)
-
-					// Wait for graceful shutdown with timeout
-					done := make(chan error, 1)
-					go func() {
-						done <- proc.Wait()
-					}()
-
-					select {
-					case <-done:
-						logger.Info().Int("pid", proc.Process.Pid).Msg("Process stopped gracefully")
-					case <-time.After(5 * time.Second):
-						logger.Warn().Int("pid", proc.Process.Pid).Msg("Force killing process")
-						_ = proc.Process.Kill()
-					}
-				}
+				processes[i].proc.Stop(processes[i].stopTimeout)
 			}
 		}
 
-		// Start Local DA
-		logger.Info().Str("binary", localDABinary).Str("port", localDAPort).Msg("📦 Starting Local DA layer...")
-		daCmd := exec.CommandContext(ctx, localDABinary, "-port", localDAPort)
-		daCmd.Stdout = os.Stdout
-		daCmd.Stderr = os.Stderr
+		// superviseProcess restarts proc when it crashes; the node shuts down once it gives up
+		superviseProcess := func(proc *supervisor.Process, stopTimeout time.Duration) {
+			proc.OnExit(func(err error) {
+				hooks.Go(procCtx, hook.Context{Event: hook.ComponentCrash, Component: proc.Name(), Error: errorString(err)})
+			})
 
-		if err := daCmd.Start(); err != nil {
-			return fmt.Errorf("failed to start Local DA: %w", err)
+			mu.Lock()
+			processes = append(processes, managedProcess{proc: proc, stopTimeout: stopTimeout})
+			mu.Unlock()
+
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				if err := proc.Supervise(procCtx); err != nil {
+					errChan <- err
+				}
+			}()
 		}
 
-		mu.Lock()
-		processes = append(processes, daCmd)
-		mu.Unlock()
-
-		logger.Info().Int("pid", daCmd.Process.Pid).Msg("✅ Local DA started")
+		// Subprocesses share the restart metrics
+		supervisorMetrics, err := supervisor.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+		if err != nil {
+			return fmt.Errorf("failed to create supervisor metrics: %w", err)
+		}
 
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if err := daCmd.Wait(); err != nil {
-				logger.Error().Err(err).Msg("Local DA exited with error")
-				errChan <- fmt.Errorf("Local DA failed: %w", err)
+		// Start Local DA
+		if daBackend == DABackendLocal && !daEmbedded {
+			logger.Info().Str("binary", localDABinary).Str("port", localDAPort).Msg("📦 Starting Local DA layer...")
+			daStdout, daStderr, err := subprocessOutput(cmd, logger, files, "local-da", daLogFile)
+			if err != nil {
+				return err
 			}
-		}()
 
-		// Wait for DA to be ready
-		time.Sleep(2 * time.Second)
+			daCommand := func(ctx context.Context) *exec.Cmd {
+				daCmd := exec.CommandContext(ctx, localDABinary, "-port", localDAPort)
+				daCmd.Stdout = daStdout
+				daCmd.Stderr = daStderr
+				daOptions.Apply(daCmd)
+				supervisor.SetProcessGroup(daCmd)
+				return daCmd
+			}
 
-		// Start Execution layer
-		logger.Info().
-			Str("binary", executionBinary).
-			Str("grpc", executionGrpcAddr).
-			Str("rpc", executionRpcAddr).
-			Msg("⚙️  Starting Execution layer...")
+			// The DA is ready once it serves JSON-RPC
+			daProc := newSubprocess(cmd, logger, nodeConfig, supervisorMetrics, daPolicy, "local-da", daCommand, jsonRPCProbe(fmt.Sprintf("http://127.0.0.1:%s", localDAPort)))
+			daProc.SetLimits(daLimits)
 
-		execArgs := []string{
-			"start",
-			"--grpc.addr", executionGrpcAddr,
-			"--rpc.addr", executionRpcAddr,
-			"--db.path", executionDBPath,
-			"--chain.id", chainID,
+			if err := daProc.Start(procCtx); err != nil {
+				return err
+			}
+			superviseProcess(daProc, shutdownCfg.da)
+			logger.Info().Msg("✅ Local DA started")
 		}
 
-		if bridgeOperators != "" {
-			execArgs = append(execArgs, "--bridge.operators", bridgeOperators)
+		// Load genesis
+		genesis, err := rollgenesis.LoadGenesis(rollgenesis.GenesisPath(nodeConfig.RootDir))
+		if err != nil {
+			cleanup()
+			return err
 		}
 
-		execCmd := exec.CommandContext(ctx, executionBinary, execArgs...)
-		execCmd.Stdout = os.Stdout
-		execCmd.Stderr = os.Stderr
+		if genesis.DAStartHeight == 0 && !nodeConfig.Node.Aggregator {
+			logger.Warn().Msg("da_start_height is not set in genesis.json")
+		}
 
-		if err := execCmd.Start(); err != nil {
+		// Create gRPC execution client, configured before anything uses it
+		executor, err := executionClient(cmd, "http://"+executionGrpcAddr)
+		if err != nil {
+			cleanup()
+			return err
+		}
+		execMetrics, err := executorMetrics(nodeConfig, genesis.ChainID)
+		if err != nil {
 			cleanup()
-			return fmt.Errorf("failed to start Execution layer: %w", err)
+			return err
+		}
+		// Submissions through the API are timed until the block including them is executed
+		latency := api.NewLatencyTracker()
+		if err := configureExecutor(cmd, logger, executor, latency, execMetrics); err != nil {
+			cleanup()
+			return err
 		}
 
-		mu.Lock()
-		processes = append(processes, execCmd)
-		mu.Unlock()
+		// Block production waits while the execution layer is restarted
+		pausableExecutor := grpc.NewPauseExecutor(executor)
+		var restartExecution func() error
 
-		logger.Info().Int("pid", execCmd.Process.Pid).Msg("✅ Execution layer started")
+		// Output and disk usage of the execution layer are only visible when it is managed here
+		var execEvents func() []api.HealthEvent
+		diskPaths := []string{nodeConfig.RootDir}
 
-		wg.Add(1)
-		go func() {
-			defer wg.Done()
-			if err := execCmd.Wait(); err != nil {
-				logger.Error().Err(err).Msg("Execution layer exited with error")
-				errChan <- fmt.Errorf("Execution layer failed: %w", err)
+		if executionExternal {
+			// The execution layer is managed elsewhere, e.g. by systemd or k8s
+			logger.Info().Str("grpc", executionGrpcAddr).Msg("🔗 Attaching to external Execution layer...")
+			if err := waitReady(ctx, cmd, logger, "execution", nil, executorProbe(executor)); err != nil {
+				cleanup()
+				return err
+			}
+			logger.Info().Msg("✅ Execution layer attached")
+		} else {
+			// Start Execution layer
+			logger.Info().
+				Str("binary", executionBinary).
+				Str("grpc", executionGrpcAddr).
+				Str("rpc", executionRpcAddr).
+				Msg("⚙️  Starting Execution layer...")
+
+			execArgs := []string{
+				"start",
+				"--grpc.addr", executionGrpcAddr,
+				"--rpc.addr", executionRpcAddr,
+				"--db.path", executionDBPath,
+				"--chain.id", chainID,
 			}
-		}()
 
-		// Wait for Execution to be ready
-		time.Sleep(3 * time.Second)
+			if bridgeOperators != "" {
+				execArgs = append(execArgs, "--bridge.operators", bridgeOperators)
+			}
 
-		// Create gRPC execution client
-		logger.Info().Msg("🔗 Connecting to Execution layer...")
-		executor := grpc.NewClient("http://" + executionGrpcAddr)
+			// Scrape known problem patterns from the output until the execution layer reports health over RPC
+			execScanner, err := newExecutionScanner(logger, nodeConfig, chainID)
+			if err != nil {
+				cleanup()
+				return err
+			}
 
-		// Setup DA client
-		daAddress := fmt.Sprintf("http://127.0.0.1:%s", localDAPort)
-		logger.Info().Str("address", daAddress).Msg("🔗 Connecting to Local DA...")
+			execStdout, execStderr, err := subprocessOutput(cmd, logger, files, "execution", executionLogFile)
+			if err != nil {
+				cleanup()
+				return err
+			}
+
+			execCommand := func(ctx context.Context) *exec.Cmd {
+				execCmd := exec.CommandContext(ctx, executionBinary, execArgs...)
+				execCmd.Stdout = io.MultiWriter(execStdout, execScanner)
+				execCmd.Stderr = io.MultiWriter(execStderr, execScanner)
+				execOptions.Apply(execCmd)
+				supervisor.SetProcessGroup(execCmd)
+				return execCmd
+			}
+
+			logger.Info().Msg("🔗 Connecting to Execution layer...")
+
+			// Execution is ready once it serves gRPC
+			execProc := newSubprocess(cmd, logger, nodeConfig, supervisorMetrics, execPolicy, "execution", execCommand, executorProbe(executor))
+			execProc.SetLimits(execLimits)
+
+			if err := execProc.Start(procCtx); err != nil {
+				cleanup()
+				return err
+			}
+			superviseProcess(execProc, shutdownCfg.execution)
+			logger.Info().Msg("✅ Execution layer started")
+
+			// Replace the execution subprocess, e.g. after an upgrade of its binary,
+			// without stopping the sequencer
+			var restarting sync.Mutex
+			restartExecution = func() error {
+				if !restarting.TryLock() {
+					return fmt.Errorf("execution: %w", supervisor.ErrReplacing)
+				}
+				defer restarting.Unlock()
+
+				pausableExecutor.Pause()
+				defer pausableExecutor.Resume()
+
+				logger.Info().Msg("⏸️  Block production paused, restarting Execution layer...")
+				if err := execProc.Replace(procCtx, shutdownCfg.execution); err != nil {
+					logger.Error().Err(err).Msg("Failed to restart Execution layer")
+					return err
+				}
+				logger.Info().Msg("▶️  Execution layer restarted, resuming block production")
+				return nil
+			}
+
+			execEvents = healthEvents(execScanner)
+			diskPaths = append(diskPaths, executionDBPath)
+		}
 
 		headerNamespace := da.NamespaceFromString(nodeConfig.DA.GetNamespace())
 		dataNamespace := da.NamespaceFromString(nodeConfig.DA.GetDataNamespace())
 
-		daJrpc, err := jsonrpc.NewClient(ctx, logger, daAddress, "", nodeConfig.DA.GasPrice, nodeConfig.DA.GasMultiplier, rollcmd.DefaultMaxBlobSize)
-		if err != nil {
-			cleanup()
-			return fmt.Errorf("failed to create DA client: %w", err)
+		// Setup DA client
+		var daLayer da.DA
+		var daAddress string
+		switch {
+		case daBackend == DABackendMock:
+			daAddress = "in-process mock"
+			logger.Info().
+				Float64("failure_rate", mockDAConfig.SubmitFailureRate).
+				Uint64("reorg_interval", mockDAConfig.ReorgInterval).
+				Uint64("reorg_depth", mockDAConfig.ReorgDepth).
+				Msg("🧪 Starting in-process mock DA...")
+
+			mockDA := seqda.NewMockDA(rollcmd.DefaultMaxBlobSize, nodeConfig.DA.GasPrice, nodeConfig.DA.GasMultiplier, nodeConfig.DA.BlockTime.Duration, mockDAConfig)
+			mockDA.Start()
+			defer mockDA.Stop()
+			daLayer = mockDA
+		case daEmbedded:
+			daAddress = fmt.Sprintf("in-process, served at http://127.0.0.1:%s", localDAPort)
+			logger.Info().Str("port", localDAPort).Msg("📦 Starting embedded Local DA...")
+
+			localDA, err := startEmbeddedDA(procCtx, logger, nodeConfig, localDAPort)
+			if err != nil {
+				cleanup()
+				return err
+			}
+			defer localDA.Stop()
+			daLayer = localDA
+		default:
+			daAddress = fmt.Sprintf("http://127.0.0.1:%s", localDAPort)
+			logger.Info().Str("address", daAddress).Msg("🔗 Connecting to Local DA...")
+
+			daJrpc, err := jsonrpc.NewClient(ctx, logger, daAddress, "", nodeConfig.DA.GasPrice, nodeConfig.DA.GasMultiplier, rollcmd.DefaultMaxBlobSize)
+			if err != nil {
+				cleanup()
+				return fmt.Errorf("failed to create DA client: %w", err)
+			}
+			daLayer = &daJrpc.DA
 		}
 
 		// Create datastore
-		datastore, err := store.NewDefaultKVStore(nodeConfig.RootDir, nodeConfig.DBPath, "pranklin-sequencer")
+		datastore, err := openDatastore(nodeConfig)
 		if err != nil {
 			cleanup()
 			return err
 		}
 
-		// Load genesis
-		genesis, err := rollgenesis.LoadGenesis(rollgenesis.GenesisPath(nodeConfig.RootDir))
+		// Move block submission to another DA network if configured
+		daLayer, err = migrateDA(ctx, cmd, logger, daLayer, datastore, nodeConfig)
 		if err != nil {
 			cleanup()
 			return err
 		}
 
-		if genesis.DAStartHeight == 0 && !nodeConfig.Node.Aggregator {
-			logger.Warn().Msg("da_start_height is not set in genesis.json")
+		// Load feature flags before the configuration is used
+		features, err := loadFeatureFlags(cmd, logger, &nodeConfig, genesis.ChainID)
+		if err != nil {
+			cleanup()
+			return err
 		}
 
 		// Create metrics provider
@@ -254,7 +581,7 @@ All components run as managed subprocesses with graceful shutdown.`,
 			ctx,
 			logger,
 			datastore,
-			&daJrpc.DA,
+			daLayer,
 			[]byte(genesis.ChainID),
 			nodeConfig.Node.BlockTime.Duration,
 			singleMetrics,
@@ -265,6 +592,45 @@ All components run as managed subprocesses with graceful shutdown.`,
 			return err
 		}
 
+		// Start DA blob integrity verifier
+		if err := startDAVerifier(ctx, cmd, logger, datastore, daLayer, nodeConfig, genesis.ChainID); err != nil {
+			cleanup()
+			return err
+		}
+
+		// Start sequencer HTTP API
+		if err := startAPIServer(ctx, cmd, logger, datastore, daLayer, latency, features, restartExecution, nodeConfig, genesis.ChainID); err != nil {
+			cleanup()
+			return err
+		}
+
+		// Start telemetry dashboard
+		if err := startDashboard(ctx, cmd, logger, datastore, dashboardLogs, execEvents, mempoolStatus(executor), nodeConfig); err != nil {
+			cleanup()
+			return err
+		}
+
+		// Start disk usage monitor; halting is reported like a component failure
+		haltNode := func(err error) {
+			select {
+			case errChan <- err:
+			default:
+			}
+		}
+		if err := startDiskMonitor(ctx, cmd, logger, datastore, diskPaths, nodeConfig, genesis.ChainID, haltNode); err != nil {
+			cleanup()
+			return err
+		}
+
+		// Start configuration drift detector
+		if err := startDriftDetector(ctx, cmd, logger, nodeConfig, genesis.ChainID); err != nil {
+			cleanup()
+			return err
+		}
+
+		// Report the execution layer mempool backlog
+		startMempoolReporter(ctx, executor, execMetrics)
+
 		// Load node key
 		nodeKey, err := key.LoadNodeKey(filepath.Dir(nodeConfig.ConfigPath()))
 		if err != nil {
@@ -279,6 +645,30 @@ All components run as managed subprocesses with graceful shutdown.`,
 			return err
 		}
 
+		// Fail calls right away while the execution layer is down
+		breakerExecutor, err := breakExecutor(cmd, logger, pausableExecutor, execMetrics)
+		if err != nil {
+			cleanup()
+			return err
+		}
+
+		// Keep slow mempool scans from taking the whole block slot
+		budgetExecutor, err := budgetGetTxs(cmd, logger, breakerExecutor, features, execMetrics, nodeConfig)
+		if err != nil {
+			cleanup()
+			return err
+		}
+
+		// Profile the block loop stages
+		profiledExecutor, nodeDA, err := profileBlockLoop(cmd, logger, budgetExecutor, daLayer, nodeConfig, genesis.ChainID)
+		if err != nil {
+			cleanup()
+			return err
+		}
+
+		// Stop taking transactions first on shutdown
+		nodeExecutor := grpc.NewDrainExecutor(profiledExecutor)
+
 		logger.Info().Msg("✅ Sequencer initialized")
 		logger.Info().Msg("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 		logger.Info().Str("DA", daAddress).Str("Execution gRPC", executionGrpcAddr).Str("Execution RPC", executionRpcAddr).Msg("📡 Component addresses")
@@ -287,25 +677,55 @@ All components run as managed subprocesses with graceful shutdown.`,
 		logger.Info().Msg("Press Ctrl+C to stop")
 		logger.Info().Msg("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
 
-		// Start the node in a goroutine
-		wg.Add(1)
+		// Start the node in a goroutine; it stops when ctx is cancelled
+		sequencerDone := make(chan struct{})
 		go func() {
-			defer wg.Done()
-			if err := rollcmd.StartNode(logger, cmd, executor, sequencer, &daJrpc.DA, p2pClient, datastore, nodeConfig, genesis, node.NodeOptions{}); err != nil {
+			defer close(sequencerDone)
+			if err := runSequencer(ctx, cmd, logger, nodeExecutor, sequencer, nodeDA, p2pClient, datastore, nodeConfig, genesis); err != nil {
 				logger.Error().Err(err).Msg("Sequencer failed")
+				hooks.Go(procCtx, hook.Context{Event: hook.ComponentCrash, Component: "sequencer", Error: err.Error()})
 				errChan <- fmt.Errorf("Sequencer failed: %w", err)
 			}
 		}()
 
+		hooks.Go(ctx, hook.Context{Event: hook.PostReady})
+
+		// stopSequencer stops the sequencer before the subprocesses it depends on
+		stopSequencer := func() {
+			cancel()
+			select {
+			case <-sequencerDone:
+			case <-time.After(shutdownCfg.sequencer):
+				logger.Warn().Dur("timeout", shutdownCfg.sequencer).Msg("Sequencer did not stop in time")
+			}
+		}
+
 		// Wait for shutdown signal or error
 		select {
 		case sig := <-sigChan:
 			logger.Info().Str("signal", sig.String()).Msg("Received shutdown signal")
-			cancel()
+			_ = hooks.Run(ctx, hook.Context{Event: hook.PreShutdown, Reason: "signal " + sig.String()})
+
+			// A second signal, or the drain timeout, ends the drain
+			drainCtx, stopDrain := context.WithTimeout(ctx, shutdownCfg.drain)
+			go func() {
+				select {
+				case <-sigChan:
+					stopDrain()
+				case <-drainCtx.Done():
+				}
+			}()
+			if shutdownCfg.drain > 0 {
+				drainSequencer(drainCtx, logger, nodeStore(datastore), nodeExecutor, nodeConfig.Node.BlockTime.Duration)
+			}
+			stopDrain()
+
+			stopSequencer()
 			cleanup()
 		case err := <-errChan:
 			logger.Error().Err(err).Msg("Component failed, shutting down")
-			cancel()
+			_ = hooks.Run(ctx, hook.Context{Event: hook.PreShutdown, Reason: err.Error()})
+			stopSequencer()
 			cleanup()
 			return err
 		}
@@ -323,12 +743,156 @@ func init() {
 	config.AddFlags(NodeCmd)
 
 	// Add unified node specific flags
-	NodeCmd.Flags().String(FlagLocalDABinary, "local-da", "Path to local-da binary")
-	NodeCmd.Flags().String(FlagLocalDAPort, "7980", "Port for local-da")
-	NodeCmd.Flags().String(FlagExecutionBinary, "../target/release/pranklin-app", "Path to pranklin-app execution binary")
-	NodeCmd.Flags().String(FlagExecutionGrpcAddr, "0.0.0.0:50051", "Execution layer gRPC address")
-	NodeCmd.Flags().String(FlagExecutionRpcAddr, "0.0.0.0:3000", "Execution layer RPC address")
-	NodeCmd.Flags().String(FlagExecutionDBPath, "./data/pranklin_db", "Execution layer database path")
-	NodeCmd.Flags().String(FlagBridgeOperators, "", "Bridge operator addresses (comma-separated)")
+	addNodeSectionFlags(NodeCmd)
+	NodeCmd.Flags().Bool(FlagExecutionExternal, false, "Connect to an execution layer already serving on --execution-grpc-addr instead of spawning --execution-binary")
 	NodeCmd.Flags().String(rollgenesis.ChainIDFlag, "pranklin-mainnet-1", "Chain ID for execution layer")
+
+	// Add DA backend flags
+	NodeCmd.Flags().String(FlagDABackend, DABackendLocal, "DA backend to use (local, mock)")
+	NodeCmd.Flags().Bool(FlagDAEmbedded, false, "Run the local DA in-process and serve it on --local-da-port instead of spawning the local-da binary")
+	NodeCmd.Flags().String(FlagDAMockSubmitLatency, "none", "Mock DA submission latency (none, fixed:50ms, uniform:10ms-200ms, normal:100ms,30ms)")
+	NodeCmd.Flags().String(FlagDAMockRetrieveLatency, "none", "Mock DA retrieval latency (none, fixed:50ms, uniform:10ms-200ms, normal:100ms,30ms)")
+	NodeCmd.Flags().Float64(FlagDAMockFailureRate, 0, "Probability (0..1) that a mock DA submission fails")
+	NodeCmd.Flags().Uint64(FlagDAMockReorgInterval, 0, "Trigger a mock DA reorg every N successful submissions (0 disables reorgs)")
+	NodeCmd.Flags().Uint64(FlagDAMockReorgDepth, 1, "Number of most recent DA heights dropped by a mock DA reorg")
+	NodeCmd.Flags().Uint64(FlagDAMockSeed, 0, "Random seed for mock DA latency and failures")
+
+	// Add DA verifier flags
+	addDAVerifierFlags(NodeCmd)
+
+	// Add DA migration flags
+	addDAMigrationFlags(NodeCmd)
+
+	// Add API flags
+	addAPIFlags(NodeCmd)
+
+	// Add profiling flags
+	addProfileFlags(NodeCmd)
+
+	// Add supervisor flags
+	addSupervisorFlags(NodeCmd)
+
+	// Add disk monitor flags
+	addDiskFlags(NodeCmd)
+
+	// Add dashboard flags
+	addDashboardFlags(NodeCmd)
+
+	// Add drift detection flags
+	addDriftFlags(NodeCmd)
+
+	// Add feature flags
+	addFeatureFlags(NodeCmd)
+
+	// Add executor flags
+	addExecutorFlags(NodeCmd)
+	addExecutorTLSFlags(NodeCmd)
+	addExecutorSamplingFlags(NodeCmd)
+	addExecutorRetryFlags(NodeCmd)
+	addExecutorTimeoutFlags(NodeCmd)
+	addExecutorTracingFlags(NodeCmd)
+	addExecutorCompressionFlags(NodeCmd)
+	addExecutorStreamFlags(NodeCmd)
+	addExecutorBreakerFlags(NodeCmd)
+
+	// Add log file flags
+	addLogFileFlags(NodeCmd)
+
+	// Add shutdown flags
+	addShutdownFlags(NodeCmd)
+}
+
+// addNodeSectionFlags adds the unified node flags that can also be set in the config file
+func addNodeSectionFlags(cmd *cobra.Command) {
+	cmd.Flags().String(FlagLocalDABinary, "local-da", "Path to local-da binary")
+	cmd.Flags().String(FlagLocalDAPort, "7980", "Port for local-da")
+	cmd.Flags().String(FlagExecutionBinary, "../target/release/pranklin-app", "Path to pranklin-app execution binary")
+	cmd.Flags().String(FlagExecutionGrpcAddr, "0.0.0.0:50051", "Execution layer gRPC address")
+	cmd.Flags().String(FlagExecutionRpcAddr, "0.0.0.0:3000", "Execution layer RPC address")
+	cmd.Flags().String(FlagExecutionDBPath, "./data/pranklin_db", "Execution layer database path")
+	cmd.Flags().String(FlagBridgeOperators, "", "Bridge operator addresses (comma-separated)")
+	cmd.Flags().StringArray(FlagExecutionEnv, nil, "Environment variable KEY=VALUE set on the execution subprocess, e.g. RUST_LOG=info (repeatable)")
+	cmd.Flags().StringArray(FlagExecutionExtraArgs, nil, "Argument appended to the execution subprocess command line (repeatable)")
+	cmd.Flags().String(FlagExecutionWorkdir, "", "Working directory of the execution subprocess; relative paths passed to it, e.g. --execution-db-path, resolve against it")
+	cmd.Flags().Uint64(FlagExecutionMaxOpenFiles, 0, "Maximum number of open files of the execution subprocess (0 inherits the node's limit)")
+	cmd.Flags().Uint64(FlagExecutionMaxMemory, 0, "Maximum virtual memory of the execution subprocess in bytes, enforced as RLIMIT_AS (0 inherits the node's limit)")
+	cmd.Flags().Int(FlagExecutionNice, 0, "Niceness of the execution subprocess, from -20 to 19; negative values need privileges (0 inherits the node's)")
+	cmd.Flags().String(FlagExecutionRestart, string(supervisor.RestartAlways), fmt.Sprintf("When the execution subprocess is restarted after it exits: %s, %s (non-zero exit or signal) or %s; the node shuts down once it is not", supervisor.RestartAlways, supervisor.RestartOnFailure, supervisor.RestartNever))
+	cmd.Flags().Int(FlagExecutionMaxRestarts, -1, "Number of times in a row the execution subprocess is restarted before the node shuts down (-1 uses --"+FlagSupervisorMaxRestarts+")")
+	cmd.Flags().StringArray(FlagDAEnv, nil, "Environment variable KEY=VALUE set on the local-da subprocess (repeatable)")
+	cmd.Flags().StringArray(FlagDAExtraArgs, nil, "Argument appended to the local-da subprocess command line (repeatable)")
+	cmd.Flags().String(FlagDAWorkdir, "", "Working directory of the local-da subprocess")
+	cmd.Flags().Uint64(FlagDAMaxOpenFiles, 0, "Maximum number of open files of the local-da subprocess (0 inherits the node's limit)")
+	cmd.Flags().Uint64(FlagDAMaxMemory, 0, "Maximum virtual memory of the local-da subprocess in bytes, enforced as RLIMIT_AS (0 inherits the node's limit)")
+	cmd.Flags().Int(FlagDANice, 0, "Niceness of the local-da subprocess, from -20 to 19; negative values need privileges (0 inherits the node's)")
+	cmd.Flags().String(FlagDARestart, string(supervisor.RestartAlways), fmt.Sprintf("When the local-da subprocess is restarted after it exits: %s, %s (non-zero exit or signal) or %s; the node shuts down once it is not", supervisor.RestartAlways, supervisor.RestartOnFailure, supervisor.RestartNever))
+	cmd.Flags().Int(FlagDAMaxRestarts, -1, "Number of times in a row the local-da subprocess is restarted before the node shuts down (-1 uses --"+FlagSupervisorMaxRestarts+")")
+	addHookFlags(cmd)
+}
+
+// applyNodeSection sets the unified node flags not given on the command line or from
+// the environment from the nodeSection of the config file.
+func applyNodeSection(cmd *cobra.Command) error {
+	// config.Load applies the environment to flags first, so it takes precedence over the file
+	cfg, err := config.Load(cmd)
+	if err != nil {
+		return err
+	}
+	return nodeconfig.ApplySection(cmd, cfg.ConfigPath(), nodeSection, nodeSectionFlags)
+}
+
+// mockDAConfigFromFlags builds the mock DA configuration from command flags
+func mockDAConfigFromFlags(cmd *cobra.Command) (seqda.MockConfig, error) {
+	submitLatency, _ := cmd.Flags().GetString(FlagDAMockSubmitLatency)
+	retrieveLatency, _ := cmd.Flags().GetString(FlagDAMockRetrieveLatency)
+	failureRate, _ := cmd.Flags().GetFloat64(FlagDAMockFailureRate)
+	reorgInterval, _ := cmd.Flags().GetUint64(FlagDAMockReorgInterval)
+	reorgDepth, _ := cmd.Flags().GetUint64(FlagDAMockReorgDepth)
+	seed, _ := cmd.Flags().GetUint64(FlagDAMockSeed)
+
+	if failureRate < 0 || failureRate > 1 {
+		return seqda.MockConfig{}, fmt.Errorf("%s must be between 0 and 1, got %v", FlagDAMockFailureRate, failureRate)
+	}
+
+	submitProfile, err := seqda.ParseLatencyProfile(submitLatency)
+	if err != nil {
+		return seqda.MockConfig{}, fmt.Errorf("invalid %s: %w", FlagDAMockSubmitLatency, err)
+	}
+
+	retrieveProfile, err := seqda.ParseLatencyProfile(retrieveLatency)
+	if err != nil {
+		return seqda.MockConfig{}, fmt.Errorf("invalid %s: %w", FlagDAMockRetrieveLatency, err)
+	}
+
+	return seqda.MockConfig{
+		SubmitLatency:     submitProfile,
+		RetrieveLatency:   retrieveProfile,
+		SubmitFailureRate: failureRate,
+		ReorgInterval:     reorgInterval,
+		ReorgDepth:        reorgDepth,
+		Seed:              seed,
+	}, nil
+}
+
+// startEmbeddedDA runs an in-memory DA in this process and serves it over JSON-RPC on
+// port, like local-da, so other nodes of a dev setup can follow it. The sequencer uses
+// the returned DA directly. The server is stopped when ctx is cancelled.
+func startEmbeddedDA(ctx context.Context, logger zerolog.Logger, nodeConfig config.Config, port string) (*seqda.MockDA, error) {
+	localDA := seqda.NewMockDA(rollcmd.DefaultMaxBlobSize, nodeConfig.DA.GasPrice, nodeConfig.DA.GasMultiplier, nodeConfig.DA.BlockTime.Duration, seqda.MockConfig{})
+	localDA.Start()
+
+	server := jsonrpc.NewServer(logger.With().Str("component", "local-da").Logger(), "127.0.0.1", port, localDA)
+	if err := server.Start(ctx); err != nil {
+		localDA.Stop()
+		return nil, fmt.Errorf("failed to serve embedded DA on port %s: %w", port, err)
+	}
+
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = server.Stop(shutdownCtx)
+	}()
+
+	return localDA, nil
 }
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/profile"
+)
+
+const (
+	// FlagProfileBlockMemBudget is the flag for the per-block memory allocation budget
+	FlagProfileBlockMemBudget = "profile.block-mem-budget"
+	// FlagProfileBlocks is the flag for the number of slowest blocks whose stage breakdown is logged
+	FlagProfileBlocks = "profile.blocks"
+)
+
+// addProfileFlags adds flags for block loop profiling
+func addProfileFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64(FlagProfileBlockMemBudget, 0, "Bytes a block may allocate across GetTxs/ExecuteTxs/DA submission before an alert is logged (0 disables the budget)")
+	cmd.Flags().Int(FlagProfileBlocks, 0, "Log a per-stage breakdown whenever a block is among the N slowest seen so far (0 disables the log)")
+}
+
+// profileBlockLoop wraps the executor and DA layer so the block loop stages are profiled,
+// unless profiling is disabled by flags and metrics are off.
+func profileBlockLoop(cmd *cobra.Command, logger zerolog.Logger, executor execution.Executor, daLayer da.DA, nodeConfig config.Config, chainID string) (execution.Executor, da.DA, error) {
+	budget, err := cmd.Flags().GetUint64(FlagProfileBlockMemBudget)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get '%s' flag: %w", FlagProfileBlockMemBudget, err)
+	}
+
+	slowest, err := cmd.Flags().GetInt(FlagProfileBlocks)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get '%s' flag: %w", FlagProfileBlocks, err)
+	}
+
+	if slowest < 0 {
+		return nil, nil, fmt.Errorf("%s must be >= 0", FlagProfileBlocks)
+	}
+
+	// Without a budget, a slowest block log or metrics nothing reads the profiles
+	prometheus := nodeConfig.Instrumentation.IsPrometheusEnabled()
+	if budget == 0 && slowest == 0 && !prometheus {
+		return executor, daLayer, nil
+	}
+
+	metrics, err := profile.DefaultMetricsProvider(prometheus)(chainID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	tracker := profile.NewTracker(profile.Config{
+		MemBudget:     budget,
+		SlowestBlocks: slowest,
+	}, logger, metrics)
+	return profile.NewExecutor(executor, tracker), profile.NewDA(daLayer, tracker), nil
+}
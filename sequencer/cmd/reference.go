@@ -0,0 +1,133 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os/signal"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+	"github.com/evstack/ev-node/pkg/config"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+	"github.com/pranklin/pranklin-sequencer/reference"
+)
+
+const (
+	// FlagReferenceGrpcAddr is the flag for the ExecutorService address of the reference executor
+	FlagReferenceGrpcAddr = "grpc.addr"
+	// FlagReferenceRpcAddr is the flag for the transaction RPC address of the reference executor
+	FlagReferenceRpcAddr = "rpc.addr"
+	// FlagReferenceAlloc is the flag for the genesis balances of the reference executor
+	FlagReferenceAlloc = "alloc"
+)
+
+// referenceIgnoredFlags are flags the unified node passes to the execution binary that
+// do not apply to the in-memory reference executor
+var referenceIgnoredFlags = []string{"db.path", "chain.id", "bridge.operators"}
+
+// ReferenceExecutorCmd returns the command running the Go reference executor
+func ReferenceExecutorCmd() *cobra.Command {
+	referenceCmd := &cobra.Command{
+		Use:   "reference-executor",
+		Short: "Run the minimal Go reference execution layer",
+	}
+
+	startCmd := &cobra.Command{
+		Use:   "start",
+		Short: "Serve the reference executor over the ExecutorService",
+		Long: `Serve an in-memory execution layer with quote balance transfers and a single limit
+order book over the ExecutorService, for conformance tests and demos.
+
+Transactions are JSON and submitted with POST /tx on the RPC address, e.g.
+
+  {"type":"transfer","from":"alice","to":"bob","amount":10}
+  {"type":"order","account":"alice","side":"buy","price":100,"quantity":2}
+
+GET /state returns the current state. The state is lost when the executor stops, so the
+sequencer it serves must start from genesis as well.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			grpcAddr, _ := cmd.Flags().GetString(FlagReferenceGrpcAddr)
+			rpcAddr, _ := cmd.Flags().GetString(FlagReferenceRpcAddr)
+			allocFlag, _ := cmd.Flags().GetStringSlice(FlagReferenceAlloc)
+
+			alloc, err := parseAlloc(allocFlag)
+			if err != nil {
+				return err
+			}
+
+			logger := rollcmd.SetupLogger(config.DefaultConfig().Log)
+			executor := reference.NewExecutor(alloc, logger)
+
+			ctx, stop := signal.NotifyContext(cmd.Context(), shutdownSignals...)
+			defer stop()
+
+			servers := []*http.Server{
+				{Addr: grpcAddr, Handler: grpc.NewExecutorServiceHandler(executor), ReadHeaderTimeout: 5 * time.Second},
+				{Addr: rpcAddr, Handler: reference.NewHandler(executor), ReadHeaderTimeout: 5 * time.Second},
+			}
+
+			errCh := make(chan error, len(servers))
+			for _, srv := range servers {
+				listener, err := net.Listen("tcp", srv.Addr)
+				if err != nil {
+					return err
+				}
+				go func() {
+					if err := srv.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+						errCh <- err
+					}
+				}()
+			}
+			logger.Info().Str("grpc", grpcAddr).Str("rpc", rpcAddr).Int("accounts", len(alloc)).Msg("Reference executor started")
+
+			select {
+			case <-ctx.Done():
+			case err = <-errCh:
+			}
+
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			for _, srv := range servers {
+				_ = srv.Shutdown(shutdownCtx)
+			}
+			return err
+		},
+	}
+
+	startCmd.Flags().String(FlagReferenceGrpcAddr, "0.0.0.0:50051", "Address to serve the ExecutorService on")
+	startCmd.Flags().String(FlagReferenceRpcAddr, "0.0.0.0:3000", "Address to serve the transaction RPC on")
+	startCmd.Flags().StringSlice(FlagReferenceAlloc, nil, "Genesis quote balances as account=amount (e.g. alice=1000,bob=1000)")
+	for _, name := range referenceIgnoredFlags {
+		startCmd.Flags().String(name, "", "Ignored by the reference executor")
+		_ = startCmd.Flags().MarkHidden(name)
+	}
+
+	referenceCmd.AddCommand(startCmd)
+	return referenceCmd
+}
+
+// parseAlloc parses account=amount genesis balances
+func parseAlloc(values []string) (map[string]uint64, error) {
+	alloc := make(map[string]uint64, len(values))
+	for _, value := range values {
+		account, amount, ok := strings.Cut(value, "=")
+		if !ok || account == "" {
+			return nil, fmt.Errorf("invalid --%s entry %q: expected account=amount", FlagReferenceAlloc, value)
+		}
+		balance, err := strconv.ParseUint(amount, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --%s amount for %s: %w", FlagReferenceAlloc, account, err)
+		}
+		alloc[account] = balance
+	}
+	return alloc, nil
+}
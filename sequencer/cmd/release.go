@@ -0,0 +1,114 @@
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/drift"
+	"github.com/pranklin/pranklin-sequencer/release"
+)
+
+const (
+	// FlagReleaseManifest is the flag for the URL or path of the signed release manifest
+	FlagReleaseManifest = "manifest"
+	// FlagReleaseManifestPubKey is the flag for the hex encoded key the release manifest must be signed with
+	FlagReleaseManifestPubKey = "manifest-pubkey"
+	// FlagReleaseBinary is the flag for the binary to verify
+	FlagReleaseBinary = "binary"
+)
+
+// VerifyBuildCmd returns the verify-build command for checking the binary is a canonical release
+func VerifyBuildCmd() *cobra.Command {
+	verifyCmd := &cobra.Command{
+		Use:   "verify-build",
+		Short: "Verify the binary is a canonical release",
+		Long: `Compare the binary against the signed release manifest at --manifest, so operators
+can confirm they run the canonical release before joining a network.
+
+The build metadata embedded in the binary by the Go toolchain names the source
+revision and platform it was built for. The release published for them is looked up
+in the manifest, and the binary must match it:
+
+  - it was built from a clean checkout of the revision, with -trimpath (as make build does)
+  - with the Go version of the release
+  - and its SHA-256 is the one of the reproducible release build
+
+The manifest is JSON signed like the configuration drift manifest:
+
+  {"manifest": {"releases": [{"version": ..., "revision": ..., "go_version": ...,
+    "goos": ..., "goarch": ..., "sha256": ...}]}, "signature": "..."}
+
+The command fails unless every check passes.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			source, _ := cmd.Flags().GetString(FlagReleaseManifest)
+			if source == "" {
+				return fmt.Errorf("--%s is required", FlagReleaseManifest)
+			}
+
+			pubKeyHex, _ := cmd.Flags().GetString(FlagReleaseManifestPubKey)
+			pubKey, err := hex.DecodeString(pubKeyHex)
+			if err != nil || len(pubKey) != ed25519.PublicKeySize {
+				return fmt.Errorf("--%s must be a hex encoded ed25519 public key", FlagReleaseManifestPubKey)
+			}
+
+			binary, _ := cmd.Flags().GetString(FlagReleaseBinary)
+			if binary == "" {
+				if binary, err = os.Executable(); err != nil {
+					return fmt.Errorf("failed to locate the running binary: %w", err)
+				}
+			}
+
+			bz, err := drift.FetchManifest(cmd.Context(), &http.Client{Timeout: 30 * time.Second}, source)
+			if err != nil {
+				return fmt.Errorf("failed to fetch release manifest: %w", err)
+			}
+			manifest, err := release.VerifyManifest(bz, ed25519.PublicKey(pubKey))
+			if err != nil {
+				return err
+			}
+
+			build, err := release.ReadBuild(binary)
+			if err != nil {
+				return err
+			}
+
+			rel, checks := release.Verify(manifest, build)
+
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 4, 2, ' ', 0)
+			fmt.Fprintln(w, "CHECK\tRESULT\tEXPECTED\tACTUAL")
+			failed := 0
+			for _, check := range checks {
+				result := "ok"
+				if !check.OK {
+					result = "FAIL"
+					failed++
+				}
+				fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", check.Name, result, check.Expected, check.Actual)
+			}
+			if err := w.Flush(); err != nil {
+				return err
+			}
+
+			if failed > 0 {
+				return errors.New("binary is not a canonical release")
+			}
+			cmd.PrintErrf("%s is the canonical release %s\n", binary, rel.Version)
+			return nil
+		},
+	}
+
+	verifyCmd.Flags().String(FlagReleaseManifest, "", "URL or path of the signed release manifest")
+	verifyCmd.Flags().String(FlagReleaseManifestPubKey, "", "Hex encoded ed25519 public key the release manifest must be signed with")
+	verifyCmd.Flags().String(FlagReleaseBinary, "", "Binary to verify (defaults to the running binary)")
+
+	return verifyCmd
+}
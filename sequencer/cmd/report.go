@@ -0,0 +1,182 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/da/jsonrpc"
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+	"github.com/evstack/ev-node/pkg/config"
+	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+
+	"github.com/pranklin/pranklin-sequencer/report"
+)
+
+const (
+	// FlagReportFrom is the flag for the start of the reported period
+	FlagReportFrom = "from"
+	// FlagReportTo is the flag for the end of the reported period
+	FlagReportTo = "to"
+	// FlagReportMaxBlockGap is the flag for the longest interval between blocks not counted as downtime
+	FlagReportMaxBlockGap = "max-block-gap"
+	// FlagReportMaxDADelay is the flag for the longest DA inclusion delay not counted as downtime
+	FlagReportMaxDADelay = "max-da-delay"
+	// FlagReportOutput is the flag for the report output file
+	FlagReportOutput = "output"
+)
+
+// gapTolerance is how many block times may pass without a block, or without DA
+// inclusion, before it is counted as downtime
+const gapTolerance = 3
+
+// ReportCmd returns the report command for generating signed service reports
+func ReportCmd() *cobra.Command {
+	reportCmd := &cobra.Command{
+		Use:   "report",
+		Short: "Generate signed service reports",
+	}
+
+	reportCmd.AddCommand(reportDowntimeCmd())
+	return reportCmd
+}
+
+// reportDowntimeCmd returns the report downtime command
+func reportDowntimeCmd() *cobra.Command {
+	downtimeCmd := &cobra.Command{
+		Use:   "downtime",
+		Short: "Report gaps in block production and DA submission over a period",
+		Long: `Reconstruct the gaps in block production and DA submission between --from and --to
+from the local store and the DA layer, and write them as a report signed with the
+sequencer key, for SLA and insurance claims.
+
+A block production gap is an interval without blocks longer than --max-block-gap. A DA
+submission gap is an interval during which a produced block waited longer than
+--max-da-delay for its header to be included in DA; the inclusion time is the
+timestamp of the DA height. Both default to three block times.
+
+The report is written as JSON:
+
+  {"report": {...}, "public_key": "...", "address": "...", "signature": "..."}
+
+where signature is the signature of the exact report bytes, and address matches the
+proposer_address of the genesis. The node must be stopped, as the datastore is opened
+directly, and the DA layer must be reachable.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) (err error) {
+			nodeConfig, err := rollcmd.ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			logger := rollcmd.SetupLogger(nodeConfig.Log)
+
+			from, err := parseReportTime(cmd, FlagReportFrom)
+			if err != nil {
+				return err
+			}
+			to, err := parseReportTime(cmd, FlagReportTo)
+			if err != nil {
+				return err
+			}
+
+			maxBlockGap, _ := cmd.Flags().GetDuration(FlagReportMaxBlockGap)
+			if maxBlockGap == 0 {
+				maxBlockGap = gapTolerance * nodeConfig.Node.BlockTime.Duration
+			}
+			maxDADelay, _ := cmd.Flags().GetDuration(FlagReportMaxDADelay)
+			if maxDADelay == 0 {
+				maxDADelay = gapTolerance * nodeConfig.DA.BlockTime.Duration
+			}
+			output, _ := cmd.Flags().GetString(FlagReportOutput)
+
+			signer, err := loadSigner(cmd, nodeConfig)
+			if err != nil {
+				return err
+			}
+
+			genesis, err := rollgenesis.LoadGenesis(rollgenesis.GenesisPath(nodeConfig.RootDir))
+			if err != nil {
+				return err
+			}
+
+			if err := checkDatastoreLock(nodeConfig); err != nil {
+				return err
+			}
+
+			datastore, err := openDatastore(nodeConfig)
+			if err != nil {
+				return err
+			}
+			defer datastore.Close()
+
+			daJrpc, err := jsonrpc.NewClient(cmd.Context(), logger, nodeConfig.DA.Address, nodeConfig.DA.AuthToken, nodeConfig.DA.GasPrice, nodeConfig.DA.GasMultiplier, rollcmd.DefaultMaxBlobSize)
+			if err != nil {
+				return err
+			}
+
+			downtime, err := report.BuildDowntime(cmd.Context(), nodeStore(datastore), &daJrpc.DA, genesis.ChainID, report.DowntimeConfig{
+				From:            from,
+				To:              to,
+				MaxBlockGap:     maxBlockGap,
+				MaxDADelay:      maxDADelay,
+				HeaderNamespace: da.NamespaceFromString(nodeConfig.DA.GetNamespace()).Bytes(),
+			})
+			if err != nil {
+				return err
+			}
+
+			bz, err := report.Sign(downtime, signer)
+			if err != nil {
+				return err
+			}
+
+			var out io.Writer = cmd.OutOrStdout()
+			if output != "-" {
+				file, err := os.Create(output)
+				if err != nil {
+					return fmt.Errorf("failed to create output file: %w", err)
+				}
+				defer func() {
+					if cerr := file.Close(); err == nil {
+						err = cerr
+					}
+				}()
+				out = file
+			}
+
+			if _, err := out.Write(append(bz, '\n')); err != nil {
+				return fmt.Errorf("failed to write report: %w", err)
+			}
+
+			cmd.PrintErrf("Reported %.0fs of block production and %.0fs of DA submission downtime (blocks %d-%d)\n",
+				downtime.BlockDowntimeSeconds, downtime.DADowntimeSeconds, downtime.FirstHeight, downtime.LastHeight)
+			return nil
+		},
+	}
+
+	config.AddFlags(downtimeCmd)
+	downtimeCmd.Flags().String(FlagReportFrom, "", "Start of the reported period (RFC 3339)")
+	downtimeCmd.Flags().String(FlagReportTo, "", "End of the reported period (RFC 3339)")
+	downtimeCmd.Flags().Duration(FlagReportMaxBlockGap, 0, "Longest interval between blocks not counted as downtime (0 uses three block times)")
+	downtimeCmd.Flags().Duration(FlagReportMaxDADelay, 0, "Longest delay of DA inclusion not counted as downtime (0 uses three DA block times)")
+	downtimeCmd.Flags().StringP(FlagReportOutput, "o", "-", "Output file (- writes to stdout)")
+	_ = downtimeCmd.MarkFlagRequired(FlagReportFrom)
+	_ = downtimeCmd.MarkFlagRequired(FlagReportTo)
+
+	return downtimeCmd
+}
+
+// parseReportTime returns the RFC 3339 time of the flag name.
+func parseReportTime(cmd *cobra.Command, name string) (time.Time, error) {
+	value, _ := cmd.Flags().GetString(name)
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid %s: %w", name, err)
+	}
+	return t, nil
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorRetryAttempts is the flag for how many times a call to the execution layer is attempted
+	FlagExecutorRetryAttempts = "executor.retry-attempts"
+	// FlagExecutorRetryMinBackoff is the flag for the wait before the first retry of a call
+	FlagExecutorRetryMinBackoff = "executor.retry-min-backoff"
+	// FlagExecutorRetryMaxBackoff is the flag for the longest wait between retries of a call
+	FlagExecutorRetryMaxBackoff = "executor.retry-max-backoff"
+)
+
+// addExecutorRetryFlags adds flags for retrying calls to the execution layer
+func addExecutorRetryFlags(cmd *cobra.Command) {
+	cmd.Flags().Int(FlagExecutorRetryAttempts, 5, "Attempts of a call to the execution layer failing with a transient error (unavailable, overloaded, aborted or timed out) before it fails (1 disables retries)")
+	cmd.Flags().Duration(FlagExecutorRetryMinBackoff, 100*time.Millisecond, "Wait before the first retry of a call to the execution layer, doubled after each retry and jittered")
+	cmd.Flags().Duration(FlagExecutorRetryMaxBackoff, 2*time.Second, "Longest wait between retries of a call to the execution layer")
+}
+
+// setRetryPolicy retries the calls of client failing with a transient error as
+// configured by flags.
+func setRetryPolicy(cmd *cobra.Command, logger zerolog.Logger, client *grpc.Client) error {
+	var policy grpc.RetryPolicy
+	var err error
+	if policy.MaxAttempts, err = cmd.Flags().GetInt(FlagExecutorRetryAttempts); err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorRetryAttempts, err)
+	}
+	if policy.MinBackoff, err = cmd.Flags().GetDuration(FlagExecutorRetryMinBackoff); err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorRetryMinBackoff, err)
+	}
+	if policy.MaxBackoff, err = cmd.Flags().GetDuration(FlagExecutorRetryMaxBackoff); err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorRetryMaxBackoff, err)
+	}
+	if err := policy.Validate(); err != nil {
+		return fmt.Errorf("invalid executor retry policy: %w", err)
+	}
+
+	client.SetRetryPolicy(policy, logger)
+	return nil
+}
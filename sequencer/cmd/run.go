@@ -1,13 +1,14 @@
 package main
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"path/filepath"
 
 	"github.com/spf13/cobra"
 
 	"github.com/evstack/ev-node/core/da"
-	"github.com/evstack/ev-node/core/execution"
 	"github.com/evstack/ev-node/da/jsonrpc"
 	"github.com/evstack/ev-node/node"
 	rollcmd "github.com/evstack/ev-node/pkg/cmd"
@@ -15,9 +16,9 @@ import (
 	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
 	"github.com/evstack/ev-node/pkg/p2p"
 	"github.com/evstack/ev-node/pkg/p2p/key"
-	"github.com/evstack/ev-node/pkg/store"
 	"github.com/evstack/ev-node/sequencers/single"
 
+	"github.com/pranklin/pranklin-sequencer/api"
 	"github.com/pranklin/pranklin-sequencer/grpc"
 )
 
@@ -33,11 +34,10 @@ var RunCmd = &cobra.Command{
 	Long: `Start a Pranklin sequencer node that connects to the Pranklin execution layer via gRPC.
 The execution layer handles trading operations for perpetual futures.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
-		// Create gRPC execution client
-		executor, err := createGRPCExecutionClient(cmd)
-		if err != nil {
-			return err
-		}
+		// Components may halt the node by cancelling its context with a cause
+		ctx, halt := context.WithCancelCause(cmd.Context())
+		defer halt(nil)
+		cmd.SetContext(ctx)
 
 		// Parse node configuration
 		nodeConfig, err := rollcmd.ParseConfig(cmd)
@@ -47,6 +47,12 @@ The execution layer handles trading operations for perpetual futures.`,
 
 		logger := rollcmd.SetupLogger(nodeConfig.Log)
 
+		// Keep recent logs for the dashboard
+		logger, dashboardLogs, err := captureDashboardLogs(cmd, logger, consoleLogOutput(nodeConfig))
+		if err != nil {
+			return err
+		}
+
 		headerNamespace := da.NamespaceFromString(nodeConfig.DA.GetNamespace())
 		dataNamespace := da.NamespaceFromString(nodeConfig.DA.GetDataNamespace())
 
@@ -58,8 +64,26 @@ The execution layer handles trading operations for perpetual futures.`,
 			return err
 		}
 
+		// Refuse to start a second node against the same root dir
+		nodeLock, err := acquireNodeLock(nodeConfig)
+		if err != nil {
+			return err
+		}
+		defer func() { _ = nodeLock.Remove() }()
+
+		// Refuse to open a datastore held by another sequencer
+		if err := checkDatastoreLock(nodeConfig); err != nil {
+			return err
+		}
+
 		// Create datastore
-		datastore, err := store.NewDefaultKVStore(nodeConfig.RootDir, nodeConfig.DBPath, "pranklin-sequencer")
+		datastore, err := openDatastore(nodeConfig)
+		if err != nil {
+			return err
+		}
+
+		// Move block submission to another DA network if configured
+		daLayer, err := migrateDA(cmd.Context(), cmd, logger, &daJrpc.DA, datastore, nodeConfig)
 		if err != nil {
 			return err
 		}
@@ -74,6 +98,27 @@ The execution layer handles trading operations for perpetual futures.`,
 			logger.Warn().Msg("da_start_height is not set in genesis.json, ask your chain developer")
 		}
 
+		// Create gRPC execution client, configured before anything uses it
+		executor, err := createGRPCExecutionClient(cmd)
+		if err != nil {
+			return err
+		}
+		execMetrics, err := executorMetrics(nodeConfig, genesis.ChainID)
+		if err != nil {
+			return err
+		}
+		// Submissions through the API are timed until the block including them is executed
+		latency := api.NewLatencyTracker()
+		if err := configureExecutor(cmd, logger, executor, latency, execMetrics); err != nil {
+			return err
+		}
+
+		// Load feature flags before the configuration is used
+		features, err := loadFeatureFlags(cmd, logger, &nodeConfig, genesis.ChainID)
+		if err != nil {
+			return err
+		}
+
 		// Create metrics provider
 		singleMetrics, err := single.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(genesis.ChainID)
 		if err != nil {
@@ -85,7 +130,7 @@ The execution layer handles trading operations for perpetual futures.`,
 			cmd.Context(),
 			logger,
 			datastore,
-			&daJrpc.DA,
+			daLayer,
 			[]byte(genesis.ChainID),
 			nodeConfig.Node.BlockTime.Duration,
 			singleMetrics,
@@ -95,6 +140,34 @@ The execution layer handles trading operations for perpetual futures.`,
 			return err
 		}
 
+		// Start DA blob integrity verifier
+		if err := startDAVerifier(cmd.Context(), cmd, logger, datastore, daLayer, nodeConfig, genesis.ChainID); err != nil {
+			return err
+		}
+
+		// Start sequencer HTTP API
+		if err := startAPIServer(cmd.Context(), cmd, logger, datastore, daLayer, latency, features, nil, nodeConfig, genesis.ChainID); err != nil {
+			return err
+		}
+
+		// Start telemetry dashboard
+		if err := startDashboard(ctx, cmd, logger, datastore, dashboardLogs, nil, mempoolStatus(executor), nodeConfig); err != nil {
+			return err
+		}
+
+		// Start disk usage monitor
+		if err := startDiskMonitor(ctx, cmd, logger, datastore, []string{nodeConfig.RootDir}, nodeConfig, genesis.ChainID, halt); err != nil {
+			return err
+		}
+
+		// Start configuration drift detector
+		if err := startDriftDetector(ctx, cmd, logger, nodeConfig, genesis.ChainID); err != nil {
+			return err
+		}
+
+		// Report the execution layer mempool backlog
+		startMempoolReporter(ctx, executor, execMetrics)
+
 		// Load node key
 		nodeKey, err := key.LoadNodeKey(filepath.Dir(nodeConfig.ConfigPath()))
 		if err != nil {
@@ -107,8 +180,32 @@ The execution layer handles trading operations for perpetual futures.`,
 			return err
 		}
 
+		// Fail calls right away while the execution layer is down
+		breakerExecutor, err := breakExecutor(cmd, logger, executor, execMetrics)
+		if err != nil {
+			return err
+		}
+
+		// Keep slow mempool scans from taking the whole block slot
+		budgetExecutor, err := budgetGetTxs(cmd, logger, breakerExecutor, features, execMetrics, nodeConfig)
+		if err != nil {
+			return err
+		}
+
+		// Profile the block loop stages
+		nodeExecutor, nodeDA, err := profileBlockLoop(cmd, logger, budgetExecutor, daLayer, nodeConfig, genesis.ChainID)
+		if err != nil {
+			return err
+		}
+
 		// Start the node
-		return rollcmd.StartNode(logger, cmd, executor, sequencer, &daJrpc.DA, p2pClient, datastore, nodeConfig, genesis, node.NodeOptions{})
+		err = rollcmd.StartNode(logger, cmd, nodeExecutor, sequencer, nodeDA, p2pClient, datastore, nodeConfig, genesis, node.NodeOptions{})
+
+		// Report why the node was halted rather than the resulting cancellation
+		if cause := context.Cause(ctx); cause != nil && !errors.Is(cause, context.Canceled) {
+			return cause
+		}
+		return err
 	},
 }
 
@@ -118,10 +215,44 @@ func init() {
 
 	// Add gRPC-specific flags
 	addGRPCFlags(RunCmd)
+
+	// Add DA verifier flags
+	addDAVerifierFlags(RunCmd)
+
+	// Add DA migration flags
+	addDAMigrationFlags(RunCmd)
+
+	// Add API flags
+	addAPIFlags(RunCmd)
+
+	// Add profiling flags
+	addProfileFlags(RunCmd)
+
+	// Add disk monitor flags
+	addDiskFlags(RunCmd)
+
+	// Add dashboard flags
+	addDashboardFlags(RunCmd)
+
+	// Add drift detection flags
+	addDriftFlags(RunCmd)
+
+	// Add feature flags
+	addFeatureFlags(RunCmd)
+
+	// Add executor flags
+	addExecutorFlags(RunCmd)
+	addExecutorSamplingFlags(RunCmd)
+	addExecutorRetryFlags(RunCmd)
+	addExecutorTimeoutFlags(RunCmd)
+	addExecutorTracingFlags(RunCmd)
+	addExecutorCompressionFlags(RunCmd)
+	addExecutorStreamFlags(RunCmd)
+	addExecutorBreakerFlags(RunCmd)
 }
 
 // createGRPCExecutionClient creates a new gRPC execution client from command flags
-func createGRPCExecutionClient(cmd *cobra.Command) (execution.Executor, error) {
+func createGRPCExecutionClient(cmd *cobra.Command) (*grpc.Client, error) {
 	// Get the gRPC executor URL from flags
 	executorURL, err := cmd.Flags().GetString(FlagGrpcExecutorURL)
 	if err != nil {
@@ -133,10 +264,11 @@ func createGRPCExecutionClient(cmd *cobra.Command) (execution.Executor, error) {
 	}
 
 	// Create and return the Pranklin gRPC client
-	return grpc.NewClient(executorURL), nil
+	return executionClient(cmd, executorURL)
 }
 
 // addGRPCFlags adds flags specific to the gRPC execution client
 func addGRPCFlags(cmd *cobra.Command) {
-	cmd.Flags().String(FlagGrpcExecutorURL, "http://localhost:50051", "URL of the gRPC execution service (http://host:port)")
+	cmd.Flags().String(FlagGrpcExecutorURL, "http://localhost:50051", "URL of the gRPC execution service (http://host:port, or https://host:port for TLS)")
+	addExecutorTLSFlags(cmd)
 }
@@ -0,0 +1,44 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorTxSampleRate is the flag for recording 1 in N executed transactions in telemetry
+	FlagExecutorTxSampleRate = "executor.tx-sample-rate"
+	// FlagExecutorSlowTxThreshold is the flag for the wait above which every transaction is recorded
+	FlagExecutorSlowTxThreshold = "executor.slow-tx-threshold"
+)
+
+// addExecutorSamplingFlags adds flags for sampling executed transactions in telemetry
+func addExecutorSamplingFlags(cmd *cobra.Command) {
+	cmd.Flags().Uint64(FlagExecutorTxSampleRate, 100, "Record the wait of 1 in N executed transactions in metrics and as debug spans (1 records all, 0 none)")
+	cmd.Flags().Duration(FlagExecutorSlowTxThreshold, 0, "Also record every transaction that waited longer than this from GetTxs to execution (0 disables it)")
+}
+
+// setTxTelemetry records the waits of the transactions client executes, sampled as
+// configured by flags.
+func setTxTelemetry(cmd *cobra.Command, logger zerolog.Logger, client *grpc.Client, metrics *grpc.Metrics) error {
+	rate, err := cmd.Flags().GetUint64(FlagExecutorTxSampleRate)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorTxSampleRate, err)
+	}
+
+	threshold, err := cmd.Flags().GetDuration(FlagExecutorSlowTxThreshold)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorSlowTxThreshold, err)
+	}
+
+	if threshold < 0 {
+		return fmt.Errorf("%s must be >= 0", FlagExecutorSlowTxThreshold)
+	}
+
+	client.SetTxTelemetry(grpc.TxSampling{Rate: rate, SlowThreshold: threshold}, logger, metrics)
+	return nil
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"syscall"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/core/execution"
+	coresequencer "github.com/evstack/ev-node/core/sequencer"
+	"github.com/evstack/ev-node/node"
+	"github.com/evstack/ev-node/pkg/config"
+	rollgenesis "github.com/evstack/ev-node/pkg/genesis"
+	"github.com/evstack/ev-node/pkg/p2p"
+	"github.com/evstack/ev-node/pkg/signer"
+	filesigner "github.com/evstack/ev-node/pkg/signer/file"
+	"github.com/evstack/ev-node/pkg/store"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagShutdownDrainTimeout is the flag for how long pending blocks are given to reach DA on shutdown
+	FlagShutdownDrainTimeout = "shutdown.drain-timeout"
+	// FlagShutdownSequencerTimeout is the flag for how long the sequencer is given to stop
+	FlagShutdownSequencerTimeout = "shutdown.sequencer-timeout"
+	// FlagShutdownExecutionTimeout is the flag for how long the execution subprocess is given to stop
+	FlagShutdownExecutionTimeout = "shutdown.execution-timeout"
+	// FlagShutdownDATimeout is the flag for how long the local-da subprocess is given to stop
+	FlagShutdownDATimeout = "shutdown.da-timeout"
+)
+
+// shutdownSignals are the signals that stop the node gracefully. On Windows, Ctrl+C
+// and Ctrl+Break arrive as os.Interrupt, and closing the console or logging off as
+// syscall.SIGTERM.
+var shutdownSignals = []os.Signal{os.Interrupt, syscall.SIGTERM}
+
+// drainPollInterval is how often the DA included height is checked while draining
+const drainPollInterval = 200 * time.Millisecond
+
+// shutdownConfig holds how long each shutdown phase of the unified node may take.
+type shutdownConfig struct {
+	drain     time.Duration
+	sequencer time.Duration
+	execution time.Duration
+	da        time.Duration
+}
+
+// addShutdownFlags adds flags for the shutdown phases of the unified node
+func addShutdownFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration(FlagShutdownDrainTimeout, 10*time.Second, "How long the sequencer keeps running without taking new transactions on shutdown, until its blocks are DA included (0 skips the drain)")
+	cmd.Flags().Duration(FlagShutdownSequencerTimeout, 5*time.Second, "How long the sequencer is given to stop before the subprocesses are stopped")
+	cmd.Flags().Duration(FlagShutdownExecutionTimeout, subprocessStopTimeout, "How long the execution subprocess is given to exit on shutdown before it is killed")
+	cmd.Flags().Duration(FlagShutdownDATimeout, subprocessStopTimeout, "How long the local-da subprocess is given to exit on shutdown before it is killed")
+}
+
+// shutdownConfigFromFlags reads the shutdown phase timeouts from command flags
+func shutdownConfigFromFlags(cmd *cobra.Command) (shutdownConfig, error) {
+	var cfg shutdownConfig
+	for name, timeout := range map[string]*time.Duration{
+		FlagShutdownDrainTimeout:     &cfg.drain,
+		FlagShutdownSequencerTimeout: &cfg.sequencer,
+		FlagShutdownExecutionTimeout: &cfg.execution,
+		FlagShutdownDATimeout:        &cfg.da,
+	} {
+		value, err := cmd.Flags().GetDuration(name)
+		if err != nil {
+			return shutdownConfig{}, fmt.Errorf("failed to get '%s' flag: %w", name, err)
+		}
+		if value < 0 {
+			return shutdownConfig{}, fmt.Errorf("%s must be >= 0", name)
+		}
+		*timeout = value
+	}
+	return cfg, nil
+}
+
+// drainSequencer stops the sequencer from taking new transactions and waits until the
+// blocks holding the transactions it already took are DA included, or ctx is done.
+// The sequencer keeps running meanwhile so the submitter can post them.
+func drainSequencer(ctx context.Context, logger zerolog.Logger, st store.Reader, executor *grpc.DrainExecutor, blockTime time.Duration) {
+	executor.Drain()
+	logger.Info().Msg("Draining: no longer taking transactions, waiting for pending blocks to be DA included")
+
+	// Transactions already queued by the sequencer go into the next block
+	select {
+	case <-ctx.Done():
+		logger.Warn().Msg("Drain cut short before the last block was produced")
+		return
+	case <-time.After(blockTime):
+	}
+
+	target, err := st.Height(ctx)
+	if err != nil {
+		logger.Warn().Err(err).Msg("Drain cut short: failed to load store height")
+		return
+	}
+
+	ticker := time.NewTicker(drainPollInterval)
+	defer ticker.Stop()
+
+	for {
+		included, err := seqda.DAIncludedHeight(ctx, st)
+		if err != nil && !errors.Is(err, ds.ErrNotFound) {
+			logger.Warn().Err(err).Msg("Drain cut short: failed to load DA included height")
+			return
+		}
+		if included >= target {
+			logger.Info().Uint64("height", target).Msg("Drained: all blocks are DA included")
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			logger.Warn().Uint64("da_included_height", included).Uint64("height", target).Msg("Drain timed out with blocks pending DA inclusion")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// loadSigner loads the file signer of the sequencer, resolving its path like ev-node.
+func loadSigner(cmd *cobra.Command, nodeConfig config.Config) (signer.Signer, error) {
+	if nodeConfig.Signer.SignerType != "file" {
+		return nil, fmt.Errorf("unknown signer type: %s", nodeConfig.Signer.SignerType)
+	}
+
+	passphrase, err := cmd.Flags().GetString(config.FlagSignerPassphrase)
+	if err != nil {
+		return nil, err
+	}
+
+	signerPath, err := filepath.Abs(strings.TrimSuffix(nodeConfig.Signer.SignerPath, "signer.json"))
+	if err != nil {
+		return nil, err
+	}
+
+	s, err := filesigner.LoadFileSystemSigner(signerPath, []byte(passphrase))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load signer: %w", err)
+	}
+	return s, nil
+}
+
+// runSequencer runs the ev-node sequencer until ctx is cancelled. It does what
+// rollcmd.StartNode does except for handling signals itself, so the unified node
+// decides when the sequencer stops.
+func runSequencer(
+	ctx context.Context,
+	cmd *cobra.Command,
+	logger zerolog.Logger,
+	executor execution.Executor,
+	sequencer coresequencer.Sequencer,
+	daLayer coreda.DA,
+	p2pClient *p2p.Client,
+	datastore ds.Batching,
+	nodeConfig config.Config,
+	genesis rollgenesis.Genesis,
+) error {
+	var s signer.Signer
+	if nodeConfig.Node.Aggregator {
+		var err error
+		if s, err = loadSigner(cmd, nodeConfig); err != nil {
+			return err
+		}
+	}
+
+	rollnode, err := node.NewNode(
+		nodeConfig,
+		executor,
+		sequencer,
+		daLayer,
+		s,
+		p2pClient,
+		genesis,
+		datastore,
+		node.DefaultMetricsProvider(nodeConfig.Instrumentation),
+		logger,
+		node.NodeOptions{},
+	)
+	if err != nil {
+		return fmt.Errorf("failed to create node: %w", err)
+	}
+
+	if err := rollnode.Run(ctx); err != nil && !errors.Is(err, context.Canceled) {
+		return err
+	}
+	return nil
+}
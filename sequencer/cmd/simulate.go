@@ -0,0 +1,148 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	rollcmd "github.com/evstack/ev-node/pkg/cmd"
+
+	"github.com/pranklin/pranklin-sequencer/simulate"
+)
+
+const (
+	// FlagSimulateFrom is the flag for the first height of the simulated range
+	FlagSimulateFrom = "from"
+	// FlagSimulateTo is the flag for the last height of the simulated range
+	FlagSimulateTo = "to"
+	// FlagSimulateBatchBlocks is the flag for the DA batch sizes to simulate
+	FlagSimulateBatchBlocks = "batch-blocks"
+	// FlagSimulateFeePerTx is the flag for the per-transaction fees to simulate
+	FlagSimulateFeePerTx = "fee-per-tx"
+	// FlagSimulateGasPrice is the flag for the DA gas price used to price submissions
+	FlagSimulateGasPrice = "gas-price"
+	// FlagSimulateGasPerSubmission is the flag for the fixed gas cost of a DA submission
+	FlagSimulateGasPerSubmission = "gas-per-submission"
+	// FlagSimulateGasPerByte is the flag for the gas cost per DA blob byte
+	FlagSimulateGasPerByte = "gas-per-byte"
+	// FlagSimulateJSON is the flag for printing results as JSON
+	FlagSimulateJSON = "json"
+)
+
+// SimulateCmd returns the simulate command for offline what-if analysis of a node's history
+func SimulateCmd() *cobra.Command {
+	simulateCmd := &cobra.Command{
+		Use:   "simulate",
+		Short: "Simulate sequencer operation over stored blocks",
+	}
+
+	simulateCmd.AddCommand(simulateEconomicsCmd())
+	return simulateCmd
+}
+
+// simulateEconomicsCmd returns the simulate economics command
+func simulateEconomicsCmd() *cobra.Command {
+	economicsCmd := &cobra.Command{
+		Use:   "economics",
+		Short: "Replay stored blocks to estimate DA costs, fee revenue and net margin",
+		Long: `Replay a range of blocks from the local store and estimate DA costs, fee revenue
+and net margin for every combination of the given batch sizes and per-transaction fees.
+
+DA costs are priced as gas-price * (gas-per-submission per submission + gas-per-byte per blob byte).
+Revenue is the number of transactions times the fee per transaction.
+The node must be stopped, as the datastore is opened directly.`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			nodeConfig, err := rollcmd.ParseConfig(cmd)
+			if err != nil {
+				return err
+			}
+
+			from, _ := cmd.Flags().GetUint64(FlagSimulateFrom)
+			to, _ := cmd.Flags().GetUint64(FlagSimulateTo)
+			batchSizes, _ := cmd.Flags().GetUintSlice(FlagSimulateBatchBlocks)
+			fees, _ := cmd.Flags().GetFloat64Slice(FlagSimulateFeePerTx)
+			gasPrice, _ := cmd.Flags().GetFloat64(FlagSimulateGasPrice)
+			gasPerSubmission, _ := cmd.Flags().GetUint64(FlagSimulateGasPerSubmission)
+			gasPerByte, _ := cmd.Flags().GetUint64(FlagSimulateGasPerByte)
+			asJSON, _ := cmd.Flags().GetBool(FlagSimulateJSON)
+
+			if !cmd.Flags().Changed(FlagSimulateGasPrice) {
+				gasPrice = nodeConfig.DA.GasPrice
+			}
+			if gasPrice <= 0 {
+				return fmt.Errorf("configured DA gas price is automatic; set --%s", FlagSimulateGasPrice)
+			}
+
+			if err := checkDatastoreLock(nodeConfig); err != nil {
+				return err
+			}
+
+			datastore, err := openDatastore(nodeConfig)
+			if err != nil {
+				return err
+			}
+			defer datastore.Close()
+
+			st := nodeStore(datastore)
+			if to == 0 {
+				if to, err = st.Height(cmd.Context()); err != nil {
+					return fmt.Errorf("failed to load store height: %w", err)
+				}
+			}
+
+			if to < from {
+				return fmt.Errorf("no stored blocks from height %d (store height %d)", from, to)
+			}
+
+			blocks, err := simulate.CollectBlockStats(cmd.Context(), st, from, to)
+			if err != nil {
+				return err
+			}
+
+			model := simulate.CostModel{
+				GasPrice:         gasPrice,
+				GasPerSubmission: gasPerSubmission,
+				GasPerByte:       gasPerByte,
+			}
+
+			var results []simulate.Result
+			for _, batchBlocks := range batchSizes {
+				for _, fee := range fees {
+					result, err := simulate.Simulate(blocks, model, simulate.Scenario{BatchBlocks: uint64(batchBlocks), FeePerTx: fee})
+					if err != nil {
+						return err
+					}
+					results = append(results, result)
+				}
+			}
+
+			if asJSON {
+				encoder := json.NewEncoder(cmd.OutOrStdout())
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(results)
+			}
+
+			cmd.Printf("Heights %d-%d, gas price %v\n\n", from, to, gasPrice)
+			w := tabwriter.NewWriter(cmd.OutOrStdout(), 0, 0, 2, ' ', tabwriter.AlignRight)
+			fmt.Fprintln(w, "batch blocks\tfee/tx\ttxs\tsubmissions\tbytes\tDA cost\trevenue\tnet margin\t")
+			for _, r := range results {
+				fmt.Fprintf(w, "%d\t%g\t%d\t%d\t%d\t%.6f\t%.6f\t%.6f\t\n", r.BatchBlocks, r.FeePerTx, r.Txs, r.Submissions, r.Bytes, r.DACost, r.Revenue, r.NetMargin)
+			}
+			return w.Flush()
+		},
+	}
+
+	economicsCmd.Flags().Uint64(FlagSimulateFrom, 1, "First height of the simulated range")
+	economicsCmd.Flags().Uint64(FlagSimulateTo, 0, "Last height of the simulated range (0 uses the latest stored height)")
+	economicsCmd.Flags().UintSlice(FlagSimulateBatchBlocks, []uint{1}, "Numbers of blocks submitted to DA together to compare (comma-separated)")
+	economicsCmd.Flags().Float64Slice(FlagSimulateFeePerTx, []float64{0}, "Fees collected per transaction to compare, in DA tokens (comma-separated)")
+	economicsCmd.Flags().Float64(FlagSimulateGasPrice, 0, "DA gas price (defaults to the configured da.gas_price)")
+	economicsCmd.Flags().Uint64(FlagSimulateGasPerSubmission, 65_000, "Fixed gas cost of a DA submission")
+	economicsCmd.Flags().Uint64(FlagSimulateGasPerByte, 8, "Gas cost per DA blob byte")
+	economicsCmd.Flags().Bool(FlagSimulateJSON, false, "Print results as JSON")
+
+	return economicsCmd
+}
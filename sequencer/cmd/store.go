@@ -0,0 +1,15 @@
+package main
+
+import (
+	ds "github.com/ipfs/go-datastore"
+	ktds "github.com/ipfs/go-datastore/keytransform"
+
+	"github.com/evstack/ev-node/node"
+	"github.com/evstack/ev-node/pkg/store"
+)
+
+// nodeStore returns the block store the full node keeps in datastore. The node keeps
+// its keys under the node.EvPrefix namespace, so a store over datastore itself is empty.
+func nodeStore(datastore ds.Batching) store.Store {
+	return store.New(ktds.Wrap(datastore, ktds.PrefixTransform{Prefix: ds.NewKey(node.EvPrefix)}))
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorStreamChunkBytes is the flag for the byte budget of mempool chunks streamed by the execution layer
+	FlagExecutorStreamChunkBytes = "executor.stream-chunk-bytes"
+)
+
+// addExecutorStreamFlags adds flags for streaming the mempool of the execution layer
+func addExecutorStreamFlags(cmd *cobra.Command) {
+	cmd.Flags().Int(FlagExecutorStreamChunkBytes, 0, "Stream the execution layer mempool in chunks of up to this many bytes instead of one GetTxs response, falling back to GetTxs if unsupported (0 disables streaming)")
+}
+
+// setTxStream streams the mempool of the execution layer to client if enabled by flags.
+func setTxStream(cmd *cobra.Command, client *grpc.Client) error {
+	chunkBytes, err := cmd.Flags().GetInt(FlagExecutorStreamChunkBytes)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorStreamChunkBytes, err)
+	}
+
+	if chunkBytes == 0 {
+		return nil
+	}
+
+	if err := client.SetTxStream(chunkBytes); err != nil {
+		return fmt.Errorf("invalid '%s' flag: %w", FlagExecutorStreamChunkBytes, err)
+	}
+	return nil
+}
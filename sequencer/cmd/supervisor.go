@@ -0,0 +1,345 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/node"
+	"github.com/evstack/ev-node/pkg/config"
+	"github.com/evstack/ev-node/pkg/store"
+
+	"github.com/pranklin/pranklin-sequencer/era"
+	"github.com/pranklin/pranklin-sequencer/grpc"
+	"github.com/pranklin/pranklin-sequencer/supervisor"
+)
+
+const (
+	// FlagSupervisorStopOrphans is the flag for stopping subprocesses left running by a previous crash
+	FlagSupervisorStopOrphans = "supervisor.stop-orphans"
+	// FlagSupervisorReadyTimeout is the flag for how long a subprocess is given to become ready
+	FlagSupervisorReadyTimeout = "supervisor.ready-timeout"
+	// FlagSupervisorMaxRestarts is the flag for how many times in a row a crashed subprocess is restarted
+	FlagSupervisorMaxRestarts = "supervisor.max-restarts"
+	// FlagSupervisorRestartBackoff is the flag for the delay before the first restart of a crashed subprocess
+	FlagSupervisorRestartBackoff = "supervisor.restart-backoff"
+	// FlagSupervisorRestartMaxBackoff is the flag for the longest delay between subprocess restarts
+	FlagSupervisorRestartMaxBackoff = "supervisor.restart-max-backoff"
+	// FlagSupervisorRawLogs is the flag for passing subprocess output to the terminal as is
+	FlagSupervisorRawLogs = "supervisor.raw-logs"
+)
+
+const (
+	// datastoreName is the name of the sequencer datastore inside the database path
+	datastoreName = "pranklin-sequencer"
+	// pidDirName is the directory inside the root directory holding subprocess pidfiles
+	pidDirName = "run"
+	// eraDirName is the directory inside the root directory holding era files of archived blocks
+	eraDirName = "era"
+	// orphanStopTimeout is how long an orphaned subprocess is given to exit before it is killed
+	orphanStopTimeout = 5 * time.Second
+	// subprocessStopTimeout is how long a subprocess is given to exit on shutdown before it is killed
+	subprocessStopTimeout = 5 * time.Second
+	// restartStableAfter is how long a subprocess has to run for its restart count to be reset
+	restartStableAfter = time.Minute
+)
+
+// addSupervisorFlags adds flags for subprocess supervision
+func addSupervisorFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagSupervisorStopOrphans, false, "Stop DA/execution subprocesses left running by a previous crash instead of refusing to start")
+	cmd.Flags().Duration(FlagSupervisorReadyTimeout, 30*time.Second, "How long the DA and execution subprocesses are given to answer readiness probes after starting")
+	cmd.Flags().Int(FlagSupervisorMaxRestarts, 5, "Number of times in a row a crashed DA/execution subprocess is restarted before the node shuts down (0 disables restarts), unless --execution.max-restarts or --da.max-restarts is set")
+	cmd.Flags().Duration(FlagSupervisorRestartBackoff, time.Second, "Delay before restarting a crashed subprocess, doubled after each restart")
+	cmd.Flags().Duration(FlagSupervisorRestartMaxBackoff, 30*time.Second, "Longest delay between restarts of a crashed subprocess")
+	cmd.Flags().Bool(FlagSupervisorRawLogs, false, "Write DA/execution subprocess output to the terminal as is instead of through the node logger")
+}
+
+// pidDir returns the directory holding subprocess pidfiles
+func pidDir(nodeConfig config.Config) string {
+	return filepath.Join(nodeConfig.RootDir, pidDirName)
+}
+
+// datastorePath returns the path of the sequencer datastore, as resolved by store.NewDefaultKVStore
+func datastorePath(nodeConfig config.Config) string {
+	dbPath := nodeConfig.DBPath
+	if !filepath.IsAbs(dbPath) {
+		dbPath = filepath.Join(nodeConfig.RootDir, dbPath)
+	}
+	return filepath.Join(dbPath, datastoreName)
+}
+
+// eraDir returns the directory holding the era files of archived blocks
+func eraDir(nodeConfig config.Config) string {
+	return filepath.Join(nodeConfig.RootDir, eraDirName)
+}
+
+// openDatastore opens the sequencer datastore. Blocks moved into era files by the
+// archive compact command are read from the era files as if they were still stored.
+func openDatastore(nodeConfig config.Config) (ds.Batching, error) {
+	raw, err := store.NewDefaultKVStore(nodeConfig.RootDir, nodeConfig.DBPath, datastoreName)
+	if err != nil {
+		return nil, err
+	}
+
+	datastore, err := era.OpenDatastore(raw, eraDir(nodeConfig), ds.NewKey(node.EvPrefix))
+	if err != nil {
+		_ = raw.Close()
+		return nil, fmt.Errorf("failed to open era files: %w", err)
+	}
+	return datastore, nil
+}
+
+// checkDatastoreLock refuses to start when the datastore is held by another live process,
+// instead of surfacing Badger's directory lock error.
+func checkDatastoreLock(nodeConfig config.Config) error {
+	err := supervisor.CheckDatastoreLock(datastorePath(nodeConfig))
+	if errors.Is(err, supervisor.ErrDatastoreLocked) {
+		return fmt.Errorf("%w\nAnother sequencer is still running against %s. Stop it before starting a new one", err, nodeConfig.RootDir)
+	}
+	return err
+}
+
+// acquireNodeLock locks the root dir for this node, refusing to start when another
+// node is running against it. Remove the returned lock on shutdown.
+func acquireNodeLock(nodeConfig config.Config) (*supervisor.PIDFile, error) {
+	lock, err := supervisor.AcquireNodeLock(nodeConfig.RootDir)
+	if errors.Is(err, supervisor.ErrNodeLocked) {
+		return nil, fmt.Errorf("%w\nAnother sequencer is already running against %s. Stop it before starting a new one", err, nodeConfig.RootDir)
+	}
+	return lock, err
+}
+
+// cleanupOrphans finds subprocesses left running by a previous crash. They are stopped
+// when --supervisor.stop-orphans is set; otherwise startup is refused with instructions.
+func cleanupOrphans(cmd *cobra.Command, logger zerolog.Logger, nodeConfig config.Config) error {
+	stopOrphans, err := cmd.Flags().GetBool(FlagSupervisorStopOrphans)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagSupervisorStopOrphans, err)
+	}
+
+	orphans, err := supervisor.FindOrphans(pidDir(nodeConfig))
+	if err != nil {
+		return fmt.Errorf("failed to look for orphaned subprocesses: %w", err)
+	}
+
+	if len(orphans) == 0 {
+		return nil
+	}
+
+	if !stopOrphans {
+		var list []string
+		for _, orphan := range orphans {
+			list = append(list, fmt.Sprintf("%s (pid %d)", orphan.Name, orphan.PID))
+		}
+		return fmt.Errorf("%w: %s\nStop them manually or restart with --%s",
+			supervisor.ErrOrphanedProcesses, strings.Join(list, ", "), FlagSupervisorStopOrphans)
+	}
+
+	for _, orphan := range orphans {
+		logger.Warn().Str("component", orphan.Name).Int("pid", orphan.PID).Msg("Stopping subprocess left running by a previous run")
+	}
+
+	return supervisor.StopOrphans(orphans, orphanStopTimeout)
+}
+
+// restartPolicy returns the restart policy of a subprocess from its restart mode and
+// limit flags and the backoff of the supervisor flags
+func restartPolicy(cmd *cobra.Command, modeFlag, maxRestartsFlag string) (supervisor.RestartPolicy, error) {
+	modeName, err := cmd.Flags().GetString(modeFlag)
+	if err != nil {
+		return supervisor.RestartPolicy{}, fmt.Errorf("failed to get '%s' flag: %w", modeFlag, err)
+	}
+	mode, err := supervisor.ParseRestartMode(modeName)
+	if err != nil {
+		return supervisor.RestartPolicy{}, fmt.Errorf("invalid %s: %w", modeFlag, err)
+	}
+
+	// Components without their own limit share the supervisor's
+	maxRestarts, err := cmd.Flags().GetInt(maxRestartsFlag)
+	if err != nil {
+		return supervisor.RestartPolicy{}, fmt.Errorf("failed to get '%s' flag: %w", maxRestartsFlag, err)
+	}
+	if maxRestarts < 0 {
+		if maxRestarts, err = cmd.Flags().GetInt(FlagSupervisorMaxRestarts); err != nil {
+			return supervisor.RestartPolicy{}, fmt.Errorf("failed to get '%s' flag: %w", FlagSupervisorMaxRestarts, err)
+		}
+	}
+
+	backoff, err := cmd.Flags().GetDuration(FlagSupervisorRestartBackoff)
+	if err != nil {
+		return supervisor.RestartPolicy{}, fmt.Errorf("failed to get '%s' flag: %w", FlagSupervisorRestartBackoff, err)
+	}
+
+	maxBackoff, err := cmd.Flags().GetDuration(FlagSupervisorRestartMaxBackoff)
+	if err != nil {
+		return supervisor.RestartPolicy{}, fmt.Errorf("failed to get '%s' flag: %w", FlagSupervisorRestartMaxBackoff, err)
+	}
+
+	if maxRestarts < 0 {
+		return supervisor.RestartPolicy{}, fmt.Errorf("%s must be >= 0", FlagSupervisorMaxRestarts)
+	}
+	if backoff <= 0 || maxBackoff < backoff {
+		return supervisor.RestartPolicy{}, fmt.Errorf("%s must be > 0 and <= %s", FlagSupervisorRestartBackoff, FlagSupervisorRestartMaxBackoff)
+	}
+
+	return supervisor.RestartPolicy{
+		Mode:        mode,
+		MaxRestarts: maxRestarts,
+		MinBackoff:  backoff,
+		MaxBackoff:  maxBackoff,
+		StableAfter: restartStableAfter,
+	}, nil
+}
+
+// newSubprocess creates the supervised subprocess name, started from command and
+// restarted according to policy.
+func newSubprocess(cmd *cobra.Command, logger zerolog.Logger, nodeConfig config.Config, metrics *supervisor.Metrics, policy supervisor.RestartPolicy, name string, command func(ctx context.Context) *exec.Cmd, probe supervisor.Probe) *supervisor.Process {
+	ready := func(ctx context.Context, exited <-chan struct{}) error {
+		return waitReady(ctx, cmd, logger, name, exited, probe)
+	}
+
+	return supervisor.NewProcess(name, command, ready, pidDir(nodeConfig), policy, logger, metrics)
+}
+
+// commandOptionsFromFlags reads the environment, extra arguments and working directory
+// of a subprocess from command flags
+func commandOptionsFromFlags(cmd *cobra.Command, envFlag, argsFlag, dirFlag string) (supervisor.CommandOptions, error) {
+	env, err := cmd.Flags().GetStringArray(envFlag)
+	if err != nil {
+		return supervisor.CommandOptions{}, fmt.Errorf("failed to get '%s' flag: %w", envFlag, err)
+	}
+	args, err := cmd.Flags().GetStringArray(argsFlag)
+	if err != nil {
+		return supervisor.CommandOptions{}, fmt.Errorf("failed to get '%s' flag: %w", argsFlag, err)
+	}
+	dir, err := cmd.Flags().GetString(dirFlag)
+	if err != nil {
+		return supervisor.CommandOptions{}, fmt.Errorf("failed to get '%s' flag: %w", dirFlag, err)
+	}
+
+	opts := supervisor.CommandOptions{Env: env, Args: args, Dir: dir}
+	if err := opts.Validate(); err != nil {
+		return supervisor.CommandOptions{}, fmt.Errorf("invalid %s: %w", envFlag, err)
+	}
+	if dir != "" {
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			return supervisor.CommandOptions{}, fmt.Errorf("%s %s is not a directory", dirFlag, dir)
+		}
+	}
+	return opts, nil
+}
+
+// limitsFromFlags reads the resource limits of a subprocess from command flags
+func limitsFromFlags(cmd *cobra.Command, openFilesFlag, memoryFlag, niceFlag string) (supervisor.Limits, error) {
+	openFiles, err := cmd.Flags().GetUint64(openFilesFlag)
+	if err != nil {
+		return supervisor.Limits{}, fmt.Errorf("failed to get '%s' flag: %w", openFilesFlag, err)
+	}
+	memory, err := cmd.Flags().GetUint64(memoryFlag)
+	if err != nil {
+		return supervisor.Limits{}, fmt.Errorf("failed to get '%s' flag: %w", memoryFlag, err)
+	}
+	nice, err := cmd.Flags().GetInt(niceFlag)
+	if err != nil {
+		return supervisor.Limits{}, fmt.Errorf("failed to get '%s' flag: %w", niceFlag, err)
+	}
+
+	limits := supervisor.Limits{OpenFiles: openFiles, Memory: memory, Nice: nice}
+	if err := limits.Validate(); err != nil {
+		return supervisor.Limits{}, fmt.Errorf("invalid %s, %s or %s: %w", openFilesFlag, memoryFlag, niceFlag, err)
+	}
+	return limits, nil
+}
+
+// subprocessOutput returns the stdout and stderr writers of subprocess name. Each line
+// is logged through logger tagged with the component and stream, unless
+// --supervisor.raw-logs is set. When log files are enabled, both streams are also
+// written as is to logFile in the log directory.
+func subprocessOutput(cmd *cobra.Command, logger zerolog.Logger, files *logFiles, name, logFile string) (io.Writer, io.Writer, error) {
+	raw, err := cmd.Flags().GetBool(FlagSupervisorRawLogs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get '%s' flag: %w", FlagSupervisorRawLogs, err)
+	}
+
+	var stdout, stderr io.Writer = os.Stdout, os.Stderr
+	if !raw {
+		// Lines without a level on stderr are mostly panics and crash reports
+		stdout = supervisor.NewLogWriter(logger, name, "stdout", zerolog.InfoLevel)
+		stderr = supervisor.NewLogWriter(logger, name, "stderr", zerolog.WarnLevel)
+	}
+
+	file, err := files.open(logFile)
+	if err != nil {
+		return nil, nil, err
+	}
+	if file != nil {
+		// A failing log file, e.g. on a full disk, must not stop the subprocess output
+		file = bestEffortWriter{file}
+		stdout, stderr = io.MultiWriter(stdout, file), io.MultiWriter(stderr, file)
+	}
+	return stdout, stderr, nil
+}
+
+// waitReady waits until the subprocess name answers probe, failing early if it exits.
+func waitReady(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, name string, exited <-chan struct{}, probe supervisor.Probe) error {
+	timeout, err := cmd.Flags().GetDuration(FlagSupervisorReadyTimeout)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagSupervisorReadyTimeout, err)
+	}
+
+	if timeout <= 0 {
+		return fmt.Errorf("%s must be > 0", FlagSupervisorReadyTimeout)
+	}
+
+	took, err := supervisor.WaitReady(ctx, timeout, exited, probe)
+	if err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+
+	logger.Info().Str("component", name).Dur("took", took).Msg("Subprocess is ready")
+	return nil
+}
+
+// jsonRPCProbe returns a probe succeeding once the JSON-RPC server at url answers a
+// request. Any HTTP response counts, since only the transport matters here.
+func jsonRPCProbe(url string) supervisor.Probe {
+	client := &http.Client{Timeout: time.Second}
+	body := []byte(`{"jsonrpc":"2.0","id":1,"method":"da.GasPrice","params":[]}`)
+
+	return func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Content-Type", "application/json")
+
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		return resp.Body.Close()
+	}
+}
+
+// executorProbe returns a probe succeeding once the execution service answers a health
+// check, which bypasses the mempool tracking and metrics of executor.
+func executorProbe(executor *grpc.Client) supervisor.Probe {
+	return func(ctx context.Context) error {
+		probeCtx, cancel := context.WithTimeout(ctx, time.Second)
+		defer cancel()
+
+		return executor.Ping(probeCtx)
+	}
+}
@@ -0,0 +1,52 @@
+package main
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorInitChainTimeout is the flag for how long an InitChain call may take
+	FlagExecutorInitChainTimeout = "executor.init-chain-timeout"
+	// FlagExecutorGetTxsTimeout is the flag for how long a GetTxs call may take
+	FlagExecutorGetTxsTimeout = "executor.gettxs-timeout"
+	// FlagExecutorExecuteTimeout is the flag for how long an ExecuteTxs call may take
+	FlagExecutorExecuteTimeout = "executor.execute-timeout"
+	// FlagExecutorSetFinalTimeout is the flag for how long a SetFinal call may take
+	FlagExecutorSetFinalTimeout = "executor.set-final-timeout"
+)
+
+// addExecutorTimeoutFlags adds flags for the timeouts of calls to the execution layer
+func addExecutorTimeoutFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration(FlagExecutorInitChainTimeout, 0, "Timeout of each InitChain attempt (0 disables it)")
+	cmd.Flags().Duration(FlagExecutorGetTxsTimeout, 10*time.Second, "Timeout of each GetTxs attempt (0 disables it)")
+	cmd.Flags().Duration(FlagExecutorExecuteTimeout, 30*time.Second, "Timeout of each ExecuteTxs attempt (0 disables it)")
+	cmd.Flags().Duration(FlagExecutorSetFinalTimeout, 30*time.Second, "Timeout of each SetFinal attempt (0 disables it)")
+}
+
+// setTimeouts bounds the calls of client to the execution layer by the timeouts
+// configured by flags.
+func setTimeouts(cmd *cobra.Command, client *grpc.Client) error {
+	var timeouts grpc.Timeouts
+	for flag, timeout := range map[string]*time.Duration{
+		FlagExecutorInitChainTimeout: &timeouts.InitChain,
+		FlagExecutorGetTxsTimeout:    &timeouts.GetTxs,
+		FlagExecutorExecuteTimeout:   &timeouts.ExecuteTxs,
+		FlagExecutorSetFinalTimeout:  &timeouts.SetFinal,
+	} {
+		var err error
+		if *timeout, err = cmd.Flags().GetDuration(flag); err != nil {
+			return fmt.Errorf("failed to get '%s' flag: %w", flag, err)
+		}
+		if *timeout < 0 {
+			return fmt.Errorf("%s must be >= 0, got %s", flag, *timeout)
+		}
+	}
+
+	client.SetTimeouts(timeouts)
+	return nil
+}
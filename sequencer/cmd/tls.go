@@ -0,0 +1,67 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorTLS is the flag for connecting to the execution service over TLS
+	FlagExecutorTLS = "executor.tls"
+	// FlagExecutorTLSCA is the flag for the CAs trusted to sign the execution service certificate
+	FlagExecutorTLSCA = "executor.tls-ca"
+	// FlagExecutorTLSCert is the flag for the client certificate presented to the execution service
+	FlagExecutorTLSCert = "executor.tls-cert"
+	// FlagExecutorTLSKey is the flag for the key of the client certificate
+	FlagExecutorTLSKey = "executor.tls-key"
+	// FlagExecutorTLSServerName is the flag for the name the execution service certificate is verified against
+	FlagExecutorTLSServerName = "executor.tls-server-name"
+)
+
+// addExecutorTLSFlags adds flags for TLS on the connection to the execution service
+func addExecutorTLSFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagExecutorTLS, false, "Connect to the execution service over TLS (https), implied by the other executor.tls-* flags")
+	cmd.Flags().String(FlagExecutorTLSCA, "", "PEM file of the CAs the execution service certificate must chain to (empty trusts the system roots)")
+	cmd.Flags().String(FlagExecutorTLSCert, "", "PEM client certificate presented to the execution service for mutual TLS")
+	cmd.Flags().String(FlagExecutorTLSKey, "", "PEM private key of --"+FlagExecutorTLSCert)
+	cmd.Flags().String(FlagExecutorTLSServerName, "", "Name the execution service certificate is verified against (empty uses the host of the address)")
+}
+
+// executionClient creates the client of the execution service at url. It connects
+// over TLS if url is https or TLS is configured by flags, upgrading an http url.
+func executionClient(cmd *cobra.Command, url string) (*grpc.Client, error) {
+	enabled, err := cmd.Flags().GetBool(FlagExecutorTLS)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorTLS, err)
+	}
+
+	var cfg grpc.TLSConfig
+	for name, value := range map[string]*string{
+		FlagExecutorTLSCA:         &cfg.CAFile,
+		FlagExecutorTLSCert:       &cfg.CertFile,
+		FlagExecutorTLSKey:        &cfg.KeyFile,
+		FlagExecutorTLSServerName: &cfg.ServerName,
+	} {
+		if *value, err = cmd.Flags().GetString(name); err != nil {
+			return nil, fmt.Errorf("failed to get '%s' flag: %w", name, err)
+		}
+		enabled = enabled || *value != ""
+	}
+
+	if rest, ok := strings.CutPrefix(url, "http://"); ok && enabled {
+		url = "https://" + rest
+	}
+	if !strings.HasPrefix(url, "https://") {
+		return grpc.NewClient(url), nil
+	}
+
+	tlsConfig, err := cfg.Load()
+	if err != nil {
+		return nil, fmt.Errorf("invalid execution service TLS configuration: %w", err)
+	}
+	return grpc.NewTLSClient(url, tlsConfig), nil
+}
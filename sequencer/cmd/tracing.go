@@ -0,0 +1,33 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.opentelemetry.io/otel"
+
+	"github.com/pranklin/pranklin-sequencer/grpc"
+)
+
+const (
+	// FlagExecutorTrace is the flag for tracing calls to the execution layer
+	FlagExecutorTrace = "executor.trace"
+)
+
+// addExecutorTracingFlags adds flags for tracing calls to the execution layer
+func addExecutorTracingFlags(cmd *cobra.Command) {
+	cmd.Flags().Bool(FlagExecutorTrace, false, "Trace each call to the execution layer with the OpenTelemetry tracer provider of the process and send its W3C trace context (traceparent)")
+}
+
+// setTracing traces the calls of client to the execution layer if enabled by flags.
+func setTracing(cmd *cobra.Command, client *grpc.Client) error {
+	enabled, err := cmd.Flags().GetBool(FlagExecutorTrace)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagExecutorTrace, err)
+	}
+
+	if enabled {
+		client.SetTracing(otel.GetTracerProvider())
+	}
+	return nil
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"context"
+	"fmt"
+
+	ds "github.com/ipfs/go-datastore"
+	"github.com/rs/zerolog"
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/config"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+const (
+	// FlagDAVerifyInterval is the flag for the interval between DA blob integrity checks
+	FlagDAVerifyInterval = "da.verify-interval"
+	// FlagDAVerifySamples is the flag for the number of heights sampled per integrity check
+	FlagDAVerifySamples = "da.verify-samples"
+)
+
+// addDAVerifierFlags adds flags for the background DA blob integrity verifier
+func addDAVerifierFlags(cmd *cobra.Command) {
+	cmd.Flags().Duration(FlagDAVerifyInterval, 0, "Interval between DA blob integrity checks of historical heights (0 disables the verifier)")
+	cmd.Flags().Int(FlagDAVerifySamples, 5, "Number of historical heights refetched from DA per integrity check")
+}
+
+// startDAVerifier starts the background DA blob integrity verifier if it is enabled.
+// The verifier stops when ctx is cancelled.
+func startDAVerifier(ctx context.Context, cmd *cobra.Command, logger zerolog.Logger, datastore ds.Batching, daLayer da.DA, nodeConfig config.Config, chainID string) error {
+	interval, err := cmd.Flags().GetDuration(FlagDAVerifyInterval)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDAVerifyInterval, err)
+	}
+
+	if interval <= 0 {
+		return nil
+	}
+
+	samples, err := cmd.Flags().GetInt(FlagDAVerifySamples)
+	if err != nil {
+		return fmt.Errorf("failed to get '%s' flag: %w", FlagDAVerifySamples, err)
+	}
+
+	if samples <= 0 {
+		return fmt.Errorf("%s must be > 0", FlagDAVerifySamples)
+	}
+
+	metrics, err := seqda.DefaultMetricsProvider(nodeConfig.Instrumentation.IsPrometheusEnabled())(chainID)
+	if err != nil {
+		return err
+	}
+
+	verifier := seqda.NewVerifier(seqda.VerifierConfig{
+		Interval:        interval,
+		Samples:         samples,
+		HeaderNamespace: da.NamespaceFromString(nodeConfig.DA.GetNamespace()).Bytes(),
+		DataNamespace:   da.NamespaceFromString(nodeConfig.DA.GetDataNamespace()).Bytes(),
+	}, nodeStore(datastore), daLayer, logger, metrics)
+
+	logger.Info().Dur("interval", interval).Int("samples", samples).Msg("Starting DA blob integrity verifier")
+	go verifier.Run(ctx)
+
+	return nil
+}
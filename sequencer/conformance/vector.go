@@ -0,0 +1,140 @@
+// Package conformance generates executor test vectors from a node store and checks
+// ExecutorService implementations against them.
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/pkg/genesis"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// ErrStateRootMismatch is returned when an executor computes a state root other than
+// the one recorded in a vector.
+var ErrStateRootMismatch = errors.New("state root mismatch")
+
+// Vector is a canonical sequence of blocks an executor must replay from genesis to the
+// recorded state roots.
+type Vector struct {
+	ChainID       string    `json:"chain_id"`
+	InitialHeight uint64    `json:"initial_height"`
+	GenesisTime   time.Time `json:"genesis_time"`
+	// GenesisStateRoot is the state root InitChain must return
+	GenesisStateRoot []byte  `json:"genesis_state_root"`
+	Blocks           []Block `json:"blocks"`
+}
+
+// Block is a block of a vector with the state root ExecuteTxs must return for it.
+type Block struct {
+	Height    uint64    `json:"height"`
+	Time      time.Time `json:"time"`
+	Txs       [][]byte  `json:"txs"`
+	StateRoot []byte    `json:"state_root"`
+}
+
+// Generate builds a vector from the blocks of st from the genesis up to height to, as
+// executed by the node: the state root before each block is the one its header commits
+// to, and the root after it is the one the node saved with its state.
+func Generate(ctx context.Context, st store.Reader, gen genesis.Genesis, to uint64) (*Vector, error) {
+	if to < gen.InitialHeight {
+		return nil, fmt.Errorf("no stored blocks from the initial height %d to %d", gen.InitialHeight, to)
+	}
+
+	vector := &Vector{
+		ChainID:       gen.ChainID,
+		InitialHeight: gen.InitialHeight,
+		GenesisTime:   gen.StartTime.UTC(),
+	}
+
+	for height := gen.InitialHeight; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		header, data, err := st.GetBlockData(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block %d: %w", height, err)
+		}
+		if height == gen.InitialHeight {
+			vector.GenesisStateRoot = header.AppHash
+		}
+
+		state, err := st.GetStateAtHeight(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load state after block %d: %w", height, err)
+		}
+
+		txs := make([][]byte, len(data.Txs))
+		for i, tx := range data.Txs {
+			txs[i] = tx
+		}
+
+		vector.Blocks = append(vector.Blocks, Block{
+			Height:    height,
+			Time:      header.Time().UTC(),
+			Txs:       txs,
+			StateRoot: state.AppHash,
+		})
+	}
+
+	return vector, nil
+}
+
+// Verify replays vector on executor, which must start from an empty state, and returns
+// ErrStateRootMismatch at the first state root it computes differently. Each block is
+// finalized after it is executed, as the node does once it is DA included.
+func Verify(ctx context.Context, executor execution.Executor, vector *Vector) error {
+	stateRoot, _, err := executor.InitChain(ctx, vector.GenesisTime, vector.InitialHeight, vector.ChainID)
+	if err != nil {
+		return fmt.Errorf("failed to initialize chain: %w", err)
+	}
+	if !bytes.Equal(stateRoot, vector.GenesisStateRoot) {
+		return fmt.Errorf("%w at genesis: expected %s, got %s", ErrStateRootMismatch, types.Hash(vector.GenesisStateRoot), types.Hash(stateRoot))
+	}
+
+	prevStateRoot := vector.GenesisStateRoot
+	for _, block := range vector.Blocks {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		stateRoot, _, err := executor.ExecuteTxs(ctx, block.Txs, block.Height, block.Time, prevStateRoot)
+		if err != nil {
+			return fmt.Errorf("failed to execute block %d: %w", block.Height, err)
+		}
+		if !bytes.Equal(stateRoot, block.StateRoot) {
+			return fmt.Errorf("%w at block %d: expected %s, got %s", ErrStateRootMismatch, block.Height, types.Hash(block.StateRoot), types.Hash(stateRoot))
+		}
+
+		if err := executor.SetFinal(ctx, block.Height); err != nil {
+			return fmt.Errorf("failed to finalize block %d: %w", block.Height, err)
+		}
+		prevStateRoot = block.StateRoot
+	}
+
+	return nil
+}
+
+// Write encodes vector as JSON to w.
+func Write(w io.Writer, vector *Vector) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(vector)
+}
+
+// Read decodes a JSON vector from r.
+func Read(r io.Reader) (*Vector, error) {
+	var vector Vector
+	if err := json.NewDecoder(r).Decode(&vector); err != nil {
+		return nil, fmt.Errorf("failed to decode vector: %w", err)
+	}
+	return &vector, nil
+}
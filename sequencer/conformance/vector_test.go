@@ -0,0 +1,94 @@
+package conformance
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+
+	coreexecution "github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/pkg/genesis"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// buildStore stores n blocks executed by a dummy executor, as a node would.
+func buildStore(t *testing.T, n uint64) (store.Store, genesis.Genesis) {
+	t.Helper()
+	ctx := context.Background()
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+	gen, _, _ := types.GetGenesisWithPrivkey("test-chain")
+
+	executor := coreexecution.NewDummyExecutor()
+	stateRoot, _, err := executor.InitChain(ctx, gen.StartTime, gen.InitialHeight, gen.ChainID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for height := gen.InitialHeight; height < gen.InitialHeight+n; height++ {
+		header, data := types.GetRandomBlock(height, 2, gen.ChainID)
+		header.BaseHeader.Time = uint64(gen.StartTime.Add(time.Duration(height) * time.Second).UnixNano())
+		header.AppHash = stateRoot
+
+		txs := [][]byte{data.Txs[0], data.Txs[1]}
+		if stateRoot, _, err = executor.ExecuteTxs(ctx, txs, height, header.Time(), stateRoot); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		batch, _ := st.NewBatch(ctx)
+		if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.SetHeight(height); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.UpdateState(types.State{ChainID: gen.ChainID, LastBlockHeight: height, AppHash: stateRoot}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return st, gen
+}
+
+func TestVector(t *testing.T) {
+	ctx := context.Background()
+	st, gen := buildStore(t, 3)
+
+	vector, err := Generate(ctx, st, gen, 3)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(vector.Blocks) != 3 || len(vector.Blocks[0].Txs) != 2 {
+		t.Fatalf("expected 3 blocks of 2 txs, got %+v", vector.Blocks)
+	}
+
+	var buf bytes.Buffer
+	if err := Write(&buf, vector); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	decoded, err := Read(&buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if err := Verify(ctx, coreexecution.NewDummyExecutor(), decoded); err != nil {
+		t.Fatalf("expected the reference executor to conform, got %v", err)
+	}
+
+	// Dropping a tx of block 2 changes the state root the executor computes
+	decoded.Blocks[1].Txs = decoded.Blocks[1].Txs[:1]
+	if err := Verify(ctx, coreexecution.NewDummyExecutor(), decoded); !errors.Is(err, ErrStateRootMismatch) {
+		t.Errorf("expected %v, got %v", ErrStateRootMismatch, err)
+	}
+
+	if _, err := Generate(ctx, st, gen, 4); err == nil {
+		t.Error("expected an error generating past the stored blocks")
+	}
+}
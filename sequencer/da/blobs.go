@@ -0,0 +1,130 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// ErrBlockBlobNotFound is returned when no blob at a DA height matches the requested block.
+var ErrBlockBlobNotFound = errors.New("block blob not found at DA height")
+
+// BlobRef locates a single blob in the DA layer.
+type BlobRef struct {
+	// DAHeight is the DA height the blob was included at
+	DAHeight uint64
+	// Namespace is the DA namespace the blob was submitted to
+	Namespace []byte
+	// ID identifies the blob in the DA layer
+	ID coreda.ID
+	// Commitment is the DA commitment to the blob
+	Commitment coreda.Commitment
+}
+
+// BlockDAHeights returns the DA heights at which the header and data of the block at
+// height were included, as recorded by the ev-node submitter.
+func BlockDAHeights(ctx context.Context, st store.Reader, height uint64) (headerDAHeight, dataDAHeight uint64, err error) {
+	headerDAHeight, err = blockDAHeight(ctx, st, height, "h")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	dataDAHeight, err = blockDAHeight(ctx, st, height, "d")
+	if err != nil {
+		return 0, 0, err
+	}
+
+	return headerDAHeight, dataDAHeight, nil
+}
+
+// DAIncludedHeight returns the highest block height known to be included in DA.
+func DAIncludedHeight(ctx context.Context, st store.Reader) (uint64, error) {
+	bz, err := st.GetMetadata(ctx, store.DAIncludedHeightKey)
+	if err != nil {
+		return 0, err
+	}
+	if len(bz) != 8 {
+		return 0, fmt.Errorf("invalid DA included height length: %d", len(bz))
+	}
+	return binary.LittleEndian.Uint64(bz), nil
+}
+
+// LocateHeaderBlob finds the blob holding the header at height with the given hash
+// among the blobs included at daHeight in namespace.
+func LocateHeaderBlob(ctx context.Context, daLayer coreda.DA, namespace []byte, daHeight, height uint64, hash types.Hash) (*BlobRef, error) {
+	return locateBlob(ctx, daLayer, namespace, daHeight, func(blob coreda.Blob) bool {
+		var header types.SignedHeader
+		if err := header.UnmarshalBinary(blob); err != nil {
+			return false
+		}
+		return header.Height() == height && bytes.Equal(header.Hash(), hash)
+	})
+}
+
+// LocateDataBlob finds the blob holding block data with the given DA commitment
+// among the blobs included at daHeight in namespace.
+func LocateDataBlob(ctx context.Context, daLayer coreda.DA, namespace []byte, daHeight uint64, commitment types.Hash) (*BlobRef, error) {
+	return locateBlob(ctx, daLayer, namespace, daHeight, func(blob coreda.Blob) bool {
+		var data types.SignedData
+		if err := data.UnmarshalBinary(blob); err != nil {
+			return false
+		}
+		return bytes.Equal(data.DACommitment(), commitment)
+	})
+}
+
+// locateBlob returns a reference to the first blob at daHeight in namespace accepted by match.
+func locateBlob(ctx context.Context, daLayer coreda.DA, namespace []byte, daHeight uint64, match func(coreda.Blob) bool) (*BlobRef, error) {
+	result, err := daLayer.GetIDs(ctx, daHeight, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get IDs at DA height %d: %w", daHeight, err)
+	}
+
+	if len(result.IDs) == 0 {
+		return nil, fmt.Errorf("%w: DA height %d is empty", ErrBlockBlobNotFound, daHeight)
+	}
+
+	blobs, err := daLayer.Get(ctx, result.IDs, namespace)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get blobs at DA height %d: %w", daHeight, err)
+	}
+
+	for i, blob := range blobs {
+		if i >= len(result.IDs) || !match(blob) {
+			continue
+		}
+
+		id := result.IDs[i]
+		_, commitment, err := coreda.SplitID(id)
+		if err != nil {
+			return nil, fmt.Errorf("invalid blob ID at DA height %d: %w", daHeight, err)
+		}
+
+		return &BlobRef{
+			DAHeight:   daHeight,
+			Namespace:  namespace,
+			ID:         id,
+			Commitment: commitment,
+		}, nil
+	}
+
+	return nil, fmt.Errorf("%w: DA height %d", ErrBlockBlobNotFound, daHeight)
+}
+
+// blockDAHeight returns the DA height at which the header ("h") or data ("d") of a block was included.
+func blockDAHeight(ctx context.Context, st store.Reader, height uint64, kind string) (uint64, error) {
+	bz, err := st.GetMetadata(ctx, fmt.Sprintf("%s/%d/%s", store.HeightToDAHeightKey, height, kind))
+	if err != nil {
+		return 0, fmt.Errorf("failed to load DA height for block %d: %w", height, err)
+	}
+	if len(bz) != 8 {
+		return 0, fmt.Errorf("invalid DA height length for block %d: %d", height, len(bz))
+	}
+	return binary.LittleEndian.Uint64(bz), nil
+}
@@ -0,0 +1,87 @@
+package da
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "da_verifier"
+)
+
+// MetricsProvider returns DA verifier Metrics.
+type MetricsProvider func(chainID string) (*Metrics, error)
+
+// DefaultMetricsProvider returns Metrics build using Prometheus client library
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+func DefaultMetricsProvider(enabled bool) MetricsProvider {
+	return func(chainID string) (*Metrics, error) {
+		if enabled {
+			return PrometheusMetrics("chain_id", chainID)
+		}
+		return NopMetrics()
+	}
+}
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of blocks whose blobs were refetched and verified
+	VerifiedBlocks metrics.Counter
+	// Number of blobs whose hash did not match the stored block
+	Mismatches metrics.Counter
+	// Number of verification attempts that failed before a comparison could be made
+	Errors metrics.Counter
+	// Last verified block height
+	LastVerifiedHeight metrics.Gauge
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(labelsAndValues ...string) (*Metrics, error) {
+	if len(labelsAndValues)%2 != 0 {
+		return nil, errors.New("uneven number of labels and values; labels and values should be provided in pairs")
+	}
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		VerifiedBlocks: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "verified_blocks",
+			Help:      "Number of blocks whose DA blobs were refetched and verified.",
+		}, labels).With(labelsAndValues...),
+		Mismatches: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "mismatches",
+			Help:      "Number of DA blobs whose hash did not match the locally stored block.",
+		}, append(labels, "kind")).With(labelsAndValues...),
+		Errors: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "errors",
+			Help:      "Number of verification attempts that could not be completed.",
+		}, labels).With(labelsAndValues...),
+		LastVerifiedHeight: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "last_verified_height",
+			Help:      "The last block height verified against DA.",
+		}, labels).With(labelsAndValues...),
+	}, nil
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() (*Metrics, error) {
+	return &Metrics{
+		VerifiedBlocks:     discard.NewCounter(),
+		Mismatches:         discard.NewCounter(),
+		Errors:             discard.NewCounter(),
+		LastVerifiedHeight: discard.NewGauge(),
+	}, nil
+}
@@ -0,0 +1,223 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/rs/zerolog"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/types"
+)
+
+// Ensure MigrationDA implements the coreda.DA interface
+var _ coreda.DA = (*MigrationDA)(nil)
+
+// MigrationConfig configures a move from one DA network to another.
+type MigrationConfig struct {
+	// CutoverHeight is the first block height submitted to the new network only
+	CutoverHeight uint64
+	// CutOver is set when the blocks below CutoverHeight are already DA included, so
+	// the node restarts reading from the new network
+	CutOver bool
+}
+
+// MigrationDA moves block submission from an old DA network to a new one without
+// halting the chain. Blobs of blocks below the cutover height are written to both
+// networks and read back from each, so the new network is proven during the
+// transition window; blobs of later blocks are only written to the new network.
+//
+// The old network stays authoritative until the cutover: its IDs are returned and its
+// failures fail the submission, while failures of the new network are logged at error
+// level without stopping block production. Reads go to the network blocks are
+// currently submitted to, falling back to the other one for blobs it does not hold.
+// Both networks use the same namespaces.
+type MigrationDA struct {
+	cfg     MigrationConfig
+	old     coreda.DA
+	new     coreda.DA
+	logger  zerolog.Logger
+	cutOver atomic.Bool
+}
+
+// NewMigrationDA creates a DA layer dual-writing to old and new until the cutover.
+//
+// Parameters:
+// - cfg: Cutover height and whether it was already reached
+// - oldDA: The DA network blocks are submitted to before the cutover
+// - newDA: The DA network blocks are submitted to from the cutover on
+// - logger: Logger for failures of the new network and the cutover
+//
+// Returns:
+// - *MigrationDA: The DA layer to hand to the node
+func NewMigrationDA(cfg MigrationConfig, oldDA, newDA coreda.DA, logger zerolog.Logger) *MigrationDA {
+	m := &MigrationDA{
+		cfg:    cfg,
+		old:    oldDA,
+		new:    newDA,
+		logger: logger.With().Str("component", "da-migration").Logger(),
+	}
+	m.cutOver.Store(cfg.CutOver)
+	return m
+}
+
+// CutOver reports whether blocks are submitted to the new network only.
+func (m *MigrationDA) CutOver() bool {
+	return m.cutOver.Load()
+}
+
+// active returns the network blocks are currently submitted to, and the other one.
+func (m *MigrationDA) active() (coreda.DA, coreda.DA) {
+	if m.cutOver.Load() {
+		return m.new, m.old
+	}
+	return m.old, m.new
+}
+
+// Get returns the blobs of ids from the active network, or from the other one if the
+// active network fails to return them.
+func (m *MigrationDA) Get(ctx context.Context, ids []coreda.ID, namespace []byte) ([]coreda.Blob, error) {
+	active, other := m.active()
+	blobs, err := active.Get(ctx, ids, namespace)
+	if err == nil || ctx.Err() != nil {
+		return blobs, err
+	}
+	if blobs, otherErr := other.Get(ctx, ids, namespace); otherErr == nil {
+		return blobs, nil
+	}
+	return nil, err
+}
+
+// GetIDs returns the IDs of the blobs at height of the active network. DA heights are
+// specific to a network, so there is no fallback.
+func (m *MigrationDA) GetIDs(ctx context.Context, height uint64, namespace []byte) (*coreda.GetIDsResult, error) {
+	active, _ := m.active()
+	return active.GetIDs(ctx, height, namespace)
+}
+
+// GetProofs returns the inclusion proofs of ids from the active network, or from the
+// other one if the active network fails to return them.
+func (m *MigrationDA) GetProofs(ctx context.Context, ids []coreda.ID, namespace []byte) ([]coreda.Proof, error) {
+	active, other := m.active()
+	proofs, err := active.GetProofs(ctx, ids, namespace)
+	if err == nil || ctx.Err() != nil {
+		return proofs, err
+	}
+	if proofs, otherErr := other.GetProofs(ctx, ids, namespace); otherErr == nil {
+		return proofs, nil
+	}
+	return nil, err
+}
+
+// Commit computes the commitments of blobs on the active network.
+func (m *MigrationDA) Commit(ctx context.Context, blobs []coreda.Blob, namespace []byte) ([]coreda.Commitment, error) {
+	active, _ := m.active()
+	return active.Commit(ctx, blobs, namespace)
+}
+
+// Validate checks the proofs of ids on the active network.
+func (m *MigrationDA) Validate(ctx context.Context, ids []coreda.ID, proofs []coreda.Proof, namespace []byte) ([]bool, error) {
+	active, _ := m.active()
+	return active.Validate(ctx, ids, proofs, namespace)
+}
+
+// GasPrice returns the gas price of the active network.
+func (m *MigrationDA) GasPrice(ctx context.Context) (float64, error) {
+	active, _ := m.active()
+	return active.GasPrice(ctx)
+}
+
+// GasMultiplier returns the gas multiplier of the active network.
+func (m *MigrationDA) GasMultiplier(ctx context.Context) (float64, error) {
+	active, _ := m.active()
+	return active.GasMultiplier(ctx)
+}
+
+// Submit submits blobs as described on MigrationDA.
+func (m *MigrationDA) Submit(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte) ([]coreda.ID, error) {
+	return m.SubmitWithOptions(ctx, blobs, gasPrice, namespace, nil)
+}
+
+// SubmitWithOptions submits blobs as described on MigrationDA, passing options to the
+// networks written to.
+func (m *MigrationDA) SubmitWithOptions(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte, options []byte) ([]coreda.ID, error) {
+	if m.afterCutover(blobs) {
+		if !m.cutOver.Swap(true) {
+			m.logger.Info().Uint64("height", m.cfg.CutoverHeight).Msg("Cut over to the new DA network")
+		}
+		return m.new.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+	}
+
+	ids, err := m.old.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := verifyBlobs(ctx, m.old, ids, blobs, namespace); err != nil {
+		m.logger.Error().Err(err).Msg("Blobs submitted to the old DA network failed verification")
+	}
+
+	newIDs, err := m.new.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+	if err != nil {
+		m.logger.Error().Err(err).Int("blobs", len(blobs)).Msg("Failed to submit blobs to the new DA network")
+		return ids, nil
+	}
+	if err := verifyBlobs(ctx, m.new, newIDs, blobs, namespace); err != nil {
+		m.logger.Error().Err(err).Msg("Blobs submitted to the new DA network failed verification")
+	}
+	return ids, nil
+}
+
+// afterCutover reports whether every blob of a submission belongs to a block at or
+// above the cutover height. A blob that is not a block header or data counts as
+// before the cutover, so it is written to both networks.
+func (m *MigrationDA) afterCutover(blobs []coreda.Blob) bool {
+	for _, blob := range blobs {
+		height, ok := blobHeight(blob)
+		if !ok || height < m.cfg.CutoverHeight {
+			return false
+		}
+	}
+	return len(blobs) > 0
+}
+
+// blobHeight returns the height of the block whose signed header or data is blob.
+//
+// Either decodes from the other's encoding without an error, since mismatched fields
+// are kept as unknown fields; but the height of data decoded as a header is always 0,
+// so a header is recognized by a nonzero height.
+func blobHeight(blob coreda.Blob) (uint64, bool) {
+	var header types.SignedHeader
+	if err := header.UnmarshalBinary(blob); err == nil && header.Height() > 0 {
+		return header.Height(), true
+	}
+
+	var data types.SignedData
+	if err := data.UnmarshalBinary(blob); err == nil && data.Metadata != nil {
+		return data.Height(), true
+	}
+	return 0, false
+}
+
+// verifyBlobs reads ids back from daLayer and checks that they hold blobs.
+func verifyBlobs(ctx context.Context, daLayer coreda.DA, ids []coreda.ID, blobs []coreda.Blob, namespace []byte) error {
+	if len(ids) != len(blobs) {
+		return fmt.Errorf("%w: %d IDs returned for %d blobs", ErrBlobMismatch, len(ids), len(blobs))
+	}
+
+	got, err := daLayer.Get(ctx, ids, namespace)
+	if err != nil {
+		return fmt.Errorf("failed to read back submitted blobs: %w", err)
+	}
+	if len(got) != len(blobs) {
+		return fmt.Errorf("%w: %d of %d submitted blobs read back", ErrBlobMismatch, len(got), len(blobs))
+	}
+	for i := range blobs {
+		if !bytes.Equal(got[i], blobs[i]) {
+			height, _, _ := coreda.SplitID(ids[i])
+			return fmt.Errorf("%w: blob %d at DA height %d", ErrBlobMismatch, i, height)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,133 @@
+package da
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/types"
+)
+
+// countingDA is a mock DA layer counting the blobs submitted to it
+type countingDA struct {
+	*MockDA
+	submitted int
+}
+
+func (c *countingDA) SubmitWithOptions(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte, options []byte) ([]coreda.ID, error) {
+	ids, err := c.MockDA.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+	if err == nil {
+		c.submitted += len(blobs)
+	}
+	return ids, err
+}
+
+func (c *countingDA) Submit(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte) ([]coreda.ID, error) {
+	return c.SubmitWithOptions(ctx, blobs, gasPrice, namespace, nil)
+}
+
+// blockBlobs returns the header and data blobs of a random block at height.
+func blockBlobs(t *testing.T, height uint64) (headerBlob, dataBlob []byte) {
+	t.Helper()
+
+	header, data := types.GetRandomBlock(height, 2, "test-chain")
+	headerBlob, err := header.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dataBlob, err = (&types.SignedData{Data: *data}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return headerBlob, dataBlob
+}
+
+func TestBlobHeight(t *testing.T) {
+	headerBlob, dataBlob := blockBlobs(t, 7)
+
+	for name, blob := range map[string][]byte{"header": headerBlob, "data": dataBlob} {
+		if height, ok := blobHeight(blob); !ok || height != 7 {
+			t.Errorf("%s: expected height 7, got %d (ok %v)", name, height, ok)
+		}
+	}
+
+	if _, ok := blobHeight([]byte("not a block")); ok {
+		t.Error("expected no height for a blob that is not a block")
+	}
+}
+
+func TestMigrationDA_Submit(t *testing.T) {
+	ctx := context.Background()
+	oldDA := &countingDA{MockDA: NewMockDA(1024*1024, 0, 0, time.Second, MockConfig{})}
+	newDA := &countingDA{MockDA: NewMockDA(1024*1024, 0, 0, time.Second, MockConfig{})}
+	migration := NewMigrationDA(MigrationConfig{CutoverHeight: 5}, oldDA, newDA, zerolog.Nop())
+
+	// Blocks below the cutover are written to both networks
+	headerBlob, dataBlob := blockBlobs(t, 4)
+	ids, err := migration.Submit(ctx, []coreda.Blob{headerBlob, dataBlob}, 0, testHeaderNamespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if oldDA.submitted != 2 || newDA.submitted != 2 {
+		t.Fatalf("expected 2 blobs on each network, got %d old and %d new", oldDA.submitted, newDA.submitted)
+	}
+	if migration.CutOver() {
+		t.Fatal("expected no cutover below the cutover height")
+	}
+
+	// The IDs are those of the old network
+	blobs, err := oldDA.Get(ctx, ids, testHeaderNamespace)
+	if err != nil {
+		t.Fatalf("expected IDs of the old network, got %v", err)
+	}
+	if !bytes.Equal(blobs[0], headerBlob) {
+		t.Fatal("expected the submitted header blob")
+	}
+
+	// From the cutover height, blocks are written to the new network only
+	headerBlob, _ = blockBlobs(t, 5)
+	if _, err := migration.Submit(ctx, []coreda.Blob{headerBlob}, 0, testHeaderNamespace); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if oldDA.submitted != 2 || newDA.submitted != 3 {
+		t.Fatalf("expected the block on the new network only, got %d old and %d new", oldDA.submitted, newDA.submitted)
+	}
+	if !migration.CutOver() {
+		t.Fatal("expected cutover at the cutover height")
+	}
+
+	// Blobs of the old network remain readable after the cutover
+	if _, err := migration.Get(ctx, ids, testHeaderNamespace); err != nil {
+		t.Fatalf("expected fallback to the old network, got %v", err)
+	}
+}
+
+func TestMigrationDA_SubmitFailures(t *testing.T) {
+	ctx := context.Background()
+	working := NewMockDA(1024*1024, 0, 0, time.Second, MockConfig{})
+	failing := NewMockDA(1024*1024, 0, 0, time.Second, MockConfig{SubmitFailureRate: 1})
+	headerBlob, _ := blockBlobs(t, 1)
+
+	// Failures of the new network do not stop block production before the cutover
+	migration := NewMigrationDA(MigrationConfig{CutoverHeight: 5}, working, failing, zerolog.Nop())
+	if _, err := migration.Submit(ctx, []coreda.Blob{headerBlob}, 0, testHeaderNamespace); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Failures of the old network do
+	migration = NewMigrationDA(MigrationConfig{CutoverHeight: 5}, failing, working, zerolog.Nop())
+	if _, err := migration.Submit(ctx, []coreda.Blob{headerBlob}, 0, testHeaderNamespace); !errors.Is(err, ErrSimulatedSubmitFailure) {
+		t.Fatalf("expected simulated failure, got %v", err)
+	}
+
+	// Until the cutover was reached
+	migration = NewMigrationDA(MigrationConfig{CutoverHeight: 1, CutOver: true}, failing, working, zerolog.Nop())
+	if _, err := migration.Submit(ctx, []coreda.Blob{headerBlob}, 0, testHeaderNamespace); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
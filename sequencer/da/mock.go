@@ -0,0 +1,346 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"strings"
+	"sync"
+	"time"
+
+	coreda "github.com/evstack/ev-node/core/da"
+)
+
+// Ensure MockDA implements the coreda.DA interface
+var _ coreda.DA = (*MockDA)(nil)
+
+// ErrSimulatedSubmitFailure is returned by MockDA when a submission is failed on purpose.
+var ErrSimulatedSubmitFailure = errors.New("mock DA: simulated submission failure")
+
+// LatencyKind identifies the distribution used to sample a latency.
+type LatencyKind string
+
+const (
+	// LatencyNone applies no delay
+	LatencyNone LatencyKind = "none"
+	// LatencyFixed always applies the same delay
+	LatencyFixed LatencyKind = "fixed"
+	// LatencyUniform samples uniformly between Min and Max
+	LatencyUniform LatencyKind = "uniform"
+	// LatencyNormal samples from a normal distribution with Mean and StdDev
+	LatencyNormal LatencyKind = "normal"
+)
+
+// LatencyProfile describes how long each DA call is delayed.
+type LatencyProfile struct {
+	Kind   LatencyKind
+	Min    time.Duration
+	Max    time.Duration
+	Mean   time.Duration
+	StdDev time.Duration
+}
+
+// ParseLatencyProfile parses a latency profile from its flag representation.
+//
+// Supported formats:
+//   - "" or "none"
+//   - "fixed:50ms"
+//   - "uniform:10ms-200ms"
+//   - "normal:100ms,30ms" (mean, standard deviation)
+func ParseLatencyProfile(s string) (LatencyProfile, error) {
+	if s == "" || s == string(LatencyNone) {
+		return LatencyProfile{Kind: LatencyNone}, nil
+	}
+
+	kind, params, ok := strings.Cut(s, ":")
+	if !ok {
+		return LatencyProfile{}, fmt.Errorf("invalid latency profile %q: expected <kind>:<params>", s)
+	}
+
+	switch LatencyKind(kind) {
+	case LatencyFixed:
+		d, err := time.ParseDuration(params)
+		if err != nil {
+			return LatencyProfile{}, fmt.Errorf("invalid fixed latency %q: %w", params, err)
+		}
+		return LatencyProfile{Kind: LatencyFixed, Min: d, Max: d}, nil
+	case LatencyUniform:
+		lo, hi, ok := strings.Cut(params, "-")
+		if !ok {
+			return LatencyProfile{}, fmt.Errorf("invalid uniform latency %q: expected <min>-<max>", params)
+		}
+		minD, err := time.ParseDuration(lo)
+		if err != nil {
+			return LatencyProfile{}, fmt.Errorf("invalid uniform latency min %q: %w", lo, err)
+		}
+		maxD, err := time.ParseDuration(hi)
+		if err != nil {
+			return LatencyProfile{}, fmt.Errorf("invalid uniform latency max %q: %w", hi, err)
+		}
+		if maxD < minD {
+			return LatencyProfile{}, fmt.Errorf("invalid uniform latency %q: max is lower than min", params)
+		}
+		return LatencyProfile{Kind: LatencyUniform, Min: minD, Max: maxD}, nil
+	case LatencyNormal:
+		mean, stddev, ok := strings.Cut(params, ",")
+		if !ok {
+			return LatencyProfile{}, fmt.Errorf("invalid normal latency %q: expected <mean>,<stddev>", params)
+		}
+		meanD, err := time.ParseDuration(mean)
+		if err != nil {
+			return LatencyProfile{}, fmt.Errorf("invalid normal latency mean %q: %w", mean, err)
+		}
+		stddevD, err := time.ParseDuration(stddev)
+		if err != nil {
+			return LatencyProfile{}, fmt.Errorf("invalid normal latency stddev %q: %w", stddev, err)
+		}
+		return LatencyProfile{Kind: LatencyNormal, Mean: meanD, StdDev: stddevD}, nil
+	default:
+		return LatencyProfile{}, fmt.Errorf("unknown latency kind %q", kind)
+	}
+}
+
+// sample draws a single latency from the profile. Negative samples are clamped to zero.
+func (p LatencyProfile) sample(rng *rand.Rand) time.Duration {
+	var d time.Duration
+	switch p.Kind {
+	case LatencyFixed:
+		d = p.Min
+	case LatencyUniform:
+		if p.Max > p.Min {
+			d = p.Min + time.Duration(rng.Int64N(int64(p.Max-p.Min)))
+		} else {
+			d = p.Min
+		}
+	case LatencyNormal:
+		d = p.Mean + time.Duration(rng.NormFloat64()*float64(p.StdDev))
+	}
+
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// MockConfig configures the failure behaviour of MockDA.
+type MockConfig struct {
+	// SubmitLatency is applied to Submit and SubmitWithOptions calls
+	SubmitLatency LatencyProfile
+	// RetrieveLatency is applied to Get, GetIDs and GetProofs calls
+	RetrieveLatency LatencyProfile
+	// SubmitFailureRate is the probability (0..1) that a submission fails
+	SubmitFailureRate float64
+	// ReorgInterval triggers a reorg every N successful submissions (0 disables reorgs)
+	ReorgInterval uint64
+	// ReorgDepth is the number of most recent DA heights dropped by a reorg
+	ReorgDepth uint64
+	// Seed makes the failure and latency sequence reproducible
+	Seed uint64
+}
+
+// MockDA is an in-process DA layer with programmable latency, submission failures and reorgs.
+//
+// It stores blobs in a coreda.DummyDA and applies the configured profiles on top of it.
+// A reorg hides every blob included in the last ReorgDepth DA heights, as if those
+// blocks had been dropped by the DA chain.
+type MockDA struct {
+	*coreda.DummyDA
+
+	cfg MockConfig
+
+	mu          sync.Mutex
+	rng         *rand.Rand
+	submissions uint64
+	lastHeight  uint64
+	dropped     map[uint64]struct{}
+}
+
+// NewMockDA creates a new in-process mock DA layer.
+//
+// Parameters:
+// - maxBlobSize: Maximum size of a single blob (and of a submission batch)
+// - gasPrice: Gas price reported by the DA layer
+// - gasMultiplier: Gas multiplier reported by the DA layer
+// - blockTime: Interval at which the simulated DA height advances
+// - cfg: Latency and failure configuration
+//
+// Returns:
+// - *MockDA: The mock DA layer; call Start to begin producing DA heights
+func NewMockDA(maxBlobSize uint64, gasPrice, gasMultiplier float64, blockTime time.Duration, cfg MockConfig) *MockDA {
+	return &MockDA{
+		DummyDA: coreda.NewDummyDA(maxBlobSize, gasPrice, gasMultiplier, blockTime),
+		cfg:     cfg,
+		rng:     rand.New(rand.NewPCG(cfg.Seed, cfg.Seed>>1|1)),
+		dropped: make(map[uint64]struct{}),
+	}
+}
+
+// Start starts advancing the simulated DA height.
+func (m *MockDA) Start() {
+	m.StartHeightTicker()
+}
+
+// Stop stops advancing the simulated DA height.
+func (m *MockDA) Stop() {
+	m.StopHeightTicker()
+}
+
+// Get returns blobs for the given IDs, failing for blobs dropped by a reorg.
+func (m *MockDA) Get(ctx context.Context, ids []coreda.ID, namespace []byte) ([]coreda.Blob, error) {
+	if err := m.delay(ctx, m.cfg.RetrieveLatency); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if m.isDropped(id) {
+			return nil, coreda.ErrBlobNotFound
+		}
+	}
+
+	return m.DummyDA.Get(ctx, ids, namespace)
+}
+
+// GetIDs returns IDs of all blobs at the given height that survived reorgs.
+func (m *MockDA) GetIDs(ctx context.Context, height uint64, namespace []byte) (*coreda.GetIDsResult, error) {
+	if err := m.delay(ctx, m.cfg.RetrieveLatency); err != nil {
+		return nil, err
+	}
+
+	result, err := m.DummyDA.GetIDs(ctx, height, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	_, dropped := m.dropped[height]
+	m.mu.Unlock()
+	if dropped {
+		result.IDs = []coreda.ID{}
+	}
+
+	return result, nil
+}
+
+// GetProofs returns inclusion proofs for the given IDs.
+func (m *MockDA) GetProofs(ctx context.Context, ids []coreda.ID, namespace []byte) ([]coreda.Proof, error) {
+	if err := m.delay(ctx, m.cfg.RetrieveLatency); err != nil {
+		return nil, err
+	}
+
+	for _, id := range ids {
+		if m.isDropped(id) {
+			return nil, coreda.ErrBlobNotFound
+		}
+	}
+
+	return m.DummyDA.GetProofs(ctx, ids, namespace)
+}
+
+// Validate validates commitments against proofs; dropped blobs never validate.
+func (m *MockDA) Validate(ctx context.Context, ids []coreda.ID, proofs []coreda.Proof, namespace []byte) ([]bool, error) {
+	results, err := m.DummyDA.Validate(ctx, ids, proofs, namespace)
+	if err != nil {
+		return nil, err
+	}
+
+	for i, id := range ids {
+		if m.isDropped(id) {
+			results[i] = false
+		}
+	}
+
+	return results, nil
+}
+
+// Submit submits blobs to the mock DA layer.
+func (m *MockDA) Submit(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte) ([]coreda.ID, error) {
+	return m.SubmitWithOptions(ctx, blobs, gasPrice, namespace, nil)
+}
+
+// SubmitWithOptions submits blobs to the mock DA layer, applying the configured
+// latency, failure rate and reorg schedule.
+func (m *MockDA) SubmitWithOptions(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte, options []byte) ([]coreda.ID, error) {
+	if err := m.delay(ctx, m.cfg.SubmitLatency); err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	fail := m.cfg.SubmitFailureRate > 0 && m.rng.Float64() < m.cfg.SubmitFailureRate
+	m.mu.Unlock()
+	if fail {
+		return nil, ErrSimulatedSubmitFailure
+	}
+
+	ids, err := m.DummyDA.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+	if err != nil {
+		return nil, err
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, id := range ids {
+		if height, _, err := coreda.SplitID(id); err == nil && height > m.lastHeight {
+			m.lastHeight = height
+		}
+	}
+
+	m.submissions++
+	if m.cfg.ReorgInterval > 0 && m.submissions%m.cfg.ReorgInterval == 0 {
+		m.reorgLocked()
+	}
+
+	return ids, nil
+}
+
+// Reorg drops the blobs included in the last ReorgDepth DA heights.
+func (m *MockDA) Reorg() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reorgLocked()
+}
+
+func (m *MockDA) reorgLocked() {
+	depth := max(m.cfg.ReorgDepth, 1)
+	for i := uint64(0); i < depth && i < m.lastHeight; i++ {
+		m.dropped[m.lastHeight-i] = struct{}{}
+	}
+}
+
+// isDropped reports whether the blob identified by id was dropped by a reorg.
+func (m *MockDA) isDropped(id coreda.ID) bool {
+	height, _, err := coreda.SplitID(id)
+	if err != nil {
+		return false
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	_, dropped := m.dropped[height]
+	return dropped
+}
+
+// delay sleeps for a latency sampled from profile, returning early if ctx is done.
+func (m *MockDA) delay(ctx context.Context, profile LatencyProfile) error {
+	if profile.Kind == "" || profile.Kind == LatencyNone {
+		return nil
+	}
+
+	m.mu.Lock()
+	d := profile.sample(m.rng)
+	m.mu.Unlock()
+	if d == 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
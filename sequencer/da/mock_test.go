@@ -0,0 +1,123 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	coreda "github.com/evstack/ev-node/core/da"
+)
+
+func TestParseLatencyProfile(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		want    LatencyProfile
+		wantErr bool
+	}{
+		{name: "empty", input: "", want: LatencyProfile{Kind: LatencyNone}},
+		{name: "none", input: "none", want: LatencyProfile{Kind: LatencyNone}},
+		{name: "fixed", input: "fixed:50ms", want: LatencyProfile{Kind: LatencyFixed, Min: 50 * time.Millisecond, Max: 50 * time.Millisecond}},
+		{name: "uniform", input: "uniform:10ms-200ms", want: LatencyProfile{Kind: LatencyUniform, Min: 10 * time.Millisecond, Max: 200 * time.Millisecond}},
+		{name: "normal", input: "normal:100ms,30ms", want: LatencyProfile{Kind: LatencyNormal, Mean: 100 * time.Millisecond, StdDev: 30 * time.Millisecond}},
+		{name: "missing params", input: "fixed", wantErr: true},
+		{name: "unknown kind", input: "pareto:1s", wantErr: true},
+		{name: "inverted uniform", input: "uniform:200ms-10ms", wantErr: true},
+		{name: "bad duration", input: "fixed:soon", wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseLatencyProfile(tt.input)
+			if tt.wantErr {
+				if err == nil {
+					t.Fatalf("expected error but got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestMockDA_SubmitFailure(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockDA(1024, 0, 0, 10*time.Millisecond, MockConfig{SubmitFailureRate: 1})
+
+	_, err := mock.Submit(ctx, []coreda.Blob{[]byte("blob")}, 0, nil)
+	if !errors.Is(err, ErrSimulatedSubmitFailure) {
+		t.Fatalf("expected simulated failure, got %v", err)
+	}
+}
+
+func TestMockDA_SubmitLatency(t *testing.T) {
+	ctx := context.Background()
+	latency := 30 * time.Millisecond
+	mock := NewMockDA(1024, 0, 0, 10*time.Millisecond, MockConfig{
+		SubmitLatency: LatencyProfile{Kind: LatencyFixed, Min: latency, Max: latency},
+	})
+
+	start := time.Now()
+	if _, err := mock.Submit(ctx, []coreda.Blob{[]byte("blob")}, 0, nil); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed < latency {
+		t.Errorf("expected submission to take at least %v, took %v", latency, elapsed)
+	}
+
+	// A cancelled context aborts the simulated delay
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := mock.Submit(cancelled, []coreda.Blob{[]byte("blob")}, 0, nil); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected context.Canceled, got %v", err)
+	}
+}
+
+func TestMockDA_Reorg(t *testing.T) {
+	ctx := context.Background()
+	mock := NewMockDA(1024, 0, 0, 10*time.Millisecond, MockConfig{ReorgInterval: 2, ReorgDepth: 1})
+	mock.Start()
+	defer mock.Stop()
+
+	waitForHeight := func() {
+		time.Sleep(20 * time.Millisecond)
+	}
+
+	first, err := mock.Submit(ctx, []coreda.Blob{[]byte("first")}, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForHeight()
+
+	second, err := mock.Submit(ctx, []coreda.Blob{[]byte("second")}, 0, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	waitForHeight()
+
+	// The second submission triggers a reorg of the last height only
+	if _, err := mock.Get(ctx, first, nil); err != nil {
+		t.Errorf("expected first blob to survive the reorg, got %v", err)
+	}
+	if _, err := mock.Get(ctx, second, nil); !errors.Is(err, coreda.ErrBlobNotFound) {
+		t.Errorf("expected second blob to be dropped, got %v", err)
+	}
+
+	height, _, err := coreda.SplitID(second[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	result, err := mock.GetIDs(ctx, height, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(result.IDs) != 0 {
+		t.Errorf("expected no IDs at reorged height, got %d", len(result.IDs))
+	}
+}
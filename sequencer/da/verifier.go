@@ -0,0 +1,143 @@
+package da
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/store"
+)
+
+// ErrBlobMismatch is returned when a blob fetched from DA does not match the locally stored block.
+var ErrBlobMismatch = errors.New("DA blob does not match stored block")
+
+// VerifierConfig configures the background blob integrity verifier.
+type VerifierConfig struct {
+	// Interval between verification rounds
+	Interval time.Duration
+	// Samples is the number of historical heights verified per round
+	Samples int
+	// HeaderNamespace is the DA namespace headers are submitted to
+	HeaderNamespace []byte
+	// DataNamespace is the DA namespace block data is submitted to
+	DataNamespace []byte
+}
+
+// Verifier periodically samples DA-included heights, refetches their blobs from DA
+// and compares them against the headers and data stored locally.
+//
+// It guards against silent corruption of the local store: a mismatch is logged at
+// error level and counted in Metrics, but never modifies the store.
+type Verifier struct {
+	cfg     VerifierConfig
+	store   store.Store
+	da      coreda.DA
+	logger  zerolog.Logger
+	metrics *Metrics
+	rng     *rand.Rand
+}
+
+// NewVerifier creates a new blob integrity verifier.
+//
+// Parameters:
+// - cfg: Sampling interval, sample size and namespaces
+// - st: The local block store to verify
+// - daLayer: The DA layer to refetch blobs from
+// - logger: Logger used to report mismatches
+// - metrics: Verifier metrics
+//
+// Returns:
+// - *Verifier: The initialized verifier; call Run to start it
+func NewVerifier(cfg VerifierConfig, st store.Store, daLayer coreda.DA, logger zerolog.Logger, metrics *Metrics) *Verifier {
+	return &Verifier{
+		cfg:     cfg,
+		store:   st,
+		da:      daLayer,
+		logger:  logger.With().Str("component", "da-verifier").Logger(),
+		metrics: metrics,
+		rng:     rand.New(rand.NewPCG(uint64(time.Now().UnixNano()), 0)),
+	}
+}
+
+// Run verifies samples every Interval until ctx is done.
+func (v *Verifier) Run(ctx context.Context) {
+	ticker := time.NewTicker(v.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			v.verifyRound(ctx)
+		}
+	}
+}
+
+// verifyRound verifies up to Samples randomly chosen DA-included heights.
+func (v *Verifier) verifyRound(ctx context.Context) {
+	included, err := DAIncludedHeight(ctx, v.store)
+	if err != nil || included == 0 {
+		return
+	}
+
+	for i := 0; i < v.cfg.Samples; i++ {
+		height := v.rng.Uint64N(included) + 1
+
+		err := v.VerifyHeight(ctx, height)
+		switch {
+		case err == nil:
+			v.metrics.VerifiedBlocks.Add(1)
+			v.metrics.LastVerifiedHeight.Set(float64(height))
+		case errors.Is(err, ErrBlobMismatch):
+			v.logger.Error().Err(err).Uint64("height", height).Msg("🚨 DA blob integrity check failed")
+		case ctx.Err() != nil:
+			return
+		default:
+			v.metrics.Errors.Add(1)
+			v.logger.Warn().Err(err).Uint64("height", height).Msg("Could not verify DA blobs")
+		}
+	}
+}
+
+// VerifyHeight refetches the header and data blobs of the block at height from DA and
+// compares their hashes against the stored block.
+func (v *Verifier) VerifyHeight(ctx context.Context, height uint64) error {
+	header, data, err := v.store.GetBlockData(ctx, height)
+	if err != nil {
+		return fmt.Errorf("failed to load block %d from store: %w", height, err)
+	}
+
+	headerDAHeight, dataDAHeight, err := BlockDAHeights(ctx, v.store, height)
+	if err != nil {
+		return err
+	}
+
+	_, err = LocateHeaderBlob(ctx, v.da, v.cfg.HeaderNamespace, headerDAHeight, height, header.Hash())
+	if errors.Is(err, ErrBlockBlobNotFound) {
+		v.metrics.Mismatches.With("kind", "header").Add(1)
+		return fmt.Errorf("%w: header at height %d not found at DA height %d", ErrBlobMismatch, height, headerDAHeight)
+	} else if err != nil {
+		return err
+	}
+
+	// Data with no transactions is never published to DA
+	if len(data.Txs) == 0 {
+		return nil
+	}
+
+	_, err = LocateDataBlob(ctx, v.da, v.cfg.DataNamespace, dataDAHeight, data.DACommitment())
+	if errors.Is(err, ErrBlockBlobNotFound) {
+		v.metrics.Mismatches.With("kind", "data").Add(1)
+		return fmt.Errorf("%w: data at height %d not found at DA height %d", ErrBlobMismatch, height, dataDAHeight)
+	} else if err != nil {
+		return err
+	}
+
+	return nil
+}
@@ -0,0 +1,112 @@
+package da
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+var (
+	testHeaderNamespace = []byte("headers")
+	testDataNamespace   = []byte("data")
+)
+
+// storeBlock saves a block locally and records the DA heights of its blobs.
+func storeBlock(t *testing.T, st store.Store, header *types.SignedHeader, data *types.Data, headerDAHeight, dataDAHeight uint64) {
+	t.Helper()
+	ctx := context.Background()
+
+	batch, err := st.NewBatch(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := batch.SetHeight(header.Height()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	for kind, daHeight := range map[string]uint64{"h": headerDAHeight, "d": dataDAHeight} {
+		bz := make([]byte, 8)
+		binary.LittleEndian.PutUint64(bz, daHeight)
+		key := fmt.Sprintf("%s/%d/%s", store.HeightToDAHeightKey, header.Height(), kind)
+		if err := st.SetMetadata(ctx, key, bz); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+// submitBlob submits blob to daLayer and returns the DA height it was included at.
+func submitBlob(t *testing.T, daLayer coreda.DA, blob []byte, namespace []byte) uint64 {
+	t.Helper()
+
+	ids, err := daLayer.Submit(context.Background(), []coreda.Blob{blob}, 0, namespace)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	height, _, err := coreda.SplitID(ids[0])
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return height
+}
+
+func TestVerifier_VerifyHeight(t *testing.T) {
+	ctx := context.Background()
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+	daLayer := NewMockDA(1024*1024, 0, 0, 10*time.Millisecond, MockConfig{})
+	daLayer.Start()
+	defer daLayer.Stop()
+
+	metrics, _ := NopMetrics()
+	verifier := NewVerifier(VerifierConfig{
+		Interval:        time.Second,
+		Samples:         1,
+		HeaderNamespace: testHeaderNamespace,
+		DataNamespace:   testDataNamespace,
+	}, st, daLayer, zerolog.Nop(), metrics)
+
+	header, data := types.GetRandomBlock(1, 2, "test-chain")
+	headerBlob, err := header.MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	dataBlob, err := (&types.SignedData{Data: *data}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	headerDAHeight := submitBlob(t, daLayer, headerBlob, testHeaderNamespace)
+	dataDAHeight := submitBlob(t, daLayer, dataBlob, testDataNamespace)
+	storeBlock(t, st, header, data, headerDAHeight, dataDAHeight)
+
+	// Wait for the mock DA height to pass the submitted heights
+	time.Sleep(30 * time.Millisecond)
+
+	if err := verifier.VerifyHeight(ctx, 1); err != nil {
+		t.Fatalf("expected block to verify, got %v", err)
+	}
+
+	// A locally corrupted block no longer matches what was published to DA
+	corrupted, corruptedData := types.GetRandomBlock(2, 2, "test-chain")
+	storeBlock(t, st, corrupted, corruptedData, headerDAHeight, dataDAHeight)
+
+	if err := verifier.VerifyHeight(ctx, 2); !errors.Is(err, ErrBlobMismatch) {
+		t.Fatalf("expected ErrBlobMismatch, got %v", err)
+	}
+}
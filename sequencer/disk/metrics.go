@@ -0,0 +1,79 @@
+package disk
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "disk"
+)
+
+// MetricsProvider returns disk monitor Metrics.
+type MetricsProvider func(chainID string) (*Metrics, error)
+
+// DefaultMetricsProvider returns Metrics build using Prometheus client library
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+func DefaultMetricsProvider(enabled bool) MetricsProvider {
+	return func(chainID string) (*Metrics, error) {
+		if enabled {
+			return PrometheusMetrics("chain_id", chainID)
+		}
+		return NopMetrics()
+	}
+}
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Percentage of the filesystem holding a monitored path that is in use
+	UsedPercent metrics.Gauge
+	// Current escalation level (0 ok, 1 warn, 2 compact, 3 halt)
+	Level metrics.Gauge
+	// Number of compactions triggered by high disk usage
+	Compactions metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(labelsAndValues ...string) (*Metrics, error) {
+	if len(labelsAndValues)%2 != 0 {
+		return nil, errors.New("uneven number of labels and values; labels and values should be provided in pairs")
+	}
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		UsedPercent: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "used_percent",
+			Help:      "Percentage of the filesystem holding a monitored data directory that is in use.",
+		}, append(labels, "path")).With(labelsAndValues...),
+		Level: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "level",
+			Help:      "Disk usage escalation level: 0 ok, 1 warn, 2 compact, 3 halt.",
+		}, labels).With(labelsAndValues...),
+		Compactions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "compactions",
+			Help:      "Number of datastore compactions triggered by high disk usage.",
+		}, labels).With(labelsAndValues...),
+	}, nil
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() (*Metrics, error) {
+	return &Metrics{
+		UsedPercent: discard.NewGauge(),
+		Level:       discard.NewGauge(),
+		Compactions: discard.NewCounter(),
+	}, nil
+}
@@ -0,0 +1,231 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// ErrDiskFull is passed to the halt function when disk usage crosses the halt threshold.
+var ErrDiskFull = errors.New("disk is almost full")
+
+// Level is the escalation level of the disk monitor.
+type Level int
+
+const (
+	// LevelOK means disk usage is below every threshold
+	LevelOK Level = iota
+	// LevelWarn means disk usage crossed the warning threshold
+	LevelWarn
+	// LevelCompact means disk usage crossed the compaction threshold
+	LevelCompact
+	// LevelHalt means disk usage crossed the halt threshold
+	LevelHalt
+)
+
+// String returns the name of the level.
+func (l Level) String() string {
+	switch l {
+	case LevelOK:
+		return "ok"
+	case LevelWarn:
+		return "warn"
+	case LevelCompact:
+		return "compact"
+	case LevelHalt:
+		return "halt"
+	default:
+		return fmt.Sprintf("level(%d)", int(l))
+	}
+}
+
+// Usage is the usage of a filesystem.
+type Usage struct {
+	// Total size of the filesystem in bytes
+	Total uint64
+	// Free bytes available to unprivileged users
+	Free uint64
+}
+
+// UsedPercent returns the percentage of the filesystem that is in use.
+func (u Usage) UsedPercent() float64 {
+	if u.Total == 0 {
+		return 0
+	}
+	return 100 * float64(u.Total-u.Free) / float64(u.Total)
+}
+
+// Config configures the disk monitor.
+type Config struct {
+	// Paths are the data directories to monitor
+	Paths []string
+	// Interval between usage checks
+	Interval time.Duration
+	// WarnPercent is the usage at which an alert is logged
+	WarnPercent float64
+	// CompactPercent is the usage at which the datastore is compacted
+	CompactPercent float64
+	// HaltPercent is the usage at which the node is halted before the store can be corrupted
+	HaltPercent float64
+}
+
+// Validate checks that the thresholds are in range and escalate.
+func (c Config) Validate() error {
+	if c.Interval <= 0 {
+		return errors.New("interval must be > 0")
+	}
+	if c.WarnPercent <= 0 || c.HaltPercent > 100 {
+		return errors.New("thresholds must be between 0 and 100")
+	}
+	if c.WarnPercent > c.CompactPercent || c.CompactPercent > c.HaltPercent {
+		return fmt.Errorf("thresholds must escalate: warn (%v) <= compact (%v) <= halt (%v)", c.WarnPercent, c.CompactPercent, c.HaltPercent)
+	}
+	return nil
+}
+
+// Monitor periodically checks the usage of the filesystems holding the data
+// directories and escalates as it grows: it alerts, then compacts the datastore,
+// and finally halts the node gracefully before a full disk can corrupt the store.
+type Monitor struct {
+	cfg     Config
+	compact func(ctx context.Context) error
+	halt    func(err error)
+	logger  zerolog.Logger
+	metrics *Metrics
+
+	level  Level
+	halted bool
+}
+
+// NewMonitor creates a new disk monitor.
+//
+// Parameters:
+// - cfg: Monitored paths, check interval and thresholds
+// - compact: Function reclaiming space in the datastore
+// - halt: Function stopping the node; it is called at most once
+// - logger: Logger used to report alerts
+// - metrics: Disk monitor metrics
+//
+// Returns:
+// - *Monitor: The initialized monitor; call Run to start it
+func NewMonitor(cfg Config, compact func(ctx context.Context) error, halt func(err error), logger zerolog.Logger, metrics *Metrics) *Monitor {
+	return &Monitor{
+		cfg:     cfg,
+		compact: compact,
+		halt:    halt,
+		logger:  logger.With().Str("component", "disk-monitor").Logger(),
+		metrics: metrics,
+	}
+}
+
+// Run checks disk usage every Interval until ctx is done or the node was halted.
+func (m *Monitor) Run(ctx context.Context) {
+	ticker := time.NewTicker(m.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		m.Check(ctx)
+		if m.halted {
+			return
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check measures disk usage once, acts on the resulting level and returns it.
+func (m *Monitor) Check(ctx context.Context) Level {
+	path, used, err := m.worstUsage()
+	if err != nil {
+		m.logger.Warn().Err(err).Msg("Could not measure disk usage")
+		return m.level
+	}
+
+	level := m.levelFor(used)
+	if level != m.level {
+		m.logger.Info().Str("from", m.level.String()).Str("to", level.String()).Str("path", path).Float64("used_percent", used).Msg("Disk usage level changed")
+	}
+	m.level = level
+	m.metrics.Level.Set(float64(level))
+
+	switch level {
+	case LevelWarn:
+		m.logger.Warn().Str("path", path).Float64("used_percent", used).Msg("⚠️  Disk usage is high")
+	case LevelCompact:
+		m.logger.Warn().Str("path", path).Float64("used_percent", used).Msg("⚠️  Disk usage is critical, compacting datastore")
+		m.metrics.Compactions.Add(1)
+		if err := m.compact(ctx); err != nil {
+			m.logger.Error().Err(err).Msg("Datastore compaction failed")
+		}
+	case LevelHalt:
+		if !m.halted {
+			m.halted = true
+			err := fmt.Errorf("%w: %s is %.1f%% full (halt threshold %.1f%%)", ErrDiskFull, path, used, m.cfg.HaltPercent)
+			m.logger.Error().Err(err).Msg("🚨 Halting node before the store is corrupted")
+			m.halt(err)
+		}
+	}
+
+	return level
+}
+
+// levelFor returns the escalation level for a usage percentage.
+func (m *Monitor) levelFor(used float64) Level {
+	switch {
+	case used >= m.cfg.HaltPercent:
+		return LevelHalt
+	case used >= m.cfg.CompactPercent:
+		return LevelCompact
+	case used >= m.cfg.WarnPercent:
+		return LevelWarn
+	default:
+		return LevelOK
+	}
+}
+
+// worstUsage returns the monitored path on the fullest filesystem and its usage.
+func (m *Monitor) worstUsage() (string, float64, error) {
+	var (
+		worstPath string
+		worstUsed float64
+	)
+
+	for _, path := range m.cfg.Paths {
+		usage, err := statUsage(existingAncestor(path))
+		if err != nil {
+			return "", 0, fmt.Errorf("failed to stat %s: %w", path, err)
+		}
+
+		used := usage.UsedPercent()
+		m.metrics.UsedPercent.With("path", path).Set(used)
+		if worstPath == "" || used > worstUsed {
+			worstPath, worstUsed = path, used
+		}
+	}
+
+	return worstPath, worstUsed, nil
+}
+
+// existingAncestor returns path, or its closest ancestor that exists, so that data
+// directories which are created later are still measured on the right filesystem.
+func existingAncestor(path string) string {
+	for {
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+		parent := filepath.Dir(path)
+		if parent == path {
+			return path
+		}
+		path = parent
+	}
+}
@@ -0,0 +1,79 @@
+package disk
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestMonitor_Check(t *testing.T) {
+	metrics, _ := NopMetrics()
+	dir := t.TempDir()
+
+	var (
+		compactions int
+		halts       []error
+	)
+	compact := func(context.Context) error {
+		compactions++
+		return nil
+	}
+	halt := func(err error) {
+		halts = append(halts, err)
+	}
+
+	// Any real filesystem is fuller than these thresholds
+	monitor := NewMonitor(Config{
+		Paths:          []string{dir + "/not-created-yet"},
+		Interval:       time.Second,
+		WarnPercent:    0.0001,
+		CompactPercent: 0.0001,
+		HaltPercent:    0.0001,
+	}, compact, halt, zerolog.Nop(), metrics)
+
+	if level := monitor.Check(context.Background()); level != LevelHalt {
+		t.Fatalf("expected %s, got %s", LevelHalt, level)
+	}
+	monitor.Check(context.Background())
+
+	if len(halts) != 1 || !errors.Is(halts[0], ErrDiskFull) {
+		t.Fatalf("expected a single ErrDiskFull halt, got %v", halts)
+	}
+	if compactions != 0 {
+		t.Errorf("expected no compaction once halted, got %d", compactions)
+	}
+}
+
+func TestMonitor_LevelFor(t *testing.T) {
+	monitor := NewMonitor(Config{
+		WarnPercent:    80,
+		CompactPercent: 90,
+		HaltPercent:    97,
+	}, nil, nil, zerolog.Nop(), nil)
+
+	for used, expected := range map[float64]Level{
+		50:   LevelOK,
+		80:   LevelWarn,
+		95:   LevelCompact,
+		99.5: LevelHalt,
+	} {
+		if level := monitor.levelFor(used); level != expected {
+			t.Errorf("expected %s at %v%%, got %s", expected, used, level)
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	cfg := Config{Interval: time.Minute, WarnPercent: 85, CompactPercent: 90, HaltPercent: 97}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg.CompactPercent = 99
+	if err := cfg.Validate(); err == nil {
+		t.Fatal("expected an error for thresholds that do not escalate")
+	}
+}
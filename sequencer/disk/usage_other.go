@@ -0,0 +1,10 @@
+//go:build !unix && !windows
+
+package disk
+
+import "errors"
+
+// statUsage is not supported on this platform.
+func statUsage(path string) (Usage, error) {
+	return Usage{}, errors.New("disk usage monitoring is not supported on this platform")
+}
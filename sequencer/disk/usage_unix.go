@@ -0,0 +1,18 @@
+//go:build unix
+
+package disk
+
+import "syscall"
+
+// statUsage returns the usage of the filesystem containing path.
+func statUsage(path string) (Usage, error) {
+	var stat syscall.Statfs_t
+	if err := syscall.Statfs(path, &stat); err != nil {
+		return Usage{}, err
+	}
+
+	return Usage{
+		Total: uint64(stat.Blocks) * uint64(stat.Bsize),
+		Free:  uint64(stat.Bavail) * uint64(stat.Bsize),
+	}, nil
+}
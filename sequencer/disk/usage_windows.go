@@ -0,0 +1,19 @@
+//go:build windows
+
+package disk
+
+import "golang.org/x/sys/windows"
+
+// statUsage returns the usage of the volume containing path.
+func statUsage(path string) (Usage, error) {
+	name, err := windows.UTF16PtrFromString(path)
+	if err != nil {
+		return Usage{}, err
+	}
+
+	var free, total, totalFree uint64
+	if err := windows.GetDiskFreeSpaceEx(name, &free, &total, &totalFree); err != nil {
+		return Usage{}, err
+	}
+	return Usage{Total: total, Free: free}, nil
+}
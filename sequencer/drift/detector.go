@@ -0,0 +1,116 @@
+package drift
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Config configures the drift detector.
+type Config struct {
+	// Source is the http(s) URL or file path of the signed reference manifest
+	Source string
+	// PublicKey is the key the manifest must be signed with
+	PublicKey ed25519.PublicKey
+	// Interval between checks
+	Interval time.Duration
+}
+
+// Validate checks that the detector can run.
+func (c Config) Validate() error {
+	if c.Source == "" {
+		return errors.New("manifest source is required")
+	}
+	if len(c.PublicKey) != ed25519.PublicKeySize {
+		return fmt.Errorf("manifest public key must be %d bytes, got %d", ed25519.PublicKeySize, len(c.PublicKey))
+	}
+	if c.Interval <= 0 {
+		return errors.New("interval must be > 0")
+	}
+	return nil
+}
+
+// Detector periodically compares the configuration the node runs with against a
+// signed reference manifest and alerts when they differ.
+type Detector struct {
+	cfg     Config
+	state   State
+	client  *http.Client
+	logger  zerolog.Logger
+	metrics *Metrics
+}
+
+// NewDetector creates a new drift detector.
+//
+// Parameters:
+// - cfg: Manifest source, trusted key and check interval
+// - state: Configuration the node is running with
+// - logger: Logger used to report drift
+// - metrics: Drift detector metrics
+//
+// Returns:
+// - *Detector: The initialized detector; call Run to start it
+func NewDetector(cfg Config, state State, logger zerolog.Logger, metrics *Metrics) *Detector {
+	return &Detector{
+		cfg:     cfg,
+		state:   state,
+		client:  &http.Client{Timeout: 30 * time.Second},
+		logger:  logger.With().Str("component", "config-drift").Logger(),
+		metrics: metrics,
+	}
+}
+
+// Run checks for drift every Interval until ctx is done.
+func (d *Detector) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.cfg.Interval)
+	defer ticker.Stop()
+
+	for {
+		if _, err := d.Check(ctx); err != nil && ctx.Err() == nil {
+			d.logger.Warn().Err(err).Str("source", d.cfg.Source).Msg("Could not check configuration drift")
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Check fetches and verifies the reference manifest once and returns the drift from it.
+func (d *Detector) Check(ctx context.Context) ([]Drift, error) {
+	bz, err := FetchManifest(ctx, d.client, d.cfg.Source)
+	if err != nil {
+		d.metrics.CheckFailures.Add(1)
+		return nil, fmt.Errorf("failed to fetch manifest: %w", err)
+	}
+
+	manifest, err := VerifyManifest(bz, d.cfg.PublicKey)
+	if err != nil {
+		d.metrics.CheckFailures.Add(1)
+		return nil, err
+	}
+
+	drifts := Compare(manifest, d.state)
+	d.metrics.Mismatches.Set(float64(len(drifts)))
+	if len(drifts) == 0 {
+		d.metrics.Drifted.Set(0)
+		return nil, nil
+	}
+
+	d.metrics.Drifted.Set(1)
+	for _, drift := range drifts {
+		d.logger.Error().
+			Str("key", drift.Key).
+			Str("expected", drift.Expected).
+			Str("actual", drift.Actual).
+			Msg("🚨 Configuration drifted from the reference manifest")
+	}
+	return drifts, nil
+}
@@ -0,0 +1,145 @@
+package drift
+
+import (
+	"context"
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestKey(t *testing.T) (ed25519.PublicKey, ed25519.PrivateKey) {
+	t.Helper()
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return pubKey, privKey
+}
+
+func TestCompare(t *testing.T) {
+	manifest := &Manifest{
+		ChainID:       "test-chain",
+		GenesisSHA256: "ABCD",
+		Parameters:    map[string]string{"node.block_time": "1s", "da.namespace": "ns", "da.block_time": "6s"},
+	}
+	state := State{
+		ChainID:       "test-chain",
+		GenesisSHA256: "abcd",
+		Parameters:    map[string]string{"node.block_time": "2s", "da.namespace": "ns"},
+	}
+
+	drifts := Compare(manifest, state)
+	expected := []Drift{
+		{Key: "da.block_time", Expected: "6s", Actual: ""},
+		{Key: "node.block_time", Expected: "1s", Actual: "2s"},
+	}
+	if len(drifts) != len(expected) {
+		t.Fatalf("expected %d drifts, got %+v", len(expected), drifts)
+	}
+	for i := range expected {
+		if drifts[i] != expected[i] {
+			t.Errorf("drift %d: expected %+v, got %+v", i, expected[i], drifts[i])
+		}
+	}
+
+	state.GenesisSHA256 = "ffff"
+	if drifts := Compare(manifest, state); len(drifts) != 3 || drifts[0].Key != "genesis_sha256" {
+		t.Errorf("expected a genesis drift first, got %+v", drifts)
+	}
+}
+
+func TestVerifyManifest(t *testing.T) {
+	pubKey, privKey := newTestKey(t)
+	otherKey, _ := newTestKey(t)
+
+	bz, err := SignManifest(Manifest{ChainID: "test-chain"}, privKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	manifest, err := VerifyManifest(bz, pubKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if manifest.ChainID != "test-chain" {
+		t.Errorf("expected chain ID test-chain, got %s", manifest.ChainID)
+	}
+
+	if _, err := VerifyManifest(bz, otherKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature for another key, got %v", err)
+	}
+}
+
+func TestDetectorCheck(t *testing.T) {
+	pubKey, privKey := newTestKey(t)
+	bz, err := SignManifest(Manifest{
+		ChainID:       "test-chain",
+		GenesisSHA256: "abcd",
+		Parameters:    map[string]string{"node.block_time": "1s"},
+	}, privKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write(bz)
+	}))
+	defer srv.Close()
+
+	metrics, _ := NopMetrics()
+	state := State{ChainID: "test-chain", GenesisSHA256: "abcd", Parameters: map[string]string{"node.block_time": "1s"}}
+	cfg := Config{Source: srv.URL, PublicKey: pubKey, Interval: time.Minute}
+
+	drifts, err := NewDetector(cfg, state, zerolog.Nop(), metrics).Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drifts) != 0 {
+		t.Errorf("expected no drift, got %+v", drifts)
+	}
+
+	state.Parameters["node.block_time"] = "500ms"
+	drifts, err = NewDetector(cfg, state, zerolog.Nop(), metrics).Check(context.Background())
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(drifts) != 1 || drifts[0].Actual != "500ms" {
+		t.Errorf("expected block time drift, got %+v", drifts)
+	}
+
+	// A manifest from a file signed by another key is rejected
+	_, otherPrivKey := newTestKey(t)
+	forged, err := SignManifest(Manifest{ChainID: "test-chain"}, otherPrivKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "manifest.json")
+	if err := os.WriteFile(path, forged, 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg.Source = path
+	if _, err := NewDetector(cfg, state, zerolog.Nop(), metrics).Check(context.Background()); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestConfigValidate(t *testing.T) {
+	pubKey, _ := newTestKey(t)
+
+	if err := (Config{Source: "manifest.json", PublicKey: pubKey, Interval: time.Hour}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := (Config{Source: "manifest.json", PublicKey: pubKey[:8], Interval: time.Hour}).Validate(); err == nil {
+		t.Error("expected an error for a short public key")
+	}
+	if err := (Config{PublicKey: pubKey, Interval: time.Hour}).Validate(); err == nil {
+		t.Error("expected an error for a missing source")
+	}
+}
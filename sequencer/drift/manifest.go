@@ -0,0 +1,134 @@
+package drift
+
+import (
+	"context"
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+)
+
+// ErrInvalidSignature is returned when a manifest is not signed by the trusted key.
+var ErrInvalidSignature = errors.New("manifest signature is invalid")
+
+// maxManifestSize bounds the size of a fetched manifest
+const maxManifestSize = 1 << 20
+
+// Manifest is the reference configuration published for a network.
+type Manifest struct {
+	ChainID string `json:"chain_id"`
+	// GenesisSHA256 is the hex encoded SHA-256 of the genesis file
+	GenesisSHA256 string `json:"genesis_sha256"`
+	// Parameters are the expected configuration values keyed by config key, e.g. "node.block_time"
+	Parameters map[string]string `json:"parameters,omitempty"`
+}
+
+// SignedManifest is a manifest with an ed25519 signature over its exact JSON bytes.
+type SignedManifest struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Signature []byte          `json:"signature"`
+}
+
+// State is the configuration a node is running with.
+type State struct {
+	ChainID       string
+	GenesisSHA256 string
+	// Parameters are the effective configuration values keyed by config key
+	Parameters map[string]string
+}
+
+// Drift is a value differing from the reference manifest.
+type Drift struct {
+	Key      string
+	Expected string
+	Actual   string
+}
+
+// VerifyManifest decodes a signed manifest and checks its signature against pubKey.
+func VerifyManifest(bz []byte, pubKey ed25519.PublicKey) (*Manifest, error) {
+	var signed SignedManifest
+	if err := json.Unmarshal(bz, &signed); err != nil {
+		return nil, fmt.Errorf("failed to decode signed manifest: %w", err)
+	}
+
+	if !ed25519.Verify(pubKey, signed.Manifest, signed.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(signed.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// SignManifest returns manifest signed with privKey, encoded as a SignedManifest.
+func SignManifest(manifest Manifest, privKey ed25519.PrivateKey) ([]byte, error) {
+	bz, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(SignedManifest{Manifest: bz, Signature: ed25519.Sign(privKey, bz)})
+}
+
+// FetchManifest reads a signed manifest from an http(s) URL or a local file.
+func FetchManifest(ctx context.Context, client *http.Client, source string) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		return os.ReadFile(source)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status fetching manifest: %s", resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxManifestSize))
+}
+
+// GenesisSHA256 returns the hex encoded SHA-256 of the genesis file at path.
+func GenesisSHA256(path string) (string, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return "", fmt.Errorf("failed to read genesis: %w", err)
+	}
+	sum := sha256.Sum256(bz)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// Compare returns the values of state differing from manifest, sorted by key.
+// Parameters missing from state are reported with an empty actual value.
+func Compare(manifest *Manifest, state State) []Drift {
+	var drifts []Drift
+	if manifest.ChainID != state.ChainID {
+		drifts = append(drifts, Drift{Key: "chain_id", Expected: manifest.ChainID, Actual: state.ChainID})
+	}
+	if !strings.EqualFold(manifest.GenesisSHA256, state.GenesisSHA256) {
+		drifts = append(drifts, Drift{Key: "genesis_sha256", Expected: manifest.GenesisSHA256, Actual: state.GenesisSHA256})
+	}
+
+	var params []Drift
+	for key, expected := range manifest.Parameters {
+		if actual := state.Parameters[key]; actual != expected {
+			params = append(params, Drift{Key: key, Expected: expected, Actual: actual})
+		}
+	}
+	sort.Slice(params, func(i, j int) bool { return params[i].Key < params[j].Key })
+
+	return append(drifts, params...)
+}
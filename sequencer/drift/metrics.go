@@ -0,0 +1,79 @@
+package drift
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "config_drift"
+)
+
+// MetricsProvider returns config drift Metrics.
+type MetricsProvider func(chainID string) (*Metrics, error)
+
+// DefaultMetricsProvider returns Metrics build using Prometheus client library
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+func DefaultMetricsProvider(enabled bool) MetricsProvider {
+	return func(chainID string) (*Metrics, error) {
+		if enabled {
+			return PrometheusMetrics("chain_id", chainID)
+		}
+		return NopMetrics()
+	}
+}
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Whether the last check found drift from the reference manifest (0 or 1)
+	Drifted metrics.Gauge
+	// Number of values differing from the reference manifest at the last check
+	Mismatches metrics.Gauge
+	// Number of checks that could not fetch or verify the reference manifest
+	CheckFailures metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(labelsAndValues ...string) (*Metrics, error) {
+	if len(labelsAndValues)%2 != 0 {
+		return nil, errors.New("uneven number of labels and values; labels and values should be provided in pairs")
+	}
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		Drifted: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "drifted",
+			Help:      "Whether the last check found drift from the reference manifest (0 or 1).",
+		}, labels).With(labelsAndValues...),
+		Mismatches: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "mismatches",
+			Help:      "Number of values differing from the reference manifest at the last check.",
+		}, labels).With(labelsAndValues...),
+		CheckFailures: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "check_failures",
+			Help:      "Number of checks that could not fetch or verify the reference manifest.",
+		}, labels).With(labelsAndValues...),
+	}, nil
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() (*Metrics, error) {
+	return &Metrics{
+		Drifted:       discard.NewGauge(),
+		Mismatches:    discard.NewGauge(),
+		CheckFailures: discard.NewCounter(),
+	}, nil
+}
@@ -0,0 +1,184 @@
+package era
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	ds "github.com/ipfs/go-datastore"
+)
+
+// deleteBatchSize is how many keys are deleted per datastore batch
+const deleteBatchSize = 10_000
+
+// CompactConfig selects the blocks moved into era files.
+type CompactConfig struct {
+	// Prefix is the namespace of the block store keys in the datastore
+	Prefix ds.Key
+	// InitialHeight is the first height of the chain; eras start at it
+	InitialHeight uint64
+	// Size is the number of blocks of an era
+	Size uint64
+	// To is the last height that may be archived, usually the DA included height
+	To uint64
+}
+
+// Compact moves every full era of blocks up to cfg.To that is not archived yet out of
+// datastore into an era file in dir, and returns the paths of the written files.
+//
+// Eras are the ranges of cfg.Size blocks from cfg.InitialHeight. Each era file is
+// written, synced and read back before the blocks are deleted from datastore, so an
+// interrupted compaction leaves the blocks in the datastore, an era file, or both.
+// Blocks still in the datastore win over their era file when both exist.
+func Compact(ctx context.Context, datastore ds.Batching, dir string, cfg CompactConfig) ([]string, error) {
+	if cfg.Size == 0 || cfg.InitialHeight == 0 {
+		return nil, errors.New("era size and initial height must be > 0")
+	}
+
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create era directory: %w", err)
+	}
+
+	files, err := OpenDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	next := cfg.InitialHeight
+	if len(files) > 0 {
+		next = files[len(files)-1].Last() + 1
+	}
+	closeFiles(files)
+
+	var written []string
+	for first := next; first+cfg.Size-1 <= cfg.To && first+cfg.Size-1 >= first; first += cfg.Size {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		path, err := compactEra(ctx, datastore, dir, cfg, first, first+cfg.Size-1)
+		if err != nil {
+			return written, err
+		}
+		written = append(written, path)
+	}
+
+	return written, nil
+}
+
+// compactEra archives the blocks [first, last] and deletes them from datastore.
+func compactEra(ctx context.Context, datastore ds.Batching, dir string, cfg CompactConfig, first, last uint64) (string, error) {
+	path := filepath.Join(dir, FileName(first, last))
+	w, err := Create(path, first)
+	if err != nil {
+		return "", err
+	}
+
+	for height := first; height <= last; height++ {
+		if err := ctx.Err(); err != nil {
+			w.Abort()
+			return "", err
+		}
+
+		entry, err := loadEntry(ctx, datastore, cfg.Prefix, height)
+		if err != nil {
+			w.Abort()
+			return "", err
+		}
+		if err := w.Add(entry); err != nil {
+			w.Abort()
+			return "", err
+		}
+	}
+	if err := w.Finish(); err != nil {
+		return "", err
+	}
+
+	// Only delete what the era file is known to hold
+	if err := verifyEra(ctx, datastore, path, cfg.Prefix); err != nil {
+		_ = os.Remove(path)
+		return "", err
+	}
+
+	if err := deleteEra(ctx, datastore, cfg.Prefix, first, last); err != nil {
+		return "", err
+	}
+	return path, nil
+}
+
+// loadEntry reads the stored values of the block at height. The block must have a header.
+func loadEntry(ctx context.Context, datastore ds.Batching, prefix ds.Key, height uint64) (Entry, error) {
+	entry := make(Entry, len(Kinds))
+	for _, kind := range Kinds {
+		value, err := datastore.Get(ctx, Key(prefix, kind, height))
+		if errors.Is(err, ds.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block %d: %w", height, err)
+		}
+		entry[kind] = value
+	}
+
+	if _, ok := entry[Kinds[0]]; !ok {
+		return nil, fmt.Errorf("block %d is not stored", height)
+	}
+	return entry, nil
+}
+
+// verifyEra checks that the era file at path holds the stored values of its blocks.
+func verifyEra(ctx context.Context, datastore ds.Batching, path string, prefix ds.Key) error {
+	f, err := Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	for height := f.First(); height <= f.Last(); height++ {
+		archived, err := f.Entry(height)
+		if err != nil {
+			return err
+		}
+		stored, err := loadEntry(ctx, datastore, prefix, height)
+		if err != nil {
+			return err
+		}
+		for _, kind := range Kinds {
+			if !bytes.Equal(archived[kind], stored[kind]) {
+				return fmt.Errorf("%w %s: block %d does not match the datastore", ErrCorrupt, path, height)
+			}
+		}
+	}
+	return nil
+}
+
+// deleteEra deletes the per-block values of the blocks [first, last] from datastore.
+func deleteEra(ctx context.Context, datastore ds.Batching, prefix ds.Key, first, last uint64) error {
+	batch, err := datastore.Batch(ctx)
+	if err != nil {
+		return err
+	}
+
+	pending := 0
+	for height := first; height <= last; height++ {
+		for _, kind := range Kinds {
+			if err := batch.Delete(ctx, Key(prefix, kind, height)); err != nil {
+				return err
+			}
+			pending++
+		}
+
+		if pending >= deleteBatchSize || height == last {
+			if err := batch.Commit(ctx); err != nil {
+				return fmt.Errorf("failed to delete archived blocks: %w", err)
+			}
+			if batch, err = datastore.Batch(ctx); err != nil {
+				return err
+			}
+			pending = 0
+		}
+	}
+	return datastore.Sync(ctx, prefix)
+}
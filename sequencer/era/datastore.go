@@ -0,0 +1,244 @@
+package era
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	ds "github.com/ipfs/go-datastore"
+)
+
+// Ensure Datastore implements the ds.Batching and ds.TxnDatastore interfaces
+var (
+	_ ds.Batching     = (*Datastore)(nil)
+	_ ds.TxnDatastore = (*Datastore)(nil)
+)
+
+// Datastore serves the block values archived in era files for keys missing from the
+// datastore it wraps, so the store reads archived blocks transparently. Writes go to
+// the wrapped datastore, and values it still holds take precedence. Queries only see
+// the wrapped datastore.
+type Datastore struct {
+	ds.Batching
+	// prefix is the namespace of the block store keys, e.g. /0 for ev-node's full node
+	prefix string
+	files  []*File
+}
+
+// OpenDatastore wraps datastore with the era files in dir. A missing directory holds
+// no era files.
+//
+// Parameters:
+// - datastore: The hot datastore, holding the blocks not archived yet
+// - dir: Directory holding the era files
+// - prefix: Namespace of the block store keys in datastore
+//
+// Returns:
+// - *Datastore: The wrapped datastore; closing it closes datastore and the era files
+// - error: Any error that occurred while opening the era files
+func OpenDatastore(datastore ds.Batching, dir string, prefix ds.Key) (*Datastore, error) {
+	files, err := OpenDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	return &Datastore{Batching: datastore, prefix: prefix.String(), files: files}, nil
+}
+
+// OpenDir opens the era files in dir, ordered by height. It fails if two files overlap.
+func OpenDir(dir string) ([]*File, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+Ext))
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*File
+	for _, path := range paths {
+		f, err := Open(path)
+		if err != nil {
+			closeFiles(files)
+			return nil, fmt.Errorf("failed to open era file: %w", err)
+		}
+		files = append(files, f)
+	}
+
+	sort.Slice(files, func(i, j int) bool { return files[i].First() < files[j].First() })
+	for i := 1; i < len(files); i++ {
+		if files[i].First() <= files[i-1].Last() {
+			closeFiles(files)
+			return nil, fmt.Errorf("era files %s and %s overlap", files[i-1].Path(), files[i].Path())
+		}
+	}
+	return files, nil
+}
+
+// closeFiles closes era files opened by OpenDir
+func closeFiles(files []*File) {
+	for _, f := range files {
+		_ = f.Close()
+	}
+}
+
+// Files returns the opened era files, ordered by height.
+func (d *Datastore) Files() []*File {
+	return d.files
+}
+
+// Get returns the value of key from the wrapped datastore, or from an era file.
+func (d *Datastore) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	value, err := d.Batching.Get(ctx, key)
+	return d.get(key, value, err)
+}
+
+// Has reports whether key is in the wrapped datastore or an era file.
+func (d *Datastore) Has(ctx context.Context, key ds.Key) (bool, error) {
+	exists, err := d.Batching.Has(ctx, key)
+	return d.has(key, exists, err)
+}
+
+// GetSize returns the size of the value of key in the wrapped datastore or an era file.
+func (d *Datastore) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	size, err := d.Batching.GetSize(ctx, key)
+	return d.getSize(key, size, err)
+}
+
+// NewTransaction starts a transaction of the wrapped datastore, which must support
+// them. Reads of the transaction fall back to the era files like the datastore's.
+func (d *Datastore) NewTransaction(ctx context.Context, readOnly bool) (ds.Txn, error) {
+	tds, ok := d.Batching.(ds.TxnDatastore)
+	if !ok {
+		return nil, errors.New("era: the wrapped datastore does not support transactions")
+	}
+
+	t, err := tds.NewTransaction(ctx, readOnly)
+	if err != nil {
+		return nil, err
+	}
+	return &txn{Txn: t, d: d}, nil
+}
+
+// Close closes the era files and the wrapped datastore.
+func (d *Datastore) Close() error {
+	closeFiles(d.files)
+	return d.Batching.Close()
+}
+
+// txn is a transaction of the wrapped datastore reading archived blocks from era files.
+type txn struct {
+	ds.Txn
+	d *Datastore
+}
+
+// Get returns the value of key in the transaction, or from an era file.
+func (t *txn) Get(ctx context.Context, key ds.Key) ([]byte, error) {
+	value, err := t.Txn.Get(ctx, key)
+	return t.d.get(key, value, err)
+}
+
+// Has reports whether key is in the transaction or an era file.
+func (t *txn) Has(ctx context.Context, key ds.Key) (bool, error) {
+	exists, err := t.Txn.Has(ctx, key)
+	return t.d.has(key, exists, err)
+}
+
+// GetSize returns the size of the value of key in the transaction or an era file.
+func (t *txn) GetSize(ctx context.Context, key ds.Key) (int, error) {
+	size, err := t.Txn.GetSize(ctx, key)
+	return t.d.getSize(key, size, err)
+}
+
+// get returns value, err of a datastore read of key, or the archived value of key if
+// the read found no value.
+func (d *Datastore) get(key ds.Key, value []byte, err error) ([]byte, error) {
+	if !errors.Is(err, ds.ErrNotFound) {
+		return value, err
+	}
+
+	archived, ok, err := d.archived(key)
+	if err != nil || !ok {
+		return nil, errors.Join(ds.ErrNotFound, err)
+	}
+	return append([]byte(nil), archived...), nil
+}
+
+// has returns exists, err of a datastore read of key, or whether key is archived if
+// the read found no value.
+func (d *Datastore) has(key ds.Key, exists bool, err error) (bool, error) {
+	if err != nil || exists {
+		return exists, err
+	}
+
+	_, ok, err := d.archived(key)
+	return ok, err
+}
+
+// getSize returns size, err of a datastore read of key, or the size of the archived
+// value of key if the read found no value.
+func (d *Datastore) getSize(key ds.Key, size int, err error) (int, error) {
+	if !errors.Is(err, ds.ErrNotFound) {
+		return size, err
+	}
+
+	archived, ok, err := d.archived(key)
+	if err != nil || !ok {
+		return -1, errors.Join(ds.ErrNotFound, err)
+	}
+	return len(archived), nil
+}
+
+// archived returns the value of key from the era file holding its height, if key is a
+// per-block key of the block store.
+func (d *Datastore) archived(key ds.Key) ([]byte, bool, error) {
+	kind, height, ok := d.parseKey(key)
+	if !ok {
+		return nil, false, nil
+	}
+
+	i := sort.Search(len(d.files), func(i int) bool { return d.files[i].Last() >= height })
+	if i == len(d.files) || !d.files[i].Contains(height) {
+		return nil, false, nil
+	}
+
+	entry, err := d.files[i].Entry(height)
+	if err != nil {
+		return nil, false, err
+	}
+	value, ok := entry[kind]
+	return value, ok, nil
+}
+
+// parseKey returns the kind and height of a per-block key such as /0/h/42.
+func (d *Datastore) parseKey(key ds.Key) (string, uint64, bool) {
+	rest, ok := strings.CutPrefix(key.String(), strings.TrimSuffix(d.prefix, "/")+"/")
+	if !ok {
+		return "", 0, false
+	}
+
+	kind, number, ok := strings.Cut(rest, "/")
+	if !ok || !isKind(kind) {
+		return "", 0, false
+	}
+	height, err := strconv.ParseUint(number, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return kind, height, true
+}
+
+// isKind reports whether kind is one of Kinds
+func isKind(kind string) bool {
+	for _, k := range Kinds {
+		if k == kind {
+			return true
+		}
+	}
+	return false
+}
+
+// Key returns the datastore key of the value of kind for the block at height under prefix.
+func Key(prefix ds.Key, kind string, height uint64) ds.Key {
+	return prefix.ChildString(kind).ChildString(strconv.FormatUint(height, 10))
+}
@@ -0,0 +1,247 @@
+package era
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	ktds "github.com/ipfs/go-datastore/keytransform"
+	dssync "github.com/ipfs/go-datastore/sync"
+
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// testPrefix is the namespace of the block store in the test datastores
+var testPrefix = ds.NewKey("/0")
+
+// newTestDatastore returns a datastore holding blocks 1 to n of a store under testPrefix.
+func newTestDatastore(t *testing.T, n uint64) ds.Batching {
+	t.Helper()
+	ctx := context.Background()
+	datastore := dssync.MutexWrap(ds.NewMapDatastore())
+	st := store.New(ktds.Wrap(datastore, ktds.PrefixTransform{Prefix: testPrefix}))
+
+	for height := uint64(1); height <= n; height++ {
+		header, data := types.GetRandomBlock(height, 2, "test-chain")
+		batch, _ := st.NewBatch(ctx)
+		if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.UpdateState(types.State{LastBlockHeight: height, AppHash: header.AppHash}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.SetHeight(height); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	return datastore
+}
+
+func TestFile_RoundTrip(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName(5, 7))
+	entries := []Entry{
+		{"h": []byte("header 5"), "d": []byte("data 5")},
+		{"h": []byte("header 6"), "d": []byte{}, "s": []byte("state 6")},
+		{"h": bytes.Repeat([]byte("x"), 1<<16)},
+	}
+
+	w, err := Create(path, 5)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, entry := range entries {
+		if err := w.Add(entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Finish(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	if f.First() != 5 || f.Last() != 7 {
+		t.Fatalf("expected heights 5 to 7, got %d to %d", f.First(), f.Last())
+	}
+	for i, want := range entries {
+		got, err := f.Entry(5 + uint64(i))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if len(got) != len(want) {
+			t.Fatalf("block %d: expected %d values, got %d", 5+i, len(want), len(got))
+		}
+		for kind, value := range want {
+			if v, ok := got[kind]; !ok || !bytes.Equal(v, value) {
+				t.Fatalf("block %d: unexpected %s value", 5+i, kind)
+			}
+		}
+	}
+
+	if _, err := f.Entry(8); !errors.Is(err, ErrNotArchived) {
+		t.Fatalf("expected ErrNotArchived, got %v", err)
+	}
+}
+
+func TestFile_Corrupt(t *testing.T) {
+	path := filepath.Join(t.TempDir(), FileName(1, 1))
+	w, err := Create(path, 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Add(Entry{"h": bytes.Repeat([]byte("header"), 100)}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Finish(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bz[headerSize+10] ^= 0xff
+	if err := os.WriteFile(path, bz, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	f, err := Open(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Entry(1); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt, got %v", err)
+	}
+
+	if err := os.WriteFile(path, bz[:len(bz)-1], 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Open(path); !errors.Is(err, ErrCorrupt) {
+		t.Fatalf("expected ErrCorrupt for a truncated file, got %v", err)
+	}
+}
+
+func TestWriter_Abort(t *testing.T) {
+	dir := t.TempDir()
+	w, err := Create(filepath.Join(dir, FileName(1, 1)), 1)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Add(Entry{"h": []byte("header")}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	w.Abort()
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Fatalf("expected no files after abort, got %d", len(entries))
+	}
+}
+
+func TestCompact(t *testing.T) {
+	ctx := context.Background()
+	datastore := newTestDatastore(t, 10)
+	dir := filepath.Join(t.TempDir(), "era")
+
+	before := store.New(ktds.Wrap(datastore, ktds.PrefixTransform{Prefix: testPrefix}))
+	want := make(map[uint64]*types.SignedHeader)
+	for height := uint64(1); height <= 10; height++ {
+		header, err := before.GetHeader(ctx, height)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		want[height] = header
+	}
+
+	cfg := CompactConfig{Prefix: testPrefix, InitialHeight: 1, Size: 4, To: 9}
+	written, err := Compact(ctx, datastore, dir, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	// Blocks 9 and 10 do not fill an era
+	if len(written) != 2 || filepath.Base(written[1]) != FileName(5, 8) {
+		t.Fatalf("expected eras 1-4 and 5-8, got %v", written)
+	}
+
+	for height := uint64(1); height <= 10; height++ {
+		exists, err := datastore.Has(ctx, Key(testPrefix, "h", height))
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if exists != (height > 8) {
+			t.Fatalf("block %d: expected in datastore %t, got %t", height, height > 8, exists)
+		}
+	}
+
+	// Compacting again writes nothing new
+	if written, err := Compact(ctx, datastore, dir, cfg); err != nil || len(written) != 0 {
+		t.Fatalf("expected no new era files, got %v, %v", written, err)
+	}
+
+	wrapped, err := OpenDatastore(datastore, dir, testPrefix)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer wrapped.Close()
+
+	st := store.New(ktds.Wrap(wrapped, ktds.PrefixTransform{Prefix: testPrefix}))
+	for height := uint64(1); height <= 10; height++ {
+		header, data, err := st.GetBlockData(ctx, height)
+		if err != nil {
+			t.Fatalf("block %d: unexpected error: %v", height, err)
+		}
+		if header.Hash().String() != want[height].Hash().String() || data == nil {
+			t.Fatalf("block %d: unexpected header", height)
+		}
+		state, err := st.GetStateAtHeight(ctx, height)
+		if err != nil {
+			t.Fatalf("block %d: unexpected error: %v", height, err)
+		}
+		if state.LastBlockHeight != height {
+			t.Fatalf("block %d: unexpected state height %d", height, state.LastBlockHeight)
+		}
+	}
+
+	// Lookups by hash go through the index, which is not archived
+	if _, _, err := st.GetBlockByHash(ctx, want[2].Hash()); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := st.GetHeader(ctx, 11); err == nil {
+		t.Fatal("expected an error for a block that was never stored")
+	}
+}
+
+func TestCompact_MissingBlock(t *testing.T) {
+	ctx := context.Background()
+	datastore := newTestDatastore(t, 3)
+	dir := t.TempDir()
+
+	if err := datastore.Delete(ctx, Key(testPrefix, "h", 2)); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := Compact(ctx, datastore, dir, CompactConfig{Prefix: testPrefix, InitialHeight: 1, Size: 3, To: 3}); err == nil {
+		t.Fatal("expected an error for a missing block")
+	}
+	if exists, _ := datastore.Has(ctx, Key(testPrefix, "h", 1)); !exists {
+		t.Fatal("expected the blocks to stay in the datastore after a failed compaction")
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 0 {
+		t.Fatalf("expected no era files after a failed compaction, got %d", len(entries))
+	}
+}
@@ -0,0 +1,341 @@
+// Package era packs finalized block ranges into immutable compressed archive files and
+// serves them back through the node datastore.
+package era
+
+import (
+	"bytes"
+	"compress/flate"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// An era file is laid out as
+//
+//	header  magic (8) | version (u32) | first height (u64) | block count (u64)
+//	entries for each block: compressed length (u32) | CRC-32 of the compressed bytes (u32) | DEFLATE compressed entry
+//	index   offset of each entry (u64 each)
+//	footer  offset of the index (u64) | magic (8)
+//
+// An entry holds the stored values of a block, one per Kinds, each as a uvarint of its
+// length plus one, 0 for a missing value, followed by the value. Integers are big endian.
+const (
+	magic      = "PRKLERA\x00"
+	version    = 1
+	headerSize = len(magic) + 4 + 8 + 8
+	footerSize = 8 + len(magic)
+	// Ext is the extension of era files
+	Ext = ".era"
+)
+
+// Kinds are the key prefixes of the per-block values of the block store archived in
+// era files: the header, data, signature and state after the block, as in ev-node's
+// store keys, e.g. /h/<height>.
+var Kinds = []string{"h", "d", "c", "s"}
+
+var (
+	// ErrCorrupt is returned when an era file does not have the expected layout or
+	// an entry does not match its checksum.
+	ErrCorrupt = errors.New("corrupt era file")
+	// ErrNotArchived is returned for a height outside an era file.
+	ErrNotArchived = errors.New("height is not archived")
+)
+
+// Entry holds the stored values of a block by kind. Kinds without a stored value are
+// absent.
+type Entry map[string][]byte
+
+// FileName returns the name of the era file of the blocks [first, last].
+func FileName(first, last uint64) string {
+	return fmt.Sprintf("%012d-%012d%s", first, last, Ext)
+}
+
+// Writer writes an era file. The file is written under a temporary name and only
+// appears at its path once Finish succeeds, so era files are never partial.
+type Writer struct {
+	path    string
+	tmp     *os.File
+	first   uint64
+	offset  uint64
+	index   []uint64
+	buf     bytes.Buffer
+	deflate *flate.Writer
+}
+
+// Create starts writing the era file at path holding blocks from height first.
+func Create(path string, first uint64) (*Writer, error) {
+	tmp, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create era file: %w", err)
+	}
+
+	deflate, err := flate.NewWriter(nil, flate.BestCompression)
+	if err != nil {
+		_ = tmp.Close()
+		_ = os.Remove(tmp.Name())
+		return nil, err
+	}
+
+	w := &Writer{path: path, tmp: tmp, first: first, deflate: deflate}
+	// The block count is written by Finish
+	if _, err := tmp.Write(make([]byte, headerSize)); err != nil {
+		w.Abort()
+		return nil, fmt.Errorf("failed to write era file: %w", err)
+	}
+	w.offset = uint64(headerSize)
+	return w, nil
+}
+
+// Add appends the entry of the next block.
+func (w *Writer) Add(entry Entry) error {
+	var raw []byte
+	for _, kind := range Kinds {
+		value, ok := entry[kind]
+		if !ok {
+			raw = binary.AppendUvarint(raw, 0)
+			continue
+		}
+		raw = binary.AppendUvarint(raw, uint64(len(value))+1)
+		raw = append(raw, value...)
+	}
+
+	w.buf.Reset()
+	w.deflate.Reset(&w.buf)
+	if _, err := w.deflate.Write(raw); err != nil {
+		return err
+	}
+	if err := w.deflate.Close(); err != nil {
+		return err
+	}
+
+	var prefix [8]byte
+	binary.BigEndian.PutUint32(prefix[:4], uint32(w.buf.Len()))
+	binary.BigEndian.PutUint32(prefix[4:], crc32.ChecksumIEEE(w.buf.Bytes()))
+	if _, err := w.tmp.Write(prefix[:]); err != nil {
+		return fmt.Errorf("failed to write era file: %w", err)
+	}
+	if _, err := w.tmp.Write(w.buf.Bytes()); err != nil {
+		return fmt.Errorf("failed to write era file: %w", err)
+	}
+
+	w.index = append(w.index, w.offset)
+	w.offset += uint64(len(prefix) + w.buf.Len())
+	return nil
+}
+
+// Finish writes the index, syncs the file and moves it to its path.
+func (w *Writer) Finish() error {
+	var tail []byte
+	for _, offset := range w.index {
+		tail = binary.BigEndian.AppendUint64(tail, offset)
+	}
+	tail = binary.BigEndian.AppendUint64(tail, w.offset)
+	tail = append(tail, magic...)
+
+	header := append([]byte(magic), make([]byte, headerSize-len(magic))...)
+	binary.BigEndian.PutUint32(header[len(magic):], version)
+	binary.BigEndian.PutUint64(header[len(magic)+4:], w.first)
+	binary.BigEndian.PutUint64(header[len(magic)+12:], uint64(len(w.index)))
+
+	if _, err := w.tmp.Write(tail); err != nil {
+		w.Abort()
+		return fmt.Errorf("failed to write era file: %w", err)
+	}
+	if _, err := w.tmp.WriteAt(header, 0); err != nil {
+		w.Abort()
+		return fmt.Errorf("failed to write era file: %w", err)
+	}
+	if err := w.tmp.Sync(); err != nil {
+		w.Abort()
+		return fmt.Errorf("failed to sync era file: %w", err)
+	}
+	if err := w.tmp.Close(); err != nil {
+		_ = os.Remove(w.tmp.Name())
+		return fmt.Errorf("failed to close era file: %w", err)
+	}
+	if err := os.Rename(w.tmp.Name(), w.path); err != nil {
+		_ = os.Remove(w.tmp.Name())
+		return fmt.Errorf("failed to move era file in place: %w", err)
+	}
+	return nil
+}
+
+// Abort discards the file being written.
+func (w *Writer) Abort() {
+	_ = w.tmp.Close()
+	_ = os.Remove(w.tmp.Name())
+}
+
+// File is an opened era file. It is safe for concurrent use.
+type File struct {
+	path  string
+	f     *os.File
+	first uint64
+	index []uint64
+	// end is the offset of the index, where the last entry ends
+	end uint64
+
+	// The last read entry is kept, as the values of a block are usually read together
+	mu         sync.Mutex
+	lastHeight uint64
+	last       Entry
+}
+
+// Open opens the era file at path and loads its index.
+func Open(path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := load(path, f)
+	if err != nil {
+		_ = f.Close()
+		return nil, err
+	}
+	return file, nil
+}
+
+// load reads the header, footer and index of the era file f.
+func load(path string, f *os.File) (*File, error) {
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	size := uint64(info.Size())
+	if size < uint64(headerSize+footerSize) {
+		return nil, fmt.Errorf("%w %s: too short", ErrCorrupt, path)
+	}
+
+	header := make([]byte, headerSize)
+	if _, err := f.ReadAt(header, 0); err != nil {
+		return nil, err
+	}
+	footer := make([]byte, footerSize)
+	if _, err := f.ReadAt(footer, int64(size)-int64(footerSize)); err != nil {
+		return nil, err
+	}
+	if string(header[:len(magic)]) != magic || string(footer[8:]) != magic {
+		return nil, fmt.Errorf("%w %s: bad magic", ErrCorrupt, path)
+	}
+	if v := binary.BigEndian.Uint32(header[len(magic):]); v != version {
+		return nil, fmt.Errorf("%w %s: unsupported version %d", ErrCorrupt, path, v)
+	}
+
+	first := binary.BigEndian.Uint64(header[len(magic)+4:])
+	count := binary.BigEndian.Uint64(header[len(magic)+12:])
+	end := binary.BigEndian.Uint64(footer[:8])
+	if end < uint64(headerSize) || end+count*8+uint64(footerSize) != size {
+		return nil, fmt.Errorf("%w %s: bad index", ErrCorrupt, path)
+	}
+
+	raw := make([]byte, count*8)
+	if _, err := f.ReadAt(raw, int64(end)); err != nil {
+		return nil, err
+	}
+	index := make([]uint64, count)
+	for i := range index {
+		index[i] = binary.BigEndian.Uint64(raw[i*8:])
+		if index[i] < uint64(headerSize) || index[i] >= end || (i > 0 && index[i] <= index[i-1]) {
+			return nil, fmt.Errorf("%w %s: bad index", ErrCorrupt, path)
+		}
+	}
+
+	return &File{path: path, f: f, first: first, index: index, end: end}, nil
+}
+
+// Path returns the path of the file.
+func (f *File) Path() string {
+	return f.path
+}
+
+// First returns the first height of the file.
+func (f *File) First() uint64 {
+	return f.first
+}
+
+// Last returns the last height of the file.
+func (f *File) Last() uint64 {
+	return f.first + uint64(len(f.index)) - 1
+}
+
+// Contains reports whether height is archived in the file.
+func (f *File) Contains(height uint64) bool {
+	return len(f.index) > 0 && height >= f.first && height <= f.Last()
+}
+
+// Entry returns the stored values of the block at height.
+func (f *File) Entry(height uint64) (Entry, error) {
+	if !f.Contains(height) {
+		return nil, ErrNotArchived
+	}
+
+	f.mu.Lock()
+	if f.last != nil && f.lastHeight == height {
+		entry := f.last
+		f.mu.Unlock()
+		return entry, nil
+	}
+	f.mu.Unlock()
+
+	entry, err := f.read(height - f.first)
+	if err != nil {
+		return nil, err
+	}
+
+	f.mu.Lock()
+	f.lastHeight, f.last = height, entry
+	f.mu.Unlock()
+	return entry, nil
+}
+
+// read reads and decodes entry i.
+func (f *File) read(i uint64) (Entry, error) {
+	start := f.index[i]
+	end := f.end
+	if i+1 < uint64(len(f.index)) {
+		end = f.index[i+1]
+	}
+
+	bz := make([]byte, end-start)
+	if _, err := f.f.ReadAt(bz, int64(start)); err != nil {
+		return nil, err
+	}
+	if len(bz) < 8 || uint64(binary.BigEndian.Uint32(bz[:4])) != uint64(len(bz)-8) {
+		return nil, fmt.Errorf("%w %s: bad entry at height %d", ErrCorrupt, f.path, f.first+i)
+	}
+	compressed := bz[8:]
+	if crc32.ChecksumIEEE(compressed) != binary.BigEndian.Uint32(bz[4:8]) {
+		return nil, fmt.Errorf("%w %s: checksum mismatch at height %d", ErrCorrupt, f.path, f.first+i)
+	}
+
+	raw, err := io.ReadAll(flate.NewReader(bytes.NewReader(compressed)))
+	if err != nil {
+		return nil, fmt.Errorf("%w %s: %v", ErrCorrupt, f.path, err)
+	}
+
+	entry := make(Entry, len(Kinds))
+	for _, kind := range Kinds {
+		length, n := binary.Uvarint(raw)
+		if n <= 0 || length > uint64(len(raw)-n)+1 {
+			return nil, fmt.Errorf("%w %s: bad entry at height %d", ErrCorrupt, f.path, f.first+i)
+		}
+		raw = raw[n:]
+		if length == 0 {
+			continue
+		}
+		entry[kind] = raw[:length-1]
+		raw = raw[length-1:]
+	}
+	return entry, nil
+}
+
+// Close closes the file.
+func (f *File) Close() error {
+	return f.f.Close()
+}
@@ -0,0 +1,37 @@
+// Package export streams stored blocks and their DA metadata to portable archives.
+package export
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/evstack/ev-node/pkg/store"
+)
+
+// Export writes the blocks in [from, to] from st to w, one record per block, and
+// returns the number of records written. Blocks are loaded one at a time, so memory
+// use does not grow with the range. w is not closed.
+func Export(ctx context.Context, st store.Reader, from, to uint64, w Writer) (uint64, error) {
+	if from == 0 || from > to {
+		return 0, fmt.Errorf("invalid height range %d-%d", from, to)
+	}
+
+	var written uint64
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return written, err
+		}
+
+		record, err := LoadRecord(ctx, st, height)
+		if err != nil {
+			return written, err
+		}
+
+		if err := w.WriteRecord(record); err != nil {
+			return written, fmt.Errorf("failed to write block %d: %w", height, err)
+		}
+		written++
+	}
+
+	return written, nil
+}
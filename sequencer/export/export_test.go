@@ -0,0 +1,192 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// newTestStore returns a store holding blocks 1 and 2, of which only block 1 is DA included.
+func newTestStore(t *testing.T) store.Store {
+	t.Helper()
+	ctx := context.Background()
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+
+	for height := uint64(1); height <= 2; height++ {
+		header, data := types.GetRandomBlock(height, 2, "test-chain")
+		batch, _ := st.NewBatch(ctx)
+		if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.SetHeight(height); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	for kind, daHeight := range map[string]uint64{"h": 10, "d": 11} {
+		bz := binary.LittleEndian.AppendUint64(nil, daHeight)
+		if err := st.SetMetadata(ctx, fmt.Sprintf("%s/1/%s", store.HeightToDAHeightKey, kind), bz); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return st
+}
+
+// splitSection splits a uvarint length-prefixed section from bz and returns it with the remainder.
+func splitSection(t *testing.T, bz []byte) ([]byte, []byte) {
+	t.Helper()
+	n, size := binary.Uvarint(bz)
+	if size <= 0 || uint64(len(bz)-size) < n {
+		t.Fatalf("truncated section")
+	}
+	return bz[size : size+int(n)], bz[size+int(n):]
+}
+
+// decodeVarints returns the varint fields of an encoded record.
+func decodeVarints(t *testing.T, bz []byte) map[protowire.Number]uint64 {
+	t.Helper()
+	fields := make(map[protowire.Number]uint64)
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			t.Fatalf("invalid tag: %v", protowire.ParseError(n))
+		}
+		bz = bz[n:]
+		if typ == protowire.VarintType {
+			v, m := protowire.ConsumeVarint(bz)
+			fields[num] = v
+			n = m
+		} else {
+			n = protowire.ConsumeFieldValue(num, typ, bz)
+		}
+		if n < 0 {
+			t.Fatalf("invalid field %d: %v", num, protowire.ParseError(n))
+		}
+		bz = bz[n:]
+	}
+	return fields
+}
+
+func TestExportPB(t *testing.T) {
+	st := newTestStore(t)
+
+	var buf bytes.Buffer
+	w := NewPBWriter(&buf)
+	written, err := Export(context.Background(), st, 1, 2, w)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if written != 2 {
+		t.Fatalf("expected 2 records, got %d", written)
+	}
+
+	rest := buf.Bytes()
+	expected := []map[protowire.Number]uint64{
+		{fieldHeight: 1, fieldHeaderDAHeight: 10, fieldDataDAHeight: 11},
+		{fieldHeight: 2, fieldHeaderDAHeight: 0, fieldDataDAHeight: 0},
+	}
+	for i, want := range expected {
+		var record []byte
+		record, rest = splitSection(t, rest)
+		got := decodeVarints(t, record)
+		for field, v := range want {
+			if got[field] != v {
+				t.Errorf("record %d: expected field %d = %d, got %d", i, field, v, got[field])
+			}
+		}
+	}
+	if len(rest) != 0 {
+		t.Errorf("expected no trailing bytes, got %d", len(rest))
+	}
+}
+
+func TestExportCAR(t *testing.T) {
+	st := newTestStore(t)
+
+	var buf bytes.Buffer
+	w := NewCARWriter(&buf)
+	if _, err := Export(context.Background(), st, 1, 2, w); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	header, rest := splitSection(t, buf.Bytes())
+
+	var roots []cid.Cid
+	for i := 0; len(rest) > 0; i++ {
+		var section []byte
+		section, rest = splitSection(t, rest)
+
+		n, id, err := cid.CidFromBytes(section)
+		if err != nil {
+			t.Fatalf("section %d: invalid CID: %v", i, err)
+		}
+		expected, err := RecordCID(section[n:])
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if !id.Equals(expected) {
+			t.Errorf("section %d: CID %s does not address its record", i, id)
+		}
+		if got := decodeVarints(t, section[n:])[fieldHeight]; got != uint64(i+1) {
+			t.Errorf("section %d: expected height %d, got %d", i, i+1, got)
+		}
+		roots = append(roots, id)
+	}
+
+	if len(roots) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(roots))
+	}
+	if !bytes.Equal(header, carHeader(roots[0])) {
+		t.Errorf("expected the first record to be the root")
+	}
+}
+
+func TestExportInvalidRange(t *testing.T) {
+	st := newTestStore(t)
+
+	if _, err := Export(context.Background(), st, 2, 1, NewPBWriter(&bytes.Buffer{})); err == nil {
+		t.Error("expected an error for an empty range")
+	}
+	if _, err := Export(context.Background(), st, 1, 3, NewPBWriter(&bytes.Buffer{})); err == nil {
+		t.Error("expected an error for a missing block")
+	}
+}
+
+func TestCARHeader(t *testing.T) {
+	id, err := RecordCID([]byte("record"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	header := carHeader(id)
+	// map(2), "roots" key
+	if !bytes.HasPrefix(header, []byte{0xa2, 0x65, 'r', 'o', 'o', 't', 's', 0x81, 0xd8, 0x2a}) {
+		t.Fatalf("unexpected header prefix %x", header[:10])
+	}
+	if !bytes.HasSuffix(header, []byte{0x67, 'v', 'e', 'r', 's', 'i', 'o', 'n', 0x01}) {
+		t.Fatalf("unexpected header suffix %x", header[len(header)-9:])
+	}
+	if !bytes.Contains(header, append([]byte{0x00}, id.Bytes()...)) {
+		t.Error("expected the header to hold the root CID")
+	}
+}
@@ -0,0 +1,237 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/pkg/genesis"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// ErrStoreNotEmpty is returned when importing into a store that already holds blocks.
+var ErrStoreNotEmpty = errors.New("import requires an empty store")
+
+// Metadata keys written by the ev-node submitter; the data key is not exported by ev-node.
+const (
+	lastSubmittedHeaderHeightKey = store.LastSubmittedHeaderHeightKey
+	lastSubmittedDataHeightKey   = "last-submitted-data-height"
+)
+
+// importLogInterval is the number of blocks between import progress logs
+const importLogInterval = 1000
+
+// Import replays the records read from r into the empty store st, starting from the
+// genesis state of executor.
+//
+// Every block is validated as a syncing node would before it is executed: the
+// signature and proposer are checked against the genesis, the data against the
+// header, and the chain is checked for continuity. The state root each header
+// commits to must match the root computed by executor for the previous block.
+//
+// DA inclusion heights are restored, and the longest prefix of DA included blocks is
+// marked as DA included, submitted and final, so the node does not resubmit blocks
+// that are already on DA.
+//
+// Returns the number of imported blocks.
+func Import(ctx context.Context, st store.Store, executor execution.Executor, gen genesis.Genesis, r Reader, logger zerolog.Logger) (uint64, error) {
+	height, err := st.Height(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("failed to load store height: %w", err)
+	}
+	if height != 0 {
+		return 0, fmt.Errorf("%w: store is at height %d", ErrStoreNotEmpty, height)
+	}
+
+	stateRoot, _, err := executor.InitChain(ctx, gen.StartTime, gen.InitialHeight, gen.ChainID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to initialize chain: %w", err)
+	}
+
+	state := types.State{
+		ChainID:         gen.ChainID,
+		InitialHeight:   gen.InitialHeight,
+		LastBlockHeight: gen.InitialHeight - 1,
+		LastBlockTime:   gen.StartTime,
+		AppHash:         stateRoot,
+	}
+
+	var (
+		imported   uint64
+		lastHeader *types.SignedHeader
+		// daIncluded is the last height up to which every block is DA included
+		daIncluded = gen.InitialHeight - 1
+		// genesisDAHeight is the first DA height holding a part of the initial block
+		genesisDAHeight uint64
+	)
+	for {
+		if err := ctx.Err(); err != nil {
+			return imported, err
+		}
+
+		record, err := r.ReadRecord()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return imported, fmt.Errorf("failed to read block %d: %w", state.LastBlockHeight+1, err)
+		}
+
+		if err := validateRecord(record, lastHeader, state, gen); err != nil {
+			return imported, fmt.Errorf("invalid block %d: %w", record.Height, err)
+		}
+
+		txs := make([][]byte, len(record.Data.Txs))
+		for i, tx := range record.Data.Txs {
+			txs[i] = tx
+		}
+
+		execCtx := context.WithValue(ctx, types.HeaderContextKey, record.Header.Header)
+		newRoot, _, err := executor.ExecuteTxs(execCtx, txs, record.Height, record.Header.Time(), state.AppHash)
+		if err != nil {
+			return imported, fmt.Errorf("failed to execute block %d: %w", record.Height, err)
+		}
+
+		state, err = state.NextState(record.Header.Header, newRoot)
+		if err != nil {
+			return imported, fmt.Errorf("failed to compute state after block %d: %w", record.Height, err)
+		}
+
+		if err := saveRecord(ctx, st, record, state); err != nil {
+			return imported, err
+		}
+
+		if daIncluded == record.Height-1 && record.HeaderDAHeight != 0 && record.DataDAHeight != 0 {
+			daIncluded = record.Height
+			if record.Height == gen.InitialHeight {
+				genesisDAHeight = min(record.HeaderDAHeight, record.DataDAHeight)
+			}
+		}
+
+		lastHeader = record.Header
+		imported++
+		if imported%importLogInterval == 0 {
+			logger.Info().Uint64("height", record.Height).Msg("imported blocks")
+		}
+	}
+
+	if imported == 0 {
+		return 0, errors.New("archive holds no blocks")
+	}
+
+	if daIncluded >= gen.InitialHeight {
+		if err := markDAIncluded(ctx, st, executor, daIncluded, genesisDAHeight); err != nil {
+			return imported, err
+		}
+	}
+
+	logger.Info().
+		Uint64("blocks", imported).
+		Uint64("height", state.LastBlockHeight).
+		Uint64("da_included_height", daIncluded).
+		Msg("import complete")
+
+	return imported, nil
+}
+
+// validateRecord checks that record is a valid successor of lastHeader in state.
+// lastHeader is nil for the first block.
+func validateRecord(record *Record, lastHeader *types.SignedHeader, state types.State, gen genesis.Genesis) error {
+	header := record.Header
+
+	if record.Height != header.Height() {
+		return fmt.Errorf("record height does not match header height %d", header.Height())
+	}
+	if expected := state.LastBlockHeight + 1; header.Height() != expected {
+		return fmt.Errorf("expected height %d", expected)
+	}
+	if header.ChainID() != state.ChainID {
+		return fmt.Errorf("chain ID %q does not match genesis chain ID %q", header.ChainID(), state.ChainID)
+	}
+	if !bytes.Equal(header.ProposerAddress, gen.ProposerAddress) {
+		return errors.New("proposer does not match genesis proposer")
+	}
+
+	if err := header.ValidateBasicWithData(record.Data); err != nil {
+		return fmt.Errorf("invalid header: %w", err)
+	}
+	if err := types.Validate(header, record.Data); err != nil {
+		return err
+	}
+
+	if lastHeader != nil {
+		if !bytes.Equal(header.LastHeaderHash, lastHeader.Hash()) {
+			return errors.New("last header hash does not match the previous block")
+		}
+		if !header.Time().After(lastHeader.Time()) {
+			return errors.New("block time must be strictly increasing")
+		}
+	}
+
+	if !bytes.Equal(header.AppHash, state.AppHash) {
+		return fmt.Errorf("state root mismatch: header commits to %s, executor computed %s", header.AppHash, types.Hash(state.AppHash))
+	}
+
+	return nil
+}
+
+// saveRecord persists record with the state after it and its DA inclusion heights.
+func saveRecord(ctx context.Context, st store.Store, record *Record, state types.State) error {
+	batch, err := st.NewBatch(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to create batch: %w", err)
+	}
+
+	if err := batch.SaveBlockData(record.Header, record.Data, &record.Header.Signature); err != nil {
+		return fmt.Errorf("failed to save block %d: %w", record.Height, err)
+	}
+	if err := batch.SetHeight(record.Height); err != nil {
+		return fmt.Errorf("failed to update height: %w", err)
+	}
+	if err := batch.UpdateState(state); err != nil {
+		return fmt.Errorf("failed to update state: %w", err)
+	}
+
+	if err := batch.Commit(); err != nil {
+		return fmt.Errorf("failed to commit block %d: %w", record.Height, err)
+	}
+
+	for kind, daHeight := range map[string]uint64{"h": record.HeaderDAHeight, "d": record.DataDAHeight} {
+		if daHeight == 0 {
+			continue
+		}
+		key := fmt.Sprintf("%s/%d/%s", store.HeightToDAHeightKey, record.Height, kind)
+		if err := st.SetMetadata(ctx, key, binary.LittleEndian.AppendUint64(nil, daHeight)); err != nil {
+			return fmt.Errorf("failed to save DA height of block %d: %w", record.Height, err)
+		}
+	}
+
+	return nil
+}
+
+// markDAIncluded records every block up to height as DA included and submitted, and
+// finalizes it in executor. genesisDAHeight is the first DA height of the chain.
+func markDAIncluded(ctx context.Context, st store.Store, executor execution.Executor, height, genesisDAHeight uint64) error {
+	if err := st.SetMetadata(ctx, store.GenesisDAHeightKey, binary.LittleEndian.AppendUint64(nil, genesisDAHeight)); err != nil {
+		return fmt.Errorf("failed to save %s: %w", store.GenesisDAHeightKey, err)
+	}
+
+	bz := binary.LittleEndian.AppendUint64(nil, height)
+	for _, key := range []string{store.DAIncludedHeightKey, lastSubmittedHeaderHeightKey, lastSubmittedDataHeightKey} {
+		if err := st.SetMetadata(ctx, key, bz); err != nil {
+			return fmt.Errorf("failed to save %s: %w", key, err)
+		}
+	}
+
+	if err := executor.SetFinal(ctx, height); err != nil {
+		return fmt.Errorf("failed to finalize height %d: %w", height, err)
+	}
+	return nil
+}
@@ -0,0 +1,272 @@
+package export
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/rs/zerolog"
+
+	coreexecution "github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/pkg/genesis"
+	"github.com/evstack/ev-node/pkg/signer/noop"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// chainOptions tamper with the produced chain.
+type chainOptions struct {
+	// badAppHashAt signs a wrong state root into the header at this height
+	badAppHashAt uint64
+}
+
+// buildChain produces n signed blocks executed by a dummy executor. The first
+// daIncluded blocks carry DA heights.
+func buildChain(t *testing.T, n, daIncluded uint64, opts chainOptions) (genesis.Genesis, []*Record) {
+	t.Helper()
+	ctx := context.Background()
+
+	gen, privKey, _ := types.GetGenesisWithPrivkey("test-chain")
+	signer, err := noop.NewNoopSigner(privKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	pubKey, err := signer.GetPublic()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	headerSigner, err := types.NewSigner(pubKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	executor := coreexecution.NewDummyExecutor()
+	stateRoot, _, err := executor.InitChain(ctx, gen.StartTime, gen.InitialHeight, gen.ChainID)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var (
+		records    []*Record
+		lastHeader *types.SignedHeader
+	)
+	for height := uint64(1); height <= n; height++ {
+		blockTime := uint64(gen.StartTime.Add(time.Duration(height) * time.Second).UnixNano())
+		data := &types.Data{
+			Metadata: &types.Metadata{ChainID: gen.ChainID, Height: height, Time: blockTime},
+			Txs:      types.Txs{types.GetRandomTx()},
+		}
+
+		header := types.Header{
+			BaseHeader:      types.BaseHeader{ChainID: gen.ChainID, Height: height, Time: blockTime},
+			DataHash:        (&types.Data{Txs: data.Txs}).DACommitment(),
+			AppHash:         stateRoot,
+			ProposerAddress: gen.ProposerAddress,
+		}
+		if height == opts.badAppHashAt {
+			header.AppHash = bytes.Repeat([]byte{0xff}, len(stateRoot))
+		}
+		if lastHeader != nil {
+			header.LastHeaderHash = lastHeader.Hash()
+		}
+
+		signature, err := types.GetSignature(header, signer)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+
+		record := &Record{
+			Height: height,
+			Header: &types.SignedHeader{Header: header, Signer: headerSigner, Signature: signature},
+			Data:   data,
+		}
+		if height <= daIncluded {
+			record.HeaderDAHeight, record.DataDAHeight = 100+height, 200+height
+		}
+		records = append(records, record)
+		lastHeader = record.Header
+
+		txs := [][]byte{data.Txs[0]}
+		if stateRoot, _, err = executor.ExecuteTxs(ctx, txs, height, header.Time(), stateRoot); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	return gen, records
+}
+
+// encodeRecords writes records in format.
+func encodeRecords(t *testing.T, format string, records []*Record) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	w, err := NewWriter(format, &buf)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, record := range records {
+		if err := w.WriteRecord(record); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return buf.Bytes()
+}
+
+// importArchive imports archive into a fresh store.
+func importArchive(t *testing.T, format string, archive []byte, gen genesis.Genesis) (store.Store, uint64, error) {
+	t.Helper()
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+	r, err := NewReader(format, bytes.NewReader(archive))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	imported, err := Import(context.Background(), st, coreexecution.NewDummyExecutor(), gen, r, zerolog.Nop())
+	return st, imported, err
+}
+
+func TestImport(t *testing.T) {
+	for _, format := range []string{FormatPB, FormatCAR} {
+		t.Run(format, func(t *testing.T) {
+			ctx := context.Background()
+			gen, records := buildChain(t, 3, 2, chainOptions{})
+
+			st, imported, err := importArchive(t, format, encodeRecords(t, format, records), gen)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if imported != 3 {
+				t.Fatalf("expected 3 blocks, got %d", imported)
+			}
+
+			height, err := st.Height(ctx)
+			if err != nil || height != 3 {
+				t.Fatalf("expected store height 3, got %d (%v)", height, err)
+			}
+
+			state, err := st.GetState(ctx)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if state.LastBlockHeight != 3 {
+				t.Errorf("expected state at height 3, got %d", state.LastBlockHeight)
+			}
+
+			record, err := LoadRecord(ctx, st, 2)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if !bytes.Equal(record.Header.Hash(), records[1].Header.Hash()) {
+				t.Error("imported header does not match the archive")
+			}
+			if record.HeaderDAHeight != 102 || record.DataDAHeight != 202 {
+				t.Errorf("expected DA heights 102/202, got %d/%d", record.HeaderDAHeight, record.DataDAHeight)
+			}
+
+			for key, expected := range map[string]uint64{
+				store.DAIncludedHeightKey: 2,
+				store.GenesisDAHeightKey:  101,
+			} {
+				bz, err := st.GetMetadata(ctx, key)
+				if err != nil {
+					t.Fatalf("unexpected error: %v", err)
+				}
+				if got := binary.LittleEndian.Uint64(bz); got != expected {
+					t.Errorf("expected %s = %d, got %d", key, expected, got)
+				}
+			}
+
+			if _, err := st.GetMetadata(ctx, fmt.Sprintf("%s/3/h", store.HeightToDAHeightKey)); err == nil {
+				t.Error("expected no DA height for a block not included in DA")
+			}
+		})
+	}
+}
+
+func TestImportRejectsInvalidArchives(t *testing.T) {
+	gen, records := buildChain(t, 3, 0, chainOptions{})
+
+	t.Run("tampered data", func(t *testing.T) {
+		tampered := *records[1]
+		tampered.Data = &types.Data{Metadata: records[1].Data.Metadata, Txs: types.Txs{types.GetRandomTx()}}
+
+		_, imported, err := importArchive(t, FormatPB, encodeRecords(t, FormatPB, []*Record{records[0], &tampered, records[2]}), gen)
+		if err == nil {
+			t.Fatal("expected an error")
+		}
+		if imported != 1 {
+			t.Errorf("expected 1 block imported before the failure, got %d", imported)
+		}
+	})
+
+	t.Run("missing block", func(t *testing.T) {
+		if _, _, err := importArchive(t, FormatPB, encodeRecords(t, FormatPB, []*Record{records[0], records[2]}), gen); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("state root mismatch", func(t *testing.T) {
+		gen, records := buildChain(t, 2, 0, chainOptions{badAppHashAt: 2})
+		if _, _, err := importArchive(t, FormatPB, encodeRecords(t, FormatPB, records), gen); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("foreign proposer", func(t *testing.T) {
+		other, _, _ := types.GetGenesisWithPrivkey("test-chain")
+		gen := gen
+		gen.ProposerAddress = other.ProposerAddress
+		if _, _, err := importArchive(t, FormatPB, encodeRecords(t, FormatPB, records), gen); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+
+	t.Run("corrupt CAR block", func(t *testing.T) {
+		archive := encodeRecords(t, FormatCAR, records)
+		archive[len(archive)-1] ^= 0xff
+		if _, _, err := importArchive(t, FormatCAR, archive, gen); err == nil {
+			t.Fatal("expected an error")
+		}
+	})
+}
+
+func TestImportRequiresEmptyStore(t *testing.T) {
+	gen, records := buildChain(t, 1, 0, chainOptions{})
+	archive := encodeRecords(t, FormatPB, records)
+
+	st, _, err := importArchive(t, FormatPB, archive, gen)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	_, err = Import(context.Background(), st, coreexecution.NewDummyExecutor(), gen, NewPBReader(bytes.NewReader(archive)), zerolog.Nop())
+	if !errors.Is(err, ErrStoreNotEmpty) {
+		t.Fatalf("expected ErrStoreNotEmpty, got %v", err)
+	}
+}
+
+func TestPBReaderEOF(t *testing.T) {
+	_, records := buildChain(t, 1, 0, chainOptions{})
+	archive := encodeRecords(t, FormatPB, records)
+
+	r := NewPBReader(bytes.NewReader(archive))
+	if _, err := r.ReadRecord(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := r.ReadRecord(); !errors.Is(err, io.EOF) {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+
+	r = NewPBReader(bytes.NewReader(archive[:len(archive)-1]))
+	if _, err := r.ReadRecord(); err == nil || errors.Is(err, io.EOF) {
+		t.Fatalf("expected a truncation error, got %v", err)
+	}
+}
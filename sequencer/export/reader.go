@@ -0,0 +1,134 @@
+package export
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/ipfs/go-cid"
+)
+
+// maxSectionSize bounds a single section so that corrupt length prefixes cannot
+// trigger huge allocations. It is far above the largest block ev-node produces.
+const maxSectionSize = 256 << 20
+
+// Reader reads records from an export stream.
+type Reader interface {
+	// ReadRecord returns the next record, or io.EOF at the end of the stream
+	ReadRecord() (*Record, error)
+}
+
+// NewReader returns a Reader for format.
+func NewReader(format string, r io.Reader) (Reader, error) {
+	switch format {
+	case FormatPB:
+		return NewPBReader(r), nil
+	case FormatCAR:
+		return NewCARReader(r), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q (expected %s or %s)", format, FormatPB, FormatCAR)
+	}
+}
+
+// pbReader reads length-delimited BlockRecord messages.
+type pbReader struct {
+	r *bufio.Reader
+}
+
+// NewPBReader creates a Reader of streams written by NewPBWriter.
+func NewPBReader(r io.Reader) Reader {
+	return &pbReader{r: bufio.NewReader(r)}
+}
+
+// ReadRecord implements Reader.
+func (p *pbReader) ReadRecord() (*Record, error) {
+	bz, err := readSection(p.r)
+	if err != nil {
+		return nil, err
+	}
+
+	record := new(Record)
+	if err := record.UnmarshalBinary(bz); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// carReader reads CARv1 archives written by NewCARWriter and checks every block
+// against its CID.
+type carReader struct {
+	r        *bufio.Reader
+	headerIn bool
+}
+
+// NewCARReader creates a Reader of archives written by NewCARWriter.
+func NewCARReader(r io.Reader) Reader {
+	return &carReader{r: bufio.NewReader(r)}
+}
+
+// ReadRecord implements Reader.
+func (c *carReader) ReadRecord() (*Record, error) {
+	if !c.headerIn {
+		header, err := readSection(c.r)
+		if err != nil {
+			return nil, err
+		}
+		// The canonical DAG-CBOR header ends with the "version" entry, map keys being sorted by length
+		if len(header) == 0 || !bytes.HasSuffix(header[:len(header)-1], appendCBORString(nil, "version")) {
+			return nil, errors.New("invalid CAR header")
+		}
+		if version := header[len(header)-1]; version != 0x01 {
+			return nil, fmt.Errorf("unsupported CAR header version byte %#x, expected CARv1", version)
+		}
+		c.headerIn = true
+	}
+
+	section, err := readSection(c.r)
+	if err != nil {
+		return nil, err
+	}
+
+	n, id, err := cid.CidFromBytes(section)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CAR block CID: %w", err)
+	}
+
+	bz := section[n:]
+	expected, err := RecordCID(bz)
+	if err != nil {
+		return nil, err
+	}
+	if !id.Equals(expected) {
+		return nil, fmt.Errorf("CAR block %s does not match its CID", id)
+	}
+
+	record := new(Record)
+	if err := record.UnmarshalBinary(bz); err != nil {
+		return nil, err
+	}
+	return record, nil
+}
+
+// readSection reads a uvarint length-prefixed section. It returns io.EOF only if the
+// stream ends before the length prefix.
+func readSection(r *bufio.Reader) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		if errors.Is(err, io.EOF) {
+			return nil, io.EOF
+		}
+		return nil, fmt.Errorf("failed to read section length: %w", err)
+	}
+	if size > maxSectionSize {
+		return nil, fmt.Errorf("section of %d bytes exceeds the %d byte limit", size, maxSectionSize)
+	}
+
+	bz := make([]byte, size)
+	if _, err := io.ReadFull(r, bz); err != nil {
+		return nil, fmt.Errorf("truncated section: %w", err)
+	}
+	return bz, nil
+}
@@ -0,0 +1,137 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	ds "github.com/ipfs/go-datastore"
+	"google.golang.org/protobuf/encoding/protowire"
+
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+// Record field numbers. A record is encoded as the protobuf message
+//
+//	message BlockRecord {
+//	  uint64 height           = 1;
+//	  bytes  header           = 2; // evnode.v1.SignedHeader
+//	  bytes  data             = 3; // evnode.v1.Data
+//	  uint64 header_da_height = 4; // 0 if not yet included in DA
+//	  uint64 data_da_height   = 5; // 0 if not yet included in DA
+//	}
+const (
+	fieldHeight         protowire.Number = 1
+	fieldHeader         protowire.Number = 2
+	fieldData           protowire.Number = 3
+	fieldHeaderDAHeight protowire.Number = 4
+	fieldDataDAHeight   protowire.Number = 5
+)
+
+// Record is an exported block together with its DA metadata.
+type Record struct {
+	Height uint64
+	Header *types.SignedHeader
+	Data   *types.Data
+	// HeaderDAHeight is the DA height the header was included at, 0 if it is not included yet
+	HeaderDAHeight uint64
+	// DataDAHeight is the DA height the data was included at, 0 if it is not included yet
+	DataDAHeight uint64
+}
+
+// MarshalBinary encodes the record as a BlockRecord protobuf message.
+func (r *Record) MarshalBinary() ([]byte, error) {
+	header, err := r.Header.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal header: %w", err)
+	}
+
+	data, err := r.Data.MarshalBinary()
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal data: %w", err)
+	}
+
+	var bz []byte
+	bz = protowire.AppendTag(bz, fieldHeight, protowire.VarintType)
+	bz = protowire.AppendVarint(bz, r.Height)
+	bz = protowire.AppendTag(bz, fieldHeader, protowire.BytesType)
+	bz = protowire.AppendBytes(bz, header)
+	bz = protowire.AppendTag(bz, fieldData, protowire.BytesType)
+	bz = protowire.AppendBytes(bz, data)
+	bz = protowire.AppendTag(bz, fieldHeaderDAHeight, protowire.VarintType)
+	bz = protowire.AppendVarint(bz, r.HeaderDAHeight)
+	bz = protowire.AppendTag(bz, fieldDataDAHeight, protowire.VarintType)
+	bz = protowire.AppendVarint(bz, r.DataDAHeight)
+
+	return bz, nil
+}
+
+// UnmarshalBinary decodes a BlockRecord protobuf message into the record.
+func (r *Record) UnmarshalBinary(bz []byte) error {
+	*r = Record{Header: new(types.SignedHeader), Data: new(types.Data)}
+
+	var header, data []byte
+	for len(bz) > 0 {
+		num, typ, n := protowire.ConsumeTag(bz)
+		if n < 0 {
+			return fmt.Errorf("invalid record: %w", protowire.ParseError(n))
+		}
+		bz = bz[n:]
+
+		switch {
+		case num == fieldHeight && typ == protowire.VarintType:
+			r.Height, n = protowire.ConsumeVarint(bz)
+		case num == fieldHeader && typ == protowire.BytesType:
+			header, n = protowire.ConsumeBytes(bz)
+		case num == fieldData && typ == protowire.BytesType:
+			data, n = protowire.ConsumeBytes(bz)
+		case num == fieldHeaderDAHeight && typ == protowire.VarintType:
+			r.HeaderDAHeight, n = protowire.ConsumeVarint(bz)
+		case num == fieldDataDAHeight && typ == protowire.VarintType:
+			r.DataDAHeight, n = protowire.ConsumeVarint(bz)
+		default:
+			// Skip unknown fields so newer archives stay readable
+			n = protowire.ConsumeFieldValue(num, typ, bz)
+		}
+		if n < 0 {
+			return fmt.Errorf("invalid record field %d: %w", num, protowire.ParseError(n))
+		}
+		bz = bz[n:]
+	}
+
+	if header == nil || data == nil {
+		return fmt.Errorf("record %d is missing its header or data", r.Height)
+	}
+	if err := r.Header.UnmarshalBinary(header); err != nil {
+		return fmt.Errorf("failed to unmarshal header %d: %w", r.Height, err)
+	}
+	if err := r.Data.UnmarshalBinary(data); err != nil {
+		return fmt.Errorf("failed to unmarshal data %d: %w", r.Height, err)
+	}
+
+	return nil
+}
+
+// LoadRecord loads the block at height and its DA metadata from st.
+func LoadRecord(ctx context.Context, st store.Reader, height uint64) (*Record, error) {
+	header, data, err := st.GetBlockData(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load block %d: %w", height, err)
+	}
+
+	record := &Record{
+		Height: height,
+		Header: header,
+		Data:   data,
+	}
+
+	record.HeaderDAHeight, record.DataDAHeight, err = seqda.BlockDAHeights(ctx, st, height)
+	if err != nil && !errors.Is(err, ds.ErrNotFound) {
+		return nil, err
+	}
+
+	return record, nil
+}
@@ -0,0 +1,158 @@
+package export
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+
+	"github.com/ipfs/go-cid"
+	mh "github.com/multiformats/go-multihash"
+)
+
+// Export formats
+const (
+	// FormatPB streams length-delimited BlockRecord messages
+	FormatPB = "pb"
+	// FormatCAR streams a CARv1 archive with one raw block per BlockRecord
+	FormatCAR = "car"
+)
+
+// Writer streams records in an export format.
+type Writer interface {
+	// WriteRecord appends a record to the stream
+	WriteRecord(record *Record) error
+	// Close flushes buffered output; it does not close the underlying writer
+	Close() error
+}
+
+// NewWriter returns a Writer for format.
+func NewWriter(format string, w io.Writer) (Writer, error) {
+	switch format {
+	case FormatPB:
+		return NewPBWriter(w), nil
+	case FormatCAR:
+		return NewCARWriter(w), nil
+	default:
+		return nil, fmt.Errorf("unknown export format %q (expected %s or %s)", format, FormatPB, FormatCAR)
+	}
+}
+
+// pbWriter writes each record prefixed by its uvarint length, as protodelim does.
+type pbWriter struct {
+	w *bufio.Writer
+}
+
+// NewPBWriter creates a Writer emitting length-delimited BlockRecord messages.
+func NewPBWriter(w io.Writer) Writer {
+	return &pbWriter{w: bufio.NewWriter(w)}
+}
+
+// WriteRecord implements Writer.
+func (p *pbWriter) WriteRecord(record *Record) error {
+	bz, err := record.MarshalBinary()
+	if err != nil {
+		return err
+	}
+	return writeSection(p.w, bz)
+}
+
+// Close implements Writer.
+func (p *pbWriter) Close() error {
+	return p.w.Flush()
+}
+
+// carWriter writes a CARv1 archive. Every record is stored as a raw (0x55) block
+// addressed by its sha2-256 CID; the first record is the archive root.
+type carWriter struct {
+	w         *bufio.Writer
+	headerOut bool
+}
+
+// NewCARWriter creates a Writer emitting a CARv1 archive of BlockRecord messages.
+// The CAR header is written with the first record, as its root is that record's CID.
+func NewCARWriter(w io.Writer) Writer {
+	return &carWriter{w: bufio.NewWriter(w)}
+}
+
+// WriteRecord implements Writer.
+func (c *carWriter) WriteRecord(record *Record) error {
+	bz, err := record.MarshalBinary()
+	if err != nil {
+		return err
+	}
+
+	id, err := RecordCID(bz)
+	if err != nil {
+		return err
+	}
+
+	if !c.headerOut {
+		if err := writeSection(c.w, carHeader(id)); err != nil {
+			return err
+		}
+		c.headerOut = true
+	}
+
+	return writeSection(c.w, append(id.Bytes(), bz...))
+}
+
+// Close implements Writer.
+func (c *carWriter) Close() error {
+	return c.w.Flush()
+}
+
+// RecordCID returns the CID addressing an encoded record in a CAR archive.
+func RecordCID(bz []byte) (cid.Cid, error) {
+	hash, err := mh.Sum(bz, mh.SHA2_256, -1)
+	if err != nil {
+		return cid.Undef, fmt.Errorf("failed to hash record: %w", err)
+	}
+	return cid.NewCidV1(cid.Raw, hash), nil
+}
+
+// carHeader encodes the DAG-CBOR CARv1 header {"roots": [root], "version": 1}.
+func carHeader(root cid.Cid) []byte {
+	// CIDs are CBOR tag 42 over a byte string holding the multibase identity prefix and the CID
+	link := append([]byte{0x00}, root.Bytes()...)
+
+	bz := []byte{0xa2} // map(2)
+	bz = appendCBORString(bz, "roots")
+	bz = append(bz, 0x81)       // array(1)
+	bz = append(bz, 0xd8, 0x2a) // tag(42)
+	bz = appendCBORHead(bz, 0x40, uint64(len(link)))
+	bz = append(bz, link...)
+	bz = appendCBORString(bz, "version")
+	bz = append(bz, 0x01) // uint(1)
+	return bz
+}
+
+// appendCBORString appends a CBOR text string.
+func appendCBORString(bz []byte, s string) []byte {
+	return append(appendCBORHead(bz, 0x60, uint64(len(s))), s...)
+}
+
+// appendCBORHead appends the head of a CBOR item of the given major type and length.
+func appendCBORHead(bz []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(bz, major|byte(n))
+	case n <= 0xff:
+		return append(bz, major|24, byte(n))
+	case n <= 0xffff:
+		return binary.BigEndian.AppendUint16(append(bz, major|25), uint16(n))
+	case n <= 0xffffffff:
+		return binary.BigEndian.AppendUint32(append(bz, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(bz, major|27), n)
+	}
+}
+
+// writeSection writes bz prefixed by its uvarint length.
+func writeSection(w io.Writer, bz []byte) error {
+	if _, err := w.Write(binary.AppendUvarint(nil, uint64(len(bz)))); err != nil {
+		return err
+	}
+	_, err := w.Write(bz)
+	return err
+}
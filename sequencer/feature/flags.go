@@ -0,0 +1,231 @@
+// Package feature gates experimental node behaviours behind flags that are set per
+// replica, so a feature can be rolled out to one replica at a time.
+package feature
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrUnknownFlag is returned for a flag that is not defined.
+	ErrUnknownFlag = errors.New("unknown feature flag")
+	// ErrStartupOnly is returned when a flag read at startup is changed at runtime.
+	ErrStartupOnly = errors.New("feature flag can only be changed at startup")
+)
+
+// LazyBlocks produces blocks only when transactions are available, through the
+// node's lazy aggregation mode.
+const LazyBlocks = "lazy-blocks"
+
+// FlowClassifier classifies proxied submissions and applies the flow policies of their
+// tags. Disabled, submissions are forwarded unclassified, e.g. while the classifier
+// plugin misbehaves.
+const FlowClassifier = "flow-classifier"
+
+// GetTxsBudget bounds GetTxs calls by their share of the block time. Disabled, block
+// production waits for the whole mempool scan.
+const GetTxsBudget = "get-txs-budget"
+
+// Definition describes a feature flag.
+type Definition struct {
+	Name        string
+	Description string
+	Default     bool
+	// Runtime reports whether the flag may be changed while the node is running;
+	// other flags are read once at startup
+	Runtime bool
+}
+
+// Definitions are the feature flags of the node.
+var Definitions = []Definition{
+	{
+		Name:        LazyBlocks,
+		Description: "Produce blocks only when transactions are available or after the lazy block interval",
+	},
+	{
+		Name:        FlowClassifier,
+		Description: "Classify proxied submissions and apply the flow policies of their tags, if a classifier is configured",
+		Default:     true,
+		Runtime:     true,
+	},
+	{
+		Name:        GetTxsBudget,
+		Description: "Bound GetTxs calls by the configured share of the block time",
+		Default:     true,
+		Runtime:     true,
+	},
+}
+
+// State is the current value of a feature flag.
+type State struct {
+	Definition
+	Enabled bool
+}
+
+// Flags holds the feature flags of a replica.
+type Flags struct {
+	logger  zerolog.Logger
+	metrics *Metrics
+
+	mu    sync.RWMutex
+	flags map[string]*State
+}
+
+// NewFlags creates the feature flags of a replica.
+//
+// Parameters:
+// - defs: Definitions of the available flags
+// - overrides: Values replacing the flag defaults, keyed by flag name
+// - logger: Logger used to report flag changes
+// - metrics: Feature flag metrics
+//
+// Returns:
+// - *Flags: The initialized flags
+// - error: ErrUnknownFlag if overrides name an undefined flag
+func NewFlags(defs []Definition, overrides map[string]bool, logger zerolog.Logger, metrics *Metrics) (*Flags, error) {
+	f := &Flags{
+		logger:  logger.With().Str("component", "feature").Logger(),
+		metrics: metrics,
+		flags:   make(map[string]*State, len(defs)),
+	}
+	for _, def := range defs {
+		f.flags[def.Name] = &State{Definition: def, Enabled: def.Default}
+	}
+
+	for name, enabled := range overrides {
+		state, ok := f.flags[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFlag, name)
+		}
+		state.Enabled = enabled
+	}
+
+	for _, state := range f.flags {
+		f.metrics.Enabled.With("flag", state.Name).Set(gaugeValue(state.Enabled))
+		if state.Enabled {
+			f.logger.Info().Str("flag", state.Name).Msg("Feature flag enabled")
+		}
+	}
+	return f, nil
+}
+
+// Enabled reports whether the named flag is enabled. Undefined flags are disabled.
+func (f *Flags) Enabled(name string) bool {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	state, ok := f.flags[name]
+	return ok && state.Enabled
+}
+
+// Set changes the named flag at runtime.
+func (f *Flags) Set(name string, enabled bool) error {
+	_, err := f.SetAll([]Change{{Name: name, Enabled: enabled}}, false)
+	return err
+}
+
+// Change is a change of a feature flag.
+type Change struct {
+	Name    string
+	Enabled bool
+}
+
+// SetAll changes the named flags at runtime, all or none: if one of changes is invalid,
+// none is applied. With dryRun, the changes are only checked. It returns the state of
+// every flag after the changes, sorted by name.
+func (f *Flags) SetAll(changes []Change, dryRun bool) ([]State, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	for _, change := range changes {
+		state, ok := f.flags[change.Name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", ErrUnknownFlag, change.Name)
+		}
+		if !state.Runtime {
+			return nil, fmt.Errorf("%w: %s", ErrStartupOnly, change.Name)
+		}
+	}
+
+	after := make(map[string]bool, len(changes))
+	for _, change := range changes {
+		after[change.Name] = change.Enabled
+	}
+
+	if !dryRun {
+		for name, enabled := range after {
+			state := f.flags[name]
+			if state.Enabled == enabled {
+				continue
+			}
+
+			state.Enabled = enabled
+			f.metrics.Enabled.With("flag", name).Set(gaugeValue(enabled))
+			f.metrics.Toggles.With("flag", name).Add(1)
+			f.logger.Info().Str("flag", name).Bool("enabled", enabled).Msg("Feature flag changed")
+		}
+	}
+
+	states := f.list()
+	for i := range states {
+		if enabled, ok := after[states[i].Name]; ok {
+			states[i].Enabled = enabled
+		}
+	}
+	return states, nil
+}
+
+// List returns the state of every flag, sorted by name.
+func (f *Flags) List() []State {
+	f.mu.RLock()
+	defer f.mu.RUnlock()
+
+	return f.list()
+}
+
+// list returns the state of every flag, sorted by name. f.mu must be held.
+func (f *Flags) list() []State {
+	states := make([]State, 0, len(f.flags))
+	for _, state := range f.flags {
+		states = append(states, *state)
+	}
+	sort.Slice(states, func(i, j int) bool { return states[i].Name < states[j].Name })
+	return states
+}
+
+// ParseOverrides parses flag values of the form name=true or name=false. A bare
+// name enables the flag.
+func ParseOverrides(values []string) (map[string]bool, error) {
+	overrides := make(map[string]bool, len(values))
+	for _, value := range values {
+		name, raw, hasValue := strings.Cut(strings.TrimSpace(value), "=")
+		if name == "" {
+			return nil, fmt.Errorf("invalid feature flag %q: missing name", value)
+		}
+
+		enabled := true
+		if hasValue {
+			var err error
+			if enabled, err = strconv.ParseBool(raw); err != nil {
+				return nil, fmt.Errorf("invalid feature flag %q: %w", value, err)
+			}
+		}
+		overrides[name] = enabled
+	}
+	return overrides, nil
+}
+
+// gaugeValue returns the gauge value of a flag state
+func gaugeValue(enabled bool) float64 {
+	if enabled {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,111 @@
+package feature
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+var testDefinitions = []Definition{
+	{Name: "startup", Description: "read at startup"},
+	{Name: "runtime", Description: "changed at runtime", Default: true, Runtime: true},
+}
+
+func newTestFlags(t *testing.T, overrides map[string]bool) *Flags {
+	t.Helper()
+	metrics, err := NopMetrics()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flags, err := NewFlags(testDefinitions, overrides, zerolog.Nop(), metrics)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return flags
+}
+
+func TestFlags(t *testing.T) {
+	flags := newTestFlags(t, map[string]bool{"startup": true})
+
+	if !flags.Enabled("startup") || !flags.Enabled("runtime") {
+		t.Errorf("expected both flags enabled, got %+v", flags.List())
+	}
+	if flags.Enabled("undefined") {
+		t.Error("expected undefined flag to be disabled")
+	}
+
+	if err := flags.Set("runtime", false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flags.Enabled("runtime") {
+		t.Error("expected runtime flag to be disabled")
+	}
+
+	if err := flags.Set("startup", false); !errors.Is(err, ErrStartupOnly) {
+		t.Errorf("expected ErrStartupOnly, got %v", err)
+	}
+	if err := flags.Set("undefined", true); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("expected ErrUnknownFlag, got %v", err)
+	}
+
+	states := flags.List()
+	if len(states) != 2 || states[0].Name != "runtime" || states[1].Name != "startup" {
+		t.Errorf("expected flags sorted by name, got %+v", states)
+	}
+}
+
+func TestFlags_SetAll(t *testing.T) {
+	flags := newTestFlags(t, nil)
+	flags.flags["other"] = &State{Definition: Definition{Name: "other", Runtime: true}}
+
+	// A dry run previews the changes without applying them
+	states, err := flags.SetAll([]Change{{Name: "runtime", Enabled: false}, {Name: "other", Enabled: true}}, true)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if states[0].Name != "other" || !states[0].Enabled || states[1].Name != "runtime" || states[1].Enabled {
+		t.Errorf("unexpected preview %+v", states)
+	}
+	if !flags.Enabled("runtime") || flags.Enabled("other") {
+		t.Errorf("expected no change from a dry run, got %+v", flags.List())
+	}
+
+	// An invalid change fails the whole batch
+	if _, err := flags.SetAll([]Change{{Name: "runtime", Enabled: false}, {Name: "startup", Enabled: true}}, false); !errors.Is(err, ErrStartupOnly) {
+		t.Fatalf("expected ErrStartupOnly, got %v", err)
+	}
+	if !flags.Enabled("runtime") {
+		t.Error("expected no change from a failed batch")
+	}
+
+	if _, err := flags.SetAll([]Change{{Name: "runtime", Enabled: false}, {Name: "other", Enabled: true}}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if flags.Enabled("runtime") || !flags.Enabled("other") {
+		t.Errorf("expected both changes applied, got %+v", flags.List())
+	}
+}
+
+func TestNewFlagsUnknownOverride(t *testing.T) {
+	metrics, _ := NopMetrics()
+	if _, err := NewFlags(testDefinitions, map[string]bool{"undefined": true}, zerolog.Nop(), metrics); !errors.Is(err, ErrUnknownFlag) {
+		t.Errorf("expected ErrUnknownFlag, got %v", err)
+	}
+}
+
+func TestParseOverrides(t *testing.T) {
+	overrides, err := ParseOverrides([]string{"a", "b=false", " c=true"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(overrides) != 3 || !overrides["a"] || overrides["b"] || !overrides["c"] {
+		t.Errorf("unexpected overrides: %+v", overrides)
+	}
+
+	for _, value := range []string{"=true", "a=maybe"} {
+		if _, err := ParseOverrides([]string{value}); err == nil {
+			t.Errorf("expected an error for %q", value)
+		}
+	}
+}
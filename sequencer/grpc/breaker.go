@@ -0,0 +1,192 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/core/execution"
+)
+
+// Ensure BreakerExecutor implements the execution.Executor interface
+var _ execution.Executor = (*BreakerExecutor)(nil)
+
+// ErrCircuitOpen is returned without calling the execution layer while the circuit
+// breaker is open.
+var ErrCircuitOpen = errors.New("execution layer circuit breaker is open")
+
+// CircuitState is the state of a circuit breaker, the value of its state gauge.
+type CircuitState int
+
+const (
+	// CircuitClosed lets calls through
+	CircuitClosed CircuitState = iota
+	// CircuitOpen fails calls with ErrCircuitOpen
+	CircuitOpen
+	// CircuitHalfOpen lets one probe call through, which decides whether the circuit closes
+	CircuitHalfOpen
+)
+
+// String returns the name of the state.
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitClosed:
+		return "closed"
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return fmt.Sprintf("CircuitState(%d)", int(s))
+}
+
+// BreakerExecutor wraps an execution.Executor with a circuit breaker, so an execution
+// layer that went away fails calls right away instead of every call waiting out its
+// timeouts and retries against it.
+//
+// Only failures of the execution layer, see failed, count; a call it answers with an
+// executor error, e.g. for an invalid block, shows it is up.
+type BreakerExecutor struct {
+	execution.Executor
+	threshold     int
+	probeInterval time.Duration
+	logger        zerolog.Logger
+	metrics       *Metrics
+
+	mu       sync.Mutex
+	state    CircuitState
+	failures int
+	openedAt time.Time
+}
+
+// NewBreakerExecutor wraps executor with a circuit breaker.
+//
+// Parameters:
+// - executor: The wrapped executor
+// - threshold: Consecutive failures that open the circuit
+// - probeInterval: How long the circuit stays open before a call probes the execution layer
+// - logger: Logger for state changes
+// - metrics: Metrics for the state and rejected calls
+//
+// Returns:
+// - *BreakerExecutor: The wrapped executor, with its circuit closed
+func NewBreakerExecutor(executor execution.Executor, threshold int, probeInterval time.Duration, logger zerolog.Logger, metrics *Metrics) *BreakerExecutor {
+	metrics.CircuitState.Set(float64(CircuitClosed))
+	return &BreakerExecutor{
+		Executor:      executor,
+		threshold:     threshold,
+		probeInterval: probeInterval,
+		logger:        logger.With().Str("component", "executor-breaker").Logger(),
+		metrics:       metrics,
+	}
+}
+
+// State returns the state of the circuit.
+func (e *BreakerExecutor) State() CircuitState {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	return e.state
+}
+
+// InitChain initializes the chain unless the circuit is open.
+func (e *BreakerExecutor) InitChain(ctx context.Context, genesisTime time.Time, initialHeight uint64, chainID string) ([]byte, uint64, error) {
+	if err := e.allow("InitChain"); err != nil {
+		return nil, 0, err
+	}
+	stateRoot, maxBytes, err := e.Executor.InitChain(ctx, genesisTime, initialHeight, chainID)
+	e.done(ctx, err)
+	return stateRoot, maxBytes, err
+}
+
+// GetTxs fetches transactions from the mempool unless the circuit is open.
+func (e *BreakerExecutor) GetTxs(ctx context.Context) ([][]byte, error) {
+	if err := e.allow("GetTxs"); err != nil {
+		return nil, err
+	}
+	txs, err := e.Executor.GetTxs(ctx)
+	e.done(ctx, err)
+	return txs, err
+}
+
+// ExecuteTxs executes the transactions of a block unless the circuit is open.
+func (e *BreakerExecutor) ExecuteTxs(ctx context.Context, txs [][]byte, blockHeight uint64, timestamp time.Time, prevStateRoot []byte) ([]byte, uint64, error) {
+	if err := e.allow("ExecuteTxs"); err != nil {
+		return nil, 0, err
+	}
+	stateRoot, maxBytes, err := e.Executor.ExecuteTxs(ctx, txs, blockHeight, timestamp, prevStateRoot)
+	e.done(ctx, err)
+	return stateRoot, maxBytes, err
+}
+
+// SetFinal marks a block final unless the circuit is open.
+func (e *BreakerExecutor) SetFinal(ctx context.Context, blockHeight uint64) error {
+	if err := e.allow("SetFinal"); err != nil {
+		return err
+	}
+	err := e.Executor.SetFinal(ctx, blockHeight)
+	e.done(ctx, err)
+	return err
+}
+
+// allow returns ErrCircuitOpen if the call named method may not reach the execution
+// layer. Once the probe interval has passed, the first call is let through as a probe.
+func (e *BreakerExecutor) allow(method string) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == CircuitOpen && time.Since(e.openedAt) >= e.probeInterval {
+		e.transition(CircuitHalfOpen)
+		return nil
+	}
+	if e.state != CircuitClosed {
+		e.metrics.CircuitRejected.With("method", method).Add(1)
+		return fmt.Errorf("%w: %s", ErrCircuitOpen, method)
+	}
+	return nil
+}
+
+// done records the result of a call let through with ctx.
+func (e *BreakerExecutor) done(ctx context.Context, err error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	switch {
+	case failed(ctx, err):
+		e.failures++
+		if e.state == CircuitHalfOpen || e.failures >= e.threshold {
+			e.openedAt = time.Now()
+			e.transition(CircuitOpen)
+			e.logger.Warn().Err(err).Int("failures", e.failures).Dur("probe_interval", e.probeInterval).Msg("Execution layer is failing, opening the circuit")
+		}
+	case err != nil && ctx.Err() != nil:
+		// A call given up by the caller says nothing about the execution layer, so an
+		// abandoned probe is retried by the next call
+		if e.state == CircuitHalfOpen {
+			e.transition(CircuitOpen)
+		}
+	default:
+		e.failures = 0
+		if e.state == CircuitHalfOpen {
+			e.transition(CircuitClosed)
+			e.logger.Info().Msg("Execution layer is back, closing the circuit")
+		}
+	}
+}
+
+// transition moves the circuit to state.
+func (e *BreakerExecutor) transition(state CircuitState) {
+	e.state = state
+	e.metrics.CircuitState.Set(float64(state))
+	e.metrics.CircuitTransitions.With("state", state.String()).Add(1)
+}
+
+// failed reports whether err, returned by a call made with ctx, is a failure of the
+// execution layer: it timed out, or failed with a transient error.
+func failed(ctx context.Context, err error) bool {
+	return err != nil && ctx.Err() == nil && (errors.Is(err, ErrTimeout) || retryable(err))
+}
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/rs/zerolog"
+)
+
+func TestBreakerExecutor(t *testing.T) {
+	ctx := context.Background()
+
+	var down atomic.Bool
+	var calls atomic.Int32
+	mockExec := &mockExecutor{
+		setFinalFunc: func(ctx context.Context, blockHeight uint64) error {
+			calls.Add(1)
+			if down.Load() {
+				return connect.NewError(connect.CodeUnavailable, errors.New("connection refused"))
+			}
+			if blockHeight == 0 {
+				return connect.NewError(connect.CodeInternal, errors.New("unknown block"))
+			}
+			return nil
+		},
+	}
+
+	metrics, _ := NopMetrics()
+	metrics.CircuitState = generic.NewGauge("circuit_state")
+	rejected := &labelCounter{counts: map[string]float64{}}
+	metrics.CircuitRejected = rejected
+	executor := NewBreakerExecutor(mockExec, 2, 50*time.Millisecond, zerolog.Nop(), metrics)
+
+	// Executor errors show the execution layer is up
+	for range 3 {
+		if err := executor.SetFinal(ctx, 0); err == nil {
+			t.Fatal("expected the executor error")
+		}
+	}
+	if executor.State() != CircuitClosed {
+		t.Fatalf("expected the circuit closed after executor errors, got %s", executor.State())
+	}
+
+	// Consecutive failures open the circuit, which then short-circuits calls
+	down.Store(true)
+	for range 2 {
+		if err := executor.SetFinal(ctx, 1); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the call to reach the execution layer, got %v", err)
+		}
+	}
+	calls.Store(0)
+	if err := executor.SetFinal(ctx, 1); !errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("expected ErrCircuitOpen, got %v", err)
+	}
+	if calls.Load() != 0 || rejected.counts["method,SetFinal"] != 1 {
+		t.Errorf("expected the call rejected without reaching the execution layer, got %d calls", calls.Load())
+	}
+	if got := metrics.CircuitState.(*generic.Gauge).Value(); got != float64(CircuitOpen) {
+		t.Errorf("expected the open state gauge, got %v", got)
+	}
+
+	// A failing probe opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	if err := executor.SetFinal(ctx, 1); errors.Is(err, ErrCircuitOpen) || calls.Load() != 1 {
+		t.Fatalf("expected a probe to reach the execution layer, got %v", err)
+	}
+	if executor.State() != CircuitOpen {
+		t.Fatalf("expected the circuit open after a failed probe, got %s", executor.State())
+	}
+
+	// A successful probe closes it
+	down.Store(false)
+	time.Sleep(60 * time.Millisecond)
+	if err := executor.SetFinal(ctx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if executor.State() != CircuitClosed {
+		t.Errorf("expected the circuit closed after a successful probe, got %s", executor.State())
+	}
+	if got := metrics.CircuitState.(*generic.Gauge).Value(); got != float64(CircuitClosed) {
+		t.Errorf("expected the closed state gauge, got %v", got)
+	}
+}
@@ -0,0 +1,77 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/core/execution"
+)
+
+// Ensure BudgetExecutor implements the execution.Executor interface
+var _ execution.Executor = (*BudgetExecutor)(nil)
+
+// BudgetExecutor wraps an execution.Executor so GetTxs returns within a time budget.
+//
+// A unary GetTxs response cut short by the budget carries no transactions, while a
+// streamed one carries the chunks received in time. The others are not lost: GetTxs
+// returns the ready transactions without removing them from the mempool, so the next
+// call picks them up.
+type BudgetExecutor struct {
+	execution.Executor
+	budget  time.Duration
+	enabled func() bool
+	logger  zerolog.Logger
+	metrics *Metrics
+}
+
+// NewBudgetExecutor wraps executor so each GetTxs call is abandoned after budget.
+//
+// Parameters:
+// - executor: The wrapped executor
+// - budget: How long a GetTxs call may take
+// - enabled: Reports whether the budget applies, read on every call (nil always applies it)
+// - logger: Logger for abandoned calls
+// - metrics: Metrics for abandoned calls
+//
+// Returns:
+// - *BudgetExecutor: The wrapped executor
+func NewBudgetExecutor(executor execution.Executor, budget time.Duration, enabled func() bool, logger zerolog.Logger, metrics *Metrics) *BudgetExecutor {
+	return &BudgetExecutor{
+		Executor: executor,
+		budget:   budget,
+		enabled:  enabled,
+		logger:   logger.With().Str("component", "get-txs-budget").Logger(),
+		metrics:  metrics,
+	}
+}
+
+// GetTxs fetches available transactions from the execution layer's mempool. A call
+// exceeding the budget returns the transactions streamed in time, if any, and no
+// error, so the block is built from what was already collected instead of waiting for
+// the mempool scan.
+func (e *BudgetExecutor) GetTxs(ctx context.Context) ([][]byte, error) {
+	if e.enabled != nil && !e.enabled() {
+		return e.Executor.GetTxs(ctx)
+	}
+
+	budgetCtx, cancel := context.WithTimeout(ctx, e.budget)
+	defer cancel()
+
+	// The deadline is propagated to the execution layer, whose answer that it passed
+	// may arrive just before it passes here
+	txs, err := e.Executor.GetTxs(budgetCtx)
+	if err != nil && ctx.Err() == nil && (errors.Is(budgetCtx.Err(), context.DeadlineExceeded) || connect.CodeOf(err) == connect.CodeDeadlineExceeded) {
+		e.metrics.GetTxsBudgetExceeded.Add(1)
+		if errors.Is(err, ErrTxStreamCut) {
+			e.logger.Warn().Dur("budget", e.budget).Int("txs", len(txs)).Msg("GetTxs exceeded its budget, proceeding with the transactions streamed so far")
+			return txs, nil
+		}
+		e.logger.Warn().Dur("budget", e.budget).Msg("GetTxs exceeded its budget, proceeding without new transactions")
+		return nil, nil
+	}
+	return txs, err
+}
@@ -0,0 +1,55 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func TestBudgetExecutor_GetTxs(t *testing.T) {
+	ctx := context.Background()
+	metrics, _ := NopMetrics()
+
+	slow := &mockExecutor{
+		getTxsFunc: func(ctx context.Context) ([][]byte, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		},
+	}
+	txs, err := NewBudgetExecutor(slow, 10*time.Millisecond, nil, zerolog.Nop(), metrics).GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("expected an exceeded budget not to fail, got %v", err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("expected no transactions, got %d", len(txs))
+	}
+
+	txs, err = NewBudgetExecutor(&mockExecutor{}, time.Second, nil, zerolog.Nop(), metrics).GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Errorf("expected 2 transactions, got %d", len(txs))
+	}
+
+	// A disabled budget waits for the call
+	calls := 0
+	slowest := &mockExecutor{
+		getTxsFunc: func(ctx context.Context) ([][]byte, error) {
+			calls++
+			time.Sleep(20 * time.Millisecond)
+			return [][]byte{[]byte("tx")}, ctx.Err()
+		},
+	}
+	if txs, err := NewBudgetExecutor(slowest, 10*time.Millisecond, func() bool { return false }, zerolog.Nop(), metrics).GetTxs(ctx); err != nil || len(txs) != 1 || calls != 1 {
+		t.Errorf("expected the call to complete without a budget, got %d txs, %v", len(txs), err)
+	}
+
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, err := NewBudgetExecutor(slow, time.Second, nil, zerolog.Nop(), metrics).GetTxs(cancelled); err == nil {
+		t.Error("expected a cancelled call to fail")
+	}
+}
@@ -2,14 +2,18 @@ package grpc
 
 import (
 	"context"
+	"crypto/sha256"
 	"crypto/tls"
 	"fmt"
 	"net"
 	"net/http"
+	"sync/atomic"
 	"time"
 
 	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
 	"golang.org/x/net/http2"
+	"google.golang.org/protobuf/types/known/emptypb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	"github.com/evstack/ev-node/core/execution"
@@ -23,7 +27,21 @@ var _ execution.Executor = (*Client)(nil)
 // Client is a Connect-RPC client that implements the execution.Executor interface.
 // It communicates with the Pranklin execution service via Connect-RPC with HTTP/2 support.
 type Client struct {
-	client v1connect.ExecutorServiceClient
+	client       v1connect.ExecutorServiceClient
+	compressed   v1connect.ExecutorServiceClient
+	health       *connect.Client[emptypb.Empty, emptypb.Empty]
+	httpClient   *http.Client
+	url          string
+	gzipAccepted atomic.Bool
+	txStream     *txStream
+	mempool      *mempoolTracker
+	telemetry    *txTelemetry
+	tracing      *tracing
+	onExecuted   func(txs [][]byte, at time.Time)
+	retry        RetryPolicy
+	timeouts     Timeouts
+	logger       zerolog.Logger
+	metrics      *Metrics
 }
 
 // NewClient creates a new Connect-RPC execution client for Pranklin with HTTP/2 support.
@@ -47,12 +65,67 @@ func NewClient(url string) *Client {
 		},
 	}
 
-	return &Client{
-		client: v1connect.NewExecutorServiceClient(
-			httpClient,
-			url,
-		),
+	return newClient(httpClient, url)
+}
+
+// NewTLSClient creates a new Connect-RPC execution client for Pranklin connecting over
+// TLS, e.g. to an execution layer on another host or behind a service mesh. HTTP/2 is
+// negotiated with the server, falling back to HTTP/1.1.
+//
+// Parameters:
+// - url: The URL of the gRPC server (e.g., "https://execution:50051")
+// - tlsConfig: The TLS configuration, with the trusted CAs and any client certificate
+//
+// Returns:
+// - *Client: The initialized Connect-RPC client with TLS transport
+func NewTLSClient(url string, tlsConfig *tls.Config) *Client {
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			TLSClientConfig:   tlsConfig,
+			ForceAttemptHTTP2: true,
+		},
+	}
+
+	return newClient(httpClient, url)
+}
+
+// newClient creates a client of the execution service at url over httpClient.
+func newClient(httpClient *http.Client, url string) *Client {
+	// Calls are recorded in no-op metrics until SetMetrics sets the node's metrics
+	metrics, _ := NopMetrics()
+	c := &Client{
+		httpClient: httpClient,
+		url:        url,
+		mempool:    newMempoolTracker(),
+		metrics:    metrics,
 	}
+	c.client = v1connect.NewExecutorServiceClient(
+		httpClient,
+		url,
+		connect.WithInterceptors(c.traceInterceptor(), c.negotiateInterceptor()),
+	)
+	// Health checks bypass the interceptors of the executor calls
+	c.health = connect.NewClient[emptypb.Empty, emptypb.Empty](httpClient, url+HealthCheckProcedure)
+	return c
+}
+
+// SetMetrics records the result and latency of each call attempt, and the retries of
+// calls, in metrics. It must be called before the client is used.
+func (c *Client) SetMetrics(metrics *Metrics) {
+	c.metrics = metrics
+}
+
+// SetTxTelemetry records the waits of executed transactions in metrics, sampled as
+// configured by sampling. It must be called before the client is used.
+func (c *Client) SetTxTelemetry(sampling TxSampling, logger zerolog.Logger, metrics *Metrics) {
+	c.telemetry = newTxTelemetry(sampling, logger, metrics)
+}
+
+// OnExecuted calls fn with the transactions of each block executed, once ExecuteTxs
+// succeeds. fn runs on the block production path, so it must return quickly. It must
+// be called before the client is used.
+func (c *Client) OnExecuted(fn func(txs [][]byte, at time.Time)) {
+	c.onExecuted = fn
 }
 
 // Close is a no-op for Connect-RPC clients (connection is managed by http.Client)
@@ -68,7 +141,11 @@ func (c *Client) InitChain(ctx context.Context, genesisTime time.Time, initialHe
 		ChainId:       chainID,
 	})
 
-	resp, err := c.client.InitChain(ctx, req)
+	var resp *connect.Response[pb.InitChainResponse]
+	err = c.call(ctx, "InitChain", c.timeouts.InitChain, func(ctx context.Context) (err error) {
+		resp, err = c.rpc().InitChain(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("connect client: failed to init chain: %w", err)
 	}
@@ -76,15 +153,28 @@ func (c *Client) InitChain(ctx context.Context, genesisTime time.Time, initialHe
 	return resp.Msg.StateRoot, resp.Msg.MaxBytes, nil
 }
 
-// GetTxs fetches available transactions from the execution layer's mempool.
+// GetTxs fetches available transactions from the execution layer's mempool. When they
+// are streamed, see SetTxStream, a stream ending early returns the transactions
+// received with ErrTxStreamCut.
 func (c *Client) GetTxs(ctx context.Context) ([][]byte, error) {
+	if c.txStream != nil && !c.txStream.unsupported.Load() {
+		if txs, ok, err := c.streamTxs(ctx); ok {
+			return txs, err
+		}
+	}
+
 	req := connect.NewRequest(&pb.GetTxsRequest{})
 
-	resp, err := c.client.GetTxs(ctx, req)
+	var resp *connect.Response[pb.GetTxsResponse]
+	err := c.call(ctx, "GetTxs", c.timeouts.GetTxs, func(ctx context.Context) (err error) {
+		resp, err = c.rpc().GetTxs(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, fmt.Errorf("connect client: failed to get txs: %w", err)
 	}
 
+	c.mempool.observe(resp.Msg.Txs, time.Now())
 	return resp.Msg.Txs, nil
 }
 
@@ -97,24 +187,71 @@ func (c *Client) ExecuteTxs(ctx context.Context, txs [][]byte, blockHeight uint6
 		PrevStateRoot: prevStateRoot,
 	})
 
-	resp, err := c.client.ExecuteTxs(ctx, req)
+	var resp *connect.Response[pb.ExecuteTxsResponse]
+	err = c.call(ctx, "ExecuteTxs", c.timeouts.ExecuteTxs, func(ctx context.Context) (err error) {
+		resp, err = c.rpc().ExecuteTxs(ctx, req)
+		return err
+	})
 	if err != nil {
 		return nil, 0, fmt.Errorf("connect client: failed to execute txs: %w", err)
 	}
 
+	c.executed(txs, blockHeight)
 	return resp.Msg.UpdatedStateRoot, resp.Msg.MaxBytes, nil
 }
 
+// executed passes the transactions of the block at height to the OnExecuted callback,
+// removes them from the mempool view and records their waits.
+func (c *Client) executed(txs [][]byte, height uint64) {
+	now := time.Now()
+	if c.onExecuted != nil {
+		c.onExecuted(txs, now)
+	}
+
+	if c.telemetry == nil {
+		c.mempool.executed(txs, now, nil)
+		return
+	}
+
+	var maxWait time.Duration
+	var seen bool
+	c.mempool.executed(txs, now, func(hash [sha256.Size]byte, wait time.Duration) {
+		c.telemetry.observe(hash, wait, height)
+		maxWait = max(maxWait, wait)
+		seen = true
+	})
+	if seen {
+		c.telemetry.block(maxWait)
+	}
+}
+
 // SetFinal marks a block as finalized at the specified height.
 func (c *Client) SetFinal(ctx context.Context, blockHeight uint64) error {
 	req := connect.NewRequest(&pb.SetFinalRequest{
 		BlockHeight: blockHeight,
 	})
 
-	_, err := c.client.SetFinal(ctx, req)
+	err := c.call(ctx, "SetFinal", c.timeouts.SetFinal, func(ctx context.Context) error {
+		_, err := c.rpc().SetFinal(ctx, req)
+		return err
+	})
 	if err != nil {
 		return fmt.Errorf("connect client: failed to set final: %w", err)
 	}
 
 	return nil
 }
+
+// GetMempoolInfo reports the transactions waiting in the execution layer's mempool.
+//
+// The execution service has no mempool RPC, so the information is derived from the
+// GetTxs responses seen by this client: transactions count as waiting from the first
+// response returning them until they are executed or no longer returned. Only the
+// mempool head returned by GetTxs is visible, so TxCount is capped by the GetTxs
+// size limit; a count stuck at that cap with a growing OldestTxAge means a backlog.
+func (c *Client) GetMempoolInfo(ctx context.Context) (MempoolInfo, error) {
+	if err := ctx.Err(); err != nil {
+		return MempoolInfo{}, err
+	}
+	return c.mempool.info(time.Now()), nil
+}
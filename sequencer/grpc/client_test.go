@@ -3,8 +3,11 @@ package grpc
 import (
 	"context"
 	"net/http/httptest"
+	"strings"
 	"testing"
 	"time"
+
+	"github.com/go-kit/kit/metrics"
 )
 
 // mockExecutor is a mock implementation of execution.Executor for testing
@@ -199,3 +202,22 @@ func TestClient_SetFinal(t *testing.T) {
 		t.Fatalf("unexpected error: %v", err)
 	}
 }
+
+// countingHistogram is a histogram counting its observations
+type countingHistogram struct {
+	count int
+}
+
+func (h *countingHistogram) With(labelValues ...string) metrics.Histogram { return h }
+func (h *countingHistogram) Observe(value float64)                        { h.count++ }
+
+// labelCounter counts additions by label values
+type labelCounter struct {
+	counts map[string]float64
+	labels string
+}
+
+func (c *labelCounter) With(labelValues ...string) metrics.Counter {
+	return &labelCounter{counts: c.counts, labels: strings.Join(labelValues, ",")}
+}
+func (c *labelCounter) Add(delta float64) { c.counts[c.labels] += delta }
@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+
+	"connectrpc.com/connect"
+
+	"github.com/evstack/ev-node/types/pb/evnode/v1/v1connect"
+)
+
+// compressionGzip is the name of gzip compression in Connect and gRPC headers
+const compressionGzip = "gzip"
+
+// SetCompression gzip compresses requests of at least minBytes, e.g. the ExecuteTxs
+// requests of large blocks, once the execution service advertises that it accepts
+// gzip. Until then, and if it never does, requests are sent uncompressed. It must be
+// called before the client is used.
+func (c *Client) SetCompression(minBytes int) error {
+	if minBytes < 0 {
+		return errors.New("compression threshold must be >= 0")
+	}
+
+	c.compressed = v1connect.NewExecutorServiceClient(
+		c.httpClient,
+		c.url,
+		connect.WithInterceptors(c.traceInterceptor(), c.negotiateInterceptor()),
+		connect.WithSendGzip(),
+		connect.WithCompressMinBytes(minBytes),
+	)
+	return nil
+}
+
+// rpc returns the client calls are sent with: the compressing one once the
+// execution service accepts gzip.
+func (c *Client) rpc() v1connect.ExecutorServiceClient {
+	if c.compressed != nil && c.gzipAccepted.Load() {
+		return c.compressed
+	}
+	return c.client
+}
+
+// negotiateInterceptor records whether the execution service accepts gzip, from the
+// compressions its responses advertise.
+func (c *Client) negotiateInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			resp, err := next(ctx, req)
+			if resp != nil {
+				c.gzipAccepted.Store(acceptsGzip(resp.Header()))
+			}
+			return resp, err
+		}
+	}
+}
+
+// acceptsGzip reports whether a response header advertises gzip among the
+// compressions accepted by the server, in the Connect or the gRPC protocol.
+func acceptsGzip(header http.Header) bool {
+	for _, key := range []string{"Accept-Encoding", "Grpc-Accept-Encoding"} {
+		for _, value := range header.Values(key) {
+			for _, name := range strings.Split(value, ",") {
+				if strings.TrimSpace(name) == compressionGzip {
+					return true
+				}
+			}
+		}
+	}
+	return false
+}
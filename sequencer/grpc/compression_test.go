@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestClient_SetCompression(t *testing.T) {
+	ctx := context.Background()
+
+	var encodings []string
+	handler := NewExecutorServiceHandler(&mockExecutor{})
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		encodings = append(encodings, r.Header.Get("Content-Encoding"))
+		handler.ServeHTTP(w, r)
+	}), &http2.Server{}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.SetCompression(1024); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	txs := [][]byte{bytes.Repeat([]byte("order"), 1000)}
+	for range 2 {
+		if _, _, err := client.ExecuteTxs(ctx, txs, 1, time.Now(), []byte("prev_state_root")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The first request learns that the service accepts gzip, later large ones are
+	// compressed and small ones are not
+	if len(encodings) != 3 || encodings[0] != "" || encodings[1] != "gzip" || encodings[2] != "" {
+		t.Errorf("unexpected request encodings %q", encodings)
+	}
+
+	if err := client.SetCompression(-1); err == nil {
+		t.Error("expected error for a negative threshold")
+	}
+}
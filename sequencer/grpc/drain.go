@@ -0,0 +1,39 @@
+package grpc
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/evstack/ev-node/core/execution"
+)
+
+// Ensure DrainExecutor implements the execution.Executor interface
+var _ execution.Executor = (*DrainExecutor)(nil)
+
+// DrainExecutor wraps an execution.Executor so the sequencer can stop taking new
+// transactions from the mempool while it shuts down. Blocks keep being executed, so
+// transactions already handed to the sequencer are still included.
+type DrainExecutor struct {
+	execution.Executor
+	draining atomic.Bool
+}
+
+// NewDrainExecutor wraps executor so GetTxs can be shut off with Drain.
+func NewDrainExecutor(executor execution.Executor) *DrainExecutor {
+	return &DrainExecutor{Executor: executor}
+}
+
+// Drain makes GetTxs return no transactions from now on.
+func (e *DrainExecutor) Drain() {
+	e.draining.Store(true)
+}
+
+// GetTxs fetches available transactions from the execution layer's mempool, or none
+// once Drain was called. Transactions left in the mempool are not lost; they are
+// picked up by the next sequencer to run.
+func (e *DrainExecutor) GetTxs(ctx context.Context) ([][]byte, error) {
+	if e.draining.Load() {
+		return nil, nil
+	}
+	return e.Executor.GetTxs(ctx)
+}
@@ -0,0 +1,28 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+)
+
+func TestDrainExecutor_GetTxs(t *testing.T) {
+	ctx := context.Background()
+	executor := NewDrainExecutor(&mockExecutor{})
+
+	txs, err := executor.GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txs) != 2 {
+		t.Errorf("expected 2 transactions, got %d", len(txs))
+	}
+
+	executor.Drain()
+	txs, err = executor.GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("expected no transactions while draining, got %d", len(txs))
+	}
+}
@@ -0,0 +1,38 @@
+package grpc
+
+import (
+	"net/http"
+
+	"connectrpc.com/connect"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	"github.com/evstack/ev-node/core/execution"
+	"github.com/evstack/ev-node/types/pb/evnode/v1/v1connect"
+
+	executorv1connect "github.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1/v1connect"
+)
+
+// NewExecutorServiceHandler creates an HTTP handler serving the ExecutorService
+// for the given executor.
+//
+// The handler speaks h2c (HTTP/2 Cleartext) so it can be used directly with
+// the h2c transport configured by NewClient.
+//
+// Parameters:
+// - executor: The underlying execution implementation to serve
+// - opts: Optional Connect handler options
+//
+// Returns:
+// - http.Handler: The HTTP handler for the ExecutorService
+func NewExecutorServiceHandler(executor execution.Executor, opts ...connect.HandlerOption) http.Handler {
+	server := NewServer(executor)
+
+	mux := http.NewServeMux()
+	path, handler := v1connect.NewExecutorServiceHandler(server, opts...)
+	mux.Handle(path, handler)
+	mux.Handle(executorv1connect.NewTxStreamServiceHandler(server, opts...))
+
+	// Use h2c to support HTTP/2 without TLS
+	return h2c.NewHandler(mux, &http2.Server{})
+}
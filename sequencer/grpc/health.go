@@ -0,0 +1,72 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"connectrpc.com/connect"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/types/known/emptypb"
+)
+
+// HealthCheckProcedure is the standard gRPC health check of grpc.health.v1.Health.
+const HealthCheckProcedure = "/grpc.health.v1.Health/Check"
+
+// healthServing is the SERVING status of a HealthCheckResponse
+const healthServing = 1
+
+// ErrNotServing is returned by Ping when the execution service reports it is not serving.
+var ErrNotServing = errors.New("execution service is not serving")
+
+// Ping checks that the execution service is serving with the gRPC health check of the
+// whole server. A server without the health service answers it as unimplemented, which
+// shows it serves all the same.
+//
+// Unlike the executor calls, Ping leaves the mempool alone and is not traced, retried
+// or recorded in metrics, so readiness probes do not skew them.
+func (c *Client) Ping(ctx context.Context) error {
+	// An empty request checks the whole server; the response is decoded by hand, so no
+	// health service types are needed
+	resp, err := c.health.CallUnary(ctx, connect.NewRequest(&emptypb.Empty{}))
+	if connect.CodeOf(err) == connect.CodeUnimplemented {
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("connect client: failed to check health: %w", err)
+	}
+
+	if status := servingStatus(resp.Msg.ProtoReflect().GetUnknown()); status != healthServing {
+		return fmt.Errorf("%w: health status %d", ErrNotServing, status)
+	}
+	return nil
+}
+
+// servingStatus returns the status of the HealthCheckResponse encoded in b, its field
+// 1. A response without it, or that is malformed, has the UNKNOWN status 0.
+func servingStatus(b []byte) uint64 {
+	var status uint64
+	for len(b) > 0 {
+		num, typ, n := protowire.ConsumeTag(b)
+		if n < 0 {
+			return 0
+		}
+		b = b[n:]
+
+		if num == 1 && typ == protowire.VarintType {
+			value, n := protowire.ConsumeVarint(b)
+			if n < 0 {
+				return 0
+			}
+			status, b = value, b[n:]
+			continue
+		}
+
+		n = protowire.ConsumeFieldValue(num, typ, b)
+		if n < 0 {
+			return 0
+		}
+		b = b[n:]
+	}
+	return status
+}
@@ -0,0 +1,46 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestClient_Ping(t *testing.T) {
+	ctx := context.Background()
+
+	// The executor handler has no health service, which still shows it serves
+	server := httptest.NewServer(h2c.NewHandler(NewExecutorServiceHandler(&mockExecutor{}), &http2.Server{}))
+	client := NewClient(server.URL)
+	if err := client.Ping(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info, _ := client.GetMempoolInfo(ctx); !info.UpdatedAt.IsZero() {
+		t.Error("expected a ping not to observe the mempool")
+	}
+	server.Close()
+
+	// h2c connections outlive the server, so a new client connects
+	if err := NewClient(server.URL).Ping(ctx); err == nil {
+		t.Error("expected error from a stopped server")
+	}
+
+	// A health service reports its status
+	for status, wantErr := range map[uint64]bool{1: false, 2: true} {
+		health := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Header().Set("Content-Type", "application/proto")
+			_, _ = w.Write(protowire.AppendVarint(protowire.AppendTag(nil, 1, protowire.VarintType), status))
+		}), &http2.Server{}))
+		err := NewClient(health.URL).Ping(ctx)
+		if (err != nil) != wantErr || wantErr != errors.Is(err, ErrNotServing) {
+			t.Errorf("status %d: expected not serving %v, got %v", status, wantErr, err)
+		}
+		health.Close()
+	}
+}
@@ -0,0 +1,93 @@
+package grpc
+
+import (
+	"crypto/sha256"
+	"sync"
+	"time"
+)
+
+// MempoolInfo describes the transactions waiting in the execution layer's mempool.
+type MempoolInfo struct {
+	// TxCount is the number of transactions waiting for a block
+	TxCount int
+	// Bytes is the total size of the waiting transactions
+	Bytes uint64
+	// OldestTxAge is how long the oldest waiting transaction has been seen for, 0 when
+	// the mempool is empty
+	OldestTxAge time.Duration
+	// UpdatedAt is when the mempool was last observed, zero before the first GetTxs
+	UpdatedAt time.Time
+}
+
+// mempoolTracker derives mempool information from the transactions returned by GetTxs.
+// The execution service does not report its mempool, but GetTxs returns the ready
+// transactions without removing them, so each response is a view of the mempool head.
+type mempoolTracker struct {
+	mu sync.Mutex
+	// pending maps the hash of each transaction of the last GetTxs response to when
+	// it was first returned
+	pending   map[[sha256.Size]byte]pendingTx
+	updatedAt time.Time
+}
+
+// pendingTx is a transaction returned by GetTxs and not yet executed
+type pendingTx struct {
+	firstSeen time.Time
+	size      int
+}
+
+// newMempoolTracker creates an empty mempool tracker
+func newMempoolTracker() *mempoolTracker {
+	return &mempoolTracker{pending: make(map[[sha256.Size]byte]pendingTx)}
+}
+
+// observe replaces the pending transactions with txs returned by GetTxs at now,
+// keeping the first time each was seen.
+func (m *mempoolTracker) observe(txs [][]byte, now time.Time) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	pending := make(map[[sha256.Size]byte]pendingTx, len(txs))
+	for _, tx := range txs {
+		hash := sha256.Sum256(tx)
+		seen, ok := m.pending[hash]
+		if !ok {
+			seen = pendingTx{firstSeen: now, size: len(tx)}
+		}
+		pending[hash] = seen
+	}
+	m.pending = pending
+	m.updatedAt = now
+}
+
+// executed removes txs included in a block at now from the pending transactions. When
+// observe is set, it is called with the wait of each transaction seen before.
+func (m *mempoolTracker) executed(txs [][]byte, now time.Time, observe func(hash [sha256.Size]byte, wait time.Duration)) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, tx := range txs {
+		hash := sha256.Sum256(tx)
+		if seen, ok := m.pending[hash]; ok {
+			if observe != nil {
+				observe(hash, now.Sub(seen.firstSeen))
+			}
+			delete(m.pending, hash)
+		}
+	}
+}
+
+// info returns the mempool information at now.
+func (m *mempoolTracker) info(now time.Time) MempoolInfo {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	info := MempoolInfo{TxCount: len(m.pending), UpdatedAt: m.updatedAt}
+	for _, tx := range m.pending {
+		info.Bytes += uint64(tx.size)
+		if age := now.Sub(tx.firstSeen); age > info.OldestTxAge {
+			info.OldestTxAge = age
+		}
+	}
+	return info
+}
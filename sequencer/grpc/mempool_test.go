@@ -0,0 +1,66 @@
+package grpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestClient_GetMempoolInfo(t *testing.T) {
+	ctx := context.Background()
+	mempool := [][]byte{[]byte("tx1"), []byte("tx22")}
+
+	mockExec := &mockExecutor{
+		getTxsFunc: func(ctx context.Context) ([][]byte, error) {
+			return mempool, nil
+		},
+	}
+
+	// Start test server
+	handler := NewExecutorServiceHandler(mockExec)
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	// Create client
+	client := NewClient(server.URL)
+
+	info, err := client.GetMempoolInfo(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.TxCount != 0 || !info.UpdatedAt.IsZero() {
+		t.Errorf("expected empty mempool before GetTxs, got %+v", info)
+	}
+
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+
+	// A transaction returned again keeps its first seen time
+	mempool = append(mempool, []byte("tx333"))
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	info, err = client.GetMempoolInfo(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.TxCount != 3 || info.Bytes != 12 || info.OldestTxAge < 10*time.Millisecond {
+		t.Errorf("expected 3 txs of 12 bytes seen for at least 10ms, got %+v", info)
+	}
+
+	// Executed transactions are no longer waiting
+	if _, _, err := client.ExecuteTxs(ctx, mempool[:2], 1, time.Now(), []byte("prev_state_root")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	info, err = client.GetMempoolInfo(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if info.TxCount != 1 || info.Bytes != 5 || info.OldestTxAge >= 10*time.Millisecond {
+		t.Errorf("expected only tx333 waiting, got %+v", info)
+	}
+}
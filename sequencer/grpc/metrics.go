@@ -0,0 +1,162 @@
+package grpc
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "executor"
+)
+
+// MetricsProvider returns executor Metrics.
+type MetricsProvider func(chainID string) (*Metrics, error)
+
+// DefaultMetricsProvider returns Metrics build using Prometheus client library
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+func DefaultMetricsProvider(enabled bool) MetricsProvider {
+	return func(chainID string) (*Metrics, error) {
+		if enabled {
+			return PrometheusMetrics("chain_id", chainID)
+		}
+		return NopMetrics()
+	}
+}
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Number of transactions waiting in the execution layer mempool
+	MempoolTxs metrics.Gauge
+	// Total size of the transactions waiting in the execution layer mempool
+	MempoolBytes metrics.Gauge
+	// Seconds the oldest waiting transaction has been seen for
+	MempoolOldestTxAge metrics.Gauge
+	// Number of GetTxs calls abandoned because they exceeded their time budget
+	GetTxsBudgetExceeded metrics.Counter
+	// Seconds sampled and slow transactions waited from first being returned by GetTxs to execution
+	TxWait metrics.Histogram
+	// Longest wait of the transactions of each block
+	BlockMaxTxWait metrics.Histogram
+	// Number of executed transactions that waited longer than the slow threshold
+	SlowTxs metrics.Counter
+	// Number of calls to the execution layer retried after a transient failure, by method
+	RPCRetries metrics.Counter
+	// Number of attempts of calls to the execution layer, by method and result code
+	RPCRequests metrics.Counter
+	// Seconds each attempt of a call to the execution layer took, by method
+	RPCDuration metrics.Histogram
+	// State of the execution layer circuit breaker: 0 closed, 1 open, 2 half-open
+	CircuitState metrics.Gauge
+	// Number of state changes of the execution layer circuit breaker, by new state
+	CircuitTransitions metrics.Counter
+	// Number of calls failed without reaching the execution layer while the circuit was open, by method
+	CircuitRejected metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(labelsAndValues ...string) (*Metrics, error) {
+	if len(labelsAndValues)%2 != 0 {
+		return nil, errors.New("uneven number of labels and values; labels and values should be provided in pairs")
+	}
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		MempoolTxs: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "mempool_txs",
+			Help:      "Number of transactions waiting in the execution layer mempool.",
+		}, labels).With(labelsAndValues...),
+		MempoolBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "mempool_bytes",
+			Help:      "Total size of the transactions waiting in the execution layer mempool.",
+		}, labels).With(labelsAndValues...),
+		MempoolOldestTxAge: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "mempool_oldest_tx_age_seconds",
+			Help:      "Seconds the oldest transaction waiting in the execution layer mempool has been seen for.",
+		}, labels).With(labelsAndValues...),
+		GetTxsBudgetExceeded: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "get_txs_budget_exceeded",
+			Help:      "Number of GetTxs calls abandoned because they exceeded their time budget.",
+		}, labels).With(labelsAndValues...),
+		TxWait: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "tx_wait_seconds",
+			Help:      "Seconds sampled and slow transactions waited from first being returned by GetTxs to execution.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 14),
+		}, labels).With(labelsAndValues...),
+		BlockMaxTxWait: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "block_max_tx_wait_seconds",
+			Help:      "Longest wait of the transactions of each block, from first being returned by GetTxs to execution.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.01, 2, 14),
+		}, labels).With(labelsAndValues...),
+		SlowTxs: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "slow_txs",
+			Help:      "Number of executed transactions that waited longer than the slow threshold.",
+		}, labels).With(labelsAndValues...),
+		RPCRetries: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "rpc_retries",
+			Help:      "Number of calls to the execution layer retried after a transient failure.",
+		}, append(labels, "method")).With(labelsAndValues...),
+		RPCRequests: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "rpc_requests",
+			Help:      "Number of attempts of calls to the execution layer, by result code.",
+		}, append(labels, "method", "code")).With(labelsAndValues...),
+		RPCDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "rpc_duration_seconds",
+			Help:      "Seconds each attempt of a call to the execution layer took.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0005, 2, 16),
+		}, append(labels, "method")).With(labelsAndValues...),
+		CircuitState: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "circuit_state",
+			Help:      "State of the execution layer circuit breaker: 0 closed, 1 open, 2 half-open.",
+		}, labels).With(labelsAndValues...),
+		CircuitTransitions: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "circuit_transitions",
+			Help:      "Number of state changes of the execution layer circuit breaker.",
+		}, append(labels, "state")).With(labelsAndValues...),
+		CircuitRejected: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "circuit_rejected",
+			Help:      "Number of calls failed without reaching the execution layer while the circuit was open.",
+		}, append(labels, "method")).With(labelsAndValues...),
+	}, nil
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() (*Metrics, error) {
+	return &Metrics{
+		MempoolTxs:           discard.NewGauge(),
+		MempoolBytes:         discard.NewGauge(),
+		MempoolOldestTxAge:   discard.NewGauge(),
+		GetTxsBudgetExceeded: discard.NewCounter(),
+		TxWait:               discard.NewHistogram(),
+		BlockMaxTxWait:       discard.NewHistogram(),
+		SlowTxs:              discard.NewCounter(),
+		RPCRetries:           discard.NewCounter(),
+		RPCRequests:          discard.NewCounter(),
+		RPCDuration:          discard.NewHistogram(),
+		CircuitState:         discard.NewGauge(),
+		CircuitTransitions:   discard.NewCounter(),
+		CircuitRejected:      discard.NewCounter(),
+	}, nil
+}
@@ -0,0 +1,52 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestClient_RPCMetrics(t *testing.T) {
+	ctx := context.Background()
+
+	handler := NewExecutorServiceHandler(&mockExecutor{
+		setFinalFunc: func(ctx context.Context, blockHeight uint64) error {
+			return errors.New("block is not executed")
+		},
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	metrics, _ := NopMetrics()
+	requests := &labelCounter{counts: map[string]float64{}}
+	duration := &countingHistogram{}
+	metrics.RPCRequests = requests
+	metrics.RPCDuration = duration
+
+	client := NewClient(server.URL)
+	client.SetMetrics(metrics)
+
+	// Every call is counted by method and result code, and timed
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := client.SetFinal(ctx, 1); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := requests.counts["method,GetTxs,code,ok"]; got != 2 {
+		t.Errorf("expected 2 successful GetTxs calls, got %v", got)
+	}
+	if got := requests.counts["method,SetFinal,code,internal"]; got != 1 {
+		t.Errorf("expected 1 failed SetFinal call, got %v in %v", got, requests.counts)
+	}
+	if duration.count != 3 {
+		t.Errorf("expected 3 timed calls, got %d", duration.count)
+	}
+}
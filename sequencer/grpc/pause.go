@@ -0,0 +1,114 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/evstack/ev-node/core/execution"
+)
+
+// Ensure PauseExecutor implements the execution.Executor interface
+var _ execution.Executor = (*PauseExecutor)(nil)
+
+// PauseExecutor wraps an execution.Executor so the execution layer can be taken away
+// from the sequencer for a while, e.g. to restart it on a new binary. While paused,
+// block production waits instead of failing against an unreachable execution layer.
+type PauseExecutor struct {
+	execution.Executor
+
+	mu sync.Mutex
+	// resumed is closed by Resume, nil while not paused
+	resumed chan struct{}
+	// calls counts the calls to the execution layer in flight
+	calls sync.WaitGroup
+}
+
+// NewPauseExecutor wraps executor so calls to it can be held with Pause.
+func NewPauseExecutor(executor execution.Executor) *PauseExecutor {
+	return &PauseExecutor{Executor: executor}
+}
+
+// Pause waits for the calls in flight to return and holds new ones until Resume is
+// called. It must not be called again before Resume.
+func (e *PauseExecutor) Pause() {
+	e.mu.Lock()
+	e.resumed = make(chan struct{})
+	e.mu.Unlock()
+
+	e.calls.Wait()
+}
+
+// Resume lets the calls held by Pause through.
+func (e *PauseExecutor) Resume() {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	close(e.resumed)
+	e.resumed = nil
+}
+
+// enter waits until the executor is not paused and counts a call in flight, which
+// must be ended with e.calls.Done. It returns the error of ctx if it is done first,
+// so a shutdown is not held up by a restart that does not complete.
+func (e *PauseExecutor) enter(ctx context.Context) error {
+	for {
+		e.mu.Lock()
+		resumed := e.resumed
+		if resumed == nil {
+			e.calls.Add(1)
+			e.mu.Unlock()
+			return nil
+		}
+		e.mu.Unlock()
+
+		select {
+		case <-resumed:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+// InitChain initializes the chain once the executor is not paused.
+func (e *PauseExecutor) InitChain(ctx context.Context, genesisTime time.Time, initialHeight uint64, chainID string) ([]byte, uint64, error) {
+	if err := e.enter(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer e.calls.Done()
+	return e.Executor.InitChain(ctx, genesisTime, initialHeight, chainID)
+}
+
+// GetTxs fetches available transactions from the execution layer's mempool, or none
+// while paused, so the sequencer keeps producing empty batches.
+func (e *PauseExecutor) GetTxs(ctx context.Context) ([][]byte, error) {
+	e.mu.Lock()
+	if e.resumed != nil {
+		e.mu.Unlock()
+		return nil, nil
+	}
+	e.calls.Add(1)
+	e.mu.Unlock()
+
+	defer e.calls.Done()
+	return e.Executor.GetTxs(ctx)
+}
+
+// ExecuteTxs executes txs once the executor is not paused, holding block production
+// meanwhile.
+func (e *PauseExecutor) ExecuteTxs(ctx context.Context, txs [][]byte, blockHeight uint64, timestamp time.Time, prevStateRoot []byte) ([]byte, uint64, error) {
+	if err := e.enter(ctx); err != nil {
+		return nil, 0, err
+	}
+	defer e.calls.Done()
+	return e.Executor.ExecuteTxs(ctx, txs, blockHeight, timestamp, prevStateRoot)
+}
+
+// SetFinal marks the block at blockHeight as final once the executor is not paused.
+func (e *PauseExecutor) SetFinal(ctx context.Context, blockHeight uint64) error {
+	if err := e.enter(ctx); err != nil {
+		return err
+	}
+	defer e.calls.Done()
+	return e.Executor.SetFinal(ctx, blockHeight)
+}
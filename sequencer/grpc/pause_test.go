@@ -0,0 +1,79 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestPauseExecutor(t *testing.T) {
+	ctx := context.Background()
+	executor := NewPauseExecutor(&mockExecutor{})
+
+	executor.Pause()
+	txs, err := executor.GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txs) != 0 {
+		t.Errorf("expected no transactions while paused, got %d", len(txs))
+	}
+
+	executed := make(chan struct{})
+	go func() {
+		defer close(executed)
+		if _, _, err := executor.ExecuteTxs(ctx, nil, 1, time.Now(), nil); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}()
+
+	select {
+	case <-executed:
+		t.Fatal("expected ExecuteTxs to wait while paused")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	// A held call returns once its context is done
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if err := executor.SetFinal(cancelled, 1); !errors.Is(err, context.Canceled) {
+		t.Errorf("expected a cancelled call to return while paused, got %v", err)
+	}
+
+	executor.Resume()
+	select {
+	case <-executed:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected ExecuteTxs to run after Resume")
+	}
+
+	// Pause waits for the calls in flight
+	release := make(chan struct{})
+	slow := NewPauseExecutor(&mockExecutor{
+		setFinalFunc: func(ctx context.Context, blockHeight uint64) error {
+			<-release
+			return nil
+		},
+	})
+	go func() { _ = slow.SetFinal(ctx, 1) }()
+	time.Sleep(10 * time.Millisecond)
+
+	paused := make(chan struct{})
+	go func() {
+		slow.Pause()
+		close(paused)
+	}()
+	select {
+	case <-paused:
+		t.Fatal("expected Pause to wait for the call in flight")
+	case <-time.After(50 * time.Millisecond):
+	}
+	close(release)
+	select {
+	case <-paused:
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Pause to return once the call returned")
+	}
+	slow.Resume()
+}
@@ -0,0 +1,120 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
+)
+
+// RetryPolicy configures how the client retries calls that fail with a transient error.
+type RetryPolicy struct {
+	// MaxAttempts is the number of attempts of a call, including the first; 0 and 1
+	// disable retries
+	MaxAttempts int
+	// MinBackoff is the wait before the first retry
+	MinBackoff time.Duration
+	// MaxBackoff caps the wait between attempts, which doubles after each retry
+	MaxBackoff time.Duration
+}
+
+// Validate checks the policy for bounds that cannot be retried with.
+func (p RetryPolicy) Validate() error {
+	if p.MaxAttempts < 0 {
+		return fmt.Errorf("max attempts must be >= 0, got %d", p.MaxAttempts)
+	}
+	if p.MinBackoff < 0 || p.MaxBackoff < 0 {
+		return errors.New("backoff must be >= 0")
+	}
+	if p.MaxBackoff < p.MinBackoff {
+		return fmt.Errorf("max backoff %s is below min backoff %s", p.MaxBackoff, p.MinBackoff)
+	}
+	return nil
+}
+
+// backoff returns the wait before retry number retry, counted from 1: a random
+// duration between half and all of the exponential backoff, so clients failing
+// together do not retry in lockstep.
+func (p RetryPolicy) backoff(retry int) time.Duration {
+	backoff := p.MinBackoff
+	for i := 1; i < retry && backoff < p.MaxBackoff; i++ {
+		backoff *= 2
+	}
+	backoff = min(backoff, p.MaxBackoff)
+	if backoff <= 1 {
+		return backoff
+	}
+	half := backoff / 2
+	return half + rand.N(backoff-half+1)
+}
+
+// finalError marks the failure of an attempt that must not be retried whatever its
+// code, e.g. because part of its result was already passed on.
+type finalError struct{ error }
+
+func (e finalError) Unwrap() error { return e.error }
+
+// retryable reports whether err is a transient failure of the execution layer that a
+// later attempt may not hit: it is unreachable, overloaded or aborted the call. Other
+// codes, such as Internal for an executor error or InvalidArgument, fail again.
+func retryable(err error) bool {
+	if errors.As(err, new(finalError)) {
+		return false
+	}
+	switch connect.CodeOf(err) {
+	case connect.CodeUnavailable, connect.CodeResourceExhausted, connect.CodeAborted, connect.CodeDeadlineExceeded:
+		return true
+	}
+	return false
+}
+
+// resultCode returns the metric label of the result of a call: "ok", or the Connect
+// code it failed with.
+func resultCode(err error) string {
+	if err == nil {
+		return "ok"
+	}
+	return connect.CodeOf(err).String()
+}
+
+// SetRetryPolicy retries calls failing with a transient error as configured by policy,
+// logging each retry. Retries are counted in the metrics set by SetMetrics. It must be
+// called before the client is used.
+//
+// A call whose response is lost, e.g. to a connection reset, may have been executed,
+// so the execution layer must accept an ExecuteTxs retried for the block it just
+// executed, or fail it with a non-retryable code.
+func (c *Client) SetRetryPolicy(policy RetryPolicy, logger zerolog.Logger) {
+	c.retry = policy
+	c.logger = logger.With().Str("component", "executor-client").Logger()
+}
+
+// call runs the call named method, each attempt within timeout, retrying it while it
+// fails with a transient error, attempts are left and ctx is not done.
+func (c *Client) call(ctx context.Context, method string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	for n := 1; ; n++ {
+		start := time.Now()
+		err := attempt(ctx, method, timeout, fn)
+		c.metrics.RPCDuration.With("method", method).Observe(time.Since(start).Seconds())
+		c.metrics.RPCRequests.With("method", method, "code", resultCode(err)).Add(1)
+		if err == nil || n >= c.retry.MaxAttempts || !retryable(err) || ctx.Err() != nil {
+			return err
+		}
+
+		backoff := c.retry.backoff(n)
+		c.metrics.RPCRetries.With("method", method).Add(1)
+		c.logger.Warn().Err(err).Str("method", method).Int("attempt", n).Dur("backoff", backoff).Msg("execution layer call failed, retrying")
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
@@ -0,0 +1,103 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestClient_SetRetryPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	var failures, calls atomic.Int32
+	handler := NewExecutorServiceHandler(&mockExecutor{
+		setFinalFunc: func(ctx context.Context, blockHeight uint64) error {
+			return errors.New("block is not executed")
+		},
+	})
+	// The first requests fail as from an overloaded proxy in front of the service
+	// h2c serves the whole connection, so the failures are injected behind it
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		if failures.Load() > 0 {
+			failures.Add(-1)
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}), &http2.Server{}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3, MinBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}, zerolog.Nop())
+
+	// Transient failures are retried
+	failures.Store(2)
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+
+	// Until the attempts run out
+	calls.Store(0)
+	failures.Store(3)
+	if _, _, err := client.ExecuteTxs(ctx, nil, 1, time.Now(), []byte("prev_state_root")); connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Fatalf("expected unavailable error, got %v", err)
+	}
+	if got := calls.Load(); got != 3 {
+		t.Errorf("expected 3 attempts, got %d", got)
+	}
+
+	// Executor errors are not retried
+	calls.Store(0)
+	if err := client.SetFinal(ctx, 1); connect.CodeOf(err) != connect.CodeInternal {
+		t.Fatalf("expected internal error, got %v", err)
+	}
+	if got := calls.Load(); got != 1 {
+		t.Errorf("expected 1 attempt, got %d", got)
+	}
+
+	// Nor are calls whose context is done
+	calls.Store(0)
+	failures.Store(3)
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := client.InitChain(cancelled, time.Now(), 1, "test-chain"); err == nil {
+		t.Fatal("expected error")
+	}
+	if got := calls.Load(); got > 1 {
+		t.Errorf("expected at most 1 attempt, got %d", got)
+	}
+}
+
+func TestRetryPolicy_Backoff(t *testing.T) {
+	policy := RetryPolicy{MaxAttempts: 10, MinBackoff: 100 * time.Millisecond, MaxBackoff: time.Second}
+	for retry, want := range map[int]time.Duration{
+		1: 100 * time.Millisecond,
+		2: 200 * time.Millisecond,
+		4: 800 * time.Millisecond,
+		5: time.Second,
+		9: time.Second,
+	} {
+		for range 20 {
+			if got := policy.backoff(retry); got < want/2 || got > want {
+				t.Errorf("retry %d: expected backoff between %s and %s, got %s", retry, want/2, want, got)
+			}
+		}
+	}
+
+	if err := (RetryPolicy{MinBackoff: time.Second, MaxBackoff: time.Millisecond}).Validate(); err == nil {
+		t.Error("expected error for max backoff below min backoff")
+	}
+}
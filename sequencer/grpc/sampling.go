@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"crypto/sha256"
+	"encoding/binary"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// TxSampling bounds the cost of per-transaction telemetry at high transaction rates.
+//
+// Every executed transaction has its wait, from the first GetTxs response returning it
+// to the block executing it, measured by the mempool tracker anyway. Recording each one
+// in a histogram and a log line is what costs, so only a sample is recorded. The
+// sample is taken by transaction hash, so other nodes sampling at the same rate
+// record the same transactions.
+type TxSampling struct {
+	// Rate records 1 in Rate transactions; 1 records all and 0 none
+	Rate uint64
+	// SlowThreshold records every transaction that waited longer, whether sampled or
+	// not, so the tail of the wait histogram is exact above it; 0 disables it
+	SlowThreshold time.Duration
+}
+
+// txTelemetry records the waits of executed transactions.
+type txTelemetry struct {
+	sampling TxSampling
+	logger   zerolog.Logger
+	metrics  *Metrics
+}
+
+// newTxTelemetry creates the telemetry of executed transactions
+func newTxTelemetry(sampling TxSampling, logger zerolog.Logger, metrics *Metrics) *txTelemetry {
+	return &txTelemetry{
+		sampling: sampling,
+		logger:   logger.With().Str("component", "tx-telemetry").Logger(),
+		metrics:  metrics,
+	}
+}
+
+// sampled reports whether the transaction with hash is in the sample.
+func (t *txTelemetry) sampled(hash [sha256.Size]byte) bool {
+	return t.sampling.Rate > 0 && binary.BigEndian.Uint64(hash[:8])%t.sampling.Rate == 0
+}
+
+// observe records the wait of a transaction executed at height if it is sampled or slow.
+// Sampled transactions are logged as spans at debug level.
+func (t *txTelemetry) observe(hash [sha256.Size]byte, wait time.Duration, height uint64) {
+	slow := t.sampling.SlowThreshold > 0 && wait > t.sampling.SlowThreshold
+	if slow {
+		t.metrics.SlowTxs.Add(1)
+	}
+
+	sampled := t.sampled(hash)
+	if !sampled && !slow {
+		return
+	}
+	t.metrics.TxWait.Observe(wait.Seconds())
+
+	if sampled {
+		t.logger.Debug().
+			Hex("tx", hash[:]).
+			Uint64("height", height).
+			Dur("wait", wait).
+			Bool("slow", slow).
+			Msg("tx span")
+	}
+}
+
+// block records the longest wait of the transactions of a block. It is recorded for
+// every block, so tail latencies show even when few transactions are sampled.
+func (t *txTelemetry) block(maxWait time.Duration) {
+	t.metrics.BlockMaxTxWait.Observe(maxWait.Seconds())
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/go-kit/kit/metrics/generic"
+	"github.com/rs/zerolog"
+)
+
+func TestClient_SetTxTelemetry(t *testing.T) {
+	ctx := context.Background()
+	mempool := [][]byte{[]byte("tx1"), []byte("tx2"), []byte("tx3")}
+
+	handler := NewExecutorServiceHandler(&mockExecutor{
+		getTxsFunc: func(ctx context.Context) ([][]byte, error) {
+			return mempool, nil
+		},
+	})
+	server := httptest.NewServer(handler)
+	defer server.Close()
+
+	newMetrics := func() *Metrics {
+		metrics, _ := NopMetrics()
+		metrics.TxWait = &countingHistogram{}
+		metrics.BlockMaxTxWait = &countingHistogram{}
+		metrics.SlowTxs = generic.NewCounter("slow_txs")
+		return metrics
+	}
+
+	// Every transaction is recorded at rate 1
+	client := NewClient(server.URL)
+	all := newMetrics()
+	client.SetTxTelemetry(TxSampling{Rate: 1}, zerolog.Nop(), all)
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := client.ExecuteTxs(ctx, mempool, 1, time.Now(), []byte("prev_state_root")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := all.TxWait.(*countingHistogram).count; got != 3 {
+		t.Errorf("expected 3 transaction waits, got %d", got)
+	}
+	if got := all.BlockMaxTxWait.(*countingHistogram).count; got != 1 {
+		t.Errorf("expected 1 block wait, got %d", got)
+	}
+
+	// Without sampling, only slow transactions and the block are recorded
+	client = NewClient(server.URL)
+	slow := newMetrics()
+	client.SetTxTelemetry(TxSampling{SlowThreshold: 5 * time.Millisecond}, zerolog.Nop(), slow)
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, _, err := client.ExecuteTxs(ctx, mempool[:1], 1, time.Now(), []byte("prev_state_root")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(10 * time.Millisecond)
+	// Unseen transactions have no wait
+	if _, _, err := client.ExecuteTxs(ctx, append(mempool[1:], []byte("tx4")), 2, time.Now(), []byte("prev_state_root")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := slow.SlowTxs.(*generic.Counter).Value(); got != 2 {
+		t.Errorf("expected 2 slow transactions, got %v", got)
+	}
+	if got := slow.TxWait.(*countingHistogram).count; got != 2 {
+		t.Errorf("expected only the 2 slow transaction waits, got %d", got)
+	}
+	if got := slow.BlockMaxTxWait.(*countingHistogram).count; got != 2 {
+		t.Errorf("expected 2 block waits, got %d", got)
+	}
+}
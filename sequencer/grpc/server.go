@@ -57,7 +57,7 @@ func (s *Server) InitChain(
 		req.Msg.ChainId,
 	)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to init chain: %w", err))
+		return nil, connect.NewError(executorCode(err), fmt.Errorf("failed to init chain: %w", err))
 	}
 
 	return connect.NewResponse(&pb.InitChainResponse{
@@ -75,7 +75,7 @@ func (s *Server) GetTxs(
 ) (*connect.Response[pb.GetTxsResponse], error) {
 	txs, err := s.executor.GetTxs(ctx)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to get txs: %w", err))
+		return nil, connect.NewError(executorCode(err), fmt.Errorf("failed to get txs: %w", err))
 	}
 
 	return connect.NewResponse(&pb.GetTxsResponse{
@@ -111,7 +111,7 @@ func (s *Server) ExecuteTxs(
 		req.Msg.PrevStateRoot,
 	)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to execute txs: %w", err))
+		return nil, connect.NewError(executorCode(err), fmt.Errorf("failed to execute txs: %w", err))
 	}
 
 	return connect.NewResponse(&pb.ExecuteTxsResponse{
@@ -133,8 +133,20 @@ func (s *Server) SetFinal(
 
 	err := s.executor.SetFinal(ctx, req.Msg.BlockHeight)
 	if err != nil {
-		return nil, connect.NewError(connect.CodeInternal, fmt.Errorf("failed to set final: %w", err))
+		return nil, connect.NewError(executorCode(err), fmt.Errorf("failed to set final: %w", err))
 	}
 
 	return connect.NewResponse(&pb.SetFinalResponse{}), nil
 }
+
+// executorCode returns the code of an executor error: the deadline or cancellation of
+// the call when that ended it, so the client can tell them apart, Internal otherwise.
+func executorCode(err error) connect.Code {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return connect.CodeDeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return connect.CodeCanceled
+	}
+	return connect.CodeInternal
+}
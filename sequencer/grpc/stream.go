@@ -0,0 +1,142 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync/atomic"
+	"time"
+
+	"connectrpc.com/connect"
+
+	executorv1 "github.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1"
+	"github.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1/v1connect"
+)
+
+// defaultChunkBytes is the byte budget of StreamTxs chunks when none is requested
+const defaultChunkBytes = 1 << 20
+
+// ErrTxStreamCut is returned by GetTxs and StreamTxs when the transaction stream ends
+// before the whole mempool was transferred. The transactions received until then are returned with it.
+var ErrTxStreamCut = errors.New("transaction stream ended early")
+
+// ErrTxStreamUnsupported is returned by StreamTxs when streaming is not set or the
+// execution layer does not serve the TxStreamService.
+var ErrTxStreamUnsupported = errors.New("transaction streaming not supported")
+
+// txStream is the streaming GetTxs state of a client
+type txStream struct {
+	client     v1connect.TxStreamServiceClient
+	chunkBytes uint32
+	// unsupported is set once the execution service answered that it has no StreamTxs
+	unsupported atomic.Bool
+}
+
+// SetTxStream fetches transactions with the StreamTxs RPC of the pranklin.executor.v1
+// TxStreamService in chunks of at most chunkBytes, so a large mempool is not held back
+// until a single huge GetTxs response is complete. Execution services without it are
+// called with GetTxs instead. The stream speaks the gRPC protocol, which execution
+// layers built on gRPC servers such as tonic serve as is. It must be called before the
+// client is used.
+func (c *Client) SetTxStream(chunkBytes int) error {
+	if chunkBytes <= 0 || chunkBytes > math.MaxUint32 {
+		return fmt.Errorf("chunk byte budget must be between 1 and %d", uint32(math.MaxUint32))
+	}
+
+	c.txStream = &txStream{
+		client:     v1connect.NewTxStreamServiceClient(c.httpClient, c.url, connect.WithGRPC()),
+		chunkBytes: uint32(chunkBytes),
+	}
+	return nil
+}
+
+// StreamTxs fetches the transactions of the execution layer's mempool with StreamTxs,
+// passing each chunk to fn as soon as it is received. It returns ErrTxStreamUnsupported
+// if streaming is not set, see SetTxStream, or the execution layer does not support it,
+// and ErrTxStreamCut if the stream ends after some chunks were passed on. A stream is
+// only retried while no chunk was passed on.
+func (c *Client) StreamTxs(ctx context.Context, fn func(txs [][]byte)) error {
+	if c.txStream == nil || c.txStream.unsupported.Load() {
+		return ErrTxStreamUnsupported
+	}
+
+	var txs [][]byte
+	err := c.call(ctx, "StreamTxs", c.timeouts.GetTxs, func(ctx context.Context) error {
+		req := connect.NewRequest(&executorv1.StreamTxsRequest{ChunkBytes: c.txStream.chunkBytes})
+		stream, err := c.txStream.client.StreamTxs(ctx, req)
+		if err != nil {
+			return err
+		}
+		defer stream.Close()
+
+		for stream.Receive() {
+			chunk := stream.Msg().Txs
+			txs = append(txs, chunk...)
+			fn(chunk)
+		}
+		if err := stream.Err(); err != nil && len(txs) > 0 {
+			return finalError{err}
+		}
+		return stream.Err()
+	})
+	if connect.CodeOf(err) == connect.CodeUnimplemented && len(txs) == 0 {
+		c.txStream.unsupported.Store(true)
+		c.logger.Warn().Msg("execution layer does not support StreamTxs, falling back to GetTxs")
+		return ErrTxStreamUnsupported
+	}
+	if err != nil {
+		if len(txs) > 0 {
+			return fmt.Errorf("connect client: %w after %d txs: %w", ErrTxStreamCut, len(txs), err)
+		}
+		return fmt.Errorf("connect client: failed to stream txs: %w", err)
+	}
+
+	c.mempool.observe(txs, time.Now())
+	return nil
+}
+
+// streamTxs fetches the transactions of the mempool with StreamTxs. ok is false if the
+// execution service does not support it.
+func (c *Client) streamTxs(ctx context.Context) (txs [][]byte, ok bool, err error) {
+	err = c.StreamTxs(ctx, func(chunk [][]byte) {
+		txs = append(txs, chunk...)
+	})
+	if errors.Is(err, ErrTxStreamUnsupported) {
+		return nil, false, nil
+	}
+	return txs, true, err
+}
+
+// StreamTxs handles the StreamTxs RPC request.
+//
+// It sends the transactions of the execution layer's mempool in chunks of at most the
+// requested byte budget. A chunk holds at least one transaction, however large.
+func (s *Server) StreamTxs(
+	ctx context.Context,
+	req *connect.Request[executorv1.StreamTxsRequest],
+	stream *connect.ServerStream[executorv1.StreamTxsResponse],
+) error {
+	chunkBytes := defaultChunkBytes
+	if req.Msg.ChunkBytes > 0 {
+		chunkBytes = int(req.Msg.ChunkBytes)
+	}
+
+	txs, err := s.executor.GetTxs(ctx)
+	if err != nil {
+		return connect.NewError(executorCode(err), fmt.Errorf("failed to get txs: %w", err))
+	}
+
+	for start := 0; start < len(txs); {
+		end, size := start+1, len(txs[start])
+		for end < len(txs) && size+len(txs[end]) <= chunkBytes {
+			size += len(txs[end])
+			end++
+		}
+		if err := stream.Send(&executorv1.StreamTxsResponse{Txs: txs[start:end]}); err != nil {
+			return err
+		}
+		start = end
+	}
+	return nil
+}
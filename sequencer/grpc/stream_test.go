@@ -0,0 +1,167 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	"github.com/rs/zerolog"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+
+	executorv1 "github.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1"
+	"github.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1/v1connect"
+)
+
+func TestClient_SetTxStream(t *testing.T) {
+	ctx := context.Background()
+
+	var expectedTxs [][]byte
+	for i := range 10 {
+		expectedTxs = append(expectedTxs, bytes.Repeat([]byte{byte('a' + i)}, 100))
+	}
+	server := httptest.NewServer(NewExecutorServiceHandler(&mockExecutor{
+		getTxsFunc: func(ctx context.Context) ([][]byte, error) {
+			return expectedTxs, nil
+		},
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.SetTxStream(250); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The mempool is paged in chunks within the byte budget
+	chunks := 0
+	err := client.StreamTxs(ctx, func(txs [][]byte) {
+		if len(txs) != 2 {
+			t.Errorf("expected chunks of 2 txs, got %d", len(txs))
+		}
+		chunks++
+	})
+	if err != nil || chunks != 5 {
+		t.Errorf("expected 5 chunks, got %d and %v", chunks, err)
+	}
+
+	txs, err := client.GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txs) != len(expectedTxs) || !bytes.Equal(txs[9], expectedTxs[9]) {
+		t.Errorf("expected the streamed mempool, got %d txs", len(txs))
+	}
+
+	if err := client.SetTxStream(0); err == nil {
+		t.Error("expected error for an empty chunk byte budget")
+	}
+}
+
+func TestClient_SetTxStream_Fallback(t *testing.T) {
+	ctx := context.Background()
+
+	// An execution service without StreamTxs
+	var streamed atomic.Int32
+	handler := NewExecutorServiceHandler(&mockExecutor{})
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == v1connect.TxStreamServiceStreamTxsProcedure {
+			streamed.Add(1)
+			http.NotFound(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r)
+	}), &http2.Server{}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.SetTxStream(1024); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for range 2 {
+		txs, err := client.GetTxs(ctx)
+		if err != nil || len(txs) != 2 {
+			t.Fatalf("expected GetTxs fallback, got %d txs and %v", len(txs), err)
+		}
+	}
+	if streamed.Load() != 1 {
+		t.Errorf("expected StreamTxs tried once, got %d", streamed.Load())
+	}
+}
+
+func TestBudgetExecutor_StreamedTxs(t *testing.T) {
+	ctx := context.Background()
+
+	// The first chunk arrives in time, the rest of the mempool does not
+	mux := http.NewServeMux()
+	mux.Handle(v1connect.TxStreamServiceStreamTxsProcedure, connect.NewServerStreamHandler(v1connect.TxStreamServiceStreamTxsProcedure,
+		func(ctx context.Context, req *connect.Request[executorv1.StreamTxsRequest], stream *connect.ServerStream[executorv1.StreamTxsResponse]) error {
+			if err := stream.Send(&executorv1.StreamTxsResponse{Txs: [][]byte{[]byte("tx1")}}); err != nil {
+				return err
+			}
+			<-ctx.Done()
+			return ctx.Err()
+		},
+	))
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	if err := client.SetTxStream(1024); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	metrics, _ := NopMetrics()
+	executor := NewBudgetExecutor(client, 100*time.Millisecond, nil, zerolog.Nop(), metrics)
+	txs, err := executor.GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(txs) != 1 || string(txs[0]) != "tx1" {
+		t.Errorf("expected the chunk streamed in time, got %q", txs)
+	}
+}
+
+func TestClient_StreamTxs_Cut(t *testing.T) {
+	ctx := context.Background()
+
+	// The stream fails transiently after its first chunk
+	var streams atomic.Int32
+	mux := http.NewServeMux()
+	mux.Handle(v1connect.TxStreamServiceStreamTxsProcedure, connect.NewServerStreamHandler(v1connect.TxStreamServiceStreamTxsProcedure,
+		func(ctx context.Context, req *connect.Request[executorv1.StreamTxsRequest], stream *connect.ServerStream[executorv1.StreamTxsResponse]) error {
+			streams.Add(1)
+			if req.Msg.ChunkBytes != 1024 {
+				return connect.NewError(connect.CodeInvalidArgument, errors.New("unexpected chunk byte budget"))
+			}
+			if err := stream.Send(&executorv1.StreamTxsResponse{Txs: [][]byte{[]byte("tx1")}}); err != nil {
+				return err
+			}
+			return connect.NewError(connect.CodeUnavailable, errors.New("mempool scan aborted"))
+		},
+	))
+	server := httptest.NewServer(h2c.NewHandler(mux, &http2.Server{}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetRetryPolicy(RetryPolicy{MaxAttempts: 3}, zerolog.Nop())
+	if err := client.SetTxStream(1024); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Chunks passed on are not streamed again by a retry
+	var txs [][]byte
+	err := client.StreamTxs(ctx, func(chunk [][]byte) {
+		txs = append(txs, chunk...)
+	})
+	if !errors.Is(err, ErrTxStreamCut) || connect.CodeOf(err) != connect.CodeUnavailable {
+		t.Errorf("expected a cut stream, got %v", err)
+	}
+	if len(txs) != 1 || streams.Load() != 1 {
+		t.Errorf("expected one stream of 1 tx, got %d streams of %d txs", streams.Load(), len(txs))
+	}
+}
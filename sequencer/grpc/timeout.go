@@ -0,0 +1,47 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"connectrpc.com/connect"
+)
+
+// ErrTimeout is returned when a call to the execution layer exceeds its deadline.
+var ErrTimeout = errors.New("execution layer call timed out")
+
+// Timeouts are the deadlines of each attempt of the calls to the execution layer. A
+// zero timeout leaves the call bounded by its context only.
+type Timeouts struct {
+	InitChain  time.Duration
+	GetTxs     time.Duration
+	ExecuteTxs time.Duration
+	SetFinal   time.Duration
+}
+
+// SetTimeouts bounds each attempt of a call by the timeout of its method, so a hung
+// execution layer fails the call with ErrTimeout instead of blocking the caller. It
+// must be called before the client is used.
+func (c *Client) SetTimeouts(timeouts Timeouts) {
+	c.timeouts = timeouts
+}
+
+// attempt runs fn once within timeout, returning ErrTimeout if the deadline is what
+// ended it. The deadline is propagated to the execution layer, whose answer that it
+// passed may arrive just before it passes here.
+func attempt(ctx context.Context, method string, timeout time.Duration, fn func(ctx context.Context) error) error {
+	if timeout <= 0 {
+		return fn(ctx)
+	}
+
+	attemptCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	err := fn(attemptCtx)
+	if err != nil && ctx.Err() == nil && (attemptCtx.Err() != nil || connect.CodeOf(err) == connect.CodeDeadlineExceeded) {
+		return fmt.Errorf("%w: %s after %s: %w", ErrTimeout, method, timeout, err)
+	}
+	return err
+}
@@ -0,0 +1,51 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+func TestClient_SetTimeouts(t *testing.T) {
+	ctx := context.Background()
+
+	// The executor hangs until the call is given up
+	handler := NewExecutorServiceHandler(&mockExecutor{
+		executeTxsFunc: func(ctx context.Context, txs [][]byte, blockHeight uint64, timestamp time.Time, prevStateRoot []byte) ([]byte, uint64, error) {
+			<-ctx.Done()
+			return nil, 0, ctx.Err()
+		},
+	})
+	server := httptest.NewServer(h2c.NewHandler(handler, &http2.Server{}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	client.SetTimeouts(Timeouts{ExecuteTxs: 50 * time.Millisecond})
+
+	// Calls exceeding their timeout fail with ErrTimeout
+	start := time.Now()
+	_, _, err := client.ExecuteTxs(ctx, nil, 1, time.Now(), []byte("prev_state_root"))
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected timeout error, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected the call to end at its timeout, took %s", elapsed)
+	}
+
+	// Methods without a timeout are bounded by their context only
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A cancelled context is not reported as a timeout
+	cancelled, cancel := context.WithCancel(ctx)
+	cancel()
+	if _, _, err := client.ExecuteTxs(cancelled, nil, 1, time.Now(), []byte("prev_state_root")); err == nil || errors.Is(err, ErrTimeout) {
+		t.Fatalf("expected cancellation error, got %v", err)
+	}
+}
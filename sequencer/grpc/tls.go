@@ -0,0 +1,56 @@
+package grpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+)
+
+// TLSConfig configures TLS on the connection to the execution service.
+type TLSConfig struct {
+	// CAFile is a PEM file of the CAs the server certificate must chain to; empty
+	// trusts the system roots
+	CAFile string
+	// CertFile is a PEM client certificate presented for mutual TLS; empty presents none
+	CertFile string
+	// KeyFile is the PEM private key of CertFile
+	KeyFile string
+	// ServerName is the name the server certificate is verified against; empty uses
+	// the host of the URL
+	ServerName string
+}
+
+// Load reads the certificates of the configuration.
+func (c TLSConfig) Load() (*tls.Config, error) {
+	if (c.CertFile == "") != (c.KeyFile == "") {
+		return nil, errors.New("client certificate and key must be set together")
+	}
+
+	cfg := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		ServerName: c.ServerName,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA file: %w", err)
+		}
+		cfg.RootCAs = x509.NewCertPool()
+		if !cfg.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificates found in CA file %s", c.CAFile)
+		}
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+
+	return cfg, nil
+}
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	"connectrpc.com/connect"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.37.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the instrumentation scope of the spans of the client
+const tracerName = "github.com/pranklin/pranklin-sequencer/grpc"
+
+// tracing creates a span for each attempt of a call to the execution layer.
+type tracing struct {
+	tracer     trace.Tracer
+	propagator propagation.TraceContext
+}
+
+// SetTracing creates a client span for each attempt of a call to the execution layer
+// with the tracers of provider, and sends it in W3C Trace Context headers so the
+// execution layer can continue the trace. Calls whose context carries a span are
+// traced as its children, sampled as decided for the parent; others start a trace if
+// the sampler of provider keeps it. It must be called before the client is used.
+func (c *Client) SetTracing(provider trace.TracerProvider) {
+	c.tracing = &tracing{tracer: provider.Tracer(tracerName)}
+}
+
+// traceInterceptor traces the calls of the client while tracing is set.
+func (c *Client) traceInterceptor() connect.UnaryInterceptorFunc {
+	return func(next connect.UnaryFunc) connect.UnaryFunc {
+		return func(ctx context.Context, req connect.AnyRequest) (connect.AnyResponse, error) {
+			if c.tracing == nil {
+				return next(ctx, req)
+			}
+
+			ctx, span := c.tracing.start(ctx, req.Spec().Procedure)
+			defer span.End()
+			c.tracing.propagator.Inject(ctx, propagation.HeaderCarrier(req.Header()))
+
+			resp, err := next(ctx, req)
+			if err != nil {
+				span.SetAttributes(semconv.RPCConnectRPCErrorCodeKey.String(connect.CodeOf(err).String()))
+				span.SetStatus(codes.Error, err.Error())
+			}
+			return resp, err
+		}
+	}
+}
+
+// start starts the client span of a call to procedure, e.g.
+// "/evnode.v1.ExecutorService/GetTxs".
+func (t *tracing) start(ctx context.Context, procedure string) (context.Context, trace.Span) {
+	name := strings.TrimPrefix(procedure, "/")
+	service, method, _ := strings.Cut(name, "/")
+	return t.tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			semconv.RPCSystemConnectRPC,
+			semconv.RPCService(service),
+			semconv.RPCMethod(method),
+		),
+	)
+}
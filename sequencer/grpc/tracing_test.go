@@ -0,0 +1,156 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/embedded"
+	"go.opentelemetry.io/otel/trace/noop"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
+)
+
+// recordingProvider records the spans of its tracers, sampling the traces they start
+type recordingProvider struct {
+	embedded.TracerProvider
+	spans []*recordedSpan
+}
+
+type recordingTracer struct {
+	embedded.Tracer
+	provider *recordingProvider
+}
+
+type recordedSpan struct {
+	noop.Span
+	name   string
+	sc     trace.SpanContext
+	parent trace.SpanContext
+	status codes.Code
+	ended  bool
+}
+
+func (p *recordingProvider) Tracer(string, ...trace.TracerOption) trace.Tracer {
+	return recordingTracer{provider: p}
+}
+
+func (t recordingTracer) Start(ctx context.Context, name string, _ ...trace.SpanStartOption) (context.Context, trace.Span) {
+	p := t.provider
+	parent := trace.SpanContextFromContext(ctx)
+	config := trace.SpanContextConfig{
+		TraceID:    trace.TraceID{9},
+		SpanID:     trace.SpanID{byte(len(p.spans) + 1)},
+		TraceFlags: trace.FlagsSampled,
+	}
+	if parent.IsValid() {
+		config.TraceID = parent.TraceID()
+		config.TraceFlags = parent.TraceFlags()
+	}
+	span := &recordedSpan{name: name, sc: trace.NewSpanContext(config), parent: parent}
+	p.spans = append(p.spans, span)
+	return trace.ContextWithSpan(ctx, span), span
+}
+
+func (s *recordedSpan) SpanContext() trace.SpanContext      { return s.sc }
+func (s *recordedSpan) IsRecording() bool                   { return !s.ended }
+func (s *recordedSpan) SetStatus(code codes.Code, _ string) { s.status = code }
+func (s *recordedSpan) End(...trace.SpanEndOption)          { s.ended = true }
+
+// traceparent formats sc as a W3C traceparent header
+func traceparent(sc trace.SpanContext) string {
+	header := http.Header{}
+	propagation.TraceContext{}.Inject(trace.ContextWithSpanContext(context.Background(), sc), propagation.HeaderCarrier(header))
+	return header.Get("traceparent")
+}
+
+func TestClient_SetTracing(t *testing.T) {
+	ctx := context.Background()
+
+	var traceparents []string
+	handler := NewExecutorServiceHandler(&mockExecutor{
+		setFinalFunc: func(ctx context.Context, blockHeight uint64) error {
+			if blockHeight == 0 {
+				return errors.New("no block")
+			}
+			return nil
+		},
+	})
+	server := httptest.NewServer(h2c.NewHandler(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		handler.ServeHTTP(w, r)
+	}), &http2.Server{}))
+	defer server.Close()
+
+	unsampled := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID: trace.TraceID{1, 2, 3},
+		SpanID:  trace.SpanID{4, 5, 6},
+	})
+	unsampledCtx := trace.ContextWithSpanContext(ctx, unsampled)
+
+	// Without tracing no trace context is sent
+	client := NewClient(server.URL)
+	if _, err := client.GetTxs(unsampledCtx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if traceparents[0] != "" {
+		t.Errorf("expected no trace context, got %q", traceparents[0])
+	}
+
+	// Without a tracer provider the trace context of the caller is passed on as is
+	client = NewClient(server.URL)
+	client.SetTracing(noop.NewTracerProvider())
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if traceparents[1] != "" {
+		t.Errorf("expected no trace context without a span, got %q", traceparents[1])
+	}
+	if err := client.SetFinal(unsampledCtx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if traceparents[2] != traceparent(unsampled) {
+		t.Errorf("expected trace context %q, got %q", traceparent(unsampled), traceparents[2])
+	}
+
+	provider := &recordingProvider{}
+	client = NewClient(server.URL)
+	client.SetTracing(provider)
+
+	// Calls without a span start a trace
+	if _, err := client.GetTxs(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	root := provider.spans[0]
+	if root.name != "evnode.v1.ExecutorService/GetTxs" || root.parent.IsValid() || !root.ended {
+		t.Errorf("unexpected root span %+v", root)
+	}
+	if traceparents[3] != traceparent(root.sc) {
+		t.Errorf("expected trace context %q, got %q", traceparent(root.sc), traceparents[3])
+	}
+
+	// Calls with a span continue its trace without sampling it
+	if err := client.SetFinal(unsampledCtx, 1); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	child := provider.spans[1]
+	if !child.parent.Equal(unsampled) || child.sc.TraceID() != unsampled.TraceID() || child.sc.IsSampled() {
+		t.Errorf("expected an unsampled child span of the trace, got %+v", child)
+	}
+	if traceparents[4] != traceparent(child.sc) {
+		t.Errorf("expected trace context %q, got %q", traceparent(child.sc), traceparents[4])
+	}
+
+	// Failed calls are marked on their span
+	if err := client.SetFinal(ctx, 0); err == nil {
+		t.Fatal("expected an error")
+	}
+	if failed := provider.spans[2]; failed.status != codes.Error || !failed.ended {
+		t.Errorf("expected a failed span, got %+v", failed)
+	}
+}
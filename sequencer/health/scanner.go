@@ -0,0 +1,194 @@
+// Package health turns known log patterns of managed subprocesses into structured
+// health events until they expose health over RPC.
+package health
+
+import (
+	"bytes"
+	"regexp"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Kind is the kind of problem a health event reports.
+type Kind string
+
+// Health event kinds
+const (
+	KindPanic        Kind = "panic"
+	KindDBCorruption Kind = "db_corruption"
+	KindSlowMatching Kind = "slow_matching"
+)
+
+// Severity is how urgently a health event needs attention.
+type Severity string
+
+// Health event severities
+const (
+	SeverityWarning  Severity = "warning"
+	SeverityCritical Severity = "critical"
+)
+
+// maxLineSize bounds the length of a buffered output line; longer lines are split
+const maxLineSize = 64 << 10
+
+// ansiEscape matches the color codes of terminal formatted logs
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// Pattern is a log pattern reported as a health event.
+type Pattern struct {
+	Kind     Kind
+	Severity Severity
+	Regexp   *regexp.Regexp
+}
+
+// ExecutionPatterns are the known problem patterns of the execution layer output.
+var ExecutionPatterns = []Pattern{
+	{
+		Kind:     KindPanic,
+		Severity: SeverityCritical,
+		// Rust panic messages, e.g. "thread 'main' panicked at src/main.rs:10:5"
+		Regexp: regexp.MustCompile(`panicked at|^panic: `),
+	},
+	{
+		Kind:     KindDBCorruption,
+		Severity: SeverityCritical,
+		// RocksDB status messages, e.g. "Corruption: block checksum mismatch"
+		Regexp: regexp.MustCompile(`(?i)corruption:|checksum mismatch|database (is )?corrupt`),
+	},
+	{
+		Kind:     KindSlowMatching,
+		Severity: SeverityWarning,
+		Regexp:   regexp.MustCompile(`(?i)slow (order )?match`),
+	},
+}
+
+// Event is a health problem scraped from subprocess output.
+type Event struct {
+	Time      time.Time
+	Component string
+	Kind      Kind
+	Severity  Severity
+	// Line is the output line the event was scraped from
+	Line string
+}
+
+// Scanner is an io.Writer matching the output of a subprocess line by line against
+// known patterns. Matching lines are logged, counted and kept as recent events.
+type Scanner struct {
+	component string
+	patterns  []Pattern
+	logger    zerolog.Logger
+	metrics   *Metrics
+
+	mu      sync.Mutex
+	pending []byte
+	events  []Event
+	next    int
+	full    bool
+}
+
+// NewScanner creates a new output scanner.
+//
+// Parameters:
+// - component: Name of the subprocess whose output is scanned
+// - patterns: Patterns reported as health events
+// - size: Number of recent events kept
+// - logger: Logger used to report events
+// - metrics: Health metrics
+//
+// Returns:
+// - *Scanner: The initialized scanner; add it to the subprocess outputs
+func NewScanner(component string, patterns []Pattern, size int, logger zerolog.Logger, metrics *Metrics) *Scanner {
+	return &Scanner{
+		component: component,
+		patterns:  patterns,
+		logger:    logger.With().Str("component", "health").Str("source", component).Logger(),
+		metrics:   metrics,
+		events:    make([]Event, size),
+	}
+}
+
+// Write scans the complete lines of p, buffering a trailing partial line until the
+// next write. It never fails so it does not interrupt the subprocess output.
+func (s *Scanner) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.pending = append(s.pending, p...)
+	for {
+		i := bytes.IndexByte(s.pending, '\n')
+		if i < 0 {
+			break
+		}
+		s.scan(s.pending[:i])
+		s.pending = s.pending[i+1:]
+	}
+
+	if len(s.pending) > maxLineSize {
+		s.scan(s.pending)
+		s.pending = nil
+	}
+	// Release the consumed prefix once the buffer is drained
+	if len(s.pending) == 0 {
+		s.pending = nil
+	}
+
+	return len(p), nil
+}
+
+// scan reports line if it matches a pattern. The first matching pattern wins.
+func (s *Scanner) scan(line []byte) {
+	line = bytes.TrimSpace(ansiEscape.ReplaceAll(line, nil))
+	if len(line) == 0 {
+		return
+	}
+
+	for _, pattern := range s.patterns {
+		if !pattern.Regexp.Match(line) {
+			continue
+		}
+
+		event := Event{
+			Time:      time.Now().UTC(),
+			Component: s.component,
+			Kind:      pattern.Kind,
+			Severity:  pattern.Severity,
+			Line:      string(line),
+		}
+		s.record(event)
+		return
+	}
+}
+
+// record logs, counts and keeps event, evicting the oldest event when full.
+func (s *Scanner) record(event Event) {
+	s.metrics.Events.With("component", event.Component, "kind", string(event.Kind)).Add(1)
+
+	log := s.logger.Warn()
+	if event.Severity == SeverityCritical {
+		log = s.logger.Error()
+	}
+	log.Str("kind", string(event.Kind)).Str("line", event.Line).Msg("🩺 Health problem in subprocess output")
+
+	if len(s.events) == 0 {
+		return
+	}
+	s.events[s.next] = event
+	s.next = (s.next + 1) % len(s.events)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// Events returns the recent health events, oldest first.
+func (s *Scanner) Events() []Event {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.full {
+		return append([]Event{}, s.events[:s.next]...)
+	}
+	return append(append([]Event{}, s.events[s.next:]...), s.events[:s.next]...)
+}
@@ -0,0 +1,83 @@
+package health
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestScanner(t *testing.T, size int) *Scanner {
+	t.Helper()
+	metrics, err := NopMetrics()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return NewScanner("execution", ExecutionPatterns, size, zerolog.Nop(), metrics)
+}
+
+func TestScannerEvents(t *testing.T) {
+	scanner := newTestScanner(t, 10)
+
+	output := strings.Join([]string{
+		"2025-01-01T00:00:00Z INFO block executed height=1",
+		"thread 'main' panicked at src/state.rs:42:9:",
+		"\x1b[33mWARN\x1b[0m slow match for order 7 took 120ms",
+		"Error: IO error: Corruption: block checksum mismatch in 000012.sst",
+		"",
+	}, "\n")
+
+	// Split the output across writes in the middle of a line
+	if _, err := scanner.Write([]byte(output[:60])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := scanner.Write([]byte(output[60:])); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	events := scanner.Events()
+	expected := []Kind{KindPanic, KindSlowMatching, KindDBCorruption}
+	if len(events) != len(expected) {
+		t.Fatalf("expected %d events, got %+v", len(expected), events)
+	}
+	for i, kind := range expected {
+		if events[i].Kind != kind || events[i].Component != "execution" {
+			t.Errorf("event %d: expected %s from execution, got %+v", i, kind, events[i])
+		}
+	}
+	if events[0].Severity != SeverityCritical || events[1].Severity != SeverityWarning {
+		t.Errorf("unexpected severities: %+v", events)
+	}
+	if events[1].Line != "WARN slow match for order 7 took 120ms" {
+		t.Errorf("expected color codes to be stripped, got %q", events[1].Line)
+	}
+
+	// A partial line is only scanned once complete
+	if _, err := scanner.Write([]byte("thread 'tokio' panicked")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(scanner.Events()); got != 3 {
+		t.Errorf("expected partial line to be buffered, got %d events", got)
+	}
+	if _, err := scanner.Write([]byte(" at src/lib.rs:1:1\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := len(scanner.Events()); got != 4 {
+		t.Errorf("expected 4 events, got %d", got)
+	}
+}
+
+func TestScannerEvictsOldest(t *testing.T) {
+	scanner := newTestScanner(t, 2)
+
+	for _, line := range []string{"slow match 1", "slow match 2", "slow match 3"} {
+		if _, err := scanner.Write([]byte(line + "\n")); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	events := scanner.Events()
+	if len(events) != 2 || events[0].Line != "slow match 2" || events[1].Line != "slow match 3" {
+		t.Errorf("expected the 2 most recent events, got %+v", events)
+	}
+}
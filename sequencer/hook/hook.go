@@ -0,0 +1,186 @@
+// Package hook runs operator hooks, scripts and webhooks, at points of the node
+// lifecycle so runbook steps such as announcements or traffic shifting are automated.
+package hook
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/pranklin/pranklin-sequencer/supervisor"
+)
+
+// Event is a point of the node lifecycle hooks run at.
+type Event string
+
+const (
+	// PreStart runs before the node starts its components; a failing hook aborts the start
+	PreStart Event = "pre-start"
+	// PostReady runs once every component is started and the sequencer is running
+	PostReady Event = "post-ready"
+	// PreShutdown runs before the node drains and stops its components
+	PreShutdown Event = "pre-shutdown"
+	// ComponentCrash runs when a component exits unexpectedly, before it is restarted
+	ComponentCrash Event = "component-crash"
+)
+
+// Context describes the event a hook runs for. Scripts receive it as JSON on stdin and
+// as PRANKLIN_HOOK_* environment variables; webhooks as the JSON body of a POST.
+type Context struct {
+	Event   Event     `json:"event"`
+	Time    time.Time `json:"time"`
+	ChainID string    `json:"chain_id"`
+	// Component is the crashed component, for ComponentCrash
+	Component string `json:"component,omitempty"`
+	// Reason is why the node shuts down, for PreShutdown
+	Reason string `json:"reason,omitempty"`
+	// Error is the exit error of the crashed component, for ComponentCrash
+	Error string `json:"error,omitempty"`
+}
+
+// env returns the context as environment variables.
+func (c Context) env() []string {
+	return []string{
+		"PRANKLIN_HOOK_EVENT=" + string(c.Event),
+		"PRANKLIN_HOOK_TIME=" + c.Time.Format(time.RFC3339Nano),
+		"PRANKLIN_HOOK_CHAIN_ID=" + c.ChainID,
+		"PRANKLIN_HOOK_COMPONENT=" + c.Component,
+		"PRANKLIN_HOOK_REASON=" + c.Reason,
+		"PRANKLIN_HOOK_ERROR=" + c.Error,
+	}
+}
+
+// Runner runs the hooks configured for each event.
+type Runner struct {
+	hooks   map[Event][]string
+	chainID string
+	timeout time.Duration
+	client  *http.Client
+	logger  zerolog.Logger
+}
+
+// NewRunner creates a hook runner.
+//
+// Parameters:
+// - hooks: The hooks of each event, run in order: an http:// or https:// URL is a
+// webhook, anything else the path of an executable
+// - chainID: Chain ID passed to the hooks
+// - timeout: How long each hook may take
+// - logger: Logger for hook runs and failures
+//
+// Returns:
+// - *Runner: The initialized runner
+func NewRunner(hooks map[Event][]string, chainID string, timeout time.Duration, logger zerolog.Logger) *Runner {
+	return &Runner{
+		hooks:   hooks,
+		chainID: chainID,
+		timeout: timeout,
+		client:  &http.Client{},
+		logger:  logger.With().Str("component", "hooks").Logger(),
+	}
+}
+
+// Run runs the hooks of hctx.Event in order and returns their joined errors. Every
+// hook runs even if an earlier one fails. Hooks are not cut short when ctx is
+// cancelled, e.g. by a shutdown, only by their timeout.
+func (r *Runner) Run(ctx context.Context, hctx Context) error {
+	hooks := r.hooks[hctx.Event]
+	if len(hooks) == 0 {
+		return nil
+	}
+
+	hctx.Time = time.Now().UTC()
+	hctx.ChainID = r.chainID
+	body, err := json.Marshal(hctx)
+	if err != nil {
+		return fmt.Errorf("failed to encode hook context: %w", err)
+	}
+
+	var errs []error
+	for _, hook := range hooks {
+		start := time.Now()
+		hookCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), r.timeout)
+		if isWebhook(hook) {
+			err = r.post(hookCtx, hook, body)
+		} else {
+			err = r.exec(hookCtx, hook, hctx, body)
+		}
+		cancel()
+
+		if err != nil {
+			r.logger.Error().Err(err).Str("event", string(hctx.Event)).Str("hook", hook).Msg("Hook failed")
+			errs = append(errs, fmt.Errorf("%s hook %s: %w", hctx.Event, hook, err))
+			continue
+		}
+		r.logger.Info().Str("event", string(hctx.Event)).Str("hook", hook).Dur("duration", time.Since(start)).Msg("Hook ran")
+	}
+	return errors.Join(errs...)
+}
+
+// Go runs the hooks of hctx.Event in the background, for events that must not delay
+// the node; failures are only logged.
+func (r *Runner) Go(ctx context.Context, hctx Context) {
+	if len(r.hooks[hctx.Event]) == 0 {
+		return
+	}
+	go func() { _ = r.Run(ctx, hctx) }()
+}
+
+// exec runs the executable at path with the context on stdin and in its environment.
+func (r *Runner) exec(ctx context.Context, path string, hctx Context, body []byte) error {
+	cmd := exec.CommandContext(ctx, path)
+	cmd.Env = append(os.Environ(), hctx.env()...)
+	cmd.Stdin = bytes.NewReader(body)
+
+	// A timed out hook is killed with the children it spawned, which would otherwise
+	// keep its output open
+	supervisor.SetProcessGroup(cmd)
+	cmd.Cancel = func() error {
+		return supervisor.SignalGroup(cmd.Process.Pid, syscall.SIGKILL)
+	}
+	cmd.WaitDelay = time.Second
+
+	output, err := cmd.CombinedOutput()
+	if len(output) > 0 {
+		r.logger.Debug().Str("hook", path).Str("output", strings.TrimSpace(string(output))).Msg("Hook output")
+	}
+	if ctx.Err() != nil {
+		return fmt.Errorf("timed out after %s", r.timeout)
+	}
+	return err
+}
+
+// post posts the context to the webhook at url, which must answer with a 2xx status.
+func (r *Runner) post(ctx context.Context, url string, body []byte) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := r.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("webhook answered %s", resp.Status)
+	}
+	return nil
+}
+
+// isWebhook reports whether hook is a webhook URL rather than an executable.
+func isWebhook(hook string) bool {
+	return strings.HasPrefix(hook, "http://") || strings.HasPrefix(hook, "https://")
+}
@@ -0,0 +1,102 @@
+package hook
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// writeScript writes an executable shell script to dir
+func writeScript(t *testing.T, dir, name, body string) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("#!/bin/sh\n"+body+"\n"), 0o755); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	return path
+}
+
+func TestRunner_Run(t *testing.T) {
+	dir := t.TempDir()
+	out := filepath.Join(dir, "out")
+	script := writeScript(t, dir, "hook.sh", `cat > `+out+`; echo >> `+out+`; echo "$PRANKLIN_HOOK_EVENT $PRANKLIN_HOOK_COMPONENT" >> `+out)
+
+	var posted Context
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		if err := json.Unmarshal(body, &posted); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	runner := NewRunner(map[Event][]string{ComponentCrash: {script, server.URL}}, "test-chain", time.Second, zerolog.Nop())
+	if err := runner.Run(context.Background(), Context{Event: ComponentCrash, Component: "execution", Error: "exit status 1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Scripts get the context as JSON on stdin and in their environment
+	bz, err := os.ReadFile(out)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	lines := strings.Split(strings.TrimSpace(string(bz)), "\n")
+	var got Context
+	if err := json.Unmarshal([]byte(lines[0]), &got); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got.ChainID != "test-chain" || got.Component != "execution" || got.Error != "exit status 1" {
+		t.Errorf("unexpected context %+v", got)
+	}
+	if lines[len(lines)-1] != "component-crash execution" {
+		t.Errorf("unexpected environment %q", lines[len(lines)-1])
+	}
+
+	// Webhooks get it as the body
+	if posted.Event != ComponentCrash || posted.Component != "execution" {
+		t.Errorf("unexpected webhook context %+v", posted)
+	}
+
+	// Events without hooks run nothing
+	if err := runner.Run(context.Background(), Context{Event: PreStart}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestRunner_RunFailures(t *testing.T) {
+	dir := t.TempDir()
+	marker := filepath.Join(dir, "ran")
+	failing := writeScript(t, dir, "fail.sh", "exit 3")
+	slow := writeScript(t, dir, "slow.sh", "sleep 10")
+	succeeding := writeScript(t, dir, "ok.sh", "touch "+marker)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	runner := NewRunner(map[Event][]string{PreStart: {failing, slow, server.URL, succeeding}}, "test-chain", 100*time.Millisecond, zerolog.Nop())
+
+	// Every hook runs, and the failures are joined
+	err := runner.Run(context.Background(), Context{Event: PreStart})
+	if err == nil {
+		t.Fatal("expected error")
+	}
+	for _, want := range []string{"exit status 3", "timed out", "503"} {
+		if !strings.Contains(err.Error(), want) {
+			t.Errorf("expected %q in %v", want, err)
+		}
+	}
+	if _, err := os.Stat(marker); err != nil {
+		t.Errorf("expected the hook after the failures to run: %v", err)
+	}
+}
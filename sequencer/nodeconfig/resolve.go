@@ -0,0 +1,196 @@
+// Package nodeconfig explains where each value of the effective node configuration
+// came from.
+package nodeconfig
+
+import (
+	"encoding"
+	"fmt"
+	"os"
+	"path"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+
+	"github.com/evstack/ev-node/pkg/config"
+)
+
+// Source is where a configuration value was taken from.
+type Source string
+
+// Configuration sources, from lowest to highest precedence
+const (
+	SourceDefault Source = "default"
+	SourceFile    Source = "file"
+	SourceEnv     Source = "env"
+	SourceFlag    Source = "flag"
+)
+
+// Redacted replaces the value of secret settings.
+const Redacted = "[redacted]"
+
+// secretSuffixes identify settings whose values must never be exposed
+var secretSuffixes = []string{"auth_token", "passphrase", "token", "secret", "password"}
+
+// Setting is a resolved configuration value.
+type Setting struct {
+	// Key is the config file key, or the flag name for settings only set by flags
+	Key    string
+	Value  any
+	Source Source
+	// Env is the environment variable the value was read from, if any
+	Env string
+}
+
+// Resolve returns every setting of cmd as resolved into cfg, sorted by key, with
+// the source it was taken from. cfg must have been loaded from cmd by config.Load.
+//
+// Node configuration values come from flags set on the command line, then the config
+// file, then defaults. Other flags are set on the command line, from the environment,
+// from a config file section by ApplySection or defaulted. config.Load only applies environment variables to flags without the
+// evnode. prefix; it fails if one is set for a prefixed flag. Secrets are redacted.
+func Resolve(cmd *cobra.Command, cfg config.Config) ([]Setting, error) {
+	// config.Load ignores unreadable config files, so they contribute no values here either
+	file := viper.New()
+	file.SetConfigFile(cfg.ConfigPath())
+	_ = file.ReadInConfig()
+
+	executable, err := os.Executable()
+	if err != nil {
+		return nil, err
+	}
+	basename := path.Base(executable)
+
+	flags := cmd.Flags()
+	seen := make(map[string]bool)
+	var settings []Setting
+
+	for key, value := range flatten(reflect.ValueOf(cfg), "") {
+		flag := flags.Lookup(config.FlagPrefixEvnode + key)
+		if flag != nil {
+			seen[flag.Name] = true
+		}
+
+		setting := Setting{Key: key, Value: value, Source: SourceDefault}
+		switch {
+		case flag != nil && flag.Changed:
+			setting.Source = SourceFlag
+		case file.IsSet(key):
+			setting.Source = SourceFile
+		}
+		settings = append(settings, redact(setting))
+	}
+
+	// Remaining flags are read directly from the command line
+	flags.VisitAll(func(flag *pflag.Flag) {
+		if seen[flag.Name] || flag.Hidden {
+			return
+		}
+
+		setting := Setting{Key: flag.Name, Value: flag.Value.String(), Source: SourceDefault}
+		if flag.Name == config.FlagRootDir {
+			setting.Value = cfg.RootDir
+		}
+		if flag.Changed {
+			setting.Source = SourceFlag
+
+			// config.Load sets flags from the environment, marking them as changed
+			if env := lookupEnv(basename, flag.Name); env != "" && os.Getenv(env) == flag.Value.String() {
+				setting.Source = SourceEnv
+				setting.Env = env
+			}
+
+			// ApplySection sets flags from the config file, marking them as changed
+			if _, ok := flag.Annotations[fileAnnotation]; ok {
+				setting.Source = SourceFile
+			}
+		}
+		settings = append(settings, redact(setting))
+	})
+
+	sort.Slice(settings, func(i, j int) bool { return settings[i].Key < settings[j].Key })
+	return settings, nil
+}
+
+// lookupEnv returns the name of the environment variable config.Load reads the flag
+// name from, or "" if none is set. Empty variables count as unset, as in viper.
+func lookupEnv(basename, name string) string {
+	if strings.HasPrefix(name, config.FlagPrefixEvnode) || strings.HasPrefix(name, config.FlagPrefixRollkit) {
+		return ""
+	}
+
+	names := []string{
+		// Read through viper.AutomaticEnv
+		strings.ToUpper(name),
+		// Bound by config.Load for every flag
+		fmt.Sprintf("%s_%s", basename, strings.ToUpper(strings.ReplaceAll(name, "-", "_"))),
+	}
+	for _, env := range names {
+		if os.Getenv(env) != "" {
+			return env
+		}
+	}
+	return ""
+}
+
+// redact hides the value of setting if it is a secret.
+func redact(setting Setting) Setting {
+	for _, suffix := range secretSuffixes {
+		if strings.HasSuffix(setting.Key, suffix) {
+			if setting.Value != "" {
+				setting.Value = Redacted
+			}
+			break
+		}
+	}
+	return setting
+}
+
+// flatten returns the leaves of the configuration struct v keyed by their dotted yaml
+// path below prefix.
+func flatten(v reflect.Value, prefix string) map[string]any {
+	leaves := make(map[string]any)
+	for v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return leaves
+		}
+		v = v.Elem()
+	}
+
+	t := v.Type()
+	for i := range t.NumField() {
+		field := t.Field(i)
+		name, _, _ := strings.Cut(field.Tag.Get("yaml"), ",")
+		if !field.IsExported() || name == "" || name == "-" {
+			continue
+		}
+
+		key := prefix + name
+		value := v.Field(i)
+
+		// Values with a text form, e.g. durations, are leaves
+		if marshaler, ok := value.Interface().(encoding.TextMarshaler); ok {
+			if text, err := marshaler.MarshalText(); err == nil {
+				leaves[key] = string(text)
+				continue
+			}
+		}
+
+		elem := value
+		for elem.Kind() == reflect.Pointer && !elem.IsNil() {
+			elem = elem.Elem()
+		}
+		if elem.Kind() == reflect.Struct {
+			for k, leaf := range flatten(elem, key+".") {
+				leaves[k] = leaf
+			}
+			continue
+		}
+
+		leaves[key] = value.Interface()
+	}
+	return leaves
+}
@@ -0,0 +1,79 @@
+package nodeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+)
+
+func TestResolve(t *testing.T) {
+	home := t.TempDir()
+	configPath := filepath.Join(home, config.AppConfigDir, config.ConfigName)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	fileConfig := "da:\n  address: http://file:7980\n  auth_token: file-token\nrpc:\n  address: 127.0.0.1:1111\n"
+	if err := os.WriteFile(configPath, []byte(fileConfig), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+	config.AddGlobalFlags(cmd, "test")
+	config.AddFlags(cmd)
+	cmd.Flags().String("dashboard.addr", "", "dashboard address")
+	cmd.Flags().String("api.addr", "", "API address")
+
+	t.Setenv("API.ADDR", ":7332")
+	if err := cmd.ParseFlags([]string{"--home", home, "--evnode.rpc.address", "127.0.0.1:2222", "--dashboard.addr", ":7333"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cfg, err := config.Load(cmd)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	settings, err := Resolve(cmd, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	byKey := make(map[string]Setting, len(settings))
+	for _, setting := range settings {
+		byKey[setting.Key] = setting
+	}
+
+	expected := map[string]Setting{
+		"da.address":      {Key: "da.address", Value: "http://file:7980", Source: SourceFile},
+		"da.auth_token":   {Key: "da.auth_token", Value: Redacted, Source: SourceFile},
+		"api.addr":        {Key: "api.addr", Value: ":7332", Source: SourceEnv, Env: "API.ADDR"},
+		"rpc.address":     {Key: "rpc.address", Value: "127.0.0.1:2222", Source: SourceFlag},
+		"log.level":       {Key: "log.level", Value: "info", Source: SourceDefault},
+		"node.block_time": {Key: "node.block_time", Value: cfg.Node.BlockTime.String(), Source: SourceDefault},
+		"dashboard.addr":  {Key: "dashboard.addr", Value: ":7333", Source: SourceFlag},
+		"home":            {Key: "home", Value: home, Source: SourceFlag},
+	}
+	for key, want := range expected {
+		got, ok := byKey[key]
+		if !ok {
+			t.Errorf("missing setting %s", key)
+			continue
+		}
+		if got != want {
+			t.Errorf("%s: expected %+v, got %+v", key, want, got)
+		}
+	}
+
+	if _, ok := byKey["evnode.rpc.address"]; ok {
+		t.Error("expected config flags to be reported under their config key only")
+	}
+	for i := 1; i < len(settings); i++ {
+		if settings[i-1].Key > settings[i].Key {
+			t.Fatalf("expected settings sorted by key, got %s before %s", settings[i-1].Key, settings[i].Key)
+		}
+	}
+}
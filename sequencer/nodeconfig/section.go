@@ -0,0 +1,111 @@
+package nodeconfig
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/goccy/go-yaml"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+// fileAnnotation marks flags set from the config file by ApplySection
+const fileAnnotation = "nodeconfig_file"
+
+// SectionKey returns the config file key of the flag name in section, e.g.
+// "pranklin.local_da_binary" for the local-da-binary flag.
+func SectionKey(section, name string) string {
+	return section + "." + strings.NewReplacer("-", "_", ".", "_").Replace(name)
+}
+
+// ApplySection sets the flags names of cmd that were not set on the command line or
+// from the environment to their values in section of the config file at path, so
+// the file provides defaults that flags override. A missing config file is ignored.
+// Slice flags are replaced by the file's value, not appended to.
+// Resolve reports flags set this way with SourceFile.
+func ApplySection(cmd *cobra.Command, path, section string, names []string) error {
+	file := viper.New()
+	file.SetConfigFile(path)
+	if err := file.ReadInConfig(); err != nil {
+		if _, statErr := os.Stat(path); os.IsNotExist(statErr) {
+			return nil
+		}
+		return fmt.Errorf("failed to read config file %s: %w", path, err)
+	}
+
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("unknown flag %s", name)
+		}
+
+		key := SectionKey(section, name)
+		if flag.Changed || !file.IsSet(key) {
+			continue
+		}
+
+		if err := setFromFile(cmd, flag, file, key); err != nil {
+			return fmt.Errorf("invalid %s in %s: %w", key, path, err)
+		}
+		if err := cmd.Flags().SetAnnotation(name, fileAnnotation, []string{key}); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// setFromFile sets flag to the value of key in file. Slice flags take a list, or a
+// scalar as a single element so that commas in it are kept.
+func setFromFile(cmd *cobra.Command, flag *pflag.Flag, file *viper.Viper, key string) error {
+	slice, ok := flag.Value.(pflag.SliceValue)
+	if !ok {
+		return cmd.Flags().Set(flag.Name, file.GetString(key))
+	}
+
+	values := []string{file.GetString(key)}
+	if _, isList := file.Get(key).([]any); isList {
+		values = file.GetStringSlice(key)
+	}
+	if err := slice.Replace(values); err != nil {
+		return err
+	}
+	flag.Changed = true
+	return nil
+}
+
+// WriteSection appends section with the current values of the flags names of cmd to
+// the config file at path.
+func WriteSection(cmd *cobra.Command, path, section string, names []string) error {
+	values := make(map[string]any, len(names))
+	for _, name := range names {
+		flag := cmd.Flags().Lookup(name)
+		if flag == nil {
+			return fmt.Errorf("unknown flag %s", name)
+		}
+		key := strings.TrimPrefix(SectionKey(section, name), section+".")
+		if slice, ok := flag.Value.(pflag.SliceValue); ok {
+			values[key] = slice.GetSlice()
+		} else {
+			values[key] = flag.Value.String()
+		}
+	}
+
+	bz, err := yaml.Marshal(map[string]map[string]any{section: values})
+	if err != nil {
+		return fmt.Errorf("failed to encode %s section: %w", section, err)
+	}
+
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("failed to open config file: %w", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write(append([]byte("\n"), bz...)); err != nil {
+		return fmt.Errorf("failed to write %s section: %w", section, err)
+	}
+	return f.Close()
+}
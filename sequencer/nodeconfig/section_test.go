@@ -0,0 +1,93 @@
+package nodeconfig
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"testing"
+
+	"github.com/spf13/cobra"
+
+	"github.com/evstack/ev-node/pkg/config"
+)
+
+func newSectionCommand() *cobra.Command {
+	cmd := &cobra.Command{Use: "test", RunE: func(*cobra.Command, []string) error { return nil }}
+	config.AddGlobalFlags(cmd, "test")
+	config.AddFlags(cmd)
+	cmd.Flags().String("execution-binary", "pranklin-app", "execution binary")
+	cmd.Flags().String("local-da-port", "7980", "local-da port")
+	cmd.Flags().StringArray("execution.env", nil, "execution environment")
+	return cmd
+}
+
+func TestSection(t *testing.T) {
+	home := t.TempDir()
+	configPath := filepath.Join(home, config.AppConfigDir, config.ConfigName)
+	if err := os.MkdirAll(filepath.Dir(configPath), 0o750); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := os.WriteFile(configPath, []byte("rpc:\n  address: 127.0.0.1:1111\n"), 0o600); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	names := []string{"execution-binary", "local-da-port", "execution.env"}
+
+	initCmd := newSectionCommand()
+	if err := initCmd.ParseFlags([]string{"--execution-binary", "/opt/pranklin-app", "--local-da-port", "8000", "--execution.env", "RUST_LOG=info,pranklin=debug", "--execution.env", "A=1"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := WriteSection(initCmd, configPath, "pranklin", names); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The file provides the values flags on the command line do not set
+	cmd := newSectionCommand()
+	if err := cmd.ParseFlags([]string{"--home", home, "--local-da-port", "9000"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := ApplySection(cmd, configPath, "pranklin", names); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if binary, _ := cmd.Flags().GetString("execution-binary"); binary != "/opt/pranklin-app" {
+		t.Errorf("expected the binary from the config file, got %q", binary)
+	}
+	if port, _ := cmd.Flags().GetString("local-da-port"); port != "9000" {
+		t.Errorf("expected the port from the command line, got %q", port)
+	}
+	// Slices keep their elements, commas included
+	if env, _ := cmd.Flags().GetStringArray("execution.env"); !slices.Equal(env, []string{"RUST_LOG=info,pranklin=debug", "A=1"}) {
+		t.Errorf("expected the environment from the config file, got %q", env)
+	}
+
+	cfg, err := config.Load(cmd)
+	if err != nil {
+		t.Fatalf("expected the section not to break the node configuration: %v", err)
+	}
+	if cfg.RPC.Address != "127.0.0.1:1111" {
+		t.Errorf("expected the node configuration to be kept, got rpc address %q", cfg.RPC.Address)
+	}
+
+	settings, err := Resolve(cmd, cfg)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, setting := range settings {
+		switch setting.Key {
+		case "execution-binary":
+			if setting.Source != SourceFile {
+				t.Errorf("expected execution-binary from %s, got %s", SourceFile, setting.Source)
+			}
+		case "local-da-port":
+			if setting.Source != SourceFlag {
+				t.Errorf("expected local-da-port from %s, got %s", SourceFlag, setting.Source)
+			}
+		}
+	}
+
+	missing := newSectionCommand()
+	if err := ApplySection(missing, filepath.Join(home, "missing.yaml"), "pranklin", names); err != nil {
+		t.Errorf("expected a missing config file to be ignored, got %v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package profile
+
+import (
+	"context"
+	"time"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/core/execution"
+)
+
+// Ensure Executor implements the execution.Executor interface
+var _ execution.Executor = (*Executor)(nil)
+
+// Executor wraps an execution.Executor and profiles each call with a Tracker.
+type Executor struct {
+	inner   execution.Executor
+	tracker *Tracker
+}
+
+// NewExecutor wraps executor so its calls are profiled by tracker.
+func NewExecutor(executor execution.Executor, tracker *Tracker) *Executor {
+	return &Executor{
+		inner:   executor,
+		tracker: tracker,
+	}
+}
+
+// InitChain initializes a new blockchain instance with genesis parameters.
+func (e *Executor) InitChain(ctx context.Context, genesisTime time.Time, initialHeight uint64, chainID string) ([]byte, uint64, error) {
+	return e.inner.InitChain(ctx, genesisTime, initialHeight, chainID)
+}
+
+// GetTxs fetches available transactions from the execution layer's mempool.
+func (e *Executor) GetTxs(ctx context.Context) (txs [][]byte, err error) {
+	err = e.tracker.Stage(StageCollect, func() error {
+		txs, err = e.inner.GetTxs(ctx)
+		return err
+	})
+	return txs, err
+}
+
+// ExecuteTxs processes transactions to produce a new block state.
+// Each successful call closes the profiling window of a block.
+func (e *Executor) ExecuteTxs(ctx context.Context, txs [][]byte, blockHeight uint64, timestamp time.Time, prevStateRoot []byte) (stateRoot []byte, maxBytes uint64, err error) {
+	err = e.tracker.Stage(StageExecute, func() error {
+		stateRoot, maxBytes, err = e.inner.ExecuteTxs(ctx, txs, blockHeight, timestamp, prevStateRoot)
+		return err
+	})
+	if err == nil {
+		e.tracker.EndBlock(blockHeight)
+	}
+	return stateRoot, maxBytes, err
+}
+
+// SetFinal marks a block as finalized at the specified height.
+func (e *Executor) SetFinal(ctx context.Context, blockHeight uint64) error {
+	return e.tracker.Stage(StageFinalize, func() error {
+		return e.inner.SetFinal(ctx, blockHeight)
+	})
+}
+
+// Ensure DA implements the coreda.DA interface
+var _ coreda.DA = (*DA)(nil)
+
+// DA wraps a coreda.DA and profiles blob submissions with a Tracker.
+type DA struct {
+	coreda.DA
+	tracker *Tracker
+}
+
+// NewDA wraps daLayer so its submissions are profiled by tracker.
+func NewDA(daLayer coreda.DA, tracker *Tracker) *DA {
+	return &DA{
+		DA:      daLayer,
+		tracker: tracker,
+	}
+}
+
+// Submit submits the blobs to the DA layer.
+func (d *DA) Submit(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte) (ids []coreda.ID, err error) {
+	err = d.tracker.Stage(StageDASubmit, func() error {
+		ids, err = d.DA.Submit(ctx, blobs, gasPrice, namespace)
+		return err
+	})
+	return ids, err
+}
+
+// SubmitWithOptions submits the blobs to the DA layer with additional options.
+func (d *DA) SubmitWithOptions(ctx context.Context, blobs []coreda.Blob, gasPrice float64, namespace []byte, options []byte) (ids []coreda.ID, err error) {
+	err = d.tracker.Stage(StageDASubmit, func() error {
+		ids, err = d.DA.SubmitWithOptions(ctx, blobs, gasPrice, namespace, options)
+		return err
+	})
+	return ids, err
+}
@@ -0,0 +1,98 @@
+package profile
+
+import (
+	"errors"
+
+	"github.com/go-kit/kit/metrics"
+	"github.com/go-kit/kit/metrics/discard"
+	"github.com/go-kit/kit/metrics/prometheus"
+	stdprometheus "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	// MetricsSubsystem is a subsystem shared by all metrics exposed by this
+	// package.
+	MetricsSubsystem = "block_loop"
+)
+
+// MetricsProvider returns block loop profiling Metrics.
+type MetricsProvider func(chainID string) (*Metrics, error)
+
+// DefaultMetricsProvider returns Metrics build using Prometheus client library
+// if Prometheus is enabled. Otherwise, it returns no-op Metrics.
+func DefaultMetricsProvider(enabled bool) MetricsProvider {
+	return func(chainID string) (*Metrics, error) {
+		if enabled {
+			return PrometheusMetrics("chain_id", chainID)
+		}
+		return NopMetrics()
+	}
+}
+
+// Metrics contains metrics exposed by this package.
+type Metrics struct {
+	// Time spent in a stage
+	StageDuration metrics.Histogram
+	// Time spent in all stages during the last block
+	BlockDuration metrics.Histogram
+	// Bytes allocated while a stage was running
+	StageAllocBytes metrics.Histogram
+	// Bytes allocated during the last block
+	BlockAllocBytes metrics.Gauge
+	// Number of blocks that exceeded the memory budget
+	BudgetExceeded metrics.Counter
+}
+
+// PrometheusMetrics returns Metrics build using Prometheus client library.
+// Optionally, labels can be provided along with their values ("foo",
+// "fooValue").
+func PrometheusMetrics(labelsAndValues ...string) (*Metrics, error) {
+	if len(labelsAndValues)%2 != 0 {
+		return nil, errors.New("uneven number of labels and values; labels and values should be provided in pairs")
+	}
+	labels := []string{}
+	for i := 0; i < len(labelsAndValues); i += 2 {
+		labels = append(labels, labelsAndValues[i])
+	}
+	return &Metrics{
+		StageDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "stage_duration_seconds",
+			Help:      "Time spent in a block loop stage.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, append(labels, "stage")).With(labelsAndValues...),
+		BlockDuration: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "block_duration_seconds",
+			Help:      "Time spent in all block loop stages during a block.",
+			Buckets:   stdprometheus.ExponentialBuckets(0.0005, 2, 14),
+		}, labels).With(labelsAndValues...),
+		StageAllocBytes: prometheus.NewHistogramFrom(stdprometheus.HistogramOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "stage_alloc_bytes",
+			Help:      "Bytes allocated by the process while a block loop stage was running.",
+			Buckets:   stdprometheus.ExponentialBuckets(64*1024, 4, 10),
+		}, append(labels, "stage")).With(labelsAndValues...),
+		BlockAllocBytes: prometheus.NewGaugeFrom(stdprometheus.GaugeOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "block_alloc_bytes",
+			Help:      "Bytes allocated by the process during the last block.",
+		}, labels).With(labelsAndValues...),
+		BudgetExceeded: prometheus.NewCounterFrom(stdprometheus.CounterOpts{
+			Subsystem: MetricsSubsystem,
+			Name:      "mem_budget_exceeded",
+			Help:      "Number of blocks whose allocations exceeded the memory budget.",
+		}, labels).With(labelsAndValues...),
+	}, nil
+}
+
+// NopMetrics returns no-op Metrics.
+func NopMetrics() (*Metrics, error) {
+	return &Metrics{
+		StageDuration:   discard.NewHistogram(),
+		BlockDuration:   discard.NewHistogram(),
+		StageAllocBytes: discard.NewHistogram(),
+		BlockAllocBytes: discard.NewGauge(),
+		BudgetExceeded:  discard.NewCounter(),
+	}, nil
+}
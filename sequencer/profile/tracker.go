@@ -0,0 +1,197 @@
+package profile
+
+import (
+	"runtime/metrics"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// Block loop stage names
+const (
+	// StageCollect is the collection of transactions from the execution mempool
+	StageCollect = "collect"
+	// StageExecute is the execution of a block's transactions
+	StageExecute = "execute"
+	// StageFinalize is the finalization of a block in the execution layer
+	StageFinalize = "finalize"
+	// StageDASubmit is the submission of headers and data to the DA layer
+	StageDASubmit = "da_submit"
+)
+
+// heapAllocsMetric is the cumulative number of bytes allocated on the heap by the process
+const heapAllocsMetric = "/gc/heap/allocs:bytes"
+
+// Config configures block loop profiling.
+type Config struct {
+	// MemBudget is the number of bytes a block may allocate before an alert is logged (0 disables the budget)
+	MemBudget uint64
+	// SlowestBlocks is the number of slowest blocks whose stage breakdown is logged (0 disables the log)
+	SlowestBlocks int
+}
+
+// BlockProfile is the per-stage breakdown of a single block.
+type BlockProfile struct {
+	// Height of the block
+	Height uint64
+	// Duration is the total time spent in stages since the previous block
+	Duration time.Duration
+	// Stages is the time spent in each stage since the previous block
+	Stages map[string]time.Duration
+	// AllocatedBytes is the number of bytes allocated since the previous block
+	AllocatedBytes uint64
+}
+
+// Tracker measures the latency and heap allocations of the block loop stages.
+//
+// The stages run on different goroutines inside ev-node: transactions are collected
+// and DA submissions are made independently of block production. A block's profile
+// therefore covers every stage that completed since the previous block, rather than
+// the work done for that block alone.
+//
+// Allocations are read from the process-wide runtime counters, so a stage's figure
+// also includes whatever other goroutines allocated while it was running. The numbers
+// are meant to spot trends and outliers, not to attribute every byte.
+type Tracker struct {
+	cfg     Config
+	logger  zerolog.Logger
+	metrics *Metrics
+
+	mu sync.Mutex
+	// blockStart holds the allocation counter when the current block started
+	blockStart uint64
+	// stages accumulates stage durations of the current block
+	stages map[string]time.Duration
+	// slowest holds the SlowestBlocks slowest blocks, slowest first
+	slowest []BlockProfile
+}
+
+// NewTracker creates a new block loop tracker.
+//
+// Parameters:
+// - cfg: Memory budget and slowest block log settings
+// - logger: Logger used to report exceeded budgets and slow blocks
+// - metrics: Profiling metrics
+//
+// Returns:
+// - *Tracker: The initialized tracker
+func NewTracker(cfg Config, logger zerolog.Logger, metrics *Metrics) *Tracker {
+	return &Tracker{
+		cfg:     cfg,
+		logger:  logger.With().Str("component", "profile").Logger(),
+		metrics: metrics,
+		stages:  make(map[string]time.Duration),
+	}
+}
+
+// Stage runs fn and records how long it took and the bytes allocated while it was running.
+func (t *Tracker) Stage(stage string, fn func() error) error {
+	before := heapAllocs()
+	start := time.Now()
+	err := fn()
+	elapsed := time.Since(start)
+
+	t.metrics.StageDuration.With("stage", stage).Observe(elapsed.Seconds())
+	t.metrics.StageAllocBytes.With("stage", stage).Observe(float64(heapAllocs() - before))
+
+	t.mu.Lock()
+	t.stages[stage] += elapsed
+	t.mu.Unlock()
+
+	return err
+}
+
+// EndBlock closes the profiling window of the block at height, checks it against the
+// memory budget and logs its breakdown if it is among the slowest blocks seen so far.
+func (t *Tracker) EndBlock(height uint64) BlockProfile {
+	now := heapAllocs()
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	profile := BlockProfile{
+		Height: height,
+		Stages: t.stages,
+	}
+	for _, d := range t.stages {
+		profile.Duration += d
+	}
+	t.stages = make(map[string]time.Duration)
+
+	start := t.blockStart
+	t.blockStart = now
+	if start == 0 {
+		// First block: there is no previous window to compare against
+		return profile
+	}
+
+	profile.AllocatedBytes = now - start
+	t.metrics.BlockAllocBytes.Set(float64(profile.AllocatedBytes))
+	t.metrics.BlockDuration.Observe(profile.Duration.Seconds())
+
+	if t.cfg.MemBudget > 0 && profile.AllocatedBytes > t.cfg.MemBudget {
+		t.metrics.BudgetExceeded.Add(1)
+		t.logger.Warn().
+			Uint64("height", height).
+			Uint64("allocated_bytes", profile.AllocatedBytes).
+			Uint64("budget_bytes", t.cfg.MemBudget).
+			Msg("⚠️  Block exceeded memory allocation budget")
+	}
+
+	if t.recordSlowest(profile) {
+		event := t.logger.Info().
+			Uint64("height", height).
+			Dur("duration", profile.Duration).
+			Uint64("allocated_bytes", profile.AllocatedBytes)
+		for stage, d := range profile.Stages {
+			event = event.Dur(stage, d)
+		}
+		event.Msg("🐢 Slow block stage breakdown")
+	}
+
+	return profile
+}
+
+// Slowest returns the slowest blocks seen so far, slowest first.
+func (t *Tracker) Slowest() []BlockProfile {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	return append([]BlockProfile(nil), t.slowest...)
+}
+
+// recordSlowest adds profile to the slowest blocks if it is slower than one of them.
+// It reports whether profile was added. The caller must hold t.mu.
+func (t *Tracker) recordSlowest(profile BlockProfile) bool {
+	if t.cfg.SlowestBlocks <= 0 {
+		return false
+	}
+
+	if len(t.slowest) == t.cfg.SlowestBlocks {
+		if profile.Duration <= t.slowest[len(t.slowest)-1].Duration {
+			return false
+		}
+		t.slowest = t.slowest[:len(t.slowest)-1]
+	}
+
+	i := sort.Search(len(t.slowest), func(i int) bool {
+		return t.slowest[i].Duration < profile.Duration
+	})
+	t.slowest = append(t.slowest, BlockProfile{})
+	copy(t.slowest[i+1:], t.slowest[i:])
+	t.slowest[i] = profile
+
+	return true
+}
+
+// heapAllocs returns the cumulative number of bytes allocated on the heap.
+func heapAllocs() uint64 {
+	sample := []metrics.Sample{{Name: heapAllocsMetric}}
+	metrics.Read(sample)
+	if sample[0].Value.Kind() != metrics.KindUint64 {
+		return 0
+	}
+	return sample[0].Value.Uint64()
+}
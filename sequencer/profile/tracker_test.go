@@ -0,0 +1,62 @@
+package profile
+
+import (
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var sink []byte
+
+func TestTracker_EndBlock(t *testing.T) {
+	metrics, _ := NopMetrics()
+	tracker := NewTracker(Config{MemBudget: 1024}, zerolog.Nop(), metrics)
+
+	// The first block only opens the allocation window
+	if profile := tracker.EndBlock(1); profile.AllocatedBytes != 0 {
+		t.Errorf("expected no allocations for the first block, got %d", profile.AllocatedBytes)
+	}
+
+	err := tracker.Stage(StageExecute, func() error {
+		sink = make([]byte, 1<<20)
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	profile := tracker.EndBlock(2)
+	if profile.AllocatedBytes < 1<<20 {
+		t.Errorf("expected at least %d bytes allocated, got %d", 1<<20, profile.AllocatedBytes)
+	}
+	if _, ok := profile.Stages[StageExecute]; !ok {
+		t.Errorf("expected %s stage in block profile, got %v", StageExecute, profile.Stages)
+	}
+}
+
+func TestTracker_Slowest(t *testing.T) {
+	metrics, _ := NopMetrics()
+	tracker := NewTracker(Config{SlowestBlocks: 2}, zerolog.Nop(), metrics)
+	tracker.EndBlock(1)
+
+	for height, sleep := range map[uint64]time.Duration{
+		2: 1 * time.Millisecond,
+		3: 20 * time.Millisecond,
+		4: 10 * time.Millisecond,
+	} {
+		_ = tracker.Stage(StageExecute, func() error {
+			time.Sleep(sleep)
+			return nil
+		})
+		tracker.EndBlock(height)
+	}
+
+	slowest := tracker.Slowest()
+	if len(slowest) != 2 {
+		t.Fatalf("expected 2 slowest blocks, got %d", len(slowest))
+	}
+	if slowest[0].Height != 3 || slowest[1].Height != 4 {
+		t.Errorf("expected heights [3 4], got [%d %d]", slowest[0].Height, slowest[1].Height)
+	}
+}
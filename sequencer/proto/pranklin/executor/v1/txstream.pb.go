@@ -0,0 +1,178 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        (unknown)
+// source: pranklin/executor/v1/txstream.proto
+
+package v1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// StreamTxsRequest asks for the transactions of the mempool
+type StreamTxsRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Byte budget of each chunk, 0 for the default of the execution layer
+	ChunkBytes    uint32 `protobuf:"varint,1,opt,name=chunk_bytes,json=chunkBytes,proto3" json:"chunk_bytes,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTxsRequest) Reset() {
+	*x = StreamTxsRequest{}
+	mi := &file_pranklin_executor_v1_txstream_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTxsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTxsRequest) ProtoMessage() {}
+
+func (x *StreamTxsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_pranklin_executor_v1_txstream_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTxsRequest.ProtoReflect.Descriptor instead.
+func (*StreamTxsRequest) Descriptor() ([]byte, []int) {
+	return file_pranklin_executor_v1_txstream_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *StreamTxsRequest) GetChunkBytes() uint32 {
+	if x != nil {
+		return x.ChunkBytes
+	}
+	return 0
+}
+
+// StreamTxsResponse carries the next chunk of transactions
+type StreamTxsResponse struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Transactions of the chunk, in mempool order
+	Txs           [][]byte `protobuf:"bytes,1,rep,name=txs,proto3" json:"txs,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamTxsResponse) Reset() {
+	*x = StreamTxsResponse{}
+	mi := &file_pranklin_executor_v1_txstream_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamTxsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamTxsResponse) ProtoMessage() {}
+
+func (x *StreamTxsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_pranklin_executor_v1_txstream_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamTxsResponse.ProtoReflect.Descriptor instead.
+func (*StreamTxsResponse) Descriptor() ([]byte, []int) {
+	return file_pranklin_executor_v1_txstream_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *StreamTxsResponse) GetTxs() [][]byte {
+	if x != nil {
+		return x.Txs
+	}
+	return nil
+}
+
+var File_pranklin_executor_v1_txstream_proto protoreflect.FileDescriptor
+
+const file_pranklin_executor_v1_txstream_proto_rawDesc = "" +
+	"\n" +
+	"#pranklin/executor/v1/txstream.proto\x12\x14pranklin.executor.v1\"3\n" +
+	"\x10StreamTxsRequest\x12\x1f\n" +
+	"\vchunk_bytes\x18\x01 \x01(\rR\n" +
+	"chunkBytes\"%\n" +
+	"\x11StreamTxsResponse\x12\x10\n" +
+	"\x03txs\x18\x01 \x03(\fR\x03txs2s\n" +
+	"\x0fTxStreamService\x12`\n" +
+	"\tStreamTxs\x12&.pranklin.executor.v1.StreamTxsRequest\x1a'.pranklin.executor.v1.StreamTxsResponse\"\x000\x01BCZAgithub.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1b\x06proto3"
+
+var (
+	file_pranklin_executor_v1_txstream_proto_rawDescOnce sync.Once
+	file_pranklin_executor_v1_txstream_proto_rawDescData []byte
+)
+
+func file_pranklin_executor_v1_txstream_proto_rawDescGZIP() []byte {
+	file_pranklin_executor_v1_txstream_proto_rawDescOnce.Do(func() {
+		file_pranklin_executor_v1_txstream_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_pranklin_executor_v1_txstream_proto_rawDesc), len(file_pranklin_executor_v1_txstream_proto_rawDesc)))
+	})
+	return file_pranklin_executor_v1_txstream_proto_rawDescData
+}
+
+var file_pranklin_executor_v1_txstream_proto_msgTypes = make([]protoimpl.MessageInfo, 2)
+var file_pranklin_executor_v1_txstream_proto_goTypes = []any{
+	(*StreamTxsRequest)(nil),  // 0: pranklin.executor.v1.StreamTxsRequest
+	(*StreamTxsResponse)(nil), // 1: pranklin.executor.v1.StreamTxsResponse
+}
+var file_pranklin_executor_v1_txstream_proto_depIdxs = []int32{
+	0, // 0: pranklin.executor.v1.TxStreamService.StreamTxs:input_type -> pranklin.executor.v1.StreamTxsRequest
+	1, // 1: pranklin.executor.v1.TxStreamService.StreamTxs:output_type -> pranklin.executor.v1.StreamTxsResponse
+	1, // [1:2] is the sub-list for method output_type
+	0, // [0:1] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_pranklin_executor_v1_txstream_proto_init() }
+func file_pranklin_executor_v1_txstream_proto_init() {
+	if File_pranklin_executor_v1_txstream_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_pranklin_executor_v1_txstream_proto_rawDesc), len(file_pranklin_executor_v1_txstream_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   2,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_pranklin_executor_v1_txstream_proto_goTypes,
+		DependencyIndexes: file_pranklin_executor_v1_txstream_proto_depIdxs,
+		MessageInfos:      file_pranklin_executor_v1_txstream_proto_msgTypes,
+	}.Build()
+	File_pranklin_executor_v1_txstream_proto = out.File
+	file_pranklin_executor_v1_txstream_proto_goTypes = nil
+	file_pranklin_executor_v1_txstream_proto_depIdxs = nil
+}
@@ -0,0 +1,113 @@
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: pranklin/executor/v1/txstream.proto
+
+package v1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/pranklin/pranklin-sequencer/proto/pranklin/executor/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// TxStreamServiceName is the fully-qualified name of the TxStreamService service.
+	TxStreamServiceName = "pranklin.executor.v1.TxStreamService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// TxStreamServiceStreamTxsProcedure is the fully-qualified name of the TxStreamService's StreamTxs
+	// RPC.
+	TxStreamServiceStreamTxsProcedure = "/pranklin.executor.v1.TxStreamService/StreamTxs"
+)
+
+// TxStreamServiceClient is a client for the pranklin.executor.v1.TxStreamService service.
+type TxStreamServiceClient interface {
+	// StreamTxs sends the transactions of the mempool in chunks of at most the
+	// requested byte budget. A chunk holds at least one transaction, however large.
+	StreamTxs(context.Context, *connect.Request[v1.StreamTxsRequest]) (*connect.ServerStreamForClient[v1.StreamTxsResponse], error)
+}
+
+// NewTxStreamServiceClient constructs a client for the pranklin.executor.v1.TxStreamService
+// service. By default, it uses the Connect protocol with the binary Protobuf Codec, asks for
+// gzipped responses, and sends uncompressed requests. To use the gRPC or gRPC-Web protocols, supply
+// the connect.WithGRPC() or connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewTxStreamServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) TxStreamServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	txStreamServiceMethods := v1.File_pranklin_executor_v1_txstream_proto.Services().ByName("TxStreamService").Methods()
+	return &txStreamServiceClient{
+		streamTxs: connect.NewClient[v1.StreamTxsRequest, v1.StreamTxsResponse](
+			httpClient,
+			baseURL+TxStreamServiceStreamTxsProcedure,
+			connect.WithSchema(txStreamServiceMethods.ByName("StreamTxs")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// txStreamServiceClient implements TxStreamServiceClient.
+type txStreamServiceClient struct {
+	streamTxs *connect.Client[v1.StreamTxsRequest, v1.StreamTxsResponse]
+}
+
+// StreamTxs calls pranklin.executor.v1.TxStreamService.StreamTxs.
+func (c *txStreamServiceClient) StreamTxs(ctx context.Context, req *connect.Request[v1.StreamTxsRequest]) (*connect.ServerStreamForClient[v1.StreamTxsResponse], error) {
+	return c.streamTxs.CallServerStream(ctx, req)
+}
+
+// TxStreamServiceHandler is an implementation of the pranklin.executor.v1.TxStreamService service.
+type TxStreamServiceHandler interface {
+	// StreamTxs sends the transactions of the mempool in chunks of at most the
+	// requested byte budget. A chunk holds at least one transaction, however large.
+	StreamTxs(context.Context, *connect.Request[v1.StreamTxsRequest], *connect.ServerStream[v1.StreamTxsResponse]) error
+}
+
+// NewTxStreamServiceHandler builds an HTTP handler from the service implementation. It returns the
+// path on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewTxStreamServiceHandler(svc TxStreamServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	txStreamServiceMethods := v1.File_pranklin_executor_v1_txstream_proto.Services().ByName("TxStreamService").Methods()
+	txStreamServiceStreamTxsHandler := connect.NewServerStreamHandler(
+		TxStreamServiceStreamTxsProcedure,
+		svc.StreamTxs,
+		connect.WithSchema(txStreamServiceMethods.ByName("StreamTxs")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/pranklin.executor.v1.TxStreamService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case TxStreamServiceStreamTxsProcedure:
+			txStreamServiceStreamTxsHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedTxStreamServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedTxStreamServiceHandler struct{}
+
+func (UnimplementedTxStreamServiceHandler) StreamTxs(context.Context, *connect.Request[v1.StreamTxsRequest], *connect.ServerStream[v1.StreamTxsResponse]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("pranklin.executor.v1.TxStreamService.StreamTxs is not implemented"))
+}
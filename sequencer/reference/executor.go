@@ -0,0 +1,181 @@
+// Package reference implements a minimal in-memory execution layer for the sequencer:
+// quote balance transfers and a single limit order book. It serves conformance tests
+// and demos, and is a template for teams building execution layers against the
+// ExecutorService.
+package reference
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/evstack/ev-node/core/execution"
+)
+
+// Ensure Executor implements the execution.Executor interface
+var _ execution.Executor = (*Executor)(nil)
+
+// maxBlockBytes is the largest block the reference executor accepts
+const maxBlockBytes = 1 << 20
+
+var (
+	// ErrNotInitialized is returned when a block is executed before InitChain.
+	ErrNotInitialized = errors.New("chain is not initialized")
+	// ErrUnexpectedBlock is returned when a block does not follow the current state.
+	ErrUnexpectedBlock = errors.New("block does not follow the current state")
+)
+
+// Executor is an in-memory execution.Executor. Its state is lost when it stops, so the
+// sequencer it serves must start from genesis too.
+type Executor struct {
+	alloc  map[string]uint64
+	logger zerolog.Logger
+
+	mu    sync.Mutex
+	state *State
+	// mempool holds submitted transactions in arrival order
+	mempool [][]byte
+	pending map[[sha256.Size]byte]struct{}
+}
+
+// NewExecutor creates a reference executor.
+//
+// Parameters:
+// - alloc: Quote balances of the accounts at genesis
+// - logger: Logger for rejected transactions
+//
+// Returns:
+// - *Executor: The executor; call InitChain before executing blocks
+func NewExecutor(alloc map[string]uint64, logger zerolog.Logger) *Executor {
+	return &Executor{
+		alloc:   alloc,
+		logger:  logger.With().Str("component", "reference-executor").Logger(),
+		pending: make(map[[sha256.Size]byte]struct{}),
+	}
+}
+
+// InitChain resets the state to the genesis allocation, with the first block at
+// initialHeight.
+func (e *Executor) InitChain(ctx context.Context, genesisTime time.Time, initialHeight uint64, chainID string) ([]byte, uint64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	e.state = newState(e.alloc)
+	e.state.Height = initialHeight - 1
+
+	return e.state.Root(), maxBlockBytes, nil
+}
+
+// Submit adds a transaction to the mempool after checking its format. Duplicates are
+// ignored.
+func (e *Executor) Submit(tx []byte) error {
+	if _, err := DecodeTx(tx); err != nil {
+		return err
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	hash := sha256.Sum256(tx)
+	if _, ok := e.pending[hash]; ok {
+		return nil
+	}
+	e.pending[hash] = struct{}{}
+	e.mempool = append(e.mempool, tx)
+	return nil
+}
+
+// GetTxs returns the transactions of the mempool without removing them; executing a
+// block removes its transactions.
+func (e *Executor) GetTxs(ctx context.Context) ([][]byte, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	txs := make([][]byte, len(e.mempool))
+	copy(txs, e.mempool)
+	return txs, nil
+}
+
+// ExecuteTxs applies txs in order on top of the state with root prevStateRoot and
+// returns the new root. Transactions that fail, e.g. for lack of balance, are
+// included without changing the state.
+func (e *Executor) ExecuteTxs(ctx context.Context, txs [][]byte, blockHeight uint64, timestamp time.Time, prevStateRoot []byte) ([]byte, uint64, error) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == nil {
+		return nil, 0, ErrNotInitialized
+	}
+	if blockHeight != e.state.Height+1 {
+		return nil, 0, fmt.Errorf("%w: expected height %d, got %d", ErrUnexpectedBlock, e.state.Height+1, blockHeight)
+	}
+	if !bytes.Equal(prevStateRoot, e.state.Root()) {
+		return nil, 0, fmt.Errorf("%w: previous state root %x is not the current root", ErrUnexpectedBlock, prevStateRoot)
+	}
+
+	state := e.state.clone()
+	state.Height = blockHeight
+	for i, bz := range txs {
+		tx, err := DecodeTx(bz)
+		if err == nil {
+			err = state.apply(tx)
+		}
+		if err != nil {
+			e.logger.Debug().Err(err).Uint64("height", blockHeight).Int("index", i).Msg("transaction rejected")
+		}
+	}
+	e.state = state
+
+	e.removeExecuted(txs)
+	return state.Root(), maxBlockBytes, nil
+}
+
+// SetFinal marks the block at blockHeight as final. The state is in memory only, so
+// finality does not change what is kept.
+func (e *Executor) SetFinal(ctx context.Context, blockHeight uint64) error {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == nil {
+		return ErrNotInitialized
+	}
+	if blockHeight > e.state.Height {
+		return fmt.Errorf("%w: block %d is not executed yet", ErrUnexpectedBlock, blockHeight)
+	}
+	return nil
+}
+
+// State returns a copy of the current state, nil before InitChain.
+func (e *Executor) State() *State {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	if e.state == nil {
+		return nil
+	}
+	return e.state.clone()
+}
+
+// removeExecuted removes txs from the mempool.
+func (e *Executor) removeExecuted(txs [][]byte) {
+	executed := make(map[[sha256.Size]byte]struct{}, len(txs))
+	for _, tx := range txs {
+		hash := sha256.Sum256(tx)
+		executed[hash] = struct{}{}
+		delete(e.pending, hash)
+	}
+
+	mempool := e.mempool[:0]
+	for _, tx := range e.mempool {
+		if _, ok := executed[sha256.Sum256(tx)]; !ok {
+			mempool = append(mempool, tx)
+		}
+	}
+	e.mempool = mempool
+}
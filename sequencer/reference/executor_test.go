@@ -0,0 +1,144 @@
+package reference
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+
+	"github.com/pranklin/pranklin-sequencer/api"
+)
+
+func TestExecutor(t *testing.T) {
+	ctx := context.Background()
+	genesisTime := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	alloc := map[string]uint64{"alice": 1000, "bob": 1000}
+
+	executor := NewExecutor(alloc, zerolog.Nop())
+	root, _, err := executor.InitChain(ctx, genesisTime, 1, "test-chain")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	txs := []string{
+		`{"type":"transfer","from":"alice","to":"carol","amount":100}`,
+		// bob cannot pay for this
+		`{"type":"transfer","from":"bob","to":"carol","amount":5000}`,
+		`{"type":"order","account":"bob","side":"sell","price":10,"quantity":5}`,
+		`{"type":"order","account":"alice","side":"buy","price":12,"quantity":3}`,
+		`not a transaction`,
+	}
+	for _, tx := range txs[:4] {
+		if err := executor.Submit([]byte(tx)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if err := executor.Submit([]byte(txs[4])); err == nil {
+		t.Error("expected a malformed transaction to be refused")
+	}
+
+	block, err := executor.GetTxs(ctx)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(block) != 4 {
+		t.Fatalf("expected 4 transactions in the mempool, got %d", len(block))
+	}
+	// A malformed transaction in a block is included without effect
+	block = append(block, []byte(txs[4]))
+
+	newRoot, _, err := executor.ExecuteTxs(ctx, block, 1, genesisTime.Add(time.Second), root)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	state := executor.State()
+	// alice bought 3 at the resting price of 10 and was refunded the rest of her escrow
+	if state.Balances["alice"] != 870 || state.Balances["bob"] != 1030 || state.Balances["carol"] != 100 {
+		t.Errorf("unexpected balances %v", state.Balances)
+	}
+	if state.Positions["alice"] != 3 || state.Positions["bob"] != -3 {
+		t.Errorf("unexpected positions %v", state.Positions)
+	}
+	if len(state.Asks) != 1 || state.Asks[0].Quantity != 2 || len(state.Bids) != 0 {
+		t.Errorf("expected 2 left on the ask, got bids %v asks %v", state.Bids, state.Asks)
+	}
+	if txs, _ := executor.GetTxs(ctx); len(txs) != 0 {
+		t.Errorf("expected executed transactions to leave the mempool, got %d", len(txs))
+	}
+
+	// Replaying the block from genesis yields the same root
+	replay := NewExecutor(alloc, zerolog.Nop())
+	replayRoot, _, _ := replay.InitChain(ctx, genesisTime, 1, "test-chain")
+	if !bytes.Equal(replayRoot, root) {
+		t.Fatal("expected the same genesis root")
+	}
+	if replayRoot, _, err = replay.ExecuteTxs(ctx, block, 1, genesisTime.Add(time.Second), root); err != nil || !bytes.Equal(replayRoot, newRoot) {
+		t.Errorf("expected the replay to reach the same root, got %x (%v)", replayRoot, err)
+	}
+
+	if _, _, err := executor.ExecuteTxs(ctx, nil, 2, genesisTime, root); !errors.Is(err, ErrUnexpectedBlock) {
+		t.Errorf("expected %v for a stale previous root, got %v", ErrUnexpectedBlock, err)
+	}
+	if err := executor.SetFinal(ctx, 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := executor.SetFinal(ctx, 2); !errors.Is(err, ErrUnexpectedBlock) {
+		t.Errorf("expected %v finalizing an unexecuted block, got %v", ErrUnexpectedBlock, err)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	executor := NewExecutor(map[string]uint64{"alice": 1}, zerolog.Nop())
+	handler := NewHandler(executor)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if code := errorCode(t, rec); rec.Code != http.StatusServiceUnavailable || code != api.CodeNotInitialized {
+		t.Errorf("expected %d %s before InitChain, got %d %s", http.StatusServiceUnavailable, api.CodeNotInitialized, rec.Code, code)
+	}
+
+	if _, _, err := executor.InitChain(context.Background(), time.Now(), 1, "test-chain"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(`{"type":"transfer","from":"alice","to":"bob","amount":1}`)))
+	if rec.Code != http.StatusAccepted {
+		t.Errorf("expected %d, got %d", http.StatusAccepted, rec.Code)
+	}
+
+	for body, code := range map[string]api.ErrorCode{
+		`{"type":"swap"}`:                api.CodeInvalidArgument,
+		strings.Repeat(" ", maxTxSize+1): api.CodePayloadTooLarge,
+	} {
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/tx", strings.NewReader(body)))
+		if got := errorCode(t, rec); got != code {
+			t.Errorf("%.20s: expected %s, got %d %s", body, code, rec.Code, got)
+		}
+	}
+
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/state", nil))
+	if rec.Code != http.StatusOK || !strings.Contains(rec.Body.String(), `"alice":1`) {
+		t.Errorf("expected the state, got %d %s", rec.Code, rec.Body.String())
+	}
+}
+
+// errorCode returns the code of the API error in rec.
+func errorCode(t *testing.T, rec *httptest.ResponseRecorder) api.ErrorCode {
+	t.Helper()
+	var resp api.ErrorResponse
+	if err := json.NewDecoder(rec.Body).Decode(&resp); err != nil {
+		t.Fatalf("expected an API error, got %q: %v", rec.Body.String(), err)
+	}
+	return resp.Error.Code
+}
@@ -0,0 +1,52 @@
+package reference
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/pranklin/pranklin-sequencer/api"
+)
+
+// maxTxSize bounds the size of a submitted transaction
+const maxTxSize = 64 << 10
+
+// NewHandler creates the RPC handler of the reference executor:
+//
+//	POST /tx     submits the JSON transaction in the body to the mempool
+//	GET  /state  returns the current state as JSON
+func NewHandler(executor *Executor) http.Handler {
+	mux := http.NewServeMux()
+
+	mux.HandleFunc("POST /tx", func(w http.ResponseWriter, r *http.Request) {
+		tx, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxTxSize))
+		if err != nil {
+			var tooLarge *http.MaxBytesError
+			if errors.As(err, &tooLarge) {
+				api.WriteError(w, api.CodePayloadTooLarge, err, map[string]any{"max_bytes": maxTxSize})
+				return
+			}
+			api.WriteError(w, api.CodeInvalidArgument, fmt.Errorf("failed to read transaction: %w", err), nil)
+			return
+		}
+		if err := executor.Submit(tx); err != nil {
+			api.WriteError(w, api.CodeInvalidArgument, err, nil)
+			return
+		}
+		w.WriteHeader(http.StatusAccepted)
+	})
+
+	mux.HandleFunc("GET /state", func(w http.ResponseWriter, r *http.Request) {
+		state := executor.State()
+		if state == nil {
+			api.WriteError(w, api.CodeNotInitialized, ErrNotInitialized, nil)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(state)
+	})
+
+	return mux
+}
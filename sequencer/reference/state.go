@@ -0,0 +1,169 @@
+package reference
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"math/bits"
+	"sort"
+)
+
+// errInsufficientBalance is returned when an account cannot pay for a transaction
+var errInsufficientBalance = errors.New("insufficient balance")
+
+// Order is a resting limit order of the book.
+type Order struct {
+	ID       uint64 `json:"id"`
+	Account  string `json:"account"`
+	Side     string `json:"side"`
+	Price    uint64 `json:"price"`
+	Quantity uint64 `json:"quantity"`
+}
+
+// State is the application state of the reference executor: quote balances, base
+// positions and the order book of a single market. Positions may be negative, as on a
+// perpetual market. Buy orders escrow their quote balance while they rest.
+type State struct {
+	Height    uint64            `json:"height"`
+	Balances  map[string]uint64 `json:"balances"`
+	Positions map[string]int64  `json:"positions"`
+	// Bids are sorted by price, highest first, then by ID
+	Bids []Order `json:"bids"`
+	// Asks are sorted by price, lowest first, then by ID
+	Asks        []Order `json:"asks"`
+	NextOrderID uint64  `json:"next_order_id"`
+}
+
+// newState returns the genesis state with the balances of alloc.
+func newState(alloc map[string]uint64) *State {
+	state := &State{
+		Balances:    make(map[string]uint64, len(alloc)),
+		Positions:   make(map[string]int64),
+		Bids:        []Order{},
+		Asks:        []Order{},
+		NextOrderID: 1,
+	}
+	for account, balance := range alloc {
+		state.Balances[account] = balance
+	}
+	return state
+}
+
+// Root returns the state root, the SHA-256 of the canonical JSON encoding of the state.
+// Maps are encoded with sorted keys, so equal states have equal roots.
+func (s *State) Root() []byte {
+	bz, err := json.Marshal(s)
+	if err != nil {
+		// The state only holds JSON encodable values
+		panic(err)
+	}
+	root := sha256.Sum256(bz)
+	return root[:]
+}
+
+// clone returns a deep copy of the state, so a block can be applied without touching
+// the committed state.
+func (s *State) clone() *State {
+	c := *s
+	c.Balances = make(map[string]uint64, len(s.Balances))
+	for k, v := range s.Balances {
+		c.Balances[k] = v
+	}
+	c.Positions = make(map[string]int64, len(s.Positions))
+	for k, v := range s.Positions {
+		c.Positions[k] = v
+	}
+	c.Bids = append([]Order{}, s.Bids...)
+	c.Asks = append([]Order{}, s.Asks...)
+	return &c
+}
+
+// apply applies tx to the state. A transaction that fails leaves the state unchanged.
+func (s *State) apply(tx *Tx) error {
+	switch tx.Type {
+	case TxTransfer:
+		if s.Balances[tx.From] < tx.Amount {
+			return errInsufficientBalance
+		}
+		s.Balances[tx.From] -= tx.Amount
+		s.Balances[tx.To] += tx.Amount
+		return nil
+	case TxOrder:
+		return s.placeOrder(tx)
+	}
+	return nil
+}
+
+// placeOrder matches an order against the opposite side of the book at the resting
+// prices and rests what is left.
+func (s *State) placeOrder(tx *Tx) error {
+	order := Order{ID: s.NextOrderID, Account: tx.Account, Side: tx.Side, Price: tx.Price, Quantity: tx.Quantity}
+
+	if order.Side == SideBuy {
+		escrow, overflow := mul(order.Price, order.Quantity)
+		if overflow || s.Balances[order.Account] < escrow {
+			return errInsufficientBalance
+		}
+		s.Balances[order.Account] -= escrow
+	}
+	s.NextOrderID++
+
+	for order.Quantity > 0 {
+		book := &s.Asks
+		if order.Side == SideSell {
+			book = &s.Bids
+		}
+		if len(*book) == 0 {
+			break
+		}
+
+		resting := &(*book)[0]
+		if (order.Side == SideBuy && resting.Price > order.Price) || (order.Side == SideSell && resting.Price < order.Price) {
+			break
+		}
+
+		quantity := min(order.Quantity, resting.Quantity)
+		if order.Side == SideBuy {
+			s.fill(order.Account, resting.Account, resting.Price, quantity, order.Price)
+		} else {
+			s.fill(resting.Account, order.Account, resting.Price, quantity, resting.Price)
+		}
+
+		order.Quantity -= quantity
+		resting.Quantity -= quantity
+		if resting.Quantity == 0 {
+			*book = (*book)[1:]
+		}
+	}
+
+	if order.Quantity > 0 {
+		s.rest(order)
+	}
+	return nil
+}
+
+// fill settles quantity traded at price. The buyer escrowed limit per unit, and is
+// refunded the difference to price.
+func (s *State) fill(buyer, seller string, price, quantity, limit uint64) {
+	s.Balances[buyer] += (limit - price) * quantity
+	s.Balances[seller] += price * quantity
+	s.Positions[buyer] += int64(quantity)
+	s.Positions[seller] -= int64(quantity)
+}
+
+// rest adds order to its side of the book, keeping price and then time priority.
+func (s *State) rest(order Order) {
+	if order.Side == SideBuy {
+		i := sort.Search(len(s.Bids), func(i int) bool { return s.Bids[i].Price < order.Price })
+		s.Bids = append(s.Bids[:i], append([]Order{order}, s.Bids[i:]...)...)
+		return
+	}
+	i := sort.Search(len(s.Asks), func(i int) bool { return s.Asks[i].Price > order.Price })
+	s.Asks = append(s.Asks[:i], append([]Order{order}, s.Asks[i:]...)...)
+}
+
+// mul returns a*b and whether it overflowed.
+func mul(a, b uint64) (uint64, bool) {
+	hi, lo := bits.Mul64(a, b)
+	return lo, hi != 0
+}
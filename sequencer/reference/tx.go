@@ -0,0 +1,72 @@
+package reference
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+)
+
+// Transaction types
+const (
+	// TxTransfer moves quote balance between accounts
+	TxTransfer = "transfer"
+	// TxOrder places a limit order on the single market
+	TxOrder = "order"
+)
+
+// Order sides
+const (
+	SideBuy  = "buy"
+	SideSell = "sell"
+)
+
+// Tx is a transaction of the reference executor, encoded as JSON, e.g.
+//
+//	{"type":"transfer","from":"alice","to":"bob","amount":10}
+//	{"type":"order","account":"alice","side":"buy","price":100,"quantity":2}
+//
+// Transactions are not signed; the reference executor trusts the named accounts.
+type Tx struct {
+	Type string `json:"type"`
+
+	// Transfer fields
+	From   string `json:"from,omitempty"`
+	To     string `json:"to,omitempty"`
+	Amount uint64 `json:"amount,omitempty"`
+
+	// Order fields
+	Account  string `json:"account,omitempty"`
+	Side     string `json:"side,omitempty"`
+	Price    uint64 `json:"price,omitempty"`
+	Quantity uint64 `json:"quantity,omitempty"`
+}
+
+// DecodeTx decodes and validates the format of a transaction.
+func DecodeTx(bz []byte) (*Tx, error) {
+	var tx Tx
+	if err := json.Unmarshal(bz, &tx); err != nil {
+		return nil, fmt.Errorf("invalid transaction: %w", err)
+	}
+
+	switch tx.Type {
+	case TxTransfer:
+		if tx.From == "" || tx.To == "" || tx.Amount == 0 {
+			return nil, errors.New("transfer requires from, to and a positive amount")
+		}
+	case TxOrder:
+		if tx.Account == "" || tx.Price == 0 || tx.Quantity == 0 {
+			return nil, errors.New("order requires an account and a positive price and quantity")
+		}
+		if tx.Quantity > math.MaxInt64 {
+			return nil, errors.New("order quantity is too large")
+		}
+		if tx.Side != SideBuy && tx.Side != SideSell {
+			return nil, fmt.Errorf("order side must be %s or %s, got %q", SideBuy, SideSell, tx.Side)
+		}
+	default:
+		return nil, fmt.Errorf("unknown transaction type %q", tx.Type)
+	}
+
+	return &tx, nil
+}
@@ -0,0 +1,185 @@
+// Package release verifies that a binary is a canonical release, built reproducibly
+// from a published source revision.
+package release
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"debug/buildinfo"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"runtime/debug"
+)
+
+// ErrInvalidSignature is returned when a manifest is not signed by the trusted key.
+var ErrInvalidSignature = errors.New("release manifest signature is invalid")
+
+// Manifest lists the canonical release binaries published for a network.
+type Manifest struct {
+	Releases []Release `json:"releases"`
+}
+
+// Release is a canonical release binary.
+type Release struct {
+	Version string `json:"version"`
+	// Revision is the source commit the binary is built from
+	Revision  string `json:"revision"`
+	GoVersion string `json:"go_version"`
+	GOOS      string `json:"goos"`
+	GOARCH    string `json:"goarch"`
+	// SHA256 is the hex encoded SHA-256 of the binary built reproducibly from Revision
+	SHA256 string `json:"sha256"`
+}
+
+// SignedManifest is a manifest with an ed25519 signature over its exact JSON bytes.
+type SignedManifest struct {
+	Manifest  json.RawMessage `json:"manifest"`
+	Signature []byte          `json:"signature"`
+}
+
+// VerifyManifest decodes a signed manifest and checks its signature against pubKey.
+func VerifyManifest(bz []byte, pubKey ed25519.PublicKey) (*Manifest, error) {
+	var signed SignedManifest
+	if err := json.Unmarshal(bz, &signed); err != nil {
+		return nil, fmt.Errorf("failed to decode signed release manifest: %w", err)
+	}
+
+	if !ed25519.Verify(pubKey, signed.Manifest, signed.Signature) {
+		return nil, ErrInvalidSignature
+	}
+
+	var manifest Manifest
+	if err := json.Unmarshal(signed.Manifest, &manifest); err != nil {
+		return nil, fmt.Errorf("failed to decode release manifest: %w", err)
+	}
+	return &manifest, nil
+}
+
+// SignManifest returns manifest signed with privKey, encoded as a SignedManifest.
+func SignManifest(manifest Manifest, privKey ed25519.PrivateKey) ([]byte, error) {
+	bz, err := json.Marshal(manifest)
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(SignedManifest{Manifest: bz, Signature: ed25519.Sign(privKey, bz)})
+}
+
+// Build is the build metadata embedded in a binary by the Go toolchain.
+type Build struct {
+	GoVersion string
+	// Revision is the source commit, empty when the binary was built without VCS information
+	Revision string
+	// Modified reports whether the source tree had uncommitted changes
+	Modified bool
+	GOOS     string
+	GOARCH   string
+	// TrimPath reports whether file system paths were removed, which reproducible builds require
+	TrimPath bool
+	// SHA256 is the hex encoded SHA-256 of the binary
+	SHA256 string
+}
+
+// ReadBuild reads the build metadata and hash of the Go binary at path.
+func ReadBuild(path string) (Build, error) {
+	info, err := buildinfo.ReadFile(path)
+	if err != nil {
+		return Build{}, fmt.Errorf("failed to read build metadata: %w", err)
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return Build{}, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return Build{}, fmt.Errorf("failed to hash binary: %w", err)
+	}
+
+	build := buildFromInfo(info)
+	build.SHA256 = hex.EncodeToString(h.Sum(nil))
+	return build, nil
+}
+
+// buildFromInfo returns the build metadata of info.
+func buildFromInfo(info *debug.BuildInfo) Build {
+	build := Build{GoVersion: info.GoVersion}
+	for _, setting := range info.Settings {
+		switch setting.Key {
+		case "vcs.revision":
+			build.Revision = setting.Value
+		case "vcs.modified":
+			build.Modified = setting.Value == "true"
+		case "GOOS":
+			build.GOOS = setting.Value
+		case "GOARCH":
+			build.GOARCH = setting.Value
+		case "-trimpath":
+			build.TrimPath = setting.Value == "true"
+		}
+	}
+	return build
+}
+
+// Check is the result of one verification step.
+type Check struct {
+	Name     string
+	Expected string
+	Actual   string
+	OK       bool
+}
+
+// Verify looks up the release of manifest built from the revision and platform of
+// build, and checks that build matches it. It returns the release, nil if there is
+// none, and the checks made; the binary is canonical if all of them pass.
+func Verify(manifest *Manifest, build Build) (*Release, []Check) {
+	checks := []Check{
+		{Name: "clean source tree", Expected: "unmodified", Actual: sourceState(build), OK: build.Revision != "" && !build.Modified},
+		{Name: "trimmed paths", Expected: "true", Actual: fmt.Sprint(build.TrimPath), OK: build.TrimPath},
+	}
+
+	var release *Release
+	for i := range manifest.Releases {
+		r := &manifest.Releases[i]
+		if r.Revision == build.Revision && r.GOOS == build.GOOS && r.GOARCH == build.GOARCH {
+			release = r
+			break
+		}
+	}
+	if release == nil {
+		return nil, append(checks, Check{
+			Name:     "published release",
+			Expected: "listed in manifest",
+			Actual:   fmt.Sprintf("%s %s/%s", orNone(build.Revision), build.GOOS, build.GOARCH),
+		})
+	}
+
+	return release, append(checks,
+		Check{Name: "go version", Expected: release.GoVersion, Actual: build.GoVersion, OK: release.GoVersion == build.GoVersion},
+		Check{Name: "binary sha256", Expected: release.SHA256, Actual: build.SHA256, OK: release.SHA256 == build.SHA256},
+	)
+}
+
+// sourceState describes the source tree build was made from.
+func sourceState(build Build) string {
+	switch {
+	case build.Revision == "":
+		return "no VCS information"
+	case build.Modified:
+		return "modified"
+	}
+	return "unmodified"
+}
+
+// orNone returns s, or "none" when it is empty.
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
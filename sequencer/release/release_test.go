@@ -0,0 +1,100 @@
+package release
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"os"
+	"runtime"
+	"testing"
+)
+
+func TestVerifyManifest(t *testing.T) {
+	pubKey, privKey, err := ed25519.GenerateKey(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	manifest := Manifest{Releases: []Release{{Version: "v1.0.0", Revision: "abc", SHA256: "00"}}}
+
+	bz, err := SignManifest(manifest, privKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	got, err := VerifyManifest(bz, pubKey)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(got.Releases) != 1 || got.Releases[0].Version != "v1.0.0" {
+		t.Errorf("unexpected manifest %+v", got)
+	}
+
+	otherKey, _, _ := ed25519.GenerateKey(nil)
+	if _, err := VerifyManifest(bz, otherKey); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected invalid signature, got %v", err)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	release := Release{Version: "v1.0.0", Revision: "abc", GoVersion: "go1.24.8", GOOS: "linux", GOARCH: "amd64", SHA256: "1234"}
+	manifest := &Manifest{Releases: []Release{
+		{Version: "v1.0.0", Revision: "abc", GoVersion: "go1.24.8", GOOS: "darwin", GOARCH: "arm64", SHA256: "5678"},
+		release,
+	}}
+	canonical := Build{GoVersion: "go1.24.8", Revision: "abc", GOOS: "linux", GOARCH: "amd64", TrimPath: true, SHA256: "1234"}
+
+	failures := func(checks []Check) []string {
+		var names []string
+		for _, check := range checks {
+			if !check.OK {
+				names = append(names, check.Name)
+			}
+		}
+		return names
+	}
+
+	// The release of the revision and platform is matched
+	got, checks := Verify(manifest, canonical)
+	if got == nil || *got != release {
+		t.Fatalf("expected release %+v, got %+v", release, got)
+	}
+	if names := failures(checks); len(names) != 0 {
+		t.Errorf("expected every check to pass, failed %v", names)
+	}
+
+	// Rebuilt binaries differ in hash or toolchain
+	rebuilt := canonical
+	rebuilt.SHA256 = "9999"
+	rebuilt.GoVersion = "go1.24.7"
+	rebuilt.Modified = true
+	if _, checks := Verify(manifest, rebuilt); len(failures(checks)) != 3 {
+		t.Errorf("expected 3 failed checks, got %v", failures(checks))
+	}
+
+	// Revisions without a release fail
+	unpublished := canonical
+	unpublished.Revision = "def"
+	if got, checks := Verify(manifest, unpublished); got != nil || len(failures(checks)) != 1 {
+		t.Errorf("expected no release, got %+v and failed %v", got, failures(checks))
+	}
+}
+
+func TestReadBuild(t *testing.T) {
+	path, err := os.Executable()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	build, err := ReadBuild(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if build.GoVersion != runtime.Version() || build.GOOS != runtime.GOOS || build.GOARCH != runtime.GOARCH {
+		t.Errorf("unexpected build metadata %+v", build)
+	}
+	if len(build.SHA256) != 64 {
+		t.Errorf("expected a hex encoded SHA-256, got %q", build.SHA256)
+	}
+
+	if _, err := ReadBuild(os.DevNull); err == nil {
+		t.Error("expected error for a file that is not a Go binary")
+	}
+}
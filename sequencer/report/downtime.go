@@ -0,0 +1,310 @@
+// Package report reconstructs service reports from the node store and the DA layer.
+package report
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/store"
+
+	seqda "github.com/pranklin/pranklin-sequencer/da"
+)
+
+// DowntimeConfig selects the period and the tolerances of a downtime report.
+type DowntimeConfig struct {
+	// From and To bound the reported period
+	From time.Time
+	To   time.Time
+	// MaxBlockGap is the longest interval between two blocks not counted as downtime
+	MaxBlockGap time.Duration
+	// MaxDADelay is the longest delay between producing a block and its header being
+	// included in DA not counted as downtime
+	MaxDADelay time.Duration
+	// HeaderNamespace is the DA namespace headers are submitted to
+	HeaderNamespace []byte
+}
+
+// Gap is a period of downtime.
+type Gap struct {
+	Start   time.Time `json:"start"`
+	End     time.Time `json:"end"`
+	Seconds float64   `json:"seconds"`
+	// FromHeight and ToHeight are the blocks around a block production gap, or the
+	// first and last block pending DA inclusion during a DA submission gap.
+	// 0 means no such block is stored.
+	FromHeight uint64 `json:"from_height"`
+	ToHeight   uint64 `json:"to_height"`
+}
+
+// Downtime is a report of the gaps in block production and DA submission of a chain.
+type Downtime struct {
+	ChainID     string    `json:"chain_id"`
+	From        time.Time `json:"from"`
+	To          time.Time `json:"to"`
+	GeneratedAt time.Time `json:"generated_at"`
+	// FirstHeight and LastHeight are the blocks produced in the period, 0 if there are none
+	FirstHeight uint64 `json:"first_height"`
+	LastHeight  uint64 `json:"last_height"`
+	Blocks      uint64 `json:"blocks"`
+
+	MaxBlockGapSeconds float64 `json:"max_block_gap_seconds"`
+	MaxDADelaySeconds  float64 `json:"max_da_delay_seconds"`
+
+	BlockGaps []Gap `json:"block_gaps"`
+	DAGaps    []Gap `json:"da_gaps"`
+
+	BlockDowntimeSeconds float64 `json:"block_downtime_seconds"`
+	DADowntimeSeconds    float64 `json:"da_downtime_seconds"`
+	// BlockUptime and DAUptime are the fractions of the period without downtime
+	BlockUptime float64 `json:"block_uptime"`
+	DAUptime    float64 `json:"da_uptime"`
+}
+
+// block is the production and DA inclusion time of a stored block.
+type block struct {
+	height uint64
+	time   time.Time
+	// included is the time of the DA height the header was included at, zero if it is
+	// not included yet
+	included time.Time
+}
+
+// BuildDowntime reconstructs the downtime of the chain in the configured period from
+// the blocks in st and the timestamps of the DA heights they were included at.
+//
+// A block production gap is an interval between consecutive blocks, or between a
+// period bound and the closest block, longer than MaxBlockGap. A DA submission gap is
+// an interval during which a produced block waited longer than MaxDADelay for its
+// header to be included in DA; blocks never included wait until the end of the period.
+// Gaps are clipped to the period.
+func BuildDowntime(ctx context.Context, st store.Reader, daLayer coreda.DA, chainID string, cfg DowntimeConfig) (*Downtime, error) {
+	if !cfg.From.Before(cfg.To) {
+		return nil, fmt.Errorf("invalid period %s - %s", cfg.From.Format(time.RFC3339), cfg.To.Format(time.RFC3339))
+	}
+
+	report := &Downtime{
+		ChainID:            chainID,
+		From:               cfg.From.UTC(),
+		To:                 cfg.To.UTC(),
+		GeneratedAt:        time.Now().UTC(),
+		MaxBlockGapSeconds: cfg.MaxBlockGap.Seconds(),
+		MaxDADelaySeconds:  cfg.MaxDADelay.Seconds(),
+		BlockGaps:          []Gap{},
+		DAGaps:             []Gap{},
+	}
+
+	height, err := st.Height(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load store height: %w", err)
+	}
+
+	first, err := firstHeightAfter(ctx, st, height, cfg.From)
+	if err != nil {
+		return nil, err
+	}
+
+	l := &loader{st: st, daLayer: daLayer, namespace: cfg.HeaderNamespace, daTimes: make(map[uint64]time.Time)}
+
+	// The blocks just outside the period tell whether it starts or ends in a gap
+	var before, after *block
+	if first > 1 {
+		if before, err = l.load(ctx, first-1); err != nil {
+			return nil, err
+		}
+	}
+
+	var blocks []block
+	for h := first; h <= height; h++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		b, err := l.load(ctx, h)
+		if err != nil {
+			return nil, err
+		}
+		if b.time.After(cfg.To) {
+			after = b
+			break
+		}
+
+		if err := l.include(ctx, b); err != nil {
+			return nil, err
+		}
+		blocks = append(blocks, *b)
+	}
+
+	if len(blocks) > 0 {
+		report.FirstHeight = blocks[0].height
+		report.LastHeight = blocks[len(blocks)-1].height
+		report.Blocks = uint64(len(blocks))
+	}
+
+	report.BlockGaps = blockGaps(blocks, before, after, cfg)
+	report.DAGaps = daGaps(blocks, cfg)
+
+	period := cfg.To.Sub(cfg.From).Seconds()
+	report.BlockDowntimeSeconds = totalSeconds(report.BlockGaps)
+	report.DADowntimeSeconds = totalSeconds(report.DAGaps)
+	report.BlockUptime = 1 - report.BlockDowntimeSeconds/period
+	report.DAUptime = 1 - report.DADowntimeSeconds/period
+
+	return report, nil
+}
+
+// firstHeightAfter returns the first height up to height with a block produced at or
+// after t, or height+1 if there is none. Block times are monotonic, so it searches.
+func firstHeightAfter(ctx context.Context, st store.Reader, height uint64, t time.Time) (uint64, error) {
+	low, high := uint64(1), height+1
+	for low < high {
+		mid := low + (high-low)/2
+		header, err := st.GetHeader(ctx, mid)
+		if err != nil {
+			return 0, fmt.Errorf("failed to load header %d: %w", mid, err)
+		}
+		if header.Time().Before(t) {
+			low = mid + 1
+		} else {
+			high = mid
+		}
+	}
+	return low, nil
+}
+
+// loader loads stored blocks and the time their headers were included in DA.
+type loader struct {
+	st        store.Reader
+	daLayer   coreda.DA
+	namespace []byte
+	// daTimes caches the timestamps of DA heights
+	daTimes map[uint64]time.Time
+}
+
+// load loads the production time of the block at height.
+func (l *loader) load(ctx context.Context, height uint64) (*block, error) {
+	header, err := l.st.GetHeader(ctx, height)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load header %d: %w", height, err)
+	}
+	return &block{height: height, time: header.Time()}, nil
+}
+
+// include sets the time the header of b was included in DA, if it is.
+func (l *loader) include(ctx context.Context, b *block) error {
+	daHeight, _, err := seqda.BlockDAHeights(ctx, l.st, b.height)
+	if errors.Is(err, ds.ErrNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	included, ok := l.daTimes[daHeight]
+	if !ok {
+		result, err := l.daLayer.GetIDs(ctx, daHeight, l.namespace)
+		if err != nil {
+			return fmt.Errorf("failed to get DA height %d of block %d: %w", daHeight, b.height, err)
+		}
+		included = result.Timestamp
+		l.daTimes[daHeight] = included
+	}
+	b.included = included
+
+	return nil
+}
+
+// blockGaps returns the block production gaps of blocks, the blocks produced in the
+// period. before and after are the closest blocks outside the period, if any.
+func blockGaps(blocks []block, before, after *block, cfg DowntimeConfig) []Gap {
+	gaps := []Gap{}
+
+	prev := block{time: cfg.From}
+	if before != nil {
+		prev = *before
+	}
+	next := append(blocks[:len(blocks):len(blocks)], block{time: cfg.To})
+	if after != nil {
+		next[len(next)-1] = *after
+	}
+
+	for _, b := range next {
+		if b.time.Sub(prev.time) > cfg.MaxBlockGap {
+			gaps = appendGap(gaps, prev.time, b.time, prev.height, b.height, cfg)
+		}
+		prev = b
+	}
+
+	return gaps
+}
+
+// daGaps returns the DA submission gaps of blocks, merging the overlapping periods
+// they waited too long for inclusion.
+func daGaps(blocks []block, cfg DowntimeConfig) []Gap {
+	type wait struct {
+		start, end time.Time
+		height     uint64
+	}
+
+	var waits []wait
+	for _, b := range blocks {
+		end := b.included
+		if end.IsZero() {
+			end = cfg.To
+		}
+		if end.Sub(b.time) > cfg.MaxDADelay {
+			waits = append(waits, wait{start: b.time, end: end, height: b.height})
+		}
+	}
+	sort.Slice(waits, func(i, j int) bool { return waits[i].start.Before(waits[j].start) })
+
+	gaps := []Gap{}
+	for i := 0; i < len(waits); {
+		start, end := waits[i].start, waits[i].end
+		fromHeight, toHeight := waits[i].height, waits[i].height
+		for i++; i < len(waits) && !waits[i].start.After(end); i++ {
+			if waits[i].end.After(end) {
+				end = waits[i].end
+			}
+			toHeight = waits[i].height
+		}
+		gaps = appendGap(gaps, start, end, fromHeight, toHeight, cfg)
+	}
+
+	return gaps
+}
+
+// appendGap appends the gap from start to end, clipped to the period, to gaps.
+func appendGap(gaps []Gap, start, end time.Time, fromHeight, toHeight uint64, cfg DowntimeConfig) []Gap {
+	if start.Before(cfg.From) {
+		start = cfg.From
+	}
+	if end.After(cfg.To) {
+		end = cfg.To
+	}
+	if !start.Before(end) {
+		return gaps
+	}
+
+	return append(gaps, Gap{
+		Start:      start.UTC(),
+		End:        end.UTC(),
+		Seconds:    end.Sub(start).Seconds(),
+		FromHeight: fromHeight,
+		ToHeight:   toHeight,
+	})
+}
+
+// totalSeconds returns the summed length of gaps.
+func totalSeconds(gaps []Gap) float64 {
+	var total float64
+	for _, gap := range gaps {
+		total += gap.Seconds
+	}
+	return total
+}
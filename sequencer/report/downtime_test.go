@@ -0,0 +1,144 @@
+package report
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	coreda "github.com/evstack/ev-node/core/da"
+	"github.com/evstack/ev-node/pkg/signer/noop"
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// timestampDA reports fixed timestamps for DA heights
+type timestampDA struct {
+	coreda.DA
+	timestamps map[uint64]time.Time
+}
+
+func (d *timestampDA) GetIDs(_ context.Context, height uint64, _ []byte) (*coreda.GetIDsResult, error) {
+	timestamp, ok := d.timestamps[height]
+	if !ok {
+		return nil, errors.New("unknown DA height")
+	}
+	return &coreda.GetIDsResult{Timestamp: timestamp}, nil
+}
+
+// storeBlock saves a block produced at t and, if daHeight is not 0, the DA height its
+// header was included at.
+func storeBlock(t *testing.T, st store.Store, height uint64, at time.Time, daHeight uint64) {
+	t.Helper()
+	ctx := context.Background()
+
+	header, data := types.GetRandomBlock(height, 1, "test-chain")
+	header.BaseHeader.Time = uint64(at.UnixNano())
+
+	batch, _ := st.NewBatch(ctx)
+	if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := batch.SetHeight(height); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := batch.Commit(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if daHeight == 0 {
+		return
+	}
+	for _, kind := range []string{"h", "d"} {
+		bz := binary.LittleEndian.AppendUint64(nil, daHeight)
+		if err := st.SetMetadata(ctx, fmt.Sprintf("%s/%d/%s", store.HeightToDAHeightKey, height, kind), bz); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+}
+
+func TestBuildDowntime(t *testing.T) {
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	at := func(seconds int) time.Time { return start.Add(time.Duration(seconds) * time.Second) }
+
+	// Blocks every second, except a 10s outage after block 4. Block 3 waits 20s for DA
+	// and blocks 7 and 8 are never included.
+	seconds := []int{0, 1, 2, 3, 13, 14, 15, 16}
+	daHeights := []uint64{1, 1, 3, 4, 5, 5, 0, 0}
+	for i, s := range seconds {
+		storeBlock(t, st, uint64(i+1), at(s), daHeights[i])
+	}
+	daLayer := &timestampDA{timestamps: map[uint64]time.Time{
+		1: at(2), 3: at(22), 4: at(4), 5: at(15),
+	}}
+
+	report, err := BuildDowntime(context.Background(), st, daLayer, "test-chain", DowntimeConfig{
+		From:        at(1),
+		To:          at(25),
+		MaxBlockGap: 3 * time.Second,
+		MaxDADelay:  5 * time.Second,
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	if report.FirstHeight != 2 || report.LastHeight != 8 || report.Blocks != 7 {
+		t.Errorf("expected blocks 2-8, got %d-%d (%d)", report.FirstHeight, report.LastHeight, report.Blocks)
+	}
+
+	// The outage, and the tail from block 8 to the end of the period
+	expectedBlockGaps := []Gap{
+		{Start: at(3), End: at(13), Seconds: 10, FromHeight: 4, ToHeight: 5},
+		{Start: at(16), End: at(25), Seconds: 9, FromHeight: 8, ToHeight: 0},
+	}
+	if fmt.Sprint(report.BlockGaps) != fmt.Sprint(expectedBlockGaps) {
+		t.Errorf("expected block gaps %v, got %v", expectedBlockGaps, report.BlockGaps)
+	}
+
+	// Block 3 waits 20s, and before it is included blocks 7 and 8 start waiting until
+	// the end of the period
+	expectedDAGaps := []Gap{
+		{Start: at(2), End: at(25), Seconds: 23, FromHeight: 3, ToHeight: 8},
+	}
+	if fmt.Sprint(report.DAGaps) != fmt.Sprint(expectedDAGaps) {
+		t.Errorf("expected DA gaps %v, got %v", expectedDAGaps, report.DAGaps)
+	}
+	if report.BlockDowntimeSeconds != 19 || report.DADowntimeSeconds != 23 {
+		t.Errorf("expected 19s and 23s of downtime, got %v and %v", report.BlockDowntimeSeconds, report.DADowntimeSeconds)
+	}
+
+	privKey, _, err := crypto.GenerateEd25519Key(nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s, _ := noop.NewNoopSigner(privKey)
+	bz, err := Sign(report, s)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var verified Downtime
+	address, err := Verify(bz, &verified)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if expected, _ := s.GetAddress(); !bytes.Equal(address, expected) {
+		t.Errorf("expected the signer address, got %x", address)
+	}
+	if verified.DADowntimeSeconds != report.DADowntimeSeconds {
+		t.Errorf("expected the signed report, got %+v", verified)
+	}
+
+	tampered := bytes.Replace(bz, []byte(`"da_downtime_seconds":23`), []byte(`"da_downtime_seconds":2`), 1)
+	if _, err := Verify(tampered, &verified); !errors.Is(err, ErrInvalidSignature) {
+		t.Errorf("expected %v for a tampered report, got %v", ErrInvalidSignature, err)
+	}
+}
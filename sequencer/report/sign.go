@@ -0,0 +1,94 @@
+package report
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/libp2p/go-libp2p/core/crypto"
+
+	"github.com/evstack/ev-node/pkg/signer"
+)
+
+// ErrInvalidSignature is returned when a signed report does not match its signature.
+var ErrInvalidSignature = errors.New("report signature is invalid")
+
+// SignedReport is a report with a signature over its exact JSON bytes by the
+// sequencer key, so it can be checked against the proposer address of the genesis.
+type SignedReport struct {
+	Report json.RawMessage `json:"report"`
+	// PublicKey is the protobuf encoded libp2p public key of the signer
+	PublicKey []byte `json:"public_key"`
+	// Address is the address of the signer, as the genesis proposer_address
+	Address   []byte `json:"address"`
+	Signature []byte `json:"signature"`
+}
+
+// Sign returns report signed with s, encoded as a SignedReport.
+func Sign(report any, s signer.Signer) ([]byte, error) {
+	bz, err := json.Marshal(report)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode report: %w", err)
+	}
+
+	pubKey, err := s.GetPublic()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer public key: %w", err)
+	}
+	pubKeyBz, err := crypto.MarshalPublicKey(pubKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signer public key: %w", err)
+	}
+
+	address, err := s.GetAddress()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get signer address: %w", err)
+	}
+
+	signature, err := s.Sign(bz)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign report: %w", err)
+	}
+
+	return json.Marshal(SignedReport{
+		Report:    bz,
+		PublicKey: pubKeyBz,
+		Address:   address,
+		Signature: signature,
+	})
+}
+
+// Verify decodes a signed report into report after checking its signature and that
+// its address belongs to its public key. It returns the address of the signer, which
+// the caller compares with the key it trusts.
+func Verify(bz []byte, report any) ([]byte, error) {
+	var signed SignedReport
+	if err := json.Unmarshal(bz, &signed); err != nil {
+		return nil, fmt.Errorf("failed to decode signed report: %w", err)
+	}
+
+	pubKey, err := crypto.UnmarshalPublicKey(signed.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signer public key: %w", err)
+	}
+
+	raw, err := pubKey.Raw()
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode signer public key: %w", err)
+	}
+	if address := sha256.Sum256(raw); !bytes.Equal(address[:], signed.Address) {
+		return nil, ErrInvalidSignature
+	}
+
+	ok, err := pubKey.Verify(signed.Report, signed.Signature)
+	if err != nil || !ok {
+		return nil, ErrInvalidSignature
+	}
+
+	if err := json.Unmarshal(signed.Report, report); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	return signed.Address, nil
+}
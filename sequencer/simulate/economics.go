@@ -0,0 +1,135 @@
+package simulate
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+// BlockStats are the DA-relevant figures of a stored block.
+type BlockStats struct {
+	// Height of the block
+	Height uint64
+	// HeaderBytes is the size of the header blob
+	HeaderBytes uint64
+	// DataBytes is the size of the data blob; 0 for blocks without transactions, whose data is never published
+	DataBytes uint64
+	// Txs is the number of transactions in the block
+	Txs uint64
+}
+
+// CostModel prices DA submissions.
+//
+// A submission carries one blob per block in a namespace, so a batch of blocks is
+// submitted as one header submission plus, if any block has transactions, one data
+// submission.
+type CostModel struct {
+	// GasPrice is the DA gas price in native DA tokens
+	GasPrice float64
+	// GasPerSubmission is the fixed gas cost of a single DA submission
+	GasPerSubmission uint64
+	// GasPerByte is the gas cost per blob byte
+	GasPerByte uint64
+}
+
+// Scenario is a set of alternative fee and batching parameters.
+type Scenario struct {
+	// BatchBlocks is the number of blocks submitted to DA together
+	BatchBlocks uint64 `json:"batch_blocks"`
+	// FeePerTx is the fee collected per transaction, in native DA tokens
+	FeePerTx float64 `json:"fee_per_tx"`
+}
+
+// Result is the simulated outcome of a scenario over a height range.
+type Result struct {
+	Scenario
+	Blocks      uint64  `json:"blocks"`
+	Txs         uint64  `json:"txs"`
+	Submissions uint64  `json:"submissions"`
+	Bytes       uint64  `json:"bytes"`
+	DACost      float64 `json:"da_cost"`
+	Revenue     float64 `json:"revenue"`
+	NetMargin   float64 `json:"net_margin"`
+}
+
+// CollectBlockStats loads the blocks in [from, to] from st and measures their blobs.
+// Data blob sizes are measured from the unsigned data, so they slightly
+// underestimate the published blobs.
+func CollectBlockStats(ctx context.Context, st store.Reader, from, to uint64) ([]BlockStats, error) {
+	if from == 0 || from > to {
+		return nil, fmt.Errorf("invalid height range %d-%d", from, to)
+	}
+
+	stats := make([]BlockStats, 0, to-from+1)
+	for height := from; height <= to; height++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+
+		header, data, err := st.GetBlockData(ctx, height)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load block %d: %w", height, err)
+		}
+
+		headerBlob, err := header.MarshalBinary()
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal header %d: %w", height, err)
+		}
+
+		block := BlockStats{
+			Height:      height,
+			HeaderBytes: uint64(len(headerBlob)),
+			Txs:         uint64(len(data.Txs)),
+		}
+
+		if len(data.Txs) > 0 {
+			dataBlob, err := (&types.SignedData{Data: *data}).MarshalBinary()
+			if err != nil {
+				return nil, fmt.Errorf("failed to marshal data %d: %w", height, err)
+			}
+			block.DataBytes = uint64(len(dataBlob))
+		}
+
+		stats = append(stats, block)
+	}
+
+	return stats, nil
+}
+
+// Simulate computes the DA cost, fee revenue and net margin of submitting blocks
+// under scenario.
+func Simulate(blocks []BlockStats, model CostModel, scenario Scenario) (Result, error) {
+	if scenario.BatchBlocks == 0 {
+		return Result{}, errors.New("batch size must be > 0")
+	}
+
+	result := Result{Scenario: scenario}
+	for start := 0; start < len(blocks); start += int(scenario.BatchBlocks) {
+		end := min(start+int(scenario.BatchBlocks), len(blocks))
+
+		var headerBytes, dataBytes uint64
+		for _, block := range blocks[start:end] {
+			headerBytes += block.HeaderBytes
+			dataBytes += block.DataBytes
+			result.Txs += block.Txs
+		}
+
+		result.Submissions++
+		result.Bytes += headerBytes
+		if dataBytes > 0 {
+			result.Submissions++
+			result.Bytes += dataBytes
+		}
+	}
+
+	result.Blocks = uint64(len(blocks))
+	gas := result.Submissions*model.GasPerSubmission + result.Bytes*model.GasPerByte
+	result.DACost = float64(gas) * model.GasPrice
+	result.Revenue = float64(result.Txs) * scenario.FeePerTx
+	result.NetMargin = result.Revenue - result.DACost
+
+	return result, nil
+}
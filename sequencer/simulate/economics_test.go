@@ -0,0 +1,96 @@
+package simulate
+
+import (
+	"context"
+	"testing"
+
+	ds "github.com/ipfs/go-datastore"
+	dssync "github.com/ipfs/go-datastore/sync"
+
+	"github.com/evstack/ev-node/pkg/store"
+	"github.com/evstack/ev-node/types"
+)
+
+func TestCollectBlockStats(t *testing.T) {
+	ctx := context.Background()
+	st := store.New(dssync.MutexWrap(ds.NewMapDatastore()))
+
+	for height, txs := range map[uint64]int{1: 0, 2: 3} {
+		header, data := types.GetRandomBlock(height, txs, "test-chain")
+		batch, _ := st.NewBatch(ctx)
+		if err := batch.SaveBlockData(header, data, &types.Signature{}); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if err := batch.Commit(); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	stats, err := CollectBlockStats(ctx, st, 1, 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(stats) != 2 {
+		t.Fatalf("expected 2 blocks, got %d", len(stats))
+	}
+	if stats[0].DataBytes != 0 || stats[0].HeaderBytes == 0 {
+		t.Errorf("expected a header blob only for the empty block, got %+v", stats[0])
+	}
+	if stats[1].Txs != 3 || stats[1].DataBytes == 0 {
+		t.Errorf("expected 3 txs and a data blob, got %+v", stats[1])
+	}
+
+	if _, err := CollectBlockStats(ctx, st, 1, 3); err == nil {
+		t.Error("expected an error for a missing block")
+	}
+}
+
+func TestSimulate(t *testing.T) {
+	blocks := []BlockStats{
+		{Height: 1, HeaderBytes: 100, Txs: 0},
+		{Height: 2, HeaderBytes: 100, DataBytes: 500, Txs: 5},
+		{Height: 3, HeaderBytes: 100, DataBytes: 500, Txs: 5},
+	}
+	model := CostModel{GasPrice: 0.5, GasPerSubmission: 1000, GasPerByte: 1}
+
+	tests := []struct {
+		name            string
+		scenario        Scenario
+		wantSubmissions uint64
+		wantCost        float64
+		wantMargin      float64
+	}{
+		{
+			// 3 header + 2 data submissions, 1300 bytes
+			name:            "per block",
+			scenario:        Scenario{BatchBlocks: 1, FeePerTx: 100},
+			wantSubmissions: 5,
+			wantCost:        0.5 * (5*1000 + 1300),
+			wantMargin:      1000 - 0.5*(5*1000+1300),
+		},
+		{
+			// 1 header + 1 data submission, 1300 bytes
+			name:            "single batch",
+			scenario:        Scenario{BatchBlocks: 10, FeePerTx: 100},
+			wantSubmissions: 2,
+			wantCost:        0.5 * (2*1000 + 1300),
+			wantMargin:      1000 - 0.5*(2*1000+1300),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result, err := Simulate(blocks, model, tt.scenario)
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if result.Submissions != tt.wantSubmissions || result.DACost != tt.wantCost || result.NetMargin != tt.wantMargin {
+				t.Errorf("expected %d submissions, cost %v and margin %v, got %+v", tt.wantSubmissions, tt.wantCost, tt.wantMargin, result)
+			}
+		})
+	}
+
+	if _, err := Simulate(blocks, model, Scenario{}); err == nil {
+		t.Error("expected an error for a zero batch size")
+	}
+}
@@ -0,0 +1,50 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// CommandOptions customizes how a subprocess is started.
+type CommandOptions struct {
+	// Env holds KEY=VALUE entries added to the environment of the node; they win over
+	// variables of the same name
+	Env []string
+	// Args are appended to the arguments the node passes
+	Args []string
+	// Dir is the working directory, the node's own if empty
+	Dir string
+}
+
+// Validate checks that every environment entry has the KEY=VALUE form.
+func (o CommandOptions) Validate() error {
+	for _, entry := range o.Env {
+		if key, _, ok := strings.Cut(entry, "="); !ok || key == "" {
+			return fmt.Errorf("invalid environment entry %q: expected KEY=VALUE", entry)
+		}
+	}
+	return nil
+}
+
+// Apply applies the options to cmd before it is started. A binary given by a relative
+// path is resolved against the node's working directory, not Dir.
+func (o CommandOptions) Apply(cmd *exec.Cmd) {
+	cmd.Args = append(cmd.Args, o.Args...)
+
+	if o.Dir != "" {
+		if !filepath.IsAbs(cmd.Path) && strings.ContainsRune(cmd.Path, filepath.Separator) {
+			if path, err := filepath.Abs(cmd.Path); err == nil {
+				cmd.Path = path
+			}
+		}
+		cmd.Dir = o.Dir
+	}
+
+	if len(o.Env) > 0 {
+		// exec keeps the last value of duplicated variables
+		cmd.Env = append(os.Environ(), o.Env...)
+	}
+}
@@ -0,0 +1,58 @@
+package supervisor
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestCommandOptions(t *testing.T) {
+	t.Setenv("RUST_LOG", "info")
+
+	opts := CommandOptions{
+		Env:  []string{"RUST_LOG=info,pranklin=debug"},
+		Args: []string{"--snapshot", "--log-format=json"},
+		Dir:  t.TempDir(),
+	}
+	if err := opts.Validate(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	cmd := exec.Command(filepath.Join(".", "bin", "pranklin-app"), "start")
+	opts.Apply(cmd)
+
+	if want := []string{filepath.Join(".", "bin", "pranklin-app"), "start", "--snapshot", "--log-format=json"}; !slices.Equal(cmd.Args, want) {
+		t.Errorf("expected args %v, got %v", want, cmd.Args)
+	}
+	if cmd.Dir != opts.Dir {
+		t.Errorf("expected working directory %s, got %s", opts.Dir, cmd.Dir)
+	}
+
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if want := filepath.Join(wd, "bin", "pranklin-app"); cmd.Path != want {
+		t.Errorf("expected the binary resolved against the node's directory %s, got %s", want, cmd.Path)
+	}
+
+	// The last entry of a variable is the one exec passes
+	if i := slices.Index(cmd.Env, "RUST_LOG=info,pranklin=debug"); i < 0 || slices.Contains(cmd.Env[i+1:], "RUST_LOG=info") {
+		t.Errorf("expected RUST_LOG to be overridden, got %v", cmd.Env)
+	}
+
+	// Without options the subprocess inherits the node's environment and directory
+	cmd = exec.Command("local-da", "-port", "7980")
+	CommandOptions{}.Apply(cmd)
+	if cmd.Env != nil || cmd.Dir != "" || len(cmd.Args) != 3 {
+		t.Errorf("expected the command to be unchanged, got env %v dir %q args %v", cmd.Env, cmd.Dir, cmd.Args)
+	}
+
+	for _, entry := range []string{"RUST_LOG", "=debug"} {
+		if err := (CommandOptions{Env: []string{entry}}).Validate(); err == nil {
+			t.Errorf("expected %q to be refused", entry)
+		}
+	}
+}
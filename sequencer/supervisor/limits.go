@@ -0,0 +1,37 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Limits constrains the resources of a subprocess, so a misbehaving one cannot exhaust
+// the host. Zero values leave the limit inherited from the node.
+type Limits struct {
+	// OpenFiles is the maximum number of open file descriptors (RLIMIT_NOFILE)
+	OpenFiles uint64
+	// Memory is the maximum size of the virtual address space in bytes (RLIMIT_AS)
+	Memory uint64
+	// Nice is the scheduling niceness of the process group, from -20 (highest
+	// priority) to 19; negative values need privileges
+	Nice int
+}
+
+// errLimitsUnsupported is returned when limits are set on a platform that cannot apply them
+var errLimitsUnsupported = errors.New("subprocess resource limits are only supported on Linux")
+
+// IsZero reports whether no limit is set.
+func (l Limits) IsZero() bool {
+	return l == Limits{}
+}
+
+// Validate checks that the niceness is in range and that the platform supports limits.
+func (l Limits) Validate() error {
+	if l.Nice < -20 || l.Nice > 19 {
+		return fmt.Errorf("niceness must be between -20 and 19, got %d", l.Nice)
+	}
+	if !l.IsZero() && !limitsSupported {
+		return errLimitsUnsupported
+	}
+	return nil
+}
@@ -0,0 +1,43 @@
+//go:build linux
+
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"syscall"
+
+	"golang.org/x/sys/unix"
+)
+
+// limitsSupported reports whether Limits can be applied on this platform
+const limitsSupported = true
+
+// apply applies the limits to the running process pid. The niceness is set on the
+// process group led by pid, covering every thread and child, or on pid alone when it
+// does not lead one.
+func (l Limits) apply(pid int) error {
+	for resource, limit := range map[int]uint64{
+		unix.RLIMIT_NOFILE: l.OpenFiles,
+		unix.RLIMIT_AS:     l.Memory,
+	} {
+		if limit == 0 {
+			continue
+		}
+		rlimit := unix.Rlimit{Cur: limit, Max: limit}
+		if err := unix.Prlimit(pid, resource, &rlimit, nil); err != nil {
+			return fmt.Errorf("failed to set resource limit %d to %d: %w", resource, limit, err)
+		}
+	}
+
+	if l.Nice != 0 {
+		err := syscall.Setpriority(syscall.PRIO_PGRP, pid, l.Nice)
+		if errors.Is(err, syscall.ESRCH) {
+			err = syscall.Setpriority(syscall.PRIO_PROCESS, pid, l.Nice)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to set niceness to %d: %w", l.Nice, err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+//go:build linux
+
+package supervisor
+
+import (
+	"context"
+	"syscall"
+	"testing"
+	"time"
+
+	"golang.org/x/sys/unix"
+)
+
+func TestProcess_Limits(t *testing.T) {
+	proc, _ := newTestProcess(t, "sleep 30", 0)
+	proc.SetLimits(Limits{OpenFiles: 64, Memory: 1 << 30, Nice: 5})
+
+	ctx := context.Background()
+	if err := proc.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer proc.Stop(time.Second)
+
+	pid := proc.run.cmd.Process.Pid
+	for resource, want := range map[int]uint64{unix.RLIMIT_NOFILE: 64, unix.RLIMIT_AS: 1 << 30} {
+		var rlimit unix.Rlimit
+		if err := unix.Prlimit(pid, resource, nil, &rlimit); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if rlimit.Cur != want || rlimit.Max != want {
+			t.Errorf("resource %d: expected limit %d, got %+v", resource, want, rlimit)
+		}
+	}
+
+	// The raw syscall returns 20 - nice
+	prio, err := syscall.Getpriority(syscall.PRIO_PROCESS, pid)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if nice := 20 - prio; nice != 5 {
+		t.Errorf("expected niceness 5, got %d", nice)
+	}
+}
+
+func TestLimits_Validate(t *testing.T) {
+	if err := (Limits{Nice: 20}).Validate(); err == nil {
+		t.Error("expected an error for an out of range niceness")
+	}
+	if err := (Limits{OpenFiles: 1024, Nice: -20}).Validate(); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+}
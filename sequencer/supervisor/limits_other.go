@@ -0,0 +1,14 @@
+//go:build !linux
+
+package supervisor
+
+// limitsSupported reports whether Limits can be applied on this platform
+const limitsSupported = false
+
+// apply fails on platforms without prlimit; Validate rejects limits before.
+func (l Limits) apply(pid int) error {
+	if l.IsZero() {
+		return nil
+	}
+	return errLimitsUnsupported
+}
@@ -0,0 +1,70 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrNodeLocked is returned when another live node holds the lock of a root dir.
+var ErrNodeLocked = errors.New("root dir is locked by another node")
+
+// nodeLockFile is the lock file a node holds in its root dir while it runs
+const nodeLockFile = "node.lock"
+
+// AcquireNodeLock locks the root dir dir for this process, so a second node started
+// against it is refused before it starts subprocesses or opens the datastore.
+//
+// A lock left behind by a node that crashed, or whose pid now belongs to another
+// program, is stale and is taken over.
+//
+// Parameters:
+// - dir: Root dir of the node
+//
+// Returns:
+// - *PIDFile: The held lock; remove it on shutdown to release the root dir
+// - error: ErrNodeLocked if another node holds the lock, or any error writing it
+func AcquireNodeLock(dir string) (*PIDFile, error) {
+	command, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve executable: %w", err)
+	}
+
+	lock := &PIDFile{
+		Path:    filepath.Join(dir, nodeLockFile),
+		Name:    "node",
+		PID:     os.Getpid(),
+		Command: command,
+	}
+
+	// The lock is written in full before it is linked into place, so a concurrent
+	// node never reads a partial lock
+	tmp := fmt.Sprintf("%s.%d", lock.Path, lock.PID)
+	content := fmt.Sprintf("%d\n%s\n", lock.PID, lock.Command)
+	if err := os.WriteFile(tmp, []byte(content), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write node lock: %w", err)
+	}
+	defer os.Remove(tmp)
+
+	// A second attempt follows the removal of a stale lock
+	for range 2 {
+		err := os.Link(tmp, lock.Path)
+		if err == nil {
+			return lock, nil
+		}
+		if !errors.Is(err, os.ErrExist) {
+			return nil, fmt.Errorf("failed to create node lock %s: %w", lock.Path, err)
+		}
+
+		holder, err := ReadPIDFile(lock.Path)
+		if err == nil && processAlive(holder.PID) && processMatches(holder.PID, holder.Command) {
+			return nil, fmt.Errorf("%w: %s is held by pid %d", ErrNodeLocked, lock.Path, holder.PID)
+		}
+		if err := os.Remove(lock.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return nil, fmt.Errorf("failed to remove stale node lock %s: %w", lock.Path, err)
+		}
+	}
+
+	return nil, fmt.Errorf("%w: %s was taken while replacing a stale lock", ErrNodeLocked, lock.Path)
+}
@@ -0,0 +1,49 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestAcquireNodeLock(t *testing.T) {
+	dir := t.TempDir()
+
+	lock, err := AcquireNodeLock(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The lock is held by this live process
+	if _, err := AcquireNodeLock(dir); !errors.Is(err, ErrNodeLocked) {
+		t.Fatalf("expected %v, got %v", ErrNodeLocked, err)
+	}
+
+	if err := lock.Remove(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A lock of an exited node is stale
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skipf("true not available: %v", err)
+	}
+	lockPath := filepath.Join(dir, nodeLockFile)
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n%s\n", exited.Process.Pid, exited.Path)), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	lock, err = AcquireNodeLock(dir)
+	if err != nil {
+		t.Fatalf("expected the stale lock to be taken over, got %v", err)
+	}
+	if holder, err := ReadPIDFile(lockPath); err != nil || holder.PID != os.Getpid() {
+		t.Errorf("expected the lock to record this process, got %+v (%v)", holder, err)
+	}
+	if err := lock.Remove(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
@@ -0,0 +1,165 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	// maxLogLineSize bounds the length of a buffered output line; longer lines are split
+	maxLogLineSize = 64 << 10
+	// levelSearchFields is how many leading fields of a line are searched for its level,
+	// leaving room for a date and time before it
+	levelSearchFields = 3
+)
+
+// ansiEscape matches the color codes of terminal formatted logs
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// logLevels maps the level names used by the subprocess loggers to zerolog levels:
+// Rust tracing ("INFO"), env_logger ("[INFO]") and zerolog console output ("INF").
+// Fatal and panic lines are logged as errors so they cannot stop this process.
+var logLevels = map[string]zerolog.Level{
+	"TRACE":   zerolog.TraceLevel,
+	"TRC":     zerolog.TraceLevel,
+	"DEBUG":   zerolog.DebugLevel,
+	"DBG":     zerolog.DebugLevel,
+	"INFO":    zerolog.InfoLevel,
+	"INF":     zerolog.InfoLevel,
+	"WARN":    zerolog.WarnLevel,
+	"WARNING": zerolog.WarnLevel,
+	"WRN":     zerolog.WarnLevel,
+	"ERROR":   zerolog.ErrorLevel,
+	"ERR":     zerolog.ErrorLevel,
+	"FATAL":   zerolog.ErrorLevel,
+	"FTL":     zerolog.ErrorLevel,
+	"PANIC":   zerolog.ErrorLevel,
+	"PNC":     zerolog.ErrorLevel,
+}
+
+// LogWriter is an io.Writer feeding the output of a subprocess line by line through a
+// logger, so subprocess logs share the format of the node logs.
+type LogWriter struct {
+	logger       zerolog.Logger
+	defaultLevel zerolog.Level
+
+	mu      sync.Mutex
+	pending []byte
+}
+
+// NewLogWriter creates a writer logging each line of a subprocess output stream.
+//
+// Parameters:
+// - logger: Logger the lines are written to, tagged with the component and stream
+// - component: Name of the subprocess (e.g. "local-da", "execution")
+// - stream: Name of the output stream ("stdout" or "stderr")
+// - defaultLevel: Level of lines that do not carry one, e.g. panic traces
+//
+// Returns:
+// - *LogWriter: The initialized writer; set it as the subprocess output
+func NewLogWriter(logger zerolog.Logger, component, stream string, defaultLevel zerolog.Level) *LogWriter {
+	return &LogWriter{
+		logger:       logger.With().Str("component", component).Str("stream", stream).Logger(),
+		defaultLevel: defaultLevel,
+	}
+}
+
+// Write logs the complete lines of p, buffering a trailing partial line until the
+// next write. It never fails so it does not interrupt the subprocess output.
+func (w *LogWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			break
+		}
+		w.log(w.pending[:i])
+		w.pending = w.pending[i+1:]
+	}
+
+	if len(w.pending) > maxLogLineSize {
+		w.log(w.pending)
+		w.pending = nil
+	}
+	// Release the consumed prefix once the buffer is drained
+	if len(w.pending) == 0 {
+		w.pending = nil
+	}
+
+	return len(p), nil
+}
+
+// log writes line at the level it carries, or the default level.
+func (w *LogWriter) log(line []byte) {
+	text := strings.TrimSpace(string(ansiEscape.ReplaceAll(line, nil)))
+	if text == "" {
+		return
+	}
+
+	level, message, ok := parseJSONLine(text)
+	if !ok {
+		level, message = parseTextLine(text, w.defaultLevel)
+	}
+	w.logger.WithLevel(level).Msg(message)
+}
+
+// parseTextLine returns the level and message of a text log line. The level is
+// searched among the leading fields; what precedes it, usually a timestamp, is
+// dropped since the node logger adds its own.
+func parseTextLine(line string, defaultLevel zerolog.Level) (zerolog.Level, string) {
+	rest := line
+	for range levelSearchFields {
+		field, after, _ := strings.Cut(strings.TrimLeft(rest, " \t"), " ")
+		if field == "" {
+			break
+		}
+		if level, ok := logLevels[strings.Trim(field, "[]:")]; ok {
+			if message := strings.TrimSpace(after); message != "" {
+				return level, message
+			}
+			return level, line
+		}
+		rest = after
+	}
+	return defaultLevel, line
+}
+
+// parseJSONLine returns the level and message of a JSON log line, as written by
+// zerolog or the tracing JSON formatter.
+func parseJSONLine(line string) (zerolog.Level, string, bool) {
+	if !strings.HasPrefix(line, "{") {
+		return 0, "", false
+	}
+
+	var entry struct {
+		Level   string `json:"level"`
+		Message string `json:"message"`
+		Msg     string `json:"msg"`
+		Fields  struct {
+			Message string `json:"message"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(line), &entry); err != nil {
+		return 0, "", false
+	}
+
+	level, ok := logLevels[strings.ToUpper(entry.Level)]
+	if !ok {
+		return 0, "", false
+	}
+
+	for _, message := range []string{entry.Message, entry.Msg, entry.Fields.Message} {
+		if message != "" {
+			return level, message, true
+		}
+	}
+	return level, line, true
+}
@@ -0,0 +1,56 @@
+package supervisor
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rs/zerolog"
+)
+
+func TestLogWriter(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewLogWriter(zerolog.New(&buf), "execution", "stdout", zerolog.WarnLevel)
+
+	lines := "2025-01-02T03:04:05.678Z  INFO pranklin_app::server: listening addr=0.0.0.0:50051\n" +
+		"\x1b[90m3:04PM\x1b[0m \x1b[31mERR\x1b[0m submission failed\n" +
+		`{"level":"debug","fields":{"message":"block executed"}}` + "\n" +
+		"thread 'main' panicked at src/main.rs:10:5\n" +
+		"\n" +
+		"[WARN] partial"
+	// Split the output across writes like a pipe would
+	for _, chunk := range []string{lines[:30], lines[30:]} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+
+	expected := []struct {
+		level   string
+		message string
+	}{
+		{"info", "pranklin_app::server: listening addr=0.0.0.0:50051"},
+		{"error", "submission failed"},
+		{"debug", "block executed"},
+		{"warn", "thread 'main' panicked at src/main.rs:10:5"},
+	}
+
+	entries := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries, got %d: %s", len(expected), len(entries), buf.String())
+	}
+
+	for i, raw := range entries {
+		var entry map[string]string
+		if err := json.Unmarshal([]byte(raw), &entry); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		if entry["level"] != expected[i].level || entry["message"] != expected[i].message {
+			t.Errorf("entry %d: expected %s %q, got %s %q", i, expected[i].level, expected[i].message, entry["level"], entry["message"])
+		}
+		if entry["component"] != "execution" || entry["stream"] != "stdout" {
+			t.Errorf("entry %d: expected component and stream tags, got %v", i, entry)
+		}
+	}
+}
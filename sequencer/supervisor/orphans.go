@@ -0,0 +1,88 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	// ErrDatastoreLocked is returned when the datastore is held by another live process.
+	ErrDatastoreLocked = errors.New("datastore is locked by another process")
+	// ErrOrphanedProcesses is returned when subprocesses from a previous run are still alive.
+	ErrOrphanedProcesses = errors.New("subprocesses from a previous run are still running")
+)
+
+// datastoreLockFile is the file Badger records the pid of the process holding the datastore in
+const datastoreLockFile = "LOCK"
+
+// FindOrphans returns the subprocesses recorded in dir whose process group is still
+// running, even if the subprocess itself has exited and left children behind.
+// Pidfiles of groups that have exited, or whose pid now belongs to another
+// program, are removed.
+func FindOrphans(dir string) ([]*PIDFile, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+pidFileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	var orphans []*PIDFile
+	for _, path := range paths {
+		pidFile, err := ReadPIDFile(path)
+		if err != nil {
+			// An unreadable pidfile can't point at a process we could clean up
+			_ = os.Remove(path)
+			continue
+		}
+
+		if pidFile.PID == os.Getpid() || !pidFile.Running() {
+			if err := pidFile.Remove(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		orphans = append(orphans, pidFile)
+	}
+
+	return orphans, nil
+}
+
+// StopOrphans terminates the process groups of orphans, killing those that do not
+// exit within timeout, and removes their pidfiles.
+func StopOrphans(orphans []*PIDFile, timeout time.Duration) error {
+	for _, orphan := range orphans {
+		if err := terminateGroup(orphan.PID, timeout); err != nil {
+			return fmt.Errorf("failed to stop orphaned %s (pid %d): %w", orphan.Name, orphan.PID, err)
+		}
+		if err := orphan.Remove(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// CheckDatastoreLock returns ErrDatastoreLocked if the Badger datastore at path is
+// held by another live process.
+//
+// Badger releases its directory lock when the owning process exits, so a LOCK file
+// left behind by a crash is harmless and is not reported.
+func CheckDatastoreLock(path string) error {
+	bz, err := os.ReadFile(filepath.Join(path, datastoreLockFile))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	} else if err != nil {
+		return fmt.Errorf("failed to read datastore lock: %w", err)
+	}
+
+	pid, err := strconv.Atoi(strings.TrimSpace(string(bz)))
+	if err != nil || pid == os.Getpid() || !processAlive(pid) {
+		return nil
+	}
+
+	return fmt.Errorf("%w: %s is held by pid %d", ErrDatastoreLocked, path, pid)
+}
@@ -0,0 +1,95 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// startSleep starts a long-running subprocess and reaps it when it exits.
+func startSleep(t *testing.T) *exec.Cmd {
+	t.Helper()
+
+	proc := exec.Command("sleep", "30")
+	if err := proc.Start(); err != nil {
+		t.Skipf("sleep not available: %v", err)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		_ = proc.Wait()
+		close(done)
+	}()
+	t.Cleanup(func() {
+		_ = proc.Process.Kill()
+		<-done
+	})
+
+	return proc
+}
+
+func TestFindOrphans(t *testing.T) {
+	dir := t.TempDir()
+	proc := startSleep(t)
+
+	if _, err := WritePIDFile(dir, "execution", proc.Process.Pid, proc.Path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// A pidfile of an exited process is stale
+	exited := exec.Command("true")
+	if err := exited.Run(); err != nil {
+		t.Skipf("true not available: %v", err)
+	}
+	stale, err := WritePIDFile(dir, "local-da", exited.Process.Pid, exited.Path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	orphans, err := FindOrphans(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orphans) != 1 || orphans[0].Name != "execution" || orphans[0].PID != proc.Process.Pid {
+		t.Fatalf("expected the execution subprocess to be orphaned, got %+v", orphans)
+	}
+	if _, err := os.Stat(stale.Path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected stale pidfile to be removed, got %v", err)
+	}
+
+	if err := StopOrphans(orphans, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(orphans[0].Path); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("expected orphan pidfile to be removed, got %v", err)
+	}
+}
+
+func TestCheckDatastoreLock(t *testing.T) {
+	dir := t.TempDir()
+	lockPath := filepath.Join(dir, datastoreLockFile)
+
+	if err := CheckDatastoreLock(dir); err != nil {
+		t.Fatalf("expected no error without a lock file, got %v", err)
+	}
+
+	// A lock file left behind by this process is not a conflict
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", os.Getpid())), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckDatastoreLock(dir); err != nil {
+		t.Fatalf("expected no error for own lock, got %v", err)
+	}
+
+	proc := startSleep(t)
+	if err := os.WriteFile(lockPath, []byte(fmt.Sprintf("%d\n", proc.Process.Pid)), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := CheckDatastoreLock(dir); !errors.Is(err, ErrDatastoreLocked) {
+		t.Fatalf("expected ErrDatastoreLocked, got %v", err)
+	}
+}
@@ -0,0 +1,116 @@
+package supervisor
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// pidFileExt is the extension of pidfiles written by the supervisor
+const pidFileExt = ".pid"
+
+// PIDFile records a managed subprocess so it can be found again after a crash.
+type PIDFile struct {
+	// Path of the pidfile
+	Path string
+	// Name of the managed component (e.g. "local-da", "execution")
+	Name string
+	// PID of the subprocess
+	PID int
+	// Command is the binary the subprocess was started from
+	Command string
+}
+
+// WritePIDFile records the subprocess pid started from command as component name in dir.
+//
+// Parameters:
+// - dir: Directory holding the supervisor pidfiles
+// - name: Name of the managed component
+// - pid: PID of the subprocess
+// - command: Binary the subprocess was started from
+//
+// Returns:
+// - *PIDFile: The written pidfile
+// - error: Any error that occurred while writing the pidfile
+func WritePIDFile(dir, name string, pid int, command string) (*PIDFile, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create pidfile directory: %w", err)
+	}
+
+	pidFile := &PIDFile{
+		Path:    filepath.Join(dir, name+pidFileExt),
+		Name:    name,
+		PID:     pid,
+		Command: command,
+	}
+
+	content := fmt.Sprintf("%d\n%s\n", pid, command)
+	if err := os.WriteFile(pidFile.Path, []byte(content), 0o644); err != nil {
+		return nil, fmt.Errorf("failed to write pidfile %s: %w", pidFile.Path, err)
+	}
+
+	return pidFile, nil
+}
+
+// ReadPIDFile reads the pidfile at path.
+func ReadPIDFile(path string) (*PIDFile, error) {
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	lines := strings.Split(strings.TrimSpace(string(bz)), "\n")
+	pid, err := strconv.Atoi(strings.TrimSpace(lines[0]))
+	if err != nil || pid <= 0 {
+		return nil, fmt.Errorf("invalid pid in %s: %q", path, lines[0])
+	}
+
+	pidFile := &PIDFile{
+		Path: path,
+		Name: strings.TrimSuffix(filepath.Base(path), pidFileExt),
+		PID:  pid,
+	}
+	if len(lines) > 1 {
+		pidFile.Command = strings.TrimSpace(lines[1])
+	}
+
+	return pidFile, nil
+}
+
+// Remove deletes the pidfile. A nil or already removed pidfile is not an error.
+func (p *PIDFile) Remove() error {
+	if p == nil {
+		return nil
+	}
+	if err := os.Remove(p.Path); err != nil && !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to remove pidfile %s: %w", p.Path, err)
+	}
+	return nil
+}
+
+// ListPIDFiles returns the pidfiles recorded in dir.
+func ListPIDFiles(dir string) ([]*PIDFile, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*"+pidFileExt))
+	if err != nil {
+		return nil, err
+	}
+
+	pidFiles := make([]*PIDFile, 0, len(paths))
+	for _, path := range paths {
+		pidFile, err := ReadPIDFile(path)
+		if err != nil {
+			continue
+		}
+		pidFiles = append(pidFiles, pidFile)
+	}
+
+	return pidFiles, nil
+}
+
+// Running reports whether the process group of the recorded subprocess is still running.
+func (p *PIDFile) Running() bool {
+	return groupAlive(p.PID) && processMatches(p.PID, p.Command)
+}
@@ -0,0 +1,52 @@
+package supervisor
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// processMatches reports whether the process with pid was started from command.
+// It guards against pid reuse after a reboot; where /proc is unavailable any
+// live process is assumed to match.
+func processMatches(pid int, command string) bool {
+	if command == "" {
+		return true
+	}
+
+	cmdline, err := os.ReadFile(filepath.Join("/proc", strconv.Itoa(pid), "cmdline"))
+	if err != nil {
+		return true
+	}
+
+	argv0, _, _ := bytes.Cut(cmdline, []byte{0})
+	return filepath.Base(string(argv0)) == filepath.Base(command)
+}
+
+// terminateGroup sends SIGTERM to the process group led by pid and kills the group if
+// any of it is still alive after timeout. pid does not have to be a child of this process.
+func terminateGroup(pid int, timeout time.Duration) error {
+	if err := SignalGroup(pid, syscall.SIGTERM); err != nil {
+		if errors.Is(err, os.ErrProcessDone) {
+			return nil
+		}
+		return err
+	}
+
+	deadline := time.Now().Add(timeout)
+	for time.Now().Before(deadline) {
+		if !groupAlive(pid) {
+			return nil
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+
+	if err := SignalGroup(pid, syscall.SIGKILL); err != nil && !errors.Is(err, os.ErrProcessDone) {
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,41 @@
+//go:build !unix && !windows && !plan9
+
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// SetProcessGroup is a no-op on platforms without POSIX process groups. Plan 9, which
+// has notes instead of signals, is not supported.
+func SetProcessGroup(cmd *exec.Cmd) {}
+
+// SignalGroup sends sig to pid. Platforms without POSIX process groups only
+// support killing the process itself.
+func SignalGroup(pid int, sig syscall.Signal) error {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return err
+	}
+	return proc.Kill()
+}
+
+// groupAlive reports whether pid exists.
+func groupAlive(pid int) bool {
+	return processAlive(pid)
+}
+
+// processAlive reports whether a process with pid exists.
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = proc.Signal(syscall.Signal(0))
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
@@ -0,0 +1,58 @@
+//go:build unix
+
+package supervisor
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"syscall"
+)
+
+// SetProcessGroup configures cmd to start in its own process group, led by the
+// subprocess, so that children it spawns can be signalled together with it.
+// Cancelling the command's context sends SIGTERM to the whole group.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.Setpgid = true
+
+	cmd.Cancel = func() error {
+		return SignalGroup(cmd.Process.Pid, syscall.SIGTERM)
+	}
+}
+
+// SignalGroup sends sig to the process group led by pid. When pid does not lead a
+// group, e.g. it was started before process groups were used, only pid is signalled.
+func SignalGroup(pid int, sig syscall.Signal) error {
+	err := syscall.Kill(-pid, sig)
+	if errors.Is(err, syscall.ESRCH) {
+		err = syscall.Kill(pid, sig)
+	}
+	if errors.Is(err, syscall.ESRCH) {
+		return os.ErrProcessDone
+	}
+	return err
+}
+
+// groupAlive reports whether any process of the group led by pid, or pid itself, exists.
+func groupAlive(pid int) bool {
+	err := syscall.Kill(-pid, 0)
+	if err == nil || errors.Is(err, syscall.EPERM) {
+		return true
+	}
+	return processAlive(pid)
+}
+
+// processAlive reports whether a process with pid exists.
+func processAlive(pid int) bool {
+	proc, err := os.FindProcess(pid)
+	if err != nil {
+		return false
+	}
+
+	err = proc.Signal(syscall.Signal(0))
+	// EPERM means the process exists but belongs to another user
+	return err == nil || errors.Is(err, syscall.EPERM)
+}
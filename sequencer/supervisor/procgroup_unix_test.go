@@ -0,0 +1,51 @@
+//go:build unix
+
+package supervisor
+
+import (
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+func TestStopOrphans_ProcessGroup(t *testing.T) {
+	dir := t.TempDir()
+
+	// The shell leads the group and leaves a child behind when it is killed
+	proc := exec.Command("sh", "-c", "sleep 30 & wait")
+	SetProcessGroup(proc)
+	if err := proc.Start(); err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+	pid := proc.Process.Pid
+	t.Cleanup(func() { _ = SignalGroup(pid, syscall.SIGKILL) })
+
+	if _, err := WritePIDFile(dir, "execution", pid, proc.Path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// Give the shell time to spawn its child, then kill only the leader
+	time.Sleep(100 * time.Millisecond)
+	_ = proc.Process.Kill()
+	_ = proc.Wait()
+
+	if !groupAlive(pid) {
+		t.Fatal("expected the child to outlive the group leader")
+	}
+
+	orphans, err := FindOrphans(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(orphans) != 1 {
+		t.Fatalf("expected the orphaned group to be found, got %+v", orphans)
+	}
+
+	if err := StopOrphans(orphans, time.Second); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if groupAlive(pid) {
+		t.Error("expected the whole process group to be stopped")
+	}
+}
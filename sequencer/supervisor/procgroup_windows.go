@@ -0,0 +1,93 @@
+//go:build windows
+
+package supervisor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"syscall"
+
+	"golang.org/x/sys/windows"
+)
+
+// stillActive is the exit code GetExitCodeProcess reports for a running process
+const stillActive = 259
+
+// SetProcessGroup configures cmd to start in its own process group, so that console
+// control events can be sent to it without reaching the node. Cancelling the
+// command's context stops its whole process tree.
+func SetProcessGroup(cmd *exec.Cmd) {
+	if cmd.SysProcAttr == nil {
+		cmd.SysProcAttr = &syscall.SysProcAttr{}
+	}
+	cmd.SysProcAttr.CreationFlags |= windows.CREATE_NEW_PROCESS_GROUP
+
+	cmd.Cancel = func() error {
+		return SignalGroup(cmd.Process.Pid, syscall.SIGTERM)
+	}
+}
+
+// SignalGroup delivers sig to the process tree rooted at pid, the closest Windows has
+// to a process group. SIGINT and SIGTERM send CTRL_BREAK to the process group of pid,
+// which console programs treat like an interrupt; when it does not share the node's
+// console, e.g. it was left over by a previous run, the tree is closed with taskkill,
+// and killed if it cannot be closed. Other signals kill the tree.
+func SignalGroup(pid int, sig syscall.Signal) error {
+	if !processAlive(pid) {
+		return os.ErrProcessDone
+	}
+
+	if sig == syscall.SIGINT || sig == syscall.SIGTERM {
+		if err := windows.GenerateConsoleCtrlEvent(windows.CTRL_BREAK_EVENT, uint32(pid)); err == nil {
+			return nil
+		}
+		// Console programs without a window can only be terminated forcefully
+		if err := taskkill(pid, false); err == nil || errors.Is(err, os.ErrProcessDone) {
+			return err
+		}
+	}
+	return taskkill(pid, true)
+}
+
+// taskkill ends the process tree rooted at pid, forcefully if force is set.
+func taskkill(pid int, force bool) error {
+	args := []string{"/T", "/PID", strconv.Itoa(pid)}
+	if force {
+		args = append([]string{"/F"}, args...)
+	}
+
+	out, err := exec.Command("taskkill", args...).CombinedOutput()
+	if err != nil {
+		if !processAlive(pid) {
+			return os.ErrProcessDone
+		}
+		return fmt.Errorf("taskkill: %w: %s", err, bytes.TrimSpace(out))
+	}
+	return nil
+}
+
+// groupAlive reports whether pid exists. Windows does not track the tree once its
+// root exited.
+func groupAlive(pid int) bool {
+	return processAlive(pid)
+}
+
+// processAlive reports whether a process with pid is running.
+func processAlive(pid int) bool {
+	handle, err := windows.OpenProcess(windows.PROCESS_QUERY_LIMITED_INFORMATION, false, uint32(pid))
+	if err != nil {
+		// The process exists but belongs to another user
+		return errors.Is(err, windows.ERROR_ACCESS_DENIED)
+	}
+	defer windows.CloseHandle(handle)
+
+	var code uint32
+	if err := windows.GetExitCodeProcess(handle, &code); err != nil {
+		return true
+	}
+	return code == stillActive
+}
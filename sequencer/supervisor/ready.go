@@ -0,0 +1,63 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+var (
+	// ErrNotReady is returned when a subprocess is not ready within the readiness timeout.
+	ErrNotReady = errors.New("subprocess did not become ready")
+	// ErrExited is returned when a subprocess exits before it is ready.
+	ErrExited = errors.New("subprocess exited before it was ready")
+)
+
+const (
+	// minProbeInterval is the delay before the first probe is retried
+	minProbeInterval = 50 * time.Millisecond
+	// maxProbeInterval bounds the delay between probes
+	maxProbeInterval = time.Second
+)
+
+// Probe checks once whether a subprocess is ready to serve requests.
+type Probe func(ctx context.Context) error
+
+// WaitReady polls probe until it succeeds, backing off between attempts.
+//
+// Parameters:
+// - ctx: Context cancelling the wait
+// - timeout: How long the subprocess is given to become ready
+// - exited: Closed when the subprocess exits, so a crash is reported at once
+// - probe: The readiness check; each attempt is bounded by the remaining timeout
+//
+// Returns:
+// - time.Duration: How long the subprocess took to become ready
+// - error: ErrNotReady wrapping the last probe error, ErrExited or the context error
+func WaitReady(ctx context.Context, timeout time.Duration, exited <-chan struct{}, probe Probe) (time.Duration, error) {
+	start := time.Now()
+	waitCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	interval := minProbeInterval
+	for {
+		err := probe(waitCtx)
+		if err == nil {
+			return time.Since(start), nil
+		}
+
+		select {
+		case <-exited:
+			return 0, ErrExited
+		case <-waitCtx.Done():
+			if ctx.Err() != nil {
+				return 0, ctx.Err()
+			}
+			return 0, fmt.Errorf("%w within %s: %w", ErrNotReady, timeout, err)
+		case <-time.After(interval):
+		}
+
+		interval = min(2*interval, maxProbeInterval)
+	}
+}
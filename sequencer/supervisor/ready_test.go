@@ -0,0 +1,40 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWaitReady(t *testing.T) {
+	ctx := context.Background()
+	errRefused := errors.New("connection refused")
+
+	attempts := 0
+	probe := func(ctx context.Context) error {
+		attempts++
+		if attempts < 3 {
+			return errRefused
+		}
+		return nil
+	}
+	if _, err := WaitReady(ctx, time.Second, nil, probe); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+
+	failing := func(ctx context.Context) error { return errRefused }
+	_, err := WaitReady(ctx, 100*time.Millisecond, nil, failing)
+	if !errors.Is(err, ErrNotReady) || !errors.Is(err, errRefused) {
+		t.Errorf("expected ErrNotReady wrapping the probe error, got %v", err)
+	}
+
+	exited := make(chan struct{})
+	close(exited)
+	if _, err := WaitReady(ctx, time.Minute, exited, failing); !errors.Is(err, ErrExited) {
+		t.Errorf("expected ErrExited, got %v", err)
+	}
+}
@@ -0,0 +1,387 @@
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+var (
+	// ErrRestartsExhausted is returned when a subprocess keeps exiting after its last allowed restart.
+	ErrRestartsExhausted = errors.New("subprocess exited too many times")
+	// ErrNotRestarted is returned when a subprocess exits and its restart mode does not restart it.
+	ErrNotRestarted = errors.New("subprocess exited and is not restarted")
+	// ErrNotRunning is returned when a subprocess that is not running is asked to restart.
+	ErrNotRunning = errors.New("subprocess is not running")
+	// ErrReplacing is returned when a subprocess is asked to restart while it is already being replaced.
+	ErrReplacing = errors.New("subprocess is already being replaced")
+)
+
+// failedRunStopTimeout is how long a run that did not become ready is given to exit before it is killed
+const failedRunStopTimeout = 5 * time.Second
+
+// ReadyFunc waits until a started subprocess is ready. exited is closed when it exits.
+type ReadyFunc func(ctx context.Context, exited <-chan struct{}) error
+
+// RestartMode selects which exits of a subprocess are followed by a restart.
+type RestartMode string
+
+// Restart modes
+const (
+	// RestartAlways restarts the subprocess whenever it exits
+	RestartAlways RestartMode = "always"
+	// RestartOnFailure restarts the subprocess when it exits with an error or a signal
+	RestartOnFailure RestartMode = "on-failure"
+	// RestartNever never restarts the subprocess
+	RestartNever RestartMode = "never"
+)
+
+// ParseRestartMode parses a restart mode name.
+func ParseRestartMode(name string) (RestartMode, error) {
+	switch mode := RestartMode(name); mode {
+	case RestartAlways, RestartOnFailure, RestartNever:
+		return mode, nil
+	}
+	return "", fmt.Errorf("unknown restart mode %q (expected %s, %s or %s)", name, RestartAlways, RestartOnFailure, RestartNever)
+}
+
+// RestartPolicy controls how a subprocess is restarted after it exits.
+type RestartPolicy struct {
+	// Mode selects the exits that are restarted; empty means RestartAlways
+	Mode RestartMode
+	// MaxRestarts is the number of consecutive restarts allowed; 0 disables restarts
+	MaxRestarts int
+	// MinBackoff is the delay before the first restart
+	MinBackoff time.Duration
+	// MaxBackoff bounds the delay between restarts, which doubles after each one
+	MaxBackoff time.Duration
+	// StableAfter is how long a run has to last for the restart count and backoff to be reset
+	StableAfter time.Duration
+}
+
+// restarts reports whether an exit with err is followed by a restart.
+func (p RestartPolicy) restarts(err error) bool {
+	switch p.Mode {
+	case RestartNever:
+		return false
+	case RestartOnFailure:
+		return err != nil
+	default:
+		return true
+	}
+}
+
+// Process is a subprocess restarted with exponential backoff when it exits on its own.
+type Process struct {
+	name    string
+	command func(ctx context.Context) *exec.Cmd
+	ready   ReadyFunc
+	pidDir  string
+	policy  RestartPolicy
+	logger  zerolog.Logger
+	metrics *Metrics
+	limits  Limits
+	onExit  func(err error)
+
+	mu      sync.Mutex
+	run     *processRun
+	stopped bool
+	// replacing is set while Replace replaces the current run
+	replacing chan struct{}
+}
+
+// processRun is one run of a supervised subprocess
+type processRun struct {
+	cmd       *exec.Cmd
+	pidFile   *PIDFile
+	startedAt time.Time
+	// exited is closed once the subprocess exited and err is set
+	exited chan struct{}
+	err    error
+	// replaced is closed once Replace started the run replacing this one
+	replaced chan struct{}
+}
+
+// NewProcess creates a supervised subprocess. Nothing is started until Start is called.
+//
+// Parameters:
+// - name: Name of the managed component (e.g. "local-da", "execution")
+// - command: Builds the command of each run; it must not be started
+// - ready: Waits until a run is ready after it started
+// - pidDir: Directory the pidfile of each run is recorded in
+// - policy: How the subprocess is restarted after it exits
+// - logger: Logger for supervision events
+// - metrics: Metrics for restarts
+//
+// Returns:
+// - *Process: The supervised subprocess
+func NewProcess(name string, command func(ctx context.Context) *exec.Cmd, ready ReadyFunc, pidDir string, policy RestartPolicy, logger zerolog.Logger, metrics *Metrics) *Process {
+	return &Process{
+		name:    name,
+		command: command,
+		ready:   ready,
+		pidDir:  pidDir,
+		policy:  policy,
+		logger:  logger.With().Str("component", name).Logger(),
+		metrics: metrics,
+	}
+}
+
+// Name returns the name of the managed component.
+func (p *Process) Name() string {
+	return p.name
+}
+
+// SetLimits sets the resource limits applied to each run right after it starts.
+// Children the subprocess spawns before then do not inherit the rlimits.
+func (p *Process) SetLimits(limits Limits) {
+	p.limits = limits
+}
+
+// OnExit sets a function called when the subprocess exits on its own or fails to
+// start, before Supervise decides whether to restart it. It must return quickly.
+func (p *Process) OnExit(fn func(err error)) {
+	p.onExit = fn
+}
+
+// Start starts a run of the subprocess and waits until it is ready. A run that does
+// not become ready, or whose limits cannot be applied, is stopped.
+func (p *Process) Start(ctx context.Context) error {
+	cmd := p.command(ctx)
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("failed to start %s: %w", p.name, err)
+	}
+
+	run := &processRun{cmd: cmd, startedAt: time.Now(), exited: make(chan struct{})}
+	pidFile, err := WritePIDFile(p.pidDir, p.name, cmd.Process.Pid, cmd.Path)
+	if err != nil {
+		// Only cleanup after a crash depends on the pidfile
+		p.logger.Warn().Err(err).Msg("Failed to write pidfile")
+	}
+	run.pidFile = pidFile
+
+	go func() {
+		run.err = cmd.Wait()
+		_ = run.pidFile.Remove()
+		close(run.exited)
+	}()
+
+	p.mu.Lock()
+	p.run = run
+	stopped := p.stopped
+	p.mu.Unlock()
+
+	if stopped {
+		p.stopRun(run, failedRunStopTimeout)
+		return fmt.Errorf("%s: supervisor is stopped", p.name)
+	}
+
+	p.logger.Info().Int("pid", cmd.Process.Pid).Msg("Subprocess started")
+
+	if err := p.limits.apply(cmd.Process.Pid); err != nil {
+		p.stopRun(run, failedRunStopTimeout)
+		return fmt.Errorf("%s: %w", p.name, err)
+	}
+
+	if err := p.ready(ctx, run.exited); err != nil {
+		p.stopRun(run, failedRunStopTimeout)
+		return err
+	}
+	return nil
+}
+
+// Supervise restarts the subprocess each time it exits, as its restart mode allows,
+// until ctx is cancelled or Stop is called, in which case it returns nil. It returns
+// ErrNotRestarted once the subprocess exits without being restarted, and
+// ErrRestartsExhausted once it exits after MaxRestarts consecutive restarts. Start
+// must have succeeded.
+func (p *Process) Supervise(ctx context.Context) error {
+	restarts := 0
+	backoff := p.policy.MinBackoff
+
+	p.mu.Lock()
+	run := p.run
+	p.mu.Unlock()
+
+	var err error
+	for {
+		// A run that failed to start has no exit to wait for
+		if run != nil {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-run.exited:
+			}
+			err = run.err
+
+			// A run stopped by Replace is followed by the new run, not restarted
+			if next, replaced := p.replaced(ctx, run); replaced {
+				run = next
+				continue
+			}
+
+			if time.Since(run.startedAt) >= p.policy.StableAfter {
+				restarts = 0
+				backoff = p.policy.MinBackoff
+			}
+		}
+
+		if p.isStopped() || ctx.Err() != nil {
+			return nil
+		}
+
+		if p.onExit != nil {
+			p.onExit(err)
+		}
+
+		// A run that failed to start counts as a failure
+		if !p.policy.restarts(err) {
+			p.logger.Error().Err(err).Str("restart", string(p.policy.Mode)).Msg("Subprocess exited, not restarting")
+			return fmt.Errorf("%s: %w with restart mode %s: %v", p.name, ErrNotRestarted, p.policy.Mode, err)
+		}
+
+		if restarts >= p.policy.MaxRestarts {
+			p.logger.Error().Err(err).Int("restarts", restarts).Msg("Subprocess exited, giving up")
+			return fmt.Errorf("%s: %w after %d restarts: %v", p.name, ErrRestartsExhausted, restarts, err)
+		}
+
+		restarts++
+		p.metrics.Restarts.With("component", p.name).Add(1)
+		p.logger.Warn().Err(err).Int("restart", restarts).Int("max_restarts", p.policy.MaxRestarts).Dur("backoff", backoff).Msg("Subprocess exited, restarting")
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(2*backoff, p.policy.MaxBackoff)
+
+		run = nil
+		if err = p.Start(ctx); err != nil {
+			p.logger.Error().Err(err).Msg("Failed to restart subprocess")
+			continue
+		}
+
+		p.mu.Lock()
+		run = p.run
+		p.mu.Unlock()
+	}
+}
+
+// Replace stops the running subprocess gracefully, killing it if it does not exit
+// within timeout, and starts a new run of its command, e.g. after its binary was
+// upgraded. It returns once the new run is ready. Supervise follows the new run
+// without counting a restart; if it fails to start, Supervise restarts it as after a
+// crash. ctx bounds the new run like the one passed to Start.
+func (p *Process) Replace(ctx context.Context, timeout time.Duration) error {
+	p.mu.Lock()
+	run := p.run
+	switch {
+	case p.stopped:
+		p.mu.Unlock()
+		return fmt.Errorf("%s: supervisor is stopped", p.name)
+	case p.replacing != nil:
+		p.mu.Unlock()
+		return fmt.Errorf("%s: %w", p.name, ErrReplacing)
+	case run == nil || run.done():
+		p.mu.Unlock()
+		return fmt.Errorf("%s: %w", p.name, ErrNotRunning)
+	}
+	replacing := make(chan struct{})
+	p.replacing = replacing
+	run.replaced = replacing
+	p.mu.Unlock()
+
+	defer func() {
+		p.mu.Lock()
+		p.replacing = nil
+		p.mu.Unlock()
+		close(replacing)
+	}()
+
+	p.logger.Info().Int("pid", run.cmd.Process.Pid).Msg("Replacing subprocess")
+	p.stopRun(run, timeout)
+	return p.Start(ctx)
+}
+
+// replaced waits until the replacement of the exited run is over, if Replace stopped
+// it, and returns the run started in its place.
+func (p *Process) replaced(ctx context.Context, run *processRun) (*processRun, bool) {
+	p.mu.Lock()
+	replacing := run.replaced
+	p.mu.Unlock()
+	if replacing == nil {
+		return nil, false
+	}
+
+	select {
+	case <-ctx.Done():
+	case <-replacing:
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.run, p.run != run
+}
+
+// Stop stops the current run without restarting it, killing it if it does not exit
+// within timeout.
+func (p *Process) Stop(timeout time.Duration) {
+	p.mu.Lock()
+	p.stopped = true
+	run := p.run
+	p.mu.Unlock()
+
+	if run != nil {
+		p.stopRun(run, timeout)
+	}
+}
+
+// stopRun terminates the process group of run, killing it if it does not exit within timeout.
+func (p *Process) stopRun(run *processRun, timeout time.Duration) {
+	select {
+	case <-run.exited:
+		return
+	default:
+	}
+
+	pid := run.cmd.Process.Pid
+	p.logger.Info().Int("pid", pid).Msg("Stopping process")
+	if err := SignalGroup(pid, syscall.SIGTERM); errors.Is(err, os.ErrProcessDone) {
+		<-run.exited
+		return
+	}
+
+	select {
+	case <-run.exited:
+		p.logger.Info().Int("pid", pid).Msg("Process stopped gracefully")
+	case <-time.After(timeout):
+		p.logger.Warn().Int("pid", pid).Msg("Force killing process")
+		_ = SignalGroup(pid, syscall.SIGKILL)
+		<-run.exited
+	}
+}
+
+// done reports whether the run exited
+func (r *processRun) done() bool {
+	select {
+	case <-r.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+// isStopped reports whether Stop was called
+func (p *Process) isStopped() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stopped
+}
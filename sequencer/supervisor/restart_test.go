@@ -0,0 +1,195 @@
+//go:build unix
+
+package supervisor
+
+import (
+	"context"
+	"errors"
+	"os/exec"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+func newTestProcess(t *testing.T, script string, maxRestarts int) (*Process, *int) {
+	t.Helper()
+	if _, err := exec.LookPath("sh"); err != nil {
+		t.Skipf("sh not available: %v", err)
+	}
+
+	starts := 0
+	command := func(ctx context.Context) *exec.Cmd {
+		starts++
+		cmd := exec.CommandContext(ctx, "sh", "-c", script)
+		SetProcessGroup(cmd)
+		return cmd
+	}
+	ready := func(ctx context.Context, exited <-chan struct{}) error { return nil }
+	policy := RestartPolicy{
+		MaxRestarts: maxRestarts,
+		MinBackoff:  10 * time.Millisecond,
+		MaxBackoff:  20 * time.Millisecond,
+		StableAfter: time.Minute,
+	}
+
+	metrics, _ := NopMetrics()
+	return NewProcess("execution", command, ready, t.TempDir(), policy, zerolog.Nop(), metrics), &starts
+}
+
+func TestProcess_RestartsExhausted(t *testing.T) {
+	proc, starts := newTestProcess(t, "exit 1", 3)
+	ctx := context.Background()
+
+	if err := proc.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := proc.Supervise(ctx); !errors.Is(err, ErrRestartsExhausted) {
+		t.Fatalf("expected ErrRestartsExhausted, got %v", err)
+	}
+	if *starts != 4 {
+		t.Errorf("expected 1 start and 3 restarts, got %d starts", *starts)
+	}
+}
+
+func TestProcess_Stop(t *testing.T) {
+	proc, starts := newTestProcess(t, "sleep 30", 3)
+	ctx := context.Background()
+
+	if err := proc.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- proc.Supervise(ctx) }()
+
+	proc.Stop(time.Second)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Supervise to return after Stop")
+	}
+	if *starts != 1 {
+		t.Errorf("expected a stopped subprocess not to be restarted, got %d starts", *starts)
+	}
+}
+
+func TestProcess_Replace(t *testing.T) {
+	proc, starts := newTestProcess(t, "sleep 30", 0)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := proc.Replace(ctx, time.Second); !errors.Is(err, ErrNotRunning) {
+		t.Fatalf("expected ErrNotRunning before Start, got %v", err)
+	}
+	if err := proc.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	done := make(chan error, 1)
+	go func() { done <- proc.Supervise(ctx) }()
+
+	// Restarts are disabled, so Supervise would give up if the replaced run counted as a crash
+	for i := 0; i < 2; i++ {
+		if err := proc.Replace(ctx, time.Second); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if *starts != 3 {
+		t.Errorf("expected 1 start and 2 replacements, got %d starts", *starts)
+	}
+
+	select {
+	case err := <-done:
+		t.Fatalf("expected Supervise to follow the new run, returned %v", err)
+	case <-time.After(100 * time.Millisecond):
+	}
+
+	proc.Stop(time.Second)
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Supervise to return after Stop")
+	}
+}
+
+func TestProcess_RestartModes(t *testing.T) {
+	for _, tc := range []struct {
+		mode   RestartMode
+		script string
+		starts int
+		err    error
+	}{
+		{RestartNever, "exit 1", 1, ErrNotRestarted},
+		{RestartOnFailure, "exit 0", 1, ErrNotRestarted},
+		{RestartOnFailure, "exit 1", 3, ErrRestartsExhausted},
+		{RestartAlways, "exit 0", 3, ErrRestartsExhausted},
+	} {
+		t.Run(string(tc.mode)+" "+tc.script, func(t *testing.T) {
+			proc, starts := newTestProcess(t, tc.script, 2)
+			proc.policy.Mode = tc.mode
+			ctx := context.Background()
+
+			if err := proc.Start(ctx); err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if err := proc.Supervise(ctx); !errors.Is(err, tc.err) {
+				t.Fatalf("expected %v, got %v", tc.err, err)
+			}
+			if *starts != tc.starts {
+				t.Errorf("expected %d starts, got %d", tc.starts, *starts)
+			}
+		})
+	}
+}
+
+func TestProcess_OnExit(t *testing.T) {
+	proc, _ := newTestProcess(t, "exit 1", 2)
+	var exits []error
+	proc.OnExit(func(err error) { exits = append(exits, err) })
+	ctx := context.Background()
+
+	if err := proc.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if err := proc.Supervise(ctx); !errors.Is(err, ErrRestartsExhausted) {
+		t.Fatalf("expected %v, got %v", ErrRestartsExhausted, err)
+	}
+	// The first run and both restarts exited
+	if len(exits) != 3 {
+		t.Fatalf("expected 3 exits, got %d", len(exits))
+	}
+	for _, err := range exits {
+		if err == nil {
+			t.Error("expected the exit error")
+		}
+	}
+
+	// A stopped subprocess did not exit on its own
+	proc, _ = newTestProcess(t, "sleep 10", 2)
+	proc.OnExit(func(err error) { t.Error("unexpected exit of a stopped subprocess") })
+	if err := proc.Start(ctx); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- proc.Supervise(ctx) }()
+	proc.Stop(time.Second)
+	if err := <-done; err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+}
+
+func TestParseRestartMode(t *testing.T) {
+	if mode, err := ParseRestartMode("on-failure"); err != nil || mode != RestartOnFailure {
+		t.Errorf("expected %s, got %s, %v", RestartOnFailure, mode, err)
+	}
+	if _, err := ParseRestartMode("sometimes"); err == nil {
+		t.Error("expected an error for an unknown restart mode")
+	}
+}
@@ -0,0 +1,195 @@
+package supervisor
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatedTimeFormat is the timestamp of rotated log files, sortable by name
+const rotatedTimeFormat = "2006-01-02T15-04-05.000"
+
+// RotateConfig configures when a log file is rotated and how many rotated files are kept.
+type RotateConfig struct {
+	// MaxSize is the size in bytes a file may reach before it is rotated, 0 for no limit
+	MaxSize int64
+	// Interval is how long a file is written to before it is rotated, 0 for no limit
+	Interval time.Duration
+	// MaxFiles is how many rotated files are kept, 0 keeps all
+	MaxFiles int
+	// MaxAge is how long rotated files are kept, 0 keeps them regardless of age
+	MaxAge time.Duration
+}
+
+// RotatingFile is an io.Writer appending to a log file that is renamed aside once it
+// grows past its size limit or interval, e.g. da.log to da-2025-01-01T00-00-00.000.log.
+// Rotated files past the retention limits are removed.
+type RotatingFile struct {
+	path string
+	cfg  RotateConfig
+
+	mu       sync.Mutex
+	file     *os.File
+	size     int64
+	openedAt time.Time
+}
+
+// OpenRotatingFile opens the log file at path for appending, creating it and its
+// directory if needed.
+//
+// Parameters:
+// - path: Path of the log file, e.g. <dir>/da.log
+// - cfg: Rotation and retention limits
+//
+// Returns:
+// - *RotatingFile: The opened file; close it when the component stops writing
+// - error: Any error that occurred while opening the file
+func OpenRotatingFile(path string, cfg RotateConfig) (*RotatingFile, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f := &RotatingFile{path: path, cfg: cfg}
+	if err := f.open(); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Write appends p to the file, rotating it first when p would take it past its size
+// limit or its interval has elapsed. A write larger than the size limit goes to a file
+// of its own.
+func (f *RotatingFile) Write(p []byte) (int, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return 0, os.ErrClosed
+	}
+
+	oversize := f.cfg.MaxSize > 0 && f.size > 0 && f.size+int64(len(p)) > f.cfg.MaxSize
+	expired := f.cfg.Interval > 0 && time.Since(f.openedAt) >= f.cfg.Interval
+	if (oversize || expired) && f.size > 0 {
+		if err := f.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := f.file.Write(p)
+	f.size += int64(n)
+	return n, err
+}
+
+// Close closes the file.
+func (f *RotatingFile) Close() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if f.file == nil {
+		return nil
+	}
+	err := f.file.Close()
+	f.file = nil
+	return err
+}
+
+// open opens the log file for appending.
+func (f *RotatingFile) open() error {
+	file, err := os.OpenFile(f.path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := file.Stat()
+	if err != nil {
+		_ = file.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	f.file = file
+	f.size = info.Size()
+	f.openedAt = time.Now()
+	return nil
+}
+
+// rotate renames the log file aside, opens a new one and removes rotated files past
+// the retention limits.
+func (f *RotatingFile) rotate() error {
+	if err := f.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file: %w", err)
+	}
+	f.file = nil
+
+	base, ext := f.nameParts()
+	rotated := filepath.Join(filepath.Dir(f.path), base+"-"+time.Now().UTC().Format(rotatedTimeFormat)+ext)
+	if err := os.Rename(f.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if err := f.open(); err != nil {
+		return err
+	}
+	return f.prune()
+}
+
+// prune removes the oldest rotated files past MaxFiles and those older than MaxAge.
+func (f *RotatingFile) prune() error {
+	if f.cfg.MaxFiles <= 0 && f.cfg.MaxAge <= 0 {
+		return nil
+	}
+
+	rotated, err := f.rotatedFiles()
+	if err != nil {
+		return err
+	}
+
+	for i, path := range rotated {
+		remove := f.cfg.MaxFiles > 0 && i < len(rotated)-f.cfg.MaxFiles
+		if !remove && f.cfg.MaxAge > 0 {
+			if info, err := os.Stat(path); err == nil && time.Since(info.ModTime()) > f.cfg.MaxAge {
+				remove = true
+			}
+		}
+		if remove {
+			if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+				return fmt.Errorf("failed to remove rotated log file: %w", err)
+			}
+		}
+	}
+	return nil
+}
+
+// rotatedFiles returns the rotated files of the log, oldest first.
+func (f *RotatingFile) rotatedFiles() ([]string, error) {
+	base, ext := f.nameParts()
+	entries, err := os.ReadDir(filepath.Dir(f.path))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list log directory: %w", err)
+	}
+
+	var rotated []string
+	for _, entry := range entries {
+		stamp, ok := strings.CutPrefix(entry.Name(), base+"-")
+		if !ok || !strings.HasSuffix(stamp, ext) {
+			continue
+		}
+		if _, err := time.Parse(rotatedTimeFormat, strings.TrimSuffix(stamp, ext)); err != nil {
+			continue
+		}
+		rotated = append(rotated, filepath.Join(filepath.Dir(f.path), entry.Name()))
+	}
+
+	sort.Strings(rotated)
+	return rotated, nil
+}
+
+// nameParts splits the log file name into its base and extension, e.g. "da" and ".log".
+func (f *RotatingFile) nameParts() (string, string) {
+	name := filepath.Base(f.path)
+	ext := filepath.Ext(name)
+	return strings.TrimSuffix(name, ext), ext
+}
@@ -0,0 +1,113 @@
+package supervisor
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRotatingFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "da.log")
+
+	f, err := OpenRotatingFile(path, RotateConfig{MaxSize: 10, MaxFiles: 2})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	// Each line fills the file, so every write after the first rotates it
+	for _, line := range []string{"line 1\n", "line 2\n", "line 3\n", "line 4\n"} {
+		if _, err := f.Write([]byte(line)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		// Rotated names have millisecond precision
+		time.Sleep(2 * time.Millisecond)
+	}
+
+	bz, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(bz) != "line 4\n" {
+		t.Errorf("expected the current file to hold the last line, got %q", bz)
+	}
+
+	// Only the 2 newest rotated files are kept
+	rotated, err := f.rotatedFiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rotated) != 2 {
+		t.Fatalf("expected 2 rotated files, got %v", rotated)
+	}
+	for i, want := range []string{"line 2\n", "line 3\n"} {
+		if !strings.HasPrefix(filepath.Base(rotated[i]), "da-") {
+			t.Errorf("unexpected rotated file name %s", rotated[i])
+		}
+		if bz, _ := os.ReadFile(rotated[i]); string(bz) != want {
+			t.Errorf("expected rotated file %d to hold %q, got %q", i, want, bz)
+		}
+	}
+
+	// Reopening appends to the current file
+	if err := f.Close(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := f.Write([]byte("x")); err == nil {
+		t.Error("expected writing a closed file to fail")
+	}
+	f, err = OpenRotatingFile(path, RotateConfig{Interval: time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+	if _, err := f.Write([]byte("line 5\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if bz, _ := os.ReadFile(path); string(bz) != "line 4\nline 5\n" {
+		t.Errorf("expected the reopened file to be appended to, got %q", bz)
+	}
+}
+
+func TestRotatingFile_Interval(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "execution.log")
+
+	f, err := OpenRotatingFile(path, RotateConfig{Interval: 10 * time.Millisecond, MaxAge: time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer f.Close()
+
+	if _, err := f.Write([]byte("before\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := f.Write([]byte("after\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	rotated, err := f.rotatedFiles()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(rotated) != 1 {
+		t.Fatalf("expected the file to rotate once its interval elapsed, got %v", rotated)
+	}
+
+	// Rotated files older than MaxAge are removed on the next rotation
+	old := time.Now().Add(-2 * time.Hour)
+	if err := os.Chtimes(rotated[0], old, old); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := f.Write([]byte("later\n")); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(rotated[0]); !os.IsNotExist(err) {
+		t.Errorf("expected the expired rotated file to be removed, got %v", err)
+	}
+}